@@ -0,0 +1,32 @@
+package blobstore
+
+import (
+	"fmt"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// dryRunBlobstore hands out blob IDs without uploading anything.
+// It pairs with the agent used for dry runs, which never fetches blobs.
+type dryRunBlobstore struct {
+	lastID int
+	lock   *sync.Mutex
+}
+
+func NewDryRunBlobstore() Blobstore {
+	return &dryRunBlobstore{lock: &sync.Mutex{}}
+}
+
+func (b *dryRunBlobstore) Get(blobID string) (LocalBlob, error) {
+	return nil, bosherr.Errorf("Getting blob '%s' is not supported in a dry run", blobID)
+}
+
+func (b *dryRunBlobstore) Add(sourcePath string) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.lastID++
+
+	return fmt.Sprintf("dry-run-blob-%d", b.lastID), nil
+}
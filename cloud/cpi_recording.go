@@ -0,0 +1,122 @@
+package cloud
+
+import (
+	"encoding/json"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+const redactedValue = "<redacted>"
+
+// CPIRecording is a single CPI request/response pair
+// stored as one JSON line by the recording CPI cmd runner
+type CPIRecording struct {
+	Request  CmdInput  `json:"request"`
+	Response CmdOutput `json:"response"`
+
+	// Error is set when the CPI could not be executed at all
+	Error string `json:"error,omitempty"`
+}
+
+// cloudPropertiesArgs lists positions of arguments which hold
+// cloud properties (or VM env) provided by the manifest
+var cloudPropertiesArgs = map[string][]int{
+	"create_stemcell": {1},
+	"create_vm":       {2, 5},
+	"create_disk":     {1},
+}
+
+var secretKeyPatterns = []string{
+	"password",
+	"secret",
+	"token",
+	"credential",
+	"private_key",
+	"api_key",
+}
+
+// RedactCmdInput replaces values of secret looking keys in cloud properties
+// so that recordings could be shared. Arguments are normalized to their
+// JSON representation which is what the CPI receives anyway.
+func RedactCmdInput(input CmdInput) (CmdInput, error) {
+	bytes, err := json.Marshal(input.Arguments)
+	if err != nil {
+		return CmdInput{}, bosherr.WrapErrorf(err, "Marshalling CPI '%s' arguments", input.Method)
+	}
+
+	var args []interface{}
+
+	err = json.Unmarshal(bytes, &args)
+	if err != nil {
+		return CmdInput{}, bosherr.WrapErrorf(err, "Unmarshalling CPI '%s' arguments", input.Method)
+	}
+
+	for _, i := range cloudPropertiesArgs[input.Method] {
+		if i < len(args) {
+			args[i] = redactSecrets(args[i])
+		}
+	}
+
+	for i, arg := range args {
+		args[i] = redactNestedCloudProperties(arg)
+	}
+
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	input.Arguments = args
+
+	return input, nil
+}
+
+func redactNestedCloudProperties(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typedValue {
+			if k == "cloud_properties" {
+				typedValue[k] = redactSecrets(v)
+			} else {
+				typedValue[k] = redactNestedCloudProperties(v)
+			}
+		}
+	case []interface{}:
+		for i, v := range typedValue {
+			typedValue[i] = redactNestedCloudProperties(v)
+		}
+	}
+
+	return value
+}
+
+func redactSecrets(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for k, v := range typedValue {
+			if isSecretKey(k) {
+				typedValue[k] = redactedValue
+			} else {
+				typedValue[k] = redactSecrets(v)
+			}
+		}
+	case []interface{}:
+		for i, v := range typedValue {
+			typedValue[i] = redactSecrets(v)
+		}
+	}
+
+	return value
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, pattern := range secretKeyPatterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package cloud_test
+
+import (
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cloud"
+)
+
+var _ = Describe("RedactCmdInput", func() {
+	It("redacts secrets in cloud properties and nested cloud properties", func() {
+		input := CmdInput{
+			Method: "create_vm",
+			Arguments: []interface{}{
+				"fake-agent-id",
+				"fake-stemcell-cid",
+				biproperty.Map{
+					"instance_type":     "m1.small",
+					"secret_access_key": "fake-secret",
+					"nested":            biproperty.Map{"password": "fake-password"},
+				},
+				map[string]biproperty.Map{
+					"private": {
+						"ip":               "10.0.0.5",
+						"cloud_properties": biproperty.Map{"subnet": "fake-subnet", "api_key": "fake-api-key"},
+					},
+				},
+				[]string{"fake-disk-cid"},
+				biproperty.Map{"bosh": biproperty.Map{"password": "fake-hashed-password"}},
+			},
+			Context:    CmdContext{DirectorID: "fake-director-id"},
+			ApiVersion: 2,
+		}
+
+		redacted, err := RedactCmdInput(input)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(redacted.Method).To(Equal("create_vm"))
+		Expect(redacted.Context).To(Equal(CmdContext{DirectorID: "fake-director-id"}))
+		Expect(redacted.ApiVersion).To(Equal(2))
+		Expect(redacted.Arguments).To(Equal([]interface{}{
+			"fake-agent-id",
+			"fake-stemcell-cid",
+			map[string]interface{}{
+				"instance_type":     "m1.small",
+				"secret_access_key": "<redacted>",
+				"nested":            map[string]interface{}{"password": "<redacted>"},
+			},
+			map[string]interface{}{
+				"private": map[string]interface{}{
+					"ip":               "10.0.0.5",
+					"cloud_properties": map[string]interface{}{"subnet": "fake-subnet", "api_key": "<redacted>"},
+				},
+			},
+			[]interface{}{"fake-disk-cid"},
+			map[string]interface{}{"bosh": map[string]interface{}{"password": "<redacted>"}},
+		}))
+	})
+
+	It("does not redact arguments of other methods", func() {
+		input := CmdInput{Method: "set_vm_metadata", Arguments: []interface{}{"fake-vm-cid", map[string]string{"token": "fake-value"}}}
+
+		redacted, err := RedactCmdInput(input)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(redacted.Arguments).To(Equal([]interface{}{"fake-vm-cid", map[string]interface{}{"token": "fake-value"}}))
+	})
+
+	It("does not modify original arguments", func() {
+		cloudProps := biproperty.Map{"password": "fake-password"}
+
+		_, err := RedactCmdInput(CmdInput{Method: "create_disk", Arguments: []interface{}{1024, cloudProps, "fake-vm-cid"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloudProps).To(Equal(biproperty.Map{"password": "fake-password"}))
+	})
+})
@@ -0,0 +1,310 @@
+package cloud
+
+import (
+	"fmt"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+)
+
+// PlannedCall is a CPI call that would have been made if not for a dry run
+type PlannedCall struct {
+	Method    string
+	Arguments []interface{}
+	Result    interface{}
+}
+
+// DryRunCloud is an in-process Cloud which validates calls
+// and simulates CIDs instead of executing the CPI
+type DryRunCloud interface {
+	Cloud
+
+	// AddExisting makes CIDs created by previous deploys known to the cloud
+	AddExisting(vmCIDs, diskCIDs, stemcellCIDs []string)
+
+	Plan() []PlannedCall
+}
+
+type dryRunCloud struct {
+	vms       map[string]bool
+	disks     map[string]bool
+	stemcells map[string]bool
+
+	// disk CID -> VM CID
+	attachments map[string]string
+
+	lastID int
+	plan   []PlannedCall
+
+	lock   *sync.Mutex
+	logTag string
+	logger boshlog.Logger
+}
+
+func NewDryRunCloud(logger boshlog.Logger) DryRunCloud {
+	return &dryRunCloud{
+		vms:         map[string]bool{},
+		disks:       map[string]bool{},
+		stemcells:   map[string]bool{},
+		attachments: map[string]string{},
+
+		lock:   &sync.Mutex{},
+		logTag: "dryRunCloud",
+		logger: logger,
+	}
+}
+
+func (c *dryRunCloud) AddExisting(vmCIDs, diskCIDs, stemcellCIDs []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, cid := range vmCIDs {
+		c.vms[cid] = true
+	}
+
+	for _, cid := range diskCIDs {
+		c.disks[cid] = true
+	}
+
+	for _, cid := range stemcellCIDs {
+		c.stemcells[cid] = true
+	}
+}
+
+func (c *dryRunCloud) Plan() []PlannedCall {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]PlannedCall{}, c.plan...)
+}
+
+func (c *dryRunCloud) CreateStemcell(imagePath string, cloudProperties biproperty.Map) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(imagePath) == 0 {
+		return "", c.invalidCall("create_stemcell", "image path must be provided")
+	}
+
+	cid := c.nextCID("stemcell")
+	c.stemcells[cid] = true
+
+	c.record(cid, "create_stemcell", imagePath, cloudProperties)
+
+	return cid, nil
+}
+
+func (c *dryRunCloud) DeleteStemcell(stemcellCID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.stemcells[stemcellCID] {
+		return c.invalidCall("delete_stemcell", fmt.Sprintf("stemcell '%s' does not exist", stemcellCID))
+	}
+
+	delete(c.stemcells, stemcellCID)
+
+	c.record(nil, "delete_stemcell", stemcellCID)
+
+	return nil
+}
+
+func (c *dryRunCloud) HasVM(vmCID string) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	found := c.vms[vmCID]
+
+	c.record(found, "has_vm", vmCID)
+
+	return found, nil
+}
+
+func (c *dryRunCloud) CreateVM(
+	agentID string,
+	stemcellCID string,
+	cloudProperties biproperty.Map,
+	diskCIDs []string,
+	networksInterfaces map[string]biproperty.Map,
+	env biproperty.Map,
+) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if len(agentID) == 0 {
+		return "", c.invalidCall("create_vm", "agent ID must be provided")
+	}
+
+	if !c.stemcells[stemcellCID] {
+		return "", c.invalidCall("create_vm", fmt.Sprintf("stemcell '%s' does not exist", stemcellCID))
+	}
+
+	if len(networksInterfaces) == 0 {
+		return "", c.invalidCall("create_vm", "at least one network must be provided")
+	}
+
+	for _, diskCID := range diskCIDs {
+		if !c.disks[diskCID] {
+			return "", c.invalidCall("create_vm", fmt.Sprintf("disk '%s' does not exist", diskCID))
+		}
+	}
+
+	cid := c.nextCID("vm")
+	c.vms[cid] = true
+
+	c.record(cid, "create_vm", agentID, stemcellCID, cloudProperties, networksInterfaces, diskCIDs, env)
+
+	return cid, nil
+}
+
+func (c *dryRunCloud) SetVMMetadata(vmCID string, metadata VMMetadata) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.vms[vmCID] {
+		return c.invalidCall("set_vm_metadata", fmt.Sprintf("VM '%s' does not exist", vmCID))
+	}
+
+	c.record(nil, "set_vm_metadata", vmCID, metadata)
+
+	return nil
+}
+
+func (c *dryRunCloud) SetDiskMetadata(diskCID string, metadata DiskMetadata) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.disks[diskCID] {
+		return c.invalidCall("set_disk_metadata", fmt.Sprintf("disk '%s' does not exist", diskCID))
+	}
+
+	c.record(nil, "set_disk_metadata", diskCID, metadata)
+
+	return nil
+}
+
+func (c *dryRunCloud) DeleteVM(vmCID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.vms[vmCID] {
+		return c.invalidCall("delete_vm", fmt.Sprintf("VM '%s' does not exist", vmCID))
+	}
+
+	delete(c.vms, vmCID)
+
+	for diskCID, attachedVMCID := range c.attachments {
+		if attachedVMCID == vmCID {
+			delete(c.attachments, diskCID)
+		}
+	}
+
+	c.record(nil, "delete_vm", vmCID)
+
+	return nil
+}
+
+func (c *dryRunCloud) CreateDisk(size int, cloudProperties biproperty.Map, vmCID string) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if size <= 0 {
+		return "", c.invalidCall("create_disk", fmt.Sprintf("disk size must be positive, got %d", size))
+	}
+
+	if !c.vms[vmCID] {
+		return "", c.invalidCall("create_disk", fmt.Sprintf("VM '%s' does not exist", vmCID))
+	}
+
+	cid := c.nextCID("disk")
+	c.disks[cid] = true
+
+	c.record(cid, "create_disk", size, cloudProperties, vmCID)
+
+	return cid, nil
+}
+
+func (c *dryRunCloud) AttachDisk(vmCID, diskCID string) (interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.vms[vmCID] {
+		return nil, c.invalidCall("attach_disk", fmt.Sprintf("VM '%s' does not exist", vmCID))
+	}
+
+	if !c.disks[diskCID] {
+		return nil, c.invalidCall("attach_disk", fmt.Sprintf("disk '%s' does not exist", diskCID))
+	}
+
+	if attachedVMCID, found := c.attachments[diskCID]; found {
+		return nil, c.invalidCall("attach_disk", fmt.Sprintf("disk '%s' is already attached to VM '%s'", diskCID, attachedVMCID))
+	}
+
+	c.attachments[diskCID] = vmCID
+
+	c.record(nil, "attach_disk", vmCID, diskCID)
+
+	return nil, nil
+}
+
+func (c *dryRunCloud) DetachDisk(vmCID, diskCID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.vms[vmCID] {
+		return c.invalidCall("detach_disk", fmt.Sprintf("VM '%s' does not exist", vmCID))
+	}
+
+	if !c.disks[diskCID] {
+		return c.invalidCall("detach_disk", fmt.Sprintf("disk '%s' does not exist", diskCID))
+	}
+
+	delete(c.attachments, diskCID)
+
+	c.record(nil, "detach_disk", vmCID, diskCID)
+
+	return nil
+}
+
+func (c *dryRunCloud) DeleteDisk(diskCID string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if !c.disks[diskCID] {
+		return c.invalidCall("delete_disk", fmt.Sprintf("disk '%s' does not exist", diskCID))
+	}
+
+	if vmCID, found := c.attachments[diskCID]; found {
+		return c.invalidCall("delete_disk", fmt.Sprintf("disk '%s' is still attached to VM '%s'", diskCID, vmCID))
+	}
+
+	delete(c.disks, diskCID)
+
+	c.record(nil, "delete_disk", diskCID)
+
+	return nil
+}
+
+func (c *dryRunCloud) Info() (CpiInfo, error) {
+	return CpiInfo{ApiVersion: MaxCpiApiVersionSupported}, nil
+}
+
+func (c *dryRunCloud) String() string {
+	return "DryRunCloud{}"
+}
+
+func (c *dryRunCloud) nextCID(kind string) string {
+	c.lastID++
+	return fmt.Sprintf("dry-run-%s-%d", kind, c.lastID)
+}
+
+func (c *dryRunCloud) record(result interface{}, method string, args ...interface{}) {
+	c.logger.Debug(c.logTag, "Planned CPI '%s' call with arguments %#v", method, args)
+	c.plan = append(c.plan, PlannedCall{Method: method, Arguments: args, Result: result})
+}
+
+func (c *dryRunCloud) invalidCall(method, reason string) error {
+	return bosherr.Errorf("Invalid CPI '%s' call: %s", method, reason)
+}
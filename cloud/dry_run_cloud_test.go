@@ -0,0 +1,115 @@
+package cloud_test
+
+import (
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cloud"
+)
+
+var _ = Describe("DryRunCloud", func() {
+	var (
+		cloud    DryRunCloud
+		networks map[string]biproperty.Map
+	)
+
+	BeforeEach(func() {
+		cloud = NewDryRunCloud(boshlog.NewLogger(boshlog.LevelNone))
+		networks = map[string]biproperty.Map{"fake-network": {"type": "manual", "ip": "10.0.0.5"}}
+	})
+
+	It("reports latest supported CPI API version", func() {
+		info, err := cloud.Info()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.ApiVersion).To(Equal(MaxCpiApiVersionSupported))
+	})
+
+	It("simulates CIDs and records planned calls", func() {
+		stemcellCID, err := cloud.CreateStemcell("/fake-image", biproperty.Map{"fake-key": "fake-value"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stemcellCID).To(Equal("dry-run-stemcell-1"))
+
+		vmCID, err := cloud.CreateVM("fake-agent-id", stemcellCID, biproperty.Map{}, []string{}, networks, biproperty.Map{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vmCID).To(Equal("dry-run-vm-2"))
+
+		diskCID, err := cloud.CreateDisk(1024, biproperty.Map{}, vmCID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(diskCID).To(Equal("dry-run-disk-3"))
+
+		_, err = cloud.AttachDisk(vmCID, diskCID)
+		Expect(err).ToNot(HaveOccurred())
+
+		found, err := cloud.HasVM(vmCID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+
+		Expect(cloud.Plan()).To(Equal([]PlannedCall{
+			{Method: "create_stemcell", Arguments: []interface{}{"/fake-image", biproperty.Map{"fake-key": "fake-value"}}, Result: "dry-run-stemcell-1"},
+			{Method: "create_vm", Arguments: []interface{}{"fake-agent-id", "dry-run-stemcell-1", biproperty.Map{}, networks, []string{}, biproperty.Map{}}, Result: "dry-run-vm-2"},
+			{Method: "create_disk", Arguments: []interface{}{1024, biproperty.Map{}, "dry-run-vm-2"}, Result: "dry-run-disk-3"},
+			{Method: "attach_disk", Arguments: []interface{}{"dry-run-vm-2", "dry-run-disk-3"}},
+			{Method: "has_vm", Arguments: []interface{}{"dry-run-vm-2"}, Result: true},
+		}))
+	})
+
+	It("knows about existing resources", func() {
+		cloud.AddExisting([]string{"fake-vm-cid"}, []string{"fake-disk-cid"}, []string{"fake-stemcell-cid"})
+
+		Expect(cloud.DetachDisk("fake-vm-cid", "fake-disk-cid")).To(Succeed())
+		Expect(cloud.DeleteVM("fake-vm-cid")).To(Succeed())
+		Expect(cloud.DeleteDisk("fake-disk-cid")).To(Succeed())
+		Expect(cloud.DeleteStemcell("fake-stemcell-cid")).To(Succeed())
+
+		Expect(cloud.Plan()).To(HaveLen(4))
+	})
+
+	Describe("validation", func() {
+		It("rejects VMs created from unknown stemcells", func() {
+			_, err := cloud.CreateVM("fake-agent-id", "fake-stemcell-cid", biproperty.Map{}, []string{}, networks, biproperty.Map{})
+			Expect(err).To(MatchError("Invalid CPI 'create_vm' call: stemcell 'fake-stemcell-cid' does not exist"))
+			Expect(cloud.Plan()).To(BeEmpty())
+		})
+
+		It("rejects VMs without networks", func() {
+			cloud.AddExisting(nil, nil, []string{"fake-stemcell-cid"})
+
+			_, err := cloud.CreateVM("fake-agent-id", "fake-stemcell-cid", biproperty.Map{}, []string{}, nil, biproperty.Map{})
+			Expect(err).To(MatchError("Invalid CPI 'create_vm' call: at least one network must be provided"))
+		})
+
+		It("rejects disks without size", func() {
+			cloud.AddExisting([]string{"fake-vm-cid"}, nil, nil)
+
+			_, err := cloud.CreateDisk(0, biproperty.Map{}, "fake-vm-cid")
+			Expect(err).To(MatchError("Invalid CPI 'create_disk' call: disk size must be positive, got 0"))
+		})
+
+		It("rejects attaching disks twice", func() {
+			cloud.AddExisting([]string{"fake-vm-cid"}, []string{"fake-disk-cid"}, nil)
+
+			_, err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+			Expect(err).To(MatchError("Invalid CPI 'attach_disk' call: disk 'fake-disk-cid' is already attached to VM 'fake-vm-cid'"))
+		})
+
+		It("rejects deleting attached disks", func() {
+			cloud.AddExisting([]string{"fake-vm-cid"}, []string{"fake-disk-cid"}, nil)
+
+			_, err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = cloud.DeleteDisk("fake-disk-cid")
+			Expect(err).To(MatchError("Invalid CPI 'delete_disk' call: disk 'fake-disk-cid' is still attached to VM 'fake-vm-cid'"))
+		})
+
+		It("rejects deleting unknown VMs", func() {
+			err := cloud.DeleteVM("fake-vm-cid")
+			Expect(err).To(MatchError("Invalid CPI 'delete_vm' call: VM 'fake-vm-cid' does not exist"))
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
 	biinstall "github.com/cloudfoundry/bosh-cli/v7/installation"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
 )
 
 type Factory interface {
@@ -15,22 +16,55 @@ type Factory interface {
 type factory struct {
 	fs        boshsys.FileSystem
 	cmdRunner boshsys.CmdRunner
+	getenv    func(string) string
+	tracer    tracing.Tracer
+	logTag    string
 	logger    boshlog.Logger
 }
 
 func NewFactory(
 	fs boshsys.FileSystem,
 	cmdRunner boshsys.CmdRunner,
+	getenv func(string) string,
+	tracer tracing.Tracer,
 	logger boshlog.Logger,
 ) Factory {
 	return &factory{
 		fs:        fs,
 		cmdRunner: cmdRunner,
+		getenv:    getenv,
+		tracer:    tracer,
+		logTag:    "cloudFactory",
 		logger:    logger,
 	}
 }
 
 func (f *factory) NewCloud(installation biinstall.Installation, directorID string, stemcellApiVersion int) (Cloud, error) {
+	var cpiCmdRunner CPICmdRunner
+
+	// Empty values are treated as unset so that variables can be cleared with VAR=
+	if replayPath := f.getenv("BOSH_CPI_REPLAY"); len(replayPath) > 0 {
+		f.logger.Info(f.logTag, "Replaying CPI calls from '%s' instead of executing the CPI", replayPath)
+		cpiCmdRunner = NewReplayCPICmdRunner(replayPath, f.fs, f.logger)
+	} else {
+		foundCPI, err := f.findCPI(installation)
+		if err != nil {
+			return nil, err
+		}
+
+		cpiCmdRunner = NewCPICmdRunner(f.cmdRunner, foundCPI, f.logger)
+
+		if recordPath := f.getenv("BOSH_CPI_RECORD"); len(recordPath) > 0 {
+			f.logger.Info(f.logTag, "Recording CPI calls to '%s'", recordPath)
+			cpiCmdRunner = NewRecordingCPICmdRunner(cpiCmdRunner, recordPath, f.fs, f.logger)
+		}
+	}
+
+	cpiCmdRunner = NewTracingCPICmdRunner(cpiCmdRunner, f.tracer)
+	return NewCloud(cpiCmdRunner, directorID, stemcellApiVersion, f.logger), nil
+}
+
+func (f *factory) findCPI(installation biinstall.Installation) (CPI, error) {
 	numberCpiBinariesFound := 0
 	foundCPI := CPI{}
 
@@ -50,9 +84,8 @@ func (f *factory) NewCloud(installation biinstall.Installation, directorID strin
 	}
 
 	if numberCpiBinariesFound != 1 {
-		return nil, bosherr.Errorf("Found %d Jobs with a 'bin/cpi' binary. Expected 1.", numberCpiBinariesFound)
+		return CPI{}, bosherr.Errorf("Found %d Jobs with a 'bin/cpi' binary. Expected 1.", numberCpiBinariesFound)
 	}
 
-	cpiCmdRunner := NewCPICmdRunner(f.cmdRunner, foundCPI, f.logger)
-	return NewCloud(cpiCmdRunner, directorID, stemcellApiVersion, f.logger), nil
+	return foundCPI, nil
 }
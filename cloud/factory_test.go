@@ -0,0 +1,67 @@
+package cloud_test
+
+import (
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cloud"
+	biinstall "github.com/cloudfoundry/bosh-cli/v7/installation"
+	biinstallmanifest "github.com/cloudfoundry/bosh-cli/v7/installation/manifest"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
+)
+
+var _ = Describe("Factory", func() {
+	var (
+		fs           *fakesys.FakeFileSystem
+		env          map[string]string
+		installation biinstall.Installation
+		factory      Factory
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		env = map[string]string{}
+		installation = biinstall.NewInstallation(biinstall.NewTarget("/fake-target", ""), nil, biinstallmanifest.Manifest{})
+
+		factory = NewFactory(
+			fs,
+			fakesys.NewFakeCmdRunner(),
+			func(key string) string { return env[key] },
+			tracing.NewNoopTracer(),
+			boshlog.NewLogger(boshlog.LevelNone),
+		)
+	})
+
+	Describe("NewCloud", func() {
+		It("returns error if installation does not have a CPI", func() {
+			_, err := factory.NewCloud(installation, "fake-director-id", 1)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Found 0 Jobs with a 'bin/cpi' binary. Expected 1."))
+		})
+
+		It("replays CPI calls instead of looking for a CPI when BOSH_CPI_REPLAY is set", func() {
+			env["BOSH_CPI_REPLAY"] = "/fake-recording.json"
+
+			err := fs.WriteFileString("/fake-recording.json",
+				`{"request":{"method":"info","arguments":[]},"response":{"result":{"stemcell_formats":["fake-format"]},"log":""}}`+"\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			cloud, err := factory.NewCloud(installation, "fake-director-id", 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			info, err := cloud.Info()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.StemcellFormats).To(Equal([]string{"fake-format"}))
+		})
+
+		It("treats empty BOSH_CPI_REPLAY as unset", func() {
+			env["BOSH_CPI_REPLAY"] = ""
+
+			_, err := factory.NewCloud(installation, "fake-director-id", 1)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Found 0 Jobs"))
+		})
+	})
+})
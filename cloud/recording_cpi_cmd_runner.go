@@ -0,0 +1,86 @@
+package cloud
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+type recordingCPICmdRunner struct {
+	runner CPICmdRunner
+	path   string
+	fs     boshsys.FileSystem
+
+	lock   *sync.Mutex
+	logTag string
+	logger boshlog.Logger
+}
+
+// NewRecordingCPICmdRunner appends every CPI request/response pair to path.
+// Recording is best effort; failing to write never fails the CPI call.
+func NewRecordingCPICmdRunner(runner CPICmdRunner, path string, fs boshsys.FileSystem, logger boshlog.Logger) CPICmdRunner {
+	return recordingCPICmdRunner{
+		runner: runner,
+		path:   path,
+		fs:     fs,
+
+		lock:   &sync.Mutex{},
+		logTag: "recordingCPICmdRunner",
+		logger: logger,
+	}
+}
+
+func (r recordingCPICmdRunner) Run(context CmdContext, method string, apiVersion int, args ...interface{}) (CmdOutput, error) {
+	output, err := r.runner.Run(context, method, apiVersion, args...)
+
+	recordErr := r.record(CmdInput{
+		Method:     method,
+		Arguments:  args,
+		Context:    context,
+		ApiVersion: apiVersion,
+	}, output, err)
+	if recordErr != nil {
+		r.logger.Warn(r.logTag, "Failed to record CPI '%s' call: %s", method, recordErr)
+	}
+
+	return output, err
+}
+
+func (r recordingCPICmdRunner) record(input CmdInput, output CmdOutput, runErr error) error {
+	redactedInput, err := RedactCmdInput(input)
+	if err != nil {
+		return err
+	}
+
+	recording := CPIRecording{Request: redactedInput, Response: output}
+
+	if runErr != nil {
+		recording.Error = runErr.Error()
+	}
+
+	bytes, err := json.Marshal(recording)
+	if err != nil {
+		return bosherr.WrapError(err, "Marshalling CPI recording")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	file, err := r.fs.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Opening CPI recording file '%s'", r.path)
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(append(bytes, '\n'))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing CPI recording file '%s'", r.path)
+	}
+
+	return nil
+}
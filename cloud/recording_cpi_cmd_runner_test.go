@@ -0,0 +1,103 @@
+package cloud_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cloud"
+	fakebicloud "github.com/cloudfoundry/bosh-cli/v7/cloud/fakes"
+)
+
+var _ = Describe("RecordingCPICmdRunner", func() {
+	var (
+		fakeCPICmdRunner *fakebicloud.FakeCPICmdRunner
+		fs               *fakesys.FakeFileSystem
+		runner           CPICmdRunner
+		context          CmdContext
+	)
+
+	BeforeEach(func() {
+		fakeCPICmdRunner = fakebicloud.NewFakeCPICmdRunner()
+		fs = fakesys.NewFakeFileSystem()
+		runner = NewRecordingCPICmdRunner(fakeCPICmdRunner, "/fake-recording.json", fs, boshlog.NewLogger(boshlog.LevelNone))
+		context = CmdContext{DirectorID: "fake-director-id"}
+	})
+
+	readRecordings := func() []CPIRecording {
+		contents, err := fs.ReadFileString("/fake-recording.json")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contents).To(HaveSuffix("\n"))
+
+		var recordings []CPIRecording
+		for _, line := range strings.Split(strings.TrimSpace(contents), "\n") {
+			var recording CPIRecording
+			Expect(json.Unmarshal([]byte(line), &recording)).To(Succeed())
+			recordings = append(recordings, recording)
+		}
+
+		return recordings
+	}
+
+	It("records redacted request and response", func() {
+		fakeCPICmdRunner.CurrentRunCmdOutput = CmdOutput{Result: "fake-disk-cid", Log: "fake-log"}
+
+		output, err := runner.Run(context, "create_disk", 2, 1024, biproperty.Map{"password": "fake-password"}, "fake-vm-cid")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal(CmdOutput{Result: "fake-disk-cid", Log: "fake-log"}))
+
+		Expect(fakeCPICmdRunner.CurrentRunInput[0].Arguments).To(Equal(
+			[]interface{}{1024, biproperty.Map{"password": "fake-password"}, "fake-vm-cid"}))
+
+		Expect(readRecordings()).To(Equal([]CPIRecording{{
+			Request: CmdInput{
+				Method:     "create_disk",
+				Arguments:  []interface{}{float64(1024), map[string]interface{}{"password": "<redacted>"}, "fake-vm-cid"},
+				Context:    context,
+				ApiVersion: 2,
+			},
+			Response: CmdOutput{Result: "fake-disk-cid", Log: "fake-log"},
+		}}))
+	})
+
+	It("records CPI errors", func() {
+		fakeCPICmdRunner.CurrentRunCmdOutput = CmdOutput{
+			Error: &CmdError{Type: "Bosh::Clouds::CloudError", Message: "fake-message"},
+		}
+
+		_, err := runner.Run(context, "delete_vm", 1, "fake-vm-cid")
+		Expect(err).ToNot(HaveOccurred())
+
+		recordings := readRecordings()
+		Expect(recordings).To(HaveLen(1))
+		Expect(recordings[0].Response.Error).To(Equal(&CmdError{Type: "Bosh::Clouds::CloudError", Message: "fake-message"}))
+		Expect(recordings[0].Error).To(BeEmpty())
+	})
+
+	It("records execution errors", func() {
+		fakeCPICmdRunner.CurrentRunError = errors.New("fake-err")
+
+		_, err := runner.Run(context, "info", 1)
+		Expect(err).To(MatchError("fake-err"))
+
+		recordings := readRecordings()
+		Expect(recordings).To(HaveLen(1))
+		Expect(recordings[0].Request.Arguments).To(Equal([]interface{}{}))
+		Expect(recordings[0].Error).To(Equal("fake-err"))
+	})
+
+	It("does not fail CPI call when recording cannot be written", func() {
+		fs.OpenFileErr = errors.New("fake-open-err")
+		fakeCPICmdRunner.CurrentRunCmdOutput = CmdOutput{Result: "fake-result"}
+
+		output, err := runner.Run(context, "info", 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal(CmdOutput{Result: "fake-result"}))
+	})
+})
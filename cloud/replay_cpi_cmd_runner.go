@@ -0,0 +1,101 @@
+package cloud
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+type replayCPICmdRunner struct {
+	path string
+	fs   boshsys.FileSystem
+
+	recordings []CPIRecording
+	loaded     bool
+	next       int
+
+	lock   *sync.Mutex
+	logTag string
+	logger boshlog.Logger
+}
+
+// NewReplayCPICmdRunner answers CPI calls from a recording made by
+// the recording CPI cmd runner instead of executing the CPI. Calls must
+// be made in the same order as they were recorded.
+func NewReplayCPICmdRunner(path string, fs boshsys.FileSystem, logger boshlog.Logger) CPICmdRunner {
+	return &replayCPICmdRunner{
+		path: path,
+		fs:   fs,
+
+		lock:   &sync.Mutex{},
+		logTag: "replayCPICmdRunner",
+		logger: logger,
+	}
+}
+
+func (r *replayCPICmdRunner) Run(context CmdContext, method string, apiVersion int, args ...interface{}) (CmdOutput, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	err := r.load()
+	if err != nil {
+		return CmdOutput{}, err
+	}
+
+	if r.next >= len(r.recordings) {
+		return CmdOutput{}, bosherr.Errorf(
+			"Replaying CPI '%s' call: recording '%s' only contains %d calls", method, r.path, len(r.recordings))
+	}
+
+	recording := r.recordings[r.next]
+
+	if recording.Request.Method != method {
+		return CmdOutput{}, bosherr.Errorf(
+			"Replaying CPI '%s' call: expected call %d to be '%s' according to recording '%s'",
+			method, r.next+1, recording.Request.Method, r.path)
+	}
+
+	r.next++
+
+	r.logger.Debug(r.logTag, "Replaying CPI '%s' call %d from '%s'", method, r.next, r.path)
+
+	if len(recording.Error) > 0 {
+		return CmdOutput{}, bosherr.Error(recording.Error)
+	}
+
+	return recording.Response, nil
+}
+
+func (r *replayCPICmdRunner) load() error {
+	if r.loaded {
+		return nil
+	}
+
+	contents, err := r.fs.ReadFileString(r.path)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading CPI recording '%s'", r.path)
+	}
+
+	for i, line := range strings.Split(contents, "\n") {
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var recording CPIRecording
+
+		err := json.Unmarshal([]byte(line), &recording)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Unmarshalling CPI recording '%s' line %d", r.path, i+1)
+		}
+
+		r.recordings = append(r.recordings, recording)
+	}
+
+	r.loaded = true
+
+	return nil
+}
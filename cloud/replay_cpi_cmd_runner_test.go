@@ -0,0 +1,82 @@
+package cloud_test
+
+import (
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cloud"
+)
+
+var _ = Describe("ReplayCPICmdRunner", func() {
+	var (
+		fs      *fakesys.FakeFileSystem
+		runner  CPICmdRunner
+		context CmdContext
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		runner = NewReplayCPICmdRunner("/fake-recording.json", fs, boshlog.NewLogger(boshlog.LevelNone))
+		context = CmdContext{DirectorID: "fake-director-id"}
+	})
+
+	Context("when recording exists", func() {
+		BeforeEach(func() {
+			err := fs.WriteFileString("/fake-recording.json", `{"request":{"method":"info","arguments":[]},"response":{"result":{"stemcell_formats":["fake-format"]},"log":""}}
+
+{"request":{"method":"create_vm","arguments":["fake-agent-id"]},"response":{"result":null,"error":{"type":"Bosh::Clouds::VMCreationFailed","message":"fake-message","ok_to_retry":true},"log":""}}
+{"request":{"method":"delete_vm","arguments":["fake-vm-cid"]},"response":{"result":null,"log":""},"error":"fake-exec-err"}
+`)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("answers calls in recorded order", func() {
+			output, err := runner.Run(context, "info", 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.Result).To(Equal(map[string]interface{}{"stemcell_formats": []interface{}{"fake-format"}}))
+
+			output, err = runner.Run(context, "create_vm", 2, "fake-agent-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.Error).To(Equal(&CmdError{Type: "Bosh::Clouds::VMCreationFailed", Message: "fake-message", OkToRetry: true}))
+
+			_, err = runner.Run(context, "delete_vm", 2, "fake-vm-cid")
+			Expect(err).To(MatchError("fake-exec-err"))
+		})
+
+		It("returns an error when calls are made in different order", func() {
+			_, err := runner.Run(context, "create_vm", 2)
+			Expect(err).To(MatchError(
+				"Replaying CPI 'create_vm' call: expected call 1 to be 'info' according to recording '/fake-recording.json'"))
+		})
+
+		It("returns an error when recording is exhausted", func() {
+			for _, method := range []string{"info", "create_vm"} {
+				_, err := runner.Run(context, method, 2)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			_, err := runner.Run(context, "delete_vm", 2)
+			Expect(err).To(HaveOccurred())
+
+			_, err = runner.Run(context, "info", 2)
+			Expect(err).To(MatchError(
+				"Replaying CPI 'info' call: recording '/fake-recording.json' only contains 3 calls"))
+		})
+	})
+
+	It("returns an error when recording cannot be read", func() {
+		_, err := runner.Run(context, "info", 1)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Reading CPI recording '/fake-recording.json'"))
+	})
+
+	It("returns an error when recording is malformed", func() {
+		Expect(fs.WriteFileString("/fake-recording.json", "{")).To(Succeed())
+
+		_, err := runner.Run(context, "info", 1)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling CPI recording '/fake-recording.json' line 1"))
+	})
+})
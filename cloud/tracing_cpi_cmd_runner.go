@@ -0,0 +1,38 @@
+package cloud
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
+)
+
+type tracingCPICmdRunner struct {
+	runner CPICmdRunner
+	tracer tracing.Tracer
+}
+
+// NewTracingCPICmdRunner records a span for each external CPI method call
+func NewTracingCPICmdRunner(runner CPICmdRunner, tracer tracing.Tracer) CPICmdRunner {
+	return tracingCPICmdRunner{runner: runner, tracer: tracer}
+}
+
+func (r tracingCPICmdRunner) Run(context CmdContext, method string, apiVersion int, args ...interface{}) (CmdOutput, error) {
+	span := r.tracer.Start("cpi "+method,
+		tracing.String("cpi.method", method),
+		tracing.Int("cpi.api_version", apiVersion),
+	)
+
+	output, err := r.runner.Run(context, method, apiVersion, args...)
+
+	if err == nil && output.Error != nil {
+		span.SetAttributes(
+			tracing.String("cpi.error.type", output.Error.Type),
+			tracing.Bool("cpi.error.ok_to_retry", output.Error.OkToRetry),
+		)
+		span.End(fmt.Errorf("%s: %s", output.Error.Type, output.Error.Message))
+	} else {
+		span.End(err)
+	}
+
+	return output, err
+}
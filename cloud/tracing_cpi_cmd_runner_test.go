@@ -0,0 +1,84 @@
+package cloud_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cloud"
+	fakebicloud "github.com/cloudfoundry/bosh-cli/v7/cloud/fakes"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing/tracingfakes"
+)
+
+var _ = Describe("TracingCPICmdRunner", func() {
+	var (
+		fakeCPICmdRunner *fakebicloud.FakeCPICmdRunner
+		tracer           *tracingfakes.FakeTracer
+		span             *tracingfakes.FakeSpan
+		runner           CPICmdRunner
+		context          CmdContext
+	)
+
+	BeforeEach(func() {
+		fakeCPICmdRunner = fakebicloud.NewFakeCPICmdRunner()
+		tracer = &tracingfakes.FakeTracer{}
+		span = &tracingfakes.FakeSpan{}
+		tracer.StartReturns(span)
+		runner = NewTracingCPICmdRunner(fakeCPICmdRunner, tracer)
+		context = CmdContext{DirectorID: "fake-director-id"}
+	})
+
+	It("records a span for CPI method call", func() {
+		fakeCPICmdRunner.CurrentRunCmdOutput = CmdOutput{Result: "fake-vm-cid"}
+
+		output, err := runner.Run(context, "create_vm", 2, "fake-arg")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(output).To(Equal(CmdOutput{Result: "fake-vm-cid"}))
+
+		Expect(fakeCPICmdRunner.CurrentRunInput).To(Equal([]fakebicloud.RunInput{{
+			Context:    context,
+			Method:     "create_vm",
+			Arguments:  []interface{}{"fake-arg"},
+			ApiVersion: 2,
+		}}))
+
+		Expect(tracer.StartCallCount()).To(Equal(1))
+		name, attrs := tracer.StartArgsForCall(0)
+		Expect(name).To(Equal("cpi create_vm"))
+		Expect(attrs).To(Equal([]tracing.Attribute{
+			tracing.String("cpi.method", "create_vm"),
+			tracing.Int("cpi.api_version", 2),
+		}))
+
+		Expect(span.EndCallCount()).To(Equal(1))
+		Expect(span.EndArgsForCall(0)).ToNot(HaveOccurred())
+	})
+
+	It("records CPI error type on the span", func() {
+		fakeCPICmdRunner.CurrentRunCmdOutput = CmdOutput{
+			Error: &CmdError{Type: "Bosh::Clouds::VMCreationFailed", Message: "fake-msg", OkToRetry: true},
+		}
+
+		_, err := runner.Run(context, "create_vm", 2)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(span.SetAttributesCallCount()).To(Equal(1))
+		Expect(span.SetAttributesArgsForCall(0)).To(Equal([]tracing.Attribute{
+			tracing.String("cpi.error.type", "Bosh::Clouds::VMCreationFailed"),
+			tracing.Bool("cpi.error.ok_to_retry", true),
+		}))
+
+		Expect(span.EndArgsForCall(0)).To(MatchError("Bosh::Clouds::VMCreationFailed: fake-msg"))
+	})
+
+	It("records execution errors on the span", func() {
+		fakeCPICmdRunner.CurrentRunError = errors.New("fake-err")
+
+		_, err := runner.Run(context, "delete_vm", 1)
+		Expect(err).To(MatchError("fake-err"))
+
+		Expect(span.EndArgsForCall(0)).To(MatchError("fake-err"))
+	})
+})
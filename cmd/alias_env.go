@@ -44,7 +44,7 @@ func (c AliasEnvCmd) Run(opts AliasEnvOpts) error {
 		return err
 	}
 
-	InfoTable{info, c.ui}.Print()
+	InfoTable{Info: info, UI: c.ui}.Print()
 
 	return nil
 }
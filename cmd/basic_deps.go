@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"io"
+
 	"code.cloudfoundry.org/clock"
 	boshcrypto "github.com/cloudfoundry/bosh-utils/crypto"
 	boshcmd "github.com/cloudfoundry/bosh-utils/fileutil"
@@ -24,6 +26,9 @@ type BasicDeps struct {
 	DigestCreationAlgorithms []boshcrypto.Algorithm
 
 	Time clock.Clock
+
+	// Set by Cmd for the duration of a command when --trace-http is given
+	HTTPTraceWriter io.Writer
 }
 
 func NewBasicDeps(ui *boshui.ConfUI, logger boshlog.Logger) BasicDeps {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -11,19 +12,30 @@ import (
 	cmdconf "github.com/cloudfoundry/bosh-cli/v7/cmd/config"
 	"github.com/cloudfoundry/bosh-cli/v7/crypto"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshpropcheck "github.com/cloudfoundry/bosh-cli/v7/director/propcheck"
 	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	bitarball "github.com/cloudfoundry/bosh-cli/v7/installation/tarball"
 	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshjob "github.com/cloudfoundry/bosh-cli/v7/release/job"
+	boshsbom "github.com/cloudfoundry/bosh-cli/v7/release/sbom"
 	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+	boshlint "github.com/cloudfoundry/bosh-cli/v7/releasedir/lint"
 	boshssh "github.com/cloudfoundry/bosh-cli/v7/ssh"
 	bistemcell "github.com/cloudfoundry/bosh-cli/v7/stemcell"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
 	boshuit "github.com/cloudfoundry/bosh-cli/v7/ui/task"
+	boshtop "github.com/cloudfoundry/bosh-cli/v7/ui/top"
 
 	boshcrypto "github.com/cloudfoundry/bosh-utils/crypto"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshfu "github.com/cloudfoundry/bosh-utils/fileutil"
+	"github.com/cloudfoundry/bosh-utils/httpclient"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
 	"github.com/cloudfoundry/bosh-cli/v7/pcap"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
 	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
 )
 
@@ -61,34 +73,55 @@ func (c Cmd) Execute() (cmdErr error) {
 	c.configureUI()
 	c.configureFS()
 
+	traceFile, err := c.openHTTPTrace()
+	if err != nil {
+		return err
+	}
+
+	if traceFile != nil {
+		defer traceFile.Close()
+		c.deps.HTTPTraceWriter = traceFile
+	}
+
 	deps := c.deps
 
 	switch opts := c.Opts.(type) {
 	case *EnvironmentOpts:
-		return NewEnvironmentCmd(deps.UI, c.director()).Run(*opts)
+		credsFunc := func() cmdconf.Creds { return c.session().Credentials() }
+		return NewEnvironmentCmd(deps.UI, c.director(), credsFunc).Run(*opts)
 
 	case *EnvironmentsOpts:
 		return NewEnvironmentsCmd(c.config(), deps.UI).Run()
 
 	case *CreateEnvOpts:
+		tracer := c.tracer(opts.OTLPTraces)
+
 		envProvider := func(manifestPath string, statePath string, vars boshtpl.Variables, op patch.Op) DeploymentPreparer {
-			return NewEnvFactory(deps, manifestPath, statePath, vars, op, opts.RecreatePersistentDisks, opts.PackageDir).Preparer()
+			return NewEnvFactory(deps, manifestPath, statePath, vars, op, opts.RecreatePersistentDisks, opts.DryRun, opts.PackageDir, tracer).Preparer()
 		}
 
-		stage := boshui.NewStage(deps.UI, deps.Time, deps.Logger)
-		return NewCreateEnvCmd(deps.UI, envProvider).Run(stage, *opts)
+		stage := boshui.NewTracingStage(boshui.NewStage(deps.UI, deps.Time, deps.Logger), tracer)
+
+		return c.traced(tracer, "create-env", func() error {
+			return NewCreateEnvCmd(deps.UI, envProvider).Run(stage, *opts)
+		})
 
 	case *DeleteEnvOpts:
+		tracer := c.tracer(opts.OTLPTraces)
+
 		envProvider := func(manifestPath string, statePath string, vars boshtpl.Variables, op patch.Op) DeploymentDeleter {
-			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false, opts.PackageDir).Deleter()
+			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false, false, opts.PackageDir, tracer).Deleter()
 		}
 
-		stage := boshui.NewStage(deps.UI, deps.Time, deps.Logger)
-		return NewDeleteEnvCmd(deps.UI, envProvider).Run(stage, *opts)
+		stage := boshui.NewTracingStage(boshui.NewStage(deps.UI, deps.Time, deps.Logger), tracer)
+
+		return c.traced(tracer, "delete-env", func() error {
+			return NewDeleteEnvCmd(deps.UI, envProvider).Run(stage, *opts)
+		})
 
 	case *StopEnvOpts:
 		envProvider := func(manifestPath string, statePath string, vars boshtpl.Variables, op patch.Op) DeploymentStateManager {
-			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false, "").StateManager()
+			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false, false, "", tracing.NewNoopTracer()).StateManager()
 		}
 
 		stage := boshui.NewStage(deps.UI, deps.Time, deps.Logger)
@@ -96,7 +129,7 @@ func (c Cmd) Execute() (cmdErr error) {
 
 	case *StartEnvOpts:
 		envProvider := func(manifestPath string, statePath string, vars boshtpl.Variables, op patch.Op) DeploymentStateManager {
-			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false, "").StateManager()
+			return NewEnvFactory(deps, manifestPath, statePath, vars, op, false, false, "", tracing.NewNoopTracer()).StateManager()
 		}
 
 		stage := boshui.NewStage(deps.UI, deps.Time, deps.Logger)
@@ -104,7 +137,7 @@ func (c Cmd) Execute() (cmdErr error) {
 
 	case *AliasEnvOpts:
 		sessionFactory := func(config cmdconf.Config) Session {
-			return NewSessionFromOpts(c.BoshOpts, config, deps.UI, true, false, deps.FS, deps.Logger)
+			return NewSessionFromOpts(c.BoshOpts, config, deps.UI, true, false, deps.FS, deps.HTTPTraceWriter, deps.Logger)
 		}
 
 		return NewAliasEnvCmd(sessionFactory, c.config(), deps.UI).Run(*opts)
@@ -114,14 +147,14 @@ func (c Cmd) Execute() (cmdErr error) {
 
 	case *LogInOpts:
 		sessionFactory := func(config cmdconf.Config) Session {
-			return NewSessionFromOpts(c.BoshOpts, config, deps.UI, true, true, deps.FS, deps.Logger)
+			return NewSessionFromOpts(c.BoshOpts, config, deps.UI, true, true, deps.FS, deps.HTTPTraceWriter, deps.Logger)
 		}
 
 		config := c.config()
 		basicStrategy := NewBasicLoginStrategy(sessionFactory, config, deps.UI)
 		uaaStrategy := NewUAALoginStrategy(sessionFactory, config, deps.UI, deps.Logger)
 
-		sess := NewSessionFromOpts(c.BoshOpts, c.config(), deps.UI, true, true, deps.FS, deps.Logger)
+		sess := NewSessionFromOpts(c.BoshOpts, c.config(), deps.UI, true, true, deps.FS, deps.HTTPTraceWriter, deps.Logger)
 
 		anonDirector, err := sess.AnonymousDirector()
 		if err != nil {
@@ -132,7 +165,7 @@ func (c Cmd) Execute() (cmdErr error) {
 
 	case *LogOutOpts:
 		config := c.config()
-		sess := NewSessionFromOpts(c.BoshOpts, config, deps.UI, true, true, deps.FS, deps.Logger)
+		sess := NewSessionFromOpts(c.BoshOpts, config, deps.UI, true, true, deps.FS, deps.HTTPTraceWriter, deps.Logger)
 		return NewLogOutCmd(sess.Environment(), config, deps.UI).Run()
 
 	case *TaskOpts:
@@ -150,7 +183,7 @@ func (c Cmd) Execute() (cmdErr error) {
 		return NewCancelTasksCmd(c.director()).Run(*opts)
 
 	case *DeploymentOpts:
-		sess := NewSessionFromOpts(c.BoshOpts, c.config(), deps.UI, true, false, deps.FS, deps.Logger)
+		sess := NewSessionFromOpts(c.BoshOpts, c.config(), deps.UI, true, false, deps.FS, deps.HTTPTraceWriter, deps.Logger)
 		return NewDeploymentCmd(sess, c.config(), deps.UI).Run()
 
 	case *DeploymentsOpts:
@@ -163,7 +196,7 @@ func (c Cmd) Execute() (cmdErr error) {
 		return NewReleasesCmd(deps.UI, c.director()).Run()
 
 	case *UploadReleaseOpts:
-		relProv, relDirProv := c.releaseProviders()
+		_, relDirProv := c.releaseProviders()
 
 		releaseDirFactory := func(dir DirOrCWDArg) (boshrel.Reader, boshreldir.ReleaseDir) {
 			releaseReader := relDirProv.NewReleaseReader(dir.Path, c.BoshOpts.Parallel)
@@ -171,7 +204,7 @@ func (c Cmd) Execute() (cmdErr error) {
 			return releaseReader, releaseDir
 		}
 
-		releaseWriter := relProv.NewArchiveWriter()
+		releaseWriter := relDirProv.NewArchiveWriter(opts.Directory.Path)
 
 		releaseArchiveFactory := func(path string) boshdir.ReleaseArchive {
 			return boshdir.NewFSReleaseArchive(path, deps.FS)
@@ -182,6 +215,7 @@ func (c Cmd) Execute() (cmdErr error) {
 			releaseWriter,
 			c.director(),
 			releaseArchiveFactory,
+			crypto.NewArtifactSigner(deps.FS),
 			deps.CmdRunner,
 			deps.FS,
 			deps.UI,
@@ -200,7 +234,7 @@ func (c Cmd) Execute() (cmdErr error) {
 			return boshdir.NewFSStemcellArchive(path, deps.FS)
 		}
 
-		return NewUploadStemcellCmd(c.director(), stemcellArchiveFactory, deps.UI).Run(*opts)
+		return NewUploadStemcellCmd(c.director(), stemcellArchiveFactory, crypto.NewArtifactSigner(deps.FS), deps.FS, deps.UI).Run(*opts)
 
 	case *DeleteStemcellOpts:
 		return NewDeleteStemcellCmd(deps.UI, c.director()).Run(*opts)
@@ -211,6 +245,18 @@ func (c Cmd) Execute() (cmdErr error) {
 
 		return NewRepackStemcellCmd(stemcellExtractor).Run(*opts)
 
+	case *StemcellFilesOpts:
+		return NewStemcellFilesCmd(c.stemcellInspectArchive, deps.UI).Run(*opts)
+
+	case *StemcellPackagesOpts:
+		return NewStemcellPackagesCmd(c.stemcellInspectArchive, deps.UI).Run(*opts)
+
+	case *DiffStemcellsOpts:
+		return NewDiffStemcellsCmd(c.stemcellInspectArchive, deps.UI).Run(*opts)
+
+	case *ValidateStemcellOpts:
+		return NewValidateStemcellCmd(c.stemcellInspectArchive, deps.UI).Run(*opts)
+
 	case *InspectStemcellTarballOpts:
 		stemcellArchiveFactory := func(path string) boshdir.StemcellArchive {
 			return boshdir.NewFSStemcellArchive(path, deps.FS)
@@ -263,7 +309,20 @@ func (c Cmd) Execute() (cmdErr error) {
 		return NewDeleteVMCmd(deps.UI, c.deployment()).Run(*opts)
 
 	case *InterpolateOpts:
-		return NewInterpolateCmd(deps.UI).Run(*opts)
+		return NewInterpolateCmd(deps.UI, c.propsValidator(), deps.FS).Run(*opts)
+
+	case *ManifestSchemaOpts:
+		return NewManifestSchemaCmd(deps.UI).Run(*opts)
+
+	case *DiffManifestsOpts:
+		return NewDiffManifestsCmd(deps.UI).Run(*opts)
+
+	case *GenerateOpsOpts:
+		return NewGenerateOpsCmd(deps.UI).Run(*opts)
+
+	case *VendorArtifactsOpts:
+		stage := boshui.NewStage(deps.UI, deps.Time, deps.Logger)
+		return NewVendorArtifactsCmd(c.tarballProvider(), stage, deps.FS, deps.UI).Run(*opts)
 
 	case *ConfigOpts:
 		return NewConfigCmd(deps.UI, c.director()).Run(*opts)
@@ -303,8 +362,11 @@ func (c Cmd) Execute() (cmdErr error) {
 	case *ManifestOpts:
 		return NewManifestCmd(deps.UI, c.deployment()).Run()
 
+	case *HistoryOpts:
+		return NewHistoryCmd(deps.UI, c.director()).Run(*opts)
+
 	case *EventsOpts:
-		return NewEventsCmd(deps.UI, c.director()).Run(*opts)
+		return NewEventsCmd(deps.UI, c.director(), deps.Time).Run(*opts)
 
 	case *EventOpts:
 		return NewEventCmd(deps.UI, c.director()).Run(*opts)
@@ -320,6 +382,39 @@ func (c Cmd) Execute() (cmdErr error) {
 			deps.UI,
 		).Run(*opts)
 
+	case *DiffReleasesOpts:
+		relProv, _ := c.releaseProviders()
+
+		releaseReaderFactory := func(path string) boshrel.Reader {
+			return relProv.NewMultiReader(path)
+		}
+
+		return NewDiffReleasesCmd(
+			releaseReaderFactory,
+			c.releaseDir,
+			boshjob.NewArchiveReaderImpl(true, deps.Compressor, deps.FS),
+			deps.FS,
+			deps.UI,
+		).Run(*opts)
+
+	case *SignOpts:
+		return NewSignCmd(crypto.NewArtifactSigner(deps.FS), deps.FS, deps.UI).Run(*opts)
+
+	case *ReleaseSBOMOpts:
+		relProv, _ := c.releaseProviders()
+
+		releaseReaderFactory := func(path string) boshrel.Reader {
+			return relProv.NewMultiReader(path)
+		}
+
+		return NewReleaseSBOMCmd(
+			releaseReaderFactory,
+			c.releaseDir,
+			c.sbomGenerator(),
+			deps.FS,
+			deps.UI,
+		).Run(*opts)
+
 	case *VMsOpts:
 		return NewVMsCmd(deps.UI, c.director(), c.BoshOpts.Parallel).Run(*opts)
 
@@ -341,7 +436,7 @@ func (c Cmd) Execute() (cmdErr error) {
 	case *DeployOpts:
 		director, deployment := c.directorAndDeployment()
 		releaseManager := c.releaseManager(director)
-		return NewDeployCmd(deps.UI, deployment, releaseManager, director).Run(*opts)
+		return NewDeployCmd(deps.UI, deployment, releaseManager, director, c.propsValidator()).Run(*opts)
 
 	case *StartOpts:
 		return NewStartCmd(deps.UI, c.deployment()).Run(*opts)
@@ -362,7 +457,20 @@ func (c Cmd) Execute() (cmdErr error) {
 		return NewCreateRecoveryPlanCmd(c.deployment(), deps.UI, deps.FS).Run(*opts)
 
 	case *RecoverOpts:
-		return NewRecoverCmd(c.deployment(), deps.UI, deps.FS).Run(*opts)
+		if !IsAutoRecovery(*opts) {
+			return NewRecoverCmd(c.deployment(), deps.UI, deps.FS).Run(*opts)
+		}
+
+		var deployments func() ([]boshdir.Deployment, error)
+
+		if opts.AllDeployments {
+			deployments = c.director().Deployments
+		} else {
+			deployment := c.deployment()
+			deployments = func() ([]boshdir.Deployment, error) { return []boshdir.Deployment{deployment}, nil }
+		}
+
+		return NewAutoRecoverCmd(deployments, deps.UI, deps.FS, deps.Time).Run(*opts)
 
 	case *CleanUpOpts:
 		return NewCleanUpCmd(deps.UI, c.director()).Run(*opts)
@@ -398,6 +506,26 @@ func (c Cmd) Execute() (cmdErr error) {
 			return NewSSHCmd(intSSHRunner, nonIntSSHRunner, resultsSSHRunner, deps.UI, sshHostBuilder).Run(*opts, c.getDeployment)
 		}
 
+	case *ExportMetricsOpts:
+		return NewExportMetricsCmd(deps.UI, c.director(), deps.Time, deps.Logger).Run(*opts)
+
+	case *TopOpts:
+		director := c.director()
+
+		screen, err := boshtop.NewTerminalScreen(os.Stdout)
+		if err != nil {
+			return err
+		}
+
+		sshProvider := boshssh.NewProvider(deps.CmdRunner, deps.FS, deps.UI, deps.Logger)
+		actions := NewTopActions(
+			director, deps.UI, opts.GatewayFlags,
+			sshProvider.NewSSHRunner(true), sshProvider.NewSSHRunner(false), sshProvider.NewResultsSSHRunner(false),
+			boshssh.NewHostBuilder(),
+		)
+
+		return NewTopCmd(director, screen, actions, deps.Time, deps.Logger).Run(*opts)
+
 	case *SCPOpts:
 		sshProvider := boshssh.NewProvider(deps.CmdRunner, deps.FS, deps.UI, deps.Logger)
 		scpRunner := sshProvider.NewSCPRunner()
@@ -430,6 +558,20 @@ func (c Cmd) Execute() (cmdErr error) {
 	case *VendorPackageOpts:
 		return NewVendorPackageCmd(c.releaseDir, deps.UI).Run(*opts)
 
+	case *LintReleaseOpts:
+		relProv, relDirProv := c.releaseProviders()
+
+		linterFactory := func(dir DirOrCWDArg) boshlint.Linter {
+			return boshlint.NewFSLinter(
+				dir.Path,
+				relProv.NewJobDirReader(dir.Path),
+				relDirProv.NewFSBlobsDir(dir.Path),
+				deps.FS,
+			)
+		}
+
+		return NewLintReleaseCmd(linterFactory, deps.UI).Run(*opts)
+
 	case *FinalizeReleaseOpts:
 		_, relDirProv := c.releaseProviders()
 		releaseReader := relDirProv.NewReleaseReader(opts.Directory.Path, c.BoshOpts.Parallel)
@@ -437,7 +579,7 @@ func (c Cmd) Execute() (cmdErr error) {
 		return NewFinalizeReleaseCmd(releaseReader, releaseDir, deps.UI).Run(*opts)
 
 	case *CreateReleaseOpts:
-		relProv, relDirProv := c.releaseProviders()
+		_, relDirProv := c.releaseProviders()
 
 		releaseDirFactory := func(dir DirOrCWDArg) (boshrel.Reader, boshreldir.ReleaseDir) {
 			releaseReader := relDirProv.NewReleaseReader(dir.Path, c.BoshOpts.Parallel)
@@ -447,7 +589,9 @@ func (c Cmd) Execute() (cmdErr error) {
 
 		_, err := NewCreateReleaseCmd(
 			releaseDirFactory,
-			relProv.NewArchiveWriter(),
+			relDirProv.NewArchiveWriter(opts.Directory.Path),
+			c.sbomGenerator(),
+			crypto.NewArtifactSigner(c.deps.FS),
 			c.deps.FS,
 			c.deps.UI,
 		).Run(*opts)
@@ -539,6 +683,23 @@ func (c Cmd) configureFS() {
 	c.panicIfErr(err)
 }
 
+// openHTTPTrace opens the --trace-http file once so that all director
+// clients created while running a command append to the same file
+func (c Cmd) openHTTPTrace() (boshsys.File, error) {
+	if len(c.BoshOpts.TraceHTTPOpt) == 0 {
+		return nil, nil
+	}
+
+	path := NewSessionContextImpl(c.BoshOpts, c.config(), c.deps.FS).HTTPTracePath()
+
+	file, err := c.deps.FS.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Opening HTTP trace file '%s'", path)
+	}
+
+	return file, nil
+}
+
 func (c Cmd) config() cmdconf.Config {
 	config, err := cmdconf.NewFSConfigFromPath(c.BoshOpts.ConfigPathOpt, c.deps.FS)
 	c.panicIfErr(err)
@@ -547,7 +708,7 @@ func (c Cmd) config() cmdconf.Config {
 }
 
 func (c Cmd) session() Session {
-	return NewSessionFromOpts(c.BoshOpts, c.config(), c.deps.UI, true, true, c.deps.FS, c.deps.Logger)
+	return NewSessionFromOpts(c.BoshOpts, c.config(), c.deps.UI, true, true, c.deps.FS, c.deps.HTTPTraceWriter, c.deps.Logger)
 }
 
 func (c Cmd) director() boshdir.Director {
@@ -576,6 +737,25 @@ func (c Cmd) directorAndDeployment() (boshdir.Director, boshdir.Deployment) {
 	return director, deployment
 }
 
+func (c Cmd) stemcellInspectArchive(path string) boshinsp.Archive {
+	return boshinsp.NewFSArchive(path, c.deps.FS)
+}
+
+func (c Cmd) tarballCache() bitarball.Cache {
+	return bitarball.NewCache(filepath.Join(os.Getenv("HOME"), ".bosh", "downloads"), c.deps.FS, c.deps.Logger)
+}
+
+func (c Cmd) tarballProvider() bitarball.Provider {
+	tarballCache := c.tarballCache()
+	httpClient := httpclient.NewHTTPClient(httpclient.CreateExternalDefaultClient(nil), c.deps.Logger)
+
+	return bitarball.NewProvider(tarballCache, c.deps.FS, httpClient, 3, 500*time.Millisecond, c.deps.Logger)
+}
+
+func (c Cmd) propsValidator() boshpropcheck.Validator {
+	return boshpropcheck.NewManifestValidator(boshpropcheck.NewFSSpecReader(c.tarballCache(), c.deps.FS))
+}
+
 func (c Cmd) releaseProviders() (boshrel.Provider, boshreldir.Provider) {
 	indexReporter := boshui.NewIndexReporter(c.deps.UI)
 	blobsReporter := boshui.NewBlobsReporter(c.deps.UI)
@@ -586,7 +766,7 @@ func (c Cmd) releaseProviders() (boshrel.Provider, boshreldir.Provider) {
 
 	releaseDirProvider := boshreldir.NewProvider(
 		indexReporter, releaseIndexReporter, blobsReporter, releaseProvider,
-		c.deps.DigestCalculator, c.deps.CmdRunner, c.deps.UUIDGen, c.deps.Time, c.deps.FS, c.deps.DigestCreationAlgorithms, c.deps.Logger)
+		c.deps.DigestCalculator, c.deps.UUIDGen, c.deps.Time, c.deps.FS, c.deps.DigestCreationAlgorithms, c.deps.Logger)
 
 	return releaseProvider, releaseDirProvider
 }
@@ -605,6 +785,8 @@ func (c Cmd) releaseManager(director boshdir.Director) ReleaseManager {
 	createReleaseCmd := NewCreateReleaseCmd(
 		releaseDirFactory,
 		releaseWriter,
+		c.sbomGenerator(),
+		crypto.NewArtifactSigner(c.deps.FS),
 		c.deps.FS,
 		c.deps.UI,
 	)
@@ -618,6 +800,7 @@ func (c Cmd) releaseManager(director boshdir.Director) ReleaseManager {
 		releaseWriter,
 		director,
 		releaseArchiveFactory,
+		crypto.NewArtifactSigner(c.deps.FS),
 		c.deps.CmdRunner,
 		c.deps.FS,
 		c.deps.UI,
@@ -631,11 +814,46 @@ func (c Cmd) blobsDir(dir DirOrCWDArg) boshreldir.BlobsDir {
 	return relDirProv.NewFSBlobsDir(dir.Path)
 }
 
+func (c Cmd) sbomGenerator() boshsbom.Generator {
+	_, relDirProv := c.releaseProviders()
+
+	blobsDirFactory := func(dirPath string) boshreldir.BlobsDir {
+		return relDirProv.NewFSBlobsDir(dirPath)
+	}
+
+	return boshsbom.NewFSGenerator(blobsDirFactory, c.deps.Compressor, c.deps.Time, c.deps.FS)
+}
+
 func (c Cmd) releaseDir(dir DirOrCWDArg) boshreldir.ReleaseDir {
 	_, relDirProv := c.releaseProviders()
 	return relDirProv.NewFSReleaseDir(dir.Path, c.BoshOpts.Parallel)
 }
 
+func (c Cmd) tracer(destination string) tracing.Tracer {
+	if len(destination) == 0 {
+		return tracing.NewNoopTracer()
+	}
+
+	exporter, err := tracing.NewExporter(destination, c.deps.FS, httpclient.CreateExternalDefaultClient(nil))
+	c.panicIfErr(err)
+
+	return tracing.NewTracer(exporter, c.deps.Time)
+}
+
+// traced exports spans even if command fails since
+// that is typically when traces are most useful
+func (c Cmd) traced(tracer tracing.Tracer, name string, f func() error) error {
+	span := tracer.Start(name)
+	err := f()
+	span.End(err)
+
+	if exportErr := tracer.Shutdown(); exportErr != nil {
+		c.deps.UI.ErrorLinef("Failed to export traces: %s", exportErr)
+	}
+
+	return err
+}
+
 func (c Cmd) panicIfErr(err error) {
 	if err != nil {
 		panic(cmdConveniencePanic{err})
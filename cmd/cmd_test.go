@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	"errors"
+	"os"
 
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
@@ -97,6 +98,31 @@ var _ = Describe("Cmd", func() {
 			})
 		})
 
+		Describe("HTTP trace", func() {
+			BeforeEach(func() {
+				boshCmd.BoshOpts = opts.BoshOpts{TraceHTTPOpt: "/trace.ndjson"}
+				boshCmd.Opts = &opts.InterpolateOpts{}
+			})
+
+			It("opens trace file for appending and closes it once command finishes", func() {
+				err := boshCmd.Execute()
+				Expect(err).ToNot(HaveOccurred())
+
+				stats := fs.GetFileTestStat("/trace.ndjson")
+				Expect(stats).ToNot(BeNil())
+				Expect(stats.Flags).To(Equal(os.O_APPEND | os.O_CREATE | os.O_WRONLY))
+				Expect(stats.Open).To(BeFalse())
+			})
+
+			It("returns error if trace file cannot be opened", func() {
+				fs.OpenFileErr = errors.New("fake-err")
+
+				err := boshCmd.Execute()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Opening HTTP trace file '/trace.ndjson': fake-err"))
+			})
+		})
+
 		It("returns error if changing tmp root fails", func() {
 			fs.ChangeTempRootErr = errors.New("fake-err")
 
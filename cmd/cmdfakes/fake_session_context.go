@@ -6,6 +6,7 @@ import (
 
 	"github.com/cloudfoundry/bosh-cli/v7/cmd"
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/config"
+	"github.com/cloudfoundry/bosh-cli/v7/director"
 )
 
 type FakeSessionContext struct {
@@ -59,6 +60,16 @@ type FakeSessionContext struct {
 	environmentReturnsOnCall map[int]struct {
 		result1 string
 	}
+	RetryPolicyStub        func() director.RetryPolicy
+	retryPolicyMutex       sync.RWMutex
+	retryPolicyArgsForCall []struct {
+	}
+	retryPolicyReturns struct {
+		result1 director.RetryPolicy
+	}
+	retryPolicyReturnsOnCall map[int]struct {
+		result1 director.RetryPolicy
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -328,6 +339,59 @@ func (fake *FakeSessionContext) EnvironmentReturnsOnCall(i int, result1 string)
 	}{result1}
 }
 
+func (fake *FakeSessionContext) RetryPolicy() director.RetryPolicy {
+	fake.retryPolicyMutex.Lock()
+	ret, specificReturn := fake.retryPolicyReturnsOnCall[len(fake.retryPolicyArgsForCall)]
+	fake.retryPolicyArgsForCall = append(fake.retryPolicyArgsForCall, struct {
+	}{})
+	stub := fake.RetryPolicyStub
+	fakeReturns := fake.retryPolicyReturns
+	fake.recordInvocation("RetryPolicy", []interface{}{})
+	fake.retryPolicyMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeSessionContext) RetryPolicyCallCount() int {
+	fake.retryPolicyMutex.RLock()
+	defer fake.retryPolicyMutex.RUnlock()
+	return len(fake.retryPolicyArgsForCall)
+}
+
+func (fake *FakeSessionContext) RetryPolicyCalls(stub func() director.RetryPolicy) {
+	fake.retryPolicyMutex.Lock()
+	defer fake.retryPolicyMutex.Unlock()
+	fake.RetryPolicyStub = stub
+}
+
+func (fake *FakeSessionContext) RetryPolicyReturns(result1 director.RetryPolicy) {
+	fake.retryPolicyMutex.Lock()
+	defer fake.retryPolicyMutex.Unlock()
+	fake.RetryPolicyStub = nil
+	fake.retryPolicyReturns = struct {
+		result1 director.RetryPolicy
+	}{result1}
+}
+
+func (fake *FakeSessionContext) RetryPolicyReturnsOnCall(i int, result1 director.RetryPolicy) {
+	fake.retryPolicyMutex.Lock()
+	defer fake.retryPolicyMutex.Unlock()
+	fake.RetryPolicyStub = nil
+	if fake.retryPolicyReturnsOnCall == nil {
+		fake.retryPolicyReturnsOnCall = make(map[int]struct {
+			result1 director.RetryPolicy
+		})
+	}
+	fake.retryPolicyReturnsOnCall[i] = struct {
+		result1 director.RetryPolicy
+	}{result1}
+}
+
 func (fake *FakeSessionContext) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -341,6 +405,8 @@ func (fake *FakeSessionContext) Invocations() map[string][][]interface{} {
 	defer fake.deploymentMutex.RUnlock()
 	fake.environmentMutex.RLock()
 	defer fake.environmentMutex.RUnlock()
+	fake.retryPolicyMutex.RLock()
+	defer fake.retryPolicyMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
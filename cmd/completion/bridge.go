@@ -23,5 +23,5 @@ func (c CmdBridge) config() cmdconf.Config {
 }
 
 func (c CmdBridge) Session() boshcmd.Session {
-	return boshcmd.NewSessionFromOpts(c.cmd.BoshOpts, c.config(), c.deps.UI, true, true, c.deps.FS, c.deps.Logger)
+	return boshcmd.NewSessionFromOpts(c.cmd.BoshOpts, c.config(), c.deps.UI, true, true, c.deps.FS, c.deps.HTTPTraceWriter, c.deps.Logger)
 }
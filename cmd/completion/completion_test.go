@@ -152,12 +152,16 @@ var globalFlags = []string{
 	"-e\tDirector environment name or URL, env: BOSH_ENVIRONMENT",
 	"--help\thelp for bosh",
 	"-h\thelp for bosh",
+	"--http-max-attempts\tMax number of attempts for idempotent Director requests, env: BOSH_HTTP_MAX_ATTEMPTS",
+	"--http-retry-delay\tInitial delay between Director request attempts, doubled for each retry (e.g. 500ms), env: BOSH_HTTP_RETRY_DELAY",
+	"--http-timeout\tTimeout waiting for Director response per attempt (e.g. 30s), env: BOSH_HTTP_TIMEOUT",
 	"--json\tOutput as JSON",
 	"--no-color\tToggle colorized output",
 	"--non-interactive\tDon't ask for user input, env: BOSH_NON_INTERACTIVE",
 	"-n\tDon't ask for user input, env: BOSH_NON_INTERACTIVE",
 	"--parallel\tThe max number of parallel operations",
 	"--sha2\tUse SHA256 checksums, env: BOSH_SHA2",
+	"--trace-http\tWrite sanitized Director request/response timings as NDJSON to a file, env: BOSH_TRACE_HTTP",
 	"--tty\tForce TTY-like output, env: BOSH_TTY",
 	"--version\tShow CLI version",
 	"-v\tShow CLI version",
@@ -194,18 +198,24 @@ var globalCommands = []string{
 	"deployment\tShow deployment information",
 	"deployments\tList deployments",
 	"diff-config\tDiff two configs by ID or content",
+	"diff-manifests\tShow differences between two manifests",
+	"diff-releases\tShow differences between two releases",
+	"diff-stemcells\tShow package differences between two stemcells",
 	"disks\tList disks",
 	"environment\tShow environment",
 	"environments\tList environments",
 	"errands\tList errands",
 	"event\tShow event details",
 	"events\tList events",
+	"export-metrics\tServe director state as Prometheus metrics",
 	"export-release\tExport the compiled release to a tarball",
 	"finalize-release\tCreate final release from dev release tarball",
 	"generate-job\tGenerate job",
+	"generate-ops\tGenerate ops file that turns one manifest into another",
 	"generate-package\tGenerate package",
 	"help\tShow this help message",
 	"help\tHelp about any command",
+	"history\tShow deployment tasks with their actions, outcome and configs in effect",
 	"ignore\tIgnore an instance",
 	"init-release\tInitialize release",
 	"inspect-local-release\tDisplay information from release metadata",
@@ -213,16 +223,19 @@ var globalCommands = []string{
 	"inspect-release\tList release contents such as jobs",
 	"instances\tList all instances in a deployment",
 	"interpolate\tInterpolates variables into a manifest",
+	"lint-release\tCheck release directory for common authoring problems",
 	"locks\tList current locks",
 	"log-in\tLog in",
 	"log-out\tLog out",
 	"logs\tFetch logs from instance(s)",
 	"manifest\tShow deployment manifest",
+	"manifest-schema\tShow JSON schema of manifests for editor integration",
 	"networks\tList networks",
 	"orphan-disk\tOrphan disk",
 	"orphaned-vms\tList all the orphaned VMs in all deployments",
 	"recover\tApply a recovery plan for disaster repair",
 	"recreate\tRecreate instance(s)",
+	"release-sbom\tShow software bill of materials for a release",
 	"releases\tList releases",
 	"remove-blob\tRemove blob",
 	"repack-stemcell\tRepack stemcell",
@@ -233,10 +246,13 @@ var globalCommands = []string{
 	"scp\tSCP to/from instance(s)",
 	"sha1ify-release\tConvert release tarball to use SHA1",
 	"sha2ify-release\tConvert release tarball to use SHA256",
+	"sign\tCreate detached signature for a release or stemcell tarball",
 	"snapshots\tList snapshots",
 	"ssh\tSSH into instance(s)",
 	"start\tStart instance(s)",
 	"start-env\tStart BOSH environment",
+	"stemcell-files\tList files in root filesystem of stemcell image",
+	"stemcell-packages\tShow packages included in stemcell",
 	"stemcells\tList stemcells",
 	"stop\tStop instance(s)",
 	"stop-env\tStop BOSH environment",
@@ -244,6 +260,7 @@ var globalCommands = []string{
 	"take-snapshot\tTake snapshot",
 	"task\tShow task status and start tracking its output",
 	"tasks\tList running or recent tasks",
+	"top\tShow live view of deployments, instances, tasks and locks",
 	"unalias-env\tRemove an aliased environment",
 	"unignore\tUnignore an instance",
 	"update-cloud-config\tUpdate current cloud config",
@@ -254,7 +271,9 @@ var globalCommands = []string{
 	"upload-blobs\tUpload blobs",
 	"upload-release\tUpload release",
 	"upload-stemcell\tUpload stemcell",
+	"validate-stemcell\tValidate stemcell metadata and image digest",
 	"variables\tList variables",
+	"vendor-artifacts\tDownload releases and stemcells referenced by a manifest for use without internet access",
 	"vendor-package\tVendor package",
 	"vms\tList all VMs in all deployments",
 }
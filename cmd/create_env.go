@@ -22,7 +22,13 @@ func NewCreateEnvCmd(ui boshui.UI, envProvider EnvProviderFunction) *CreateEnvCm
 func (c *CreateEnvCmd) Run(stage boshui.Stage, opts CreateEnvOpts) error {
 	c.ui.BeginLinef("Deployment manifest: '%s'\n", opts.Args.Manifest.Path)
 
-	depPreparer := c.envProvider(opts.Args.Manifest.Path, opts.StatePath, opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp())
+	if opts.DryRun {
+		// Missing variables must not be generated and saved to external sources
+		depPreparer := c.envProvider(opts.Args.Manifest.Path, opts.StatePath, opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp())
+		return depPreparer.PlanDeployment(stage, opts.Recreate, opts.RecreatePersistentDisks)
+	}
+
+	depPreparer := c.envProvider(opts.Args.Manifest.Path, opts.StatePath, opts.VarFlags.AsGeneratingVariables(), opts.OpsFlags.AsOp())
 
 	return depPreparer.PrepareDeployment(stage, opts.Recreate, opts.RecreatePersistentDisks, opts.SkipDrain)
 }
@@ -56,6 +56,7 @@ import (
 	fakebistemcell "github.com/cloudfoundry/bosh-cli/v7/stemcell/stemcellfakes"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
 	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	fakevarsrc "github.com/cloudfoundry/bosh-cli/v7/varsource/varsourcefakes"
 )
 
 var _ = Describe("CreateEnvCmd", func() {
@@ -122,6 +123,10 @@ var _ = Describe("CreateEnvCmd", func() {
 
 			fakeStage *fakeui.FakeStage
 
+			dryRun bool
+
+			providedDeploymentVars boshtpl.Variables
+
 			deploymentManifestPath string
 			deploymentStatePath    string
 			cpiReleaseTarballPath  string
@@ -209,6 +214,7 @@ var _ = Describe("CreateEnvCmd", func() {
 			fakeDeploymentValidator = fakebideplval.NewFakeValidator()
 
 			fakeStage = fakeui.NewFakeStage()
+			dryRun = false
 
 			fakeUUIDGenerator = &fakeuuid.FakeGenerator{}
 
@@ -310,7 +316,12 @@ var _ = Describe("CreateEnvCmd", func() {
 
 		JustBeforeEach(func() {
 			doGet := func(deploymentManifestPath string, statePath string, deploymentVars boshtpl.Variables, deploymentOp patch.Op) cmd.DeploymentPreparer {
+				providedDeploymentVars = deploymentVars
+
 				deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fs, configUUIDGenerator, logger, biconfig.DeploymentStatePath(deploymentManifestPath, statePath))
+				if dryRun {
+					deploymentStateService = biconfig.NewDryRunDeploymentStateService(fs, configUUIDGenerator, logger, biconfig.DeploymentStatePath(deploymentManifestPath, statePath))
+				}
 				deploymentRepo := biconfig.NewDeploymentRepo(deploymentStateService)
 				releaseRepo := biconfig.NewReleaseRepo(deploymentStateService, fakeUUIDGenerator)
 				stemcellRepo := biconfig.NewStemcellRepo(deploymentStateService, fakeUUIDGenerator)
@@ -655,6 +666,116 @@ var _ = Describe("CreateEnvCmd", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		It("generates missing variables in writable variable sources", func() {
+			source := &fakevarsrc.FakeWritableSource{}
+			defaultCreateEnvOpts.VarFlags.VarsSources = []opts.VarsSourceArg{{Source: source}}
+
+			err := command.Run(fakeStage, defaultCreateEnvOpts)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, found, err := providedDeploymentVars.Get(boshtpl.VariableDefinition{Name: "password", Type: "password"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(source.SetCallCount()).To(Equal(1))
+		})
+
+		Context("when DryRun is specified", func() {
+			var expectDryRunDeploy *gomock.Call
+
+			BeforeEach(func() {
+				dryRun = true
+				defaultCreateEnvOpts.DryRun = true
+
+				mockVMManagerFactory.EXPECT().NewManager(gomock.Any(), gomock.Any()).Return(fakeVMManager).AnyTimes()
+			})
+
+			JustBeforeEach(func() {
+				expectDryRunDeploy = mockDeployer.EXPECT().Deploy(
+					gomock.AssignableToTypeOf(bicloud.NewDryRunCloud(logger)),
+					gomock.Any(),
+					cloudStemcell,
+					fakeVMManager,
+					gomock.Any(),
+					false,
+					gomock.Any(),
+					gomock.Any(),
+				).DoAndReturn(func(cloud bicloud.Cloud, deploymentManifest bideplmanifest.Manifest, _, _, _, _ interface{}, _ interface{}, stage boshui.Stage) (deployment.Deployment, error) {
+					Expect(fakeStage.SubStages).To(ContainElement(stage))
+					Expect(deploymentManifest.Name).To(Equal(boshDeploymentManifest.Name))
+					Expect(deploymentManifest.Update.UpdateWatchTime).To(Equal(bideplmanifest.WatchTime{Start: 0, End: 1000}))
+
+					cid, err := cloud.CreateStemcell("fake-image-path", biproperty.Map{"password": "fake-password"})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(cid).To(Equal("dry-run-stemcell-1"))
+
+					return nil, nil
+				}).AnyTimes()
+			})
+
+			It("installs the CPI and deploys to a dry run cloud without calling the CPI", func() {
+				expectInstall.Times(1)
+				expectNewCloud.Times(0)
+				expectDeploy.Times(0)
+				expectDryRunDeploy.Times(1)
+
+				err := command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("prints planned CPI calls with redacted cloud properties", func() {
+				err := command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stdOut).To(gbytes.Say("create_stemcell"))
+				Expect(stdOut).To(gbytes.Say(regexp.QuoteMeta(`["fake-image-path",{"password":"<redacted>"}]`)))
+				Expect(stdOut).To(gbytes.Say("dry-run-stemcell-1"))
+				Expect(stdOut).ToNot(gbytes.Say("fake-password"))
+			})
+
+			It("leaves an existing deployment state file untouched", func() {
+				err := fs.WriteFileString(deploymentStatePath, `{"current_vm_cid":"fake-vm-cid"}`)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fs.ReadFileString(deploymentStatePath)).To(Equal(`{"current_vm_cid":"fake-vm-cid"}`))
+			})
+
+			It("does not create a deployment state file or migrate the legacy one", func() {
+				expectLegacyMigrate.Times(0)
+
+				err := command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fs.FileExists(deploymentStatePath)).To(BeFalse())
+			})
+
+			It("does not generate missing variables in writable variable sources", func() {
+				source := &fakevarsrc.FakeWritableSource{}
+				defaultCreateEnvOpts.VarFlags.VarsSources = []opts.VarsSourceArg{{Source: source}}
+
+				err := command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, found, err := providedDeploymentVars.Get(boshtpl.VariableDefinition{Name: "password", Type: "password"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(BeFalse())
+
+				Expect(source.GetCallCount()).To(Equal(1))
+				Expect(source.SetCallCount()).To(Equal(0))
+			})
+
+			It("returns deploy errors", func() {
+				expectDryRunDeploy.Return(nil, errors.New("fake-deploy-error"))
+
+				err := command.Run(fakeStage, defaultCreateEnvOpts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-deploy-error"))
+			})
+		})
+
 		Context("when SkipDrain is specified", func() {
 			BeforeEach(func() {
 				expectedSkipDrain = true
@@ -38,6 +38,34 @@ func (p InstanceGroupPlan) resolutionPlan(problem boshdir.Problem) string {
 
 type RecoveryPlan struct {
 	InstanceGroupsPlan []InstanceGroupPlan `yaml:"instance_groups_plan"`
+
+	// Used by automated recovery; deployments without their own plan
+	// fall back to InstanceGroupsPlan unless all deployments are recovered
+	// since instance groups of unrelated deployments may share names
+	DeploymentsPlan []DeploymentPlan             `yaml:"deployments_plan,omitempty"`
+	RateLimits      map[string]RecoveryRateLimit `yaml:"rate_limits,omitempty"`
+}
+
+type DeploymentPlan struct {
+	Name               string              `yaml:"name"`
+	InstanceGroupsPlan []InstanceGroupPlan `yaml:"instance_groups_plan"`
+}
+
+// RecoveryRateLimit allows at most Max resolutions of a problem type per window
+type RecoveryRateLimit struct {
+	Max int    `yaml:"max"`
+	Per string `yaml:"per"`
+}
+
+// instanceGroupsPlanFor returns false when deployment is not listed in DeploymentsPlan
+func (p RecoveryPlan) instanceGroupsPlanFor(deployment string) ([]InstanceGroupPlan, bool) {
+	for _, d := range p.DeploymentsPlan {
+		if d.Name == deployment {
+			return d.InstanceGroupsPlan, true
+		}
+	}
+
+	return p.InstanceGroupsPlan, false
 }
 
 type CreateRecoveryPlanCmd struct {
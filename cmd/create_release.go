@@ -9,7 +9,9 @@ import (
 	semver "github.com/cppforlife/go-semi-semantic/version"
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	bicrypto "github.com/cloudfoundry/bosh-cli/v7/crypto"
 	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshsbom "github.com/cloudfoundry/bosh-cli/v7/release/sbom"
 	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
 )
@@ -17,6 +19,8 @@ import (
 type CreateReleaseCmd struct {
 	releaseDirFactory func(DirOrCWDArg) (boshrel.Reader, boshreldir.ReleaseDir)
 	releaseWriter     boshrel.Writer
+	sbomGenerator     boshsbom.Generator
+	signer            bicrypto.ArtifactSigner
 	fs                boshsys.FileSystem
 	ui                boshui.UI
 }
@@ -24,13 +28,19 @@ type CreateReleaseCmd struct {
 func NewCreateReleaseCmd(
 	releaseDirFactory func(DirOrCWDArg) (boshrel.Reader, boshreldir.ReleaseDir),
 	releaseWriter boshrel.Writer,
+	sbomGenerator boshsbom.Generator,
+	signer bicrypto.ArtifactSigner,
 	fs boshsys.FileSystem,
 	ui boshui.UI,
 ) CreateReleaseCmd {
-	return CreateReleaseCmd{releaseDirFactory, releaseWriter, fs, ui}
+	return CreateReleaseCmd{releaseDirFactory, releaseWriter, sbomGenerator, signer, fs, ui}
 }
 
 func (c CreateReleaseCmd) Run(opts CreateReleaseOpts) (boshrel.Release, error) {
+	if len(opts.SignKey.Bytes) > 0 && opts.Tarball.ExpandedPath == "" {
+		return nil, bosherr.Error("Signing release requires --tarball")
+	}
+
 	releaseManifestReader, releaseDir := c.releaseDirFactory(opts.Directory)
 	manifestGiven := len(opts.Args.Manifest.Path) > 0
 
@@ -57,6 +67,7 @@ func (c CreateReleaseCmd) Run(opts CreateReleaseOpts) (boshrel.Release, error) {
 	}
 
 	dstPath := opts.Tarball.ExpandedPath
+	var sigPath string
 
 	if dstPath != "" {
 		path, err := c.releaseWriter.Write(release, nil)
@@ -71,13 +82,50 @@ func (c CreateReleaseCmd) Run(opts CreateReleaseOpts) (boshrel.Release, error) {
 		if err != nil {
 			return nil, bosherr.WrapErrorf(err, "Moving release archive to final destination")
 		}
+
+		if len(opts.SignKey.Bytes) > 0 {
+			sigPath, err = signArtifact(c.signer, c.fs, dstPath, "", opts.SignKey.Bytes)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	ReleaseTables{Release: release, ArchivePath: dstPath}.Print(c.ui)
+	if opts.SBOM.ExpandedPath != "" {
+		// Blobs and vendored packages are only known when building from release directory
+		var releaseDirPath string
+		if !manifestGiven {
+			releaseDirPath = opts.Directory.Path
+		}
+
+		err = c.writeSBOM(release, releaseDirPath, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ReleaseTables{Release: release, ArchivePath: dstPath, SignaturePath: sigPath}.Print(c.ui)
 
 	return release, nil
 }
 
+func (c CreateReleaseCmd) writeSBOM(release boshrel.Release, releaseDirPath string, opts CreateReleaseOpts) error {
+	sbomBytes, err := c.sbomGenerator.Generate(release, releaseDirPath, opts.SBOMFormat)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Generating software bill of materials")
+	}
+
+	sbomPath := strings.Replace(opts.SBOM.ExpandedPath, "((name))", release.Name(), -1)
+	sbomPath = strings.Replace(sbomPath, "((version))", release.Version(), -1)
+
+	err = c.fs.WriteFile(sbomPath, sbomBytes)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing software bill of materials to '%s'", sbomPath)
+	}
+
+	return nil
+}
+
 func (c CreateReleaseCmd) buildRelease(releaseDir boshreldir.ReleaseDir, opts CreateReleaseOpts) (boshrel.Release, error) {
 	var err error
 
@@ -10,8 +10,10 @@ import (
 
 	"github.com/cloudfoundry/bosh-cli/v7/cmd"
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakecrypto "github.com/cloudfoundry/bosh-cli/v7/crypto/cryptofakes"
 	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
 	fakerel "github.com/cloudfoundry/bosh-cli/v7/release/releasefakes"
+	fakesbom "github.com/cloudfoundry/bosh-cli/v7/release/sbom/sbomfakes"
 	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
 	fakereldir "github.com/cloudfoundry/bosh-cli/v7/releasedir/releasedirfakes"
 	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
@@ -25,6 +27,8 @@ var _ = Describe("CreateReleaseCmd", func() {
 		ui            *fakeui.FakeUI
 		fakeFS        *fakesys.FakeFileSystem
 		fakeWriter    *fakerel.FakeWriter
+		sbomGenerator *fakesbom.FakeGenerator
+		signer        *fakecrypto.FakeArtifactSigner
 		command       cmd.CreateReleaseCmd
 	)
 
@@ -38,9 +42,11 @@ var _ = Describe("CreateReleaseCmd", func() {
 		}
 
 		fakeWriter = &fakerel.FakeWriter{}
+		sbomGenerator = &fakesbom.FakeGenerator{}
+		signer = &fakecrypto.FakeArtifactSigner{}
 		fakeFS = fakesys.NewFakeFileSystem()
 		ui = &fakeui.FakeUI{}
-		command = cmd.NewCreateReleaseCmd(releaseDirFactory, fakeWriter, fakeFS, ui)
+		command = cmd.NewCreateReleaseCmd(releaseDirFactory, fakeWriter, sbomGenerator, signer, fakeFS, ui)
 	})
 
 	Describe("Run", func() {
@@ -218,6 +224,53 @@ var _ = Describe("CreateReleaseCmd", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(ContainSubstring("fake-err"))
 				})
+
+				Context("with sign key", func() {
+					BeforeEach(func() {
+						createReleaseOpts.SignKey = opts.FileBytesArg{Bytes: []byte("private-key")}
+
+						fakeWriter.WriteStub = func(rel boshrel.Release, skipPkgs []string) (string, error) {
+							err := fakeFS.WriteFileString("/temp-tarball.tgz", "release content blah")
+							Expect(err).ToNot(HaveOccurred())
+							return "/temp-tarball.tgz", nil
+						}
+					})
+
+					It("writes detached signature next to release tarball", func() {
+						signer.SignReturns([]byte("signature"), nil)
+
+						err := act()
+						Expect(err).ToNot(HaveOccurred())
+
+						Expect(signer.SignCallCount()).To(Equal(1))
+						path, key := signer.SignArgsForCall(0)
+						Expect(path).To(Equal("/tarball-destination.tgz"))
+						Expect(key).To(Equal([]byte("private-key")))
+
+						Expect(fakeFS.ReadFileString("/tarball-destination.tgz.sig")).To(Equal("signature"))
+
+						Expect(ui.Tables[0].Header).To(ContainElement(boshtbl.NewHeader("Signature")))
+						Expect(ui.Tables[0].Rows[0]).To(ContainElement(boshtbl.NewValueString("/tarball-destination.tgz.sig")))
+					})
+
+					It("returns error if signing fails", func() {
+						signer.SignReturns(nil, errors.New("fake-err"))
+
+						err := act()
+						Expect(err).To(HaveOccurred())
+						Expect(err.Error()).To(ContainSubstring("fake-err"))
+					})
+				})
+			})
+
+			It("returns error if sign key is given without tarball", func() {
+				createReleaseOpts.SignKey = opts.FileBytesArg{Bytes: []byte("private-key")}
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Signing release requires --tarball"))
+
+				Expect(releaseReader.ReadCallCount()).To(Equal(0))
 			})
 		})
 
@@ -501,6 +554,54 @@ var _ = Describe("CreateReleaseCmd", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-err"))
 			})
+
+			Context("with software bill of materials", func() {
+				BeforeEach(func() {
+					createReleaseOpts.SBOM = opts.FileArg{ExpandedPath: "/sbom/((name))-((version)).spdx.json"}
+					createReleaseOpts.SBOMFormat = "spdx"
+
+					releaseDir.DefaultNameReturns("default-rel-name", nil)
+					releaseDir.NextDevVersionReturns(semver.MustNewVersionFromString("next-dev+ver"), nil)
+
+					releaseDir.BuildReleaseStub = func(name string, version semver.Version, force bool) (boshrel.Release, error) {
+						release.SetName(name)
+						release.SetVersion(version.String())
+						return release, nil
+					}
+				})
+
+				It("writes generated document to interpolated path", func() {
+					sbomGenerator.GenerateReturns([]byte("sbom-content"), nil)
+
+					err := act()
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(sbomGenerator.GenerateCallCount()).To(Equal(1))
+					rel, dirPath, format := sbomGenerator.GenerateArgsForCall(0)
+					Expect(rel).To(Equal(release))
+					Expect(dirPath).To(Equal("/dir"))
+					Expect(format).To(Equal("spdx"))
+
+					Expect(fakeFS.ReadFileString("/sbom/default-rel-name-next-dev+ver.spdx.json")).To(Equal("sbom-content"))
+				})
+
+				It("returns error if generating document fails", func() {
+					sbomGenerator.GenerateReturns(nil, errors.New("fake-err"))
+
+					err := act()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-err"))
+				})
+
+				It("returns error if writing document fails", func() {
+					sbomGenerator.GenerateReturns([]byte("sbom-content"), nil)
+					fakeFS.WriteFileError = errors.New("fake-err")
+
+					err := act()
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-err"))
+				})
+			})
 		})
 	})
 })
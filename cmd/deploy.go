@@ -6,6 +6,7 @@ import (
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshpropcheck "github.com/cloudfoundry/bosh-cli/v7/director/propcheck"
 	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
 )
@@ -15,6 +16,7 @@ type DeployCmd struct {
 	deployment      boshdir.Deployment
 	releaseUploader ReleaseUploader
 	director        boshdir.Director
+	propsValidator  boshpropcheck.Validator
 }
 
 type ReleaseUploader interface {
@@ -33,8 +35,9 @@ func NewDeployCmd(
 	deployment boshdir.Deployment,
 	releaseUploader ReleaseUploader,
 	director boshdir.Director,
+	propsValidator boshpropcheck.Validator,
 ) DeployCmd {
-	return DeployCmd{ui, deployment, releaseUploader, director}
+	return DeployCmd{ui, deployment, releaseUploader, director, propsValidator}
 }
 
 func (c DeployCmd) Run(opts DeployOpts) error {
@@ -72,6 +75,18 @@ func (c DeployCmd) Run(opts DeployOpts) error {
 		return bosherr.WrapErrorf(err, "Evaluating manifest")
 	}
 
+	err = validateManifest("deployment", bytes, c.ui)
+	if err != nil {
+		return err
+	}
+
+	if opts.ValidateProperties {
+		err = validateProperties(c.propsValidator, bytes, c.ui)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = c.checkDeploymentName(bytes)
 	if err != nil {
 		return err
@@ -12,6 +12,8 @@ import (
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	fakepropcheck "github.com/cloudfoundry/bosh-cli/v7/director/propcheck/propcheckfakes"
+	boshsch "github.com/cloudfoundry/bosh-cli/v7/director/schema"
 	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
 	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
 )
@@ -22,6 +24,7 @@ var _ = Describe("DeployCmd", func() {
 		deployment      *fakedir.FakeDeployment
 		releaseUploader *fakecmd.FakeReleaseUploader
 		director        *fakedir.FakeDirector
+		propsValidator  *fakepropcheck.FakeValidator
 		command         cmd.DeployCmd
 	)
 
@@ -36,8 +39,9 @@ var _ = Describe("DeployCmd", func() {
 		}
 
 		director = &fakedir.FakeDirector{}
+		propsValidator = &fakepropcheck.FakeValidator{}
 
-		command = cmd.NewDeployCmd(ui, deployment, releaseUploader, director)
+		command = cmd.NewDeployCmd(ui, deployment, releaseUploader, director, propsValidator)
 	})
 
 	Describe("Run", func() {
@@ -148,6 +152,93 @@ var _ = Describe("DeployCmd", func() {
 			Expect(bytes).To(Equal([]byte("name: dep\nname1: val1-from-kv\nname2: val2-from-file\nxyz: val\n")))
 		})
 
+		It("does not deploy if manifest structure is invalid", func() {
+			deployOpts.Args.Manifest = opts.FileBytesArg{
+				Bytes: []byte("name: dep\ninstance_groups:\n- name: web\n  azs: z1\n"),
+			}
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Found 4 error(s) in deployment manifest"))
+
+			Expect(ui.Errors).To(ContainElement(
+				"Error: '/instance_groups/name=web/azs': Expected array but got string"))
+
+			Expect(releaseUploader.UploadReleasesCallCount()).To(Equal(0))
+			Expect(deployment.UpdateCallCount()).To(Equal(0))
+		})
+
+		It("does not validate job properties by default", func() {
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(propsValidator.ValidateCallCount()).To(Equal(0))
+		})
+
+		Context("when validating job properties", func() {
+			BeforeEach(func() {
+				deployOpts.ValidateProperties = true
+			})
+
+			It("validates evaluated manifest before uploading releases and deploys if there are only warnings", func() {
+				deployOpts.Args.Manifest = opts.FileBytesArg{Bytes: []byte("name: ((name))")}
+				deployOpts.VarKVs = []boshtpl.VarKV{{Name: "name", Value: "dep"}}
+
+				propsValidator.ValidateReturns([]boshsch.Problem{
+					{Level: boshsch.LevelWarning, Path: "/releases/name=rel", Message: "Skipping validation"},
+				}, nil)
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(propsValidator.ValidateCallCount()).To(Equal(1))
+				Expect(propsValidator.ValidateArgsForCall(0)).To(Equal([]byte("name: dep\n")))
+
+				Expect(ui.Errors).To(ContainElement("Warning: '/releases/name=rel': Skipping validation"))
+				Expect(deployment.UpdateCallCount()).To(Equal(1))
+			})
+
+			It("does not upload releases or deploy if job properties have errors", func() {
+				propsValidator.ValidateReturns([]boshsch.Problem{
+					{Level: boshsch.LevelError, Path: "/instance_groups/name=web/jobs/name=web/properties/prot", Message: "Unknown property 'prot' for job 'web'"},
+				}, nil)
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Found 1 error(s) in job properties"))
+
+				Expect(ui.Errors).To(ContainElement(
+					"Error: '/instance_groups/name=web/jobs/name=web/properties/prot': Unknown property 'prot' for job 'web'"))
+
+				Expect(releaseUploader.UploadReleasesCallCount()).To(Equal(0))
+				Expect(deployment.UpdateCallCount()).To(Equal(0))
+			})
+
+			It("returns error if job properties cannot be validated", func() {
+				propsValidator.ValidateReturns(nil, errors.New("fake-err"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(releaseUploader.UploadReleasesCallCount()).To(Equal(0))
+			})
+		})
+
+		It("deploys manifest with unknown keys after showing warnings", func() {
+			deployOpts.Args.Manifest = opts.FileBytesArg{
+				Bytes: []byte("name: dep\nrelease: []\n"),
+			}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Errors).To(ContainElement(
+				"Warning: '/release': Unknown key 'release' (did you mean 'releases'?)"))
+
+			Expect(deployment.UpdateCallCount()).To(Equal(1))
+		})
+
 		It("does not deploy if name specified in the manifest does not match deployment's name", func() {
 			deployOpts.Args.Manifest = opts.FileBytesArg{
 				Bytes: []byte("name: other-name"),
@@ -1,6 +1,12 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	biagentclient "github.com/cloudfoundry/bosh-agent/agentclient"
 	bihttpagent "github.com/cloudfoundry/bosh-agent/agentclient/http"
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	bihttpclient "github.com/cloudfoundry/bosh-utils/httpclient"
@@ -21,6 +27,7 @@ import (
 	birelsetmanifest "github.com/cloudfoundry/bosh-cli/v7/release/set/manifest"
 	bistemcell "github.com/cloudfoundry/bosh-cli/v7/stemcell"
 	biui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
 )
 
 func NewDeploymentPreparer(
@@ -101,10 +108,171 @@ type DeploymentPreparer struct {
 	targetProvider                          biinstall.TargetProvider
 }
 
-func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage, recreate bool, recreatePersistentDisks bool, skipDrain bool) (err error) {
+type preparedDeployment struct {
+	deploymentState      biconfig.DeploymentState
+	target               biinstall.Target
+	extractedStemcell    bistemcell.ExtractedStemcell
+	deploymentManifest   bideplmanifest.Manifest
+	installationManifest biinstallmanifest.Manifest
+	manifestSHA          string
+}
+
+func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage, recreate bool, recreatePersistentDisks bool, skipDrain bool) error {
+	return c.withValidatedDeployment(stage, recreate, recreatePersistentDisks, false, func(p preparedDeployment) error {
+		return c.cpiInstaller.WithInstalledCpiRelease(p.installationManifest, p.target, stage, func(installation biinstall.Installation) error {
+			stemcellApiVersion := c.stemcellApiVersion(p.extractedStemcell)
+
+			cloud, err := c.cloudFactory.NewCloud(installation, p.deploymentState.DirectorID, stemcellApiVersion)
+			if err != nil {
+				return bosherr.WrapError(err, "Creating CPI client from CPI installation")
+			}
+
+			deploy := func() error {
+				agentClient, err := c.agentClientFactory.NewAgentClient(p.deploymentState.DirectorID, p.installationManifest.Mbus, p.installationManifest.Cert.CA)
+				if err != nil {
+					return err
+				}
+
+				blobstore, err := c.blobstoreFactory.Create(p.installationManifest.Mbus, bihttpclient.CreateDefaultClientInsecureSkipVerify())
+				if err != nil {
+					return bosherr.WrapError(err, "Creating blobstore client")
+				}
+
+				return c.deploy(
+					p.deploymentState,
+					p.extractedStemcell,
+					p.deploymentManifest,
+					p.manifestSHA,
+					skipDrain,
+					stage,
+					cloud,
+					agentClient,
+					blobstore,
+				)
+			}
+
+			cpiInfo, err := cloud.Info()
+			if err != nil {
+				return bosherr.WrapError(err, "Error getting CPI info")
+			}
+
+			if stemcellApiVersion >= bicloud.StemcellNoRegistryAsOfVersion &&
+				cpiInfo.ApiVersion == bicloud.MaxCpiApiVersionSupported {
+				return deploy()
+			} else {
+				return bosherr.Errorf(
+					"The `bosh` cli requires CPI v2.0 or greater, you are using %d",
+					cpiInfo.ApiVersion,
+				)
+			}
+		})
+	})
+}
+
+// PlanDeployment goes through the same steps as PrepareDeployment,
+// including CPI installation, but deploys to an in-process cloud
+// with an agent that accepts every request. Nothing is created on the IaaS
+// and the deployment state file is neither migrated nor written.
+func (c *DeploymentPreparer) PlanDeployment(stage biui.Stage, recreate bool, recreatePersistentDisks bool) error {
+	return c.withValidatedDeployment(stage, recreate, recreatePersistentDisks, true, func(p preparedDeployment) error {
+		return c.cpiInstaller.WithInstalledCpiRelease(p.installationManifest, p.target, stage, func(biinstall.Installation) error {
+			cloud := bicloud.NewDryRunCloud(c.logger)
+			cloud.AddExisting(c.existingCIDs(p.deploymentState))
+
+			// There are no jobs to watch, so do not wait for them
+			deploymentManifest := p.deploymentManifest
+			deploymentManifest.Update.UpdateWatchTime = bideplmanifest.WatchTime{Start: 0, End: 1000}
+
+			err := c.deploy(
+				p.deploymentState,
+				p.extractedStemcell,
+				deploymentManifest,
+				p.manifestSHA,
+				false,
+				stage,
+				cloud,
+				bidepl.NewDryRunAgentClient(),
+				biblobstore.NewDryRunBlobstore(),
+			)
+			if err != nil {
+				return err
+			}
+
+			return c.printPlan(cloud.Plan())
+		})
+	})
+}
+
+func (c *DeploymentPreparer) existingCIDs(deploymentState biconfig.DeploymentState) (vmCIDs, diskCIDs, stemcellCIDs []string) {
+	if len(deploymentState.CurrentVMCID) > 0 {
+		vmCIDs = append(vmCIDs, deploymentState.CurrentVMCID)
+	}
+
+	for _, diskRecord := range deploymentState.Disks {
+		diskCIDs = append(diskCIDs, diskRecord.CID)
+	}
+
+	for _, stemcellRecord := range deploymentState.Stemcells {
+		stemcellCIDs = append(stemcellCIDs, stemcellRecord.CID)
+	}
+
+	return vmCIDs, diskCIDs, stemcellCIDs
+}
+
+func (c *DeploymentPreparer) printPlan(plan []bicloud.PlannedCall) error {
+	table := boshtbl.Table{
+		Content: "CPI calls",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("#"),
+			boshtbl.NewHeader("Method"),
+			boshtbl.NewHeader("Arguments"),
+			boshtbl.NewHeader("Result"),
+		},
+
+		Notes: []string{"Secrets in cloud properties are redacted"},
+	}
+
+	for i, call := range plan {
+		input, err := bicloud.RedactCmdInput(bicloud.CmdInput{Method: call.Method, Arguments: call.Arguments})
+		if err != nil {
+			return err
+		}
+
+		// Keep redaction placeholders readable in the table
+		arguments := &bytes.Buffer{}
+		encoder := json.NewEncoder(arguments)
+		encoder.SetEscapeHTML(false)
+
+		err = encoder.Encode(input.Arguments)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Marshalling CPI '%s' arguments", call.Method)
+		}
+
+		result := ""
+		if call.Result != nil {
+			result = fmt.Sprintf("%v", call.Result)
+		}
+
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueInt(i + 1),
+			boshtbl.NewValueString(call.Method),
+			boshtbl.NewValueString(strings.TrimSpace(arguments.String())),
+			boshtbl.NewValueString(result),
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	return nil
+}
+
+// withValidatedDeployment leaves the deployment state file alone for dry runs;
+// their deployment state service is expected to keep changes in memory
+func (c *DeploymentPreparer) withValidatedDeployment(stage biui.Stage, recreate bool, recreatePersistentDisks bool, dryRun bool, fn func(preparedDeployment) error) (err error) {
 	c.ui.BeginLinef("Deployment state: '%s'\n", c.deploymentStateService.Path())
 
-	if !c.deploymentStateService.Exists() {
+	if !dryRun && !c.deploymentStateService.Exists() {
 		migrated, err := c.legacyDeploymentStateMigrator.MigrateIfExists(biconfig.LegacyDeploymentStatePath(c.deploymentManifestPath))
 		if err != nil {
 			return bosherr.WrapError(err, "Migrating legacy deployment state file")
@@ -189,54 +357,26 @@ func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage, recreate bool,
 		return nil
 	}
 
-	err = c.cpiInstaller.WithInstalledCpiRelease(installationManifest, target, stage, func(installation biinstall.Installation) error {
-		stemcellApiVersion := c.stemcellApiVersion(extractedStemcell)
-
-		cloud, err := c.cloudFactory.NewCloud(installation, deploymentState.DirectorID, stemcellApiVersion)
-		if err != nil {
-			return bosherr.WrapError(err, "Creating CPI client from CPI installation")
-		}
-
-		deploy := func() error {
-			return c.deploy(
-				deploymentState,
-				extractedStemcell,
-				installationManifest,
-				deploymentManifest,
-				manifestSHA,
-				skipDrain,
-				stage,
-				cloud,
-			)
-		}
-
-		cpiInfo, err := cloud.Info()
-		if err != nil {
-			return bosherr.WrapError(err, "Error getting CPI info")
-		}
-
-		if stemcellApiVersion >= bicloud.StemcellNoRegistryAsOfVersion &&
-			cpiInfo.ApiVersion == bicloud.MaxCpiApiVersionSupported {
-			return deploy()
-		} else {
-			return bosherr.Errorf(
-				"The `bosh` cli requires CPI v2.0 or greater, you are using %d",
-				cpiInfo.ApiVersion,
-			)
-		}
+	return fn(preparedDeployment{
+		deploymentState:      deploymentState,
+		target:               target,
+		extractedStemcell:    extractedStemcell,
+		deploymentManifest:   deploymentManifest,
+		installationManifest: installationManifest,
+		manifestSHA:          manifestSHA,
 	})
-	return err
 }
 
 func (c *DeploymentPreparer) deploy(
 	deploymentState biconfig.DeploymentState,
 	extractedStemcell bistemcell.ExtractedStemcell,
-	installationManifest biinstallmanifest.Manifest,
 	deploymentManifest bideplmanifest.Manifest,
 	manifestSHA string,
 	skipDrain bool,
 	stage biui.Stage,
 	cloud bicloud.Cloud,
+	agentClient biagentclient.AgentClient,
+	blobstore biblobstore.Blobstore,
 ) (err error) {
 	stemcellManager := c.stemcellManagerFactory.NewManager(cloud)
 
@@ -245,17 +385,8 @@ func (c *DeploymentPreparer) deploy(
 		return err
 	}
 
-	agentClient, err := c.agentClientFactory.NewAgentClient(deploymentState.DirectorID, installationManifest.Mbus, installationManifest.Cert.CA)
-	if err != nil {
-		return err
-	}
 	vmManager := c.vmManagerFactory.NewManager(cloud, agentClient)
 
-	blobstore, err := c.blobstoreFactory.Create(installationManifest.Mbus, bihttpclient.CreateDefaultClientInsecureSkipVerify())
-	if err != nil {
-		return bosherr.WrapError(err, "Creating blobstore client")
-	}
-
 	err = stage.PerformComplex("deploying", func(deployStage biui.Stage) error {
 		err = c.deploymentRecord.Clear()
 		if err != nil {
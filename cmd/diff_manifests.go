@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshmandiff "github.com/cloudfoundry/bosh-cli/v7/director/manifestdiff"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+type DiffManifestsCmd struct {
+	ui boshui.UI
+}
+
+func NewDiffManifestsCmd(ui boshui.UI) DiffManifestsCmd {
+	return DiffManifestsCmd{ui: ui}
+}
+
+func (c DiffManifestsCmd) Run(opts DiffManifestsOpts) error {
+	if opts.Format != "text" && opts.Format != "json" {
+		return bosherr.Errorf("Unknown format '%s', expected 'text' or 'json'", opts.Format)
+	}
+
+	result, err := boshmandiff.Compare(
+		opts.Args.From.Bytes, opts.Args.To.Bytes, boshmandiff.Opts{Redact: !opts.NoRedact})
+	if err != nil {
+		return bosherr.WrapError(err, "Comparing manifests")
+	}
+
+	if opts.Format == "json" {
+		changes := result.Changes
+		if changes == nil {
+			changes = []boshmandiff.Change{}
+		}
+
+		bytes, err := json.MarshalIndent(map[string]interface{}{"changes": changes}, "", "  ")
+		if err != nil {
+			return bosherr.WrapError(err, "Marshaling changes")
+		}
+
+		c.ui.PrintBlock(bytes)
+
+		return nil
+	}
+
+	var lines [][]interface{}
+
+	for _, line := range result.Lines {
+		lines = append(lines, []interface{}{line.Text, line.State})
+	}
+
+	// Printed as a block so that it's not dropped when output is not a terminal
+	c.ui.PrintBlock([]byte(NewDiff(lines).String()))
+
+	return nil
+}
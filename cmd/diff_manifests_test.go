@@ -0,0 +1,117 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("DiffManifestsCmd", func() {
+	var (
+		ui      *fakeui.FakeUI
+		command DiffManifestsCmd
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		command = NewDiffManifestsCmd(ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts DiffManifestsOpts
+		)
+
+		BeforeEach(func() {
+			opts = DiffManifestsOpts{
+				Args: DiffManifestsArgs{
+					From: FileBytesArg{Bytes: []byte(`
+instance_groups:
+- name: web
+  instances: 1
+  properties:
+    password: old-pass
+- name: db
+`)},
+					To: FileBytesArg{Bytes: []byte(`
+instance_groups:
+- name: db
+- name: web
+  instances: 2
+  properties:
+    password: new-pass
+`)},
+				},
+				Format: "text",
+			}
+		})
+
+		It("prints differences like the director diff with credentials redacted", func() {
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(Equal([]string{
+				"  instance_groups:\n" +
+					"  - name: web\n" +
+					"-   instances: 1\n" +
+					"+   instances: 2\n" +
+					"    properties:\n" +
+					"-     password: <redacted>\n" +
+					"+     password: <redacted>\n",
+			}))
+		})
+
+		It("shows credentials if redaction is disabled", func() {
+			opts.NoRedact = true
+
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks[0]).To(ContainSubstring("-     password: old-pass\n"))
+			Expect(ui.Blocks[0]).To(ContainSubstring("+     password: new-pass\n"))
+		})
+
+		It("prints changes as JSON", func() {
+			opts.Format = "json"
+
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(HaveLen(1))
+			Expect(ui.Blocks[0]).To(MatchJSON(`{
+  "changes": [
+    {"path": "/instance_groups/name=web/instances", "type": "changed", "from": 1, "to": 2},
+    {"path": "/instance_groups/name=web/properties/password", "type": "changed", "from": "<redacted>", "to": "<redacted>"}
+  ]
+}`))
+		})
+
+		It("prints empty list of changes as JSON when manifests are equal", func() {
+			opts.Format = "json"
+			opts.Args.To = opts.Args.From
+
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ui.Blocks[0]).To(MatchJSON(`{"changes": []}`))
+		})
+
+		It("returns error if format is unknown", func() {
+			opts.Format = "yaml"
+
+			err := command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Unknown format 'yaml', expected 'text' or 'json'"))
+		})
+
+		It("returns error if manifest cannot be parsed", func() {
+			opts.Args.From = FileBytesArg{Bytes: []byte("name: [")}
+
+			err := command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Comparing manifests"))
+		})
+	})
+})
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	semver "github.com/cppforlife/go-semi-semantic/version"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshjob "github.com/cloudfoundry/bosh-cli/v7/release/job"
+	boshman "github.com/cloudfoundry/bosh-cli/v7/release/manifest"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+type DiffReleasesCmd struct {
+	releaseReaderFactory func(path string) boshrel.Reader
+	releaseDirFactory    func(DirOrCWDArg) boshreldir.ReleaseDir
+	jobReader            boshjob.ArchiveReader
+	fs                   boshsys.FileSystem
+	ui                   boshui.UI
+}
+
+func NewDiffReleasesCmd(
+	releaseReaderFactory func(path string) boshrel.Reader,
+	releaseDirFactory func(DirOrCWDArg) boshreldir.ReleaseDir,
+	jobReader boshjob.ArchiveReader,
+	fs boshsys.FileSystem,
+	ui boshui.UI,
+) DiffReleasesCmd {
+	return DiffReleasesCmd{
+		releaseReaderFactory: releaseReaderFactory,
+		releaseDirFactory:    releaseDirFactory,
+		jobReader:            jobReader,
+		fs:                   fs,
+		ui:                   ui,
+	}
+}
+
+func (c DiffReleasesCmd) Run(opts DiffReleasesOpts) error {
+	fromRelease, err := c.readRelease(opts.Args.From, opts)
+	if err != nil {
+		return err
+	}
+	defer fromRelease.CleanUp() //nolint:errcheck
+
+	toRelease, err := c.readRelease(opts.Args.To, opts)
+	if err != nil {
+		return err
+	}
+	defer toRelease.CleanUp() //nolint:errcheck
+
+	for _, release := range []boshrel.Release{fromRelease, toRelease} {
+		err = c.loadJobSpecs(release)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.printDiff(boshrel.NewDiff(fromRelease, toRelease))
+
+	return nil
+}
+
+// readRelease accepts a path to a release tarball, release directory or
+// release manifest; anything else is treated as a version in the release index.
+func (c DiffReleasesCmd) readRelease(arg string, opts DiffReleasesOpts) (boshrel.Release, error) {
+	if c.fs.FileExists(arg) {
+		release, err := c.releaseReaderFactory(arg).Read(arg)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Reading release '%s'", arg)
+		}
+
+		return release, nil
+	}
+
+	version, err := semver.NewVersionFromString(arg)
+	if err != nil {
+		return nil, bosherr.Errorf("Expected '%s' to be a release tarball, release directory or release version", arg)
+	}
+
+	release, err := c.releaseDirFactory(opts.Directory).FindRelease(opts.Name, version)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Finding release version '%s'", arg)
+	}
+
+	return release, nil
+}
+
+// loadJobSpecs reads job specs from job archives for releases
+// read without extracting their jobs.
+func (c DiffReleasesCmd) loadJobSpecs(release boshrel.Release) error {
+	for _, job := range release.Jobs() {
+		if job.Properties != nil {
+			continue
+		}
+
+		ref := boshman.JobRef{Name: job.Name(), Fingerprint: job.Fingerprint(), SHA1: job.ArchiveDigest()}
+
+		extractedJob, err := c.jobReader.Read(ref, job.ArchivePath())
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading job '%s' spec", job.Name())
+		}
+
+		job.Properties = extractedJob.Properties
+		job.Consumes = extractedJob.Consumes
+		job.Provides = extractedJob.Provides
+
+		err = extractedJob.CleanUp()
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Cleaning up job '%s'", job.Name())
+		}
+	}
+
+	return nil
+}
+
+func (c DiffReleasesCmd) printDiff(diff boshrel.Diff) {
+	for _, content := range []string{"jobs", "packages"} {
+		resourceDiffs := diff.Jobs
+		header := "Job"
+
+		if content == "packages" {
+			resourceDiffs = diff.Packages
+			header = "Package"
+		}
+
+		table := boshtbl.Table{
+			Content: content,
+			Header: []boshtbl.Header{
+				boshtbl.NewHeader(header),
+				boshtbl.NewHeader("Change"),
+				boshtbl.NewHeader("From Fingerprint"),
+				boshtbl.NewHeader("To Fingerprint"),
+			},
+		}
+
+		for _, resourceDiff := range resourceDiffs {
+			table.Rows = append(table.Rows, []boshtbl.Value{
+				boshtbl.NewValueString(resourceDiff.Name),
+				boshtbl.NewValueString(resourceDiff.Change),
+				boshtbl.NewValueString(resourceDiff.FromFingerprint),
+				boshtbl.NewValueString(resourceDiff.ToFingerprint),
+			})
+		}
+
+		c.ui.PrintTable(table)
+	}
+
+	specsTable := boshtbl.Table{
+		Content: "spec changes",
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Job"),
+			boshtbl.NewHeader("Type"),
+			boshtbl.NewHeader("Name"),
+			boshtbl.NewHeader("Change"),
+			boshtbl.NewHeader("From"),
+			boshtbl.NewHeader("To"),
+		},
+		Notes: []string{"Property defaults are shown as JSON; spec changes are only listed for jobs present in both releases"},
+	}
+
+	for _, specDiff := range diff.Specs {
+		specsTable.Rows = append(specsTable.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(specDiff.Job),
+			boshtbl.NewValueString(specDiff.Kind),
+			boshtbl.NewValueString(specDiff.Name),
+			boshtbl.NewValueString(specDiff.Change),
+			boshtbl.NewValueString(specDiff.From),
+			boshtbl.NewValueString(specDiff.To),
+		})
+	}
+
+	c.ui.PrintTable(specsTable)
+}
@@ -0,0 +1,230 @@
+package cmd_test
+
+import (
+	"errors"
+
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	semver "github.com/cppforlife/go-semi-semantic/version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshjob "github.com/cloudfoundry/bosh-cli/v7/release/job"
+	fakejob "github.com/cloudfoundry/bosh-cli/v7/release/job/jobfakes"
+	boshman "github.com/cloudfoundry/bosh-cli/v7/release/manifest"
+	fakerel "github.com/cloudfoundry/bosh-cli/v7/release/releasefakes"
+	. "github.com/cloudfoundry/bosh-cli/v7/release/resource"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+	fakereldir "github.com/cloudfoundry/bosh-cli/v7/releasedir/releasedirfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("DiffReleasesCmd", func() {
+	var (
+		releaseReader *fakerel.FakeReader
+		releaseDir    *fakereldir.FakeReleaseDir
+		jobReader     *fakejob.FakeArchiveReader
+		fs            *fakesys.FakeFileSystem
+		ui            *fakeui.FakeUI
+		command       DiffReleasesCmd
+
+		readerPaths []string
+		releaseDirs []DirOrCWDArg
+	)
+
+	BeforeEach(func() {
+		releaseReader = &fakerel.FakeReader{}
+		releaseDir = &fakereldir.FakeReleaseDir{}
+		jobReader = &fakejob.FakeArchiveReader{}
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+
+		readerPaths = nil
+		releaseDirs = nil
+
+		releaseReaderFactory := func(path string) boshrel.Reader {
+			readerPaths = append(readerPaths, path)
+			return releaseReader
+		}
+
+		releaseDirFactory := func(dir DirOrCWDArg) boshreldir.ReleaseDir {
+			releaseDirs = append(releaseDirs, dir)
+			return releaseDir
+		}
+
+		command = NewDiffReleasesCmd(releaseReaderFactory, releaseDirFactory, jobReader, fs, ui)
+	})
+
+	newRelease := func(jobs ...*boshjob.Job) *fakerel.FakeRelease {
+		release := &fakerel.FakeRelease{}
+		release.JobsReturns(jobs)
+		return release
+	}
+
+	newJob := func(name, fp string, props map[string]boshjob.PropertyDefinition) *boshjob.Job {
+		job := boshjob.NewJob(NewResourceWithBuiltArchive(name, fp, "/"+name+"-"+fp+".tgz", name+"-sha1"))
+		job.Properties = props
+		return job
+	}
+
+	Describe("Run", func() {
+		var (
+			opts DiffReleasesOpts
+		)
+
+		BeforeEach(func() {
+			opts = DiffReleasesOpts{
+				Args:      DiffReleasesArgs{From: "/from.tgz", To: "/to.tgz"},
+				Directory: DirOrCWDArg{Path: "/release-dir"},
+				Name:      "rel",
+			}
+		})
+
+		It("prints fingerprint and spec differences between two release tarballs", func() {
+			err := fs.WriteFileString("/from.tgz", "")
+			Expect(err).ToNot(HaveOccurred())
+			err = fs.WriteFileString("/to.tgz", "")
+			Expect(err).ToNot(HaveOccurred())
+
+			fromRelease := newRelease(newJob("job", "fp1", map[string]boshjob.PropertyDefinition{
+				"port": {Default: biproperty.Property(80)},
+			}))
+			toRelease := newRelease(newJob("job", "fp2", map[string]boshjob.PropertyDefinition{
+				"port": {Default: biproperty.Property(8080)},
+			}))
+
+			releaseReader.ReadStub = func(path string) (boshrel.Release, error) {
+				if path == "/from.tgz" {
+					return fromRelease, nil
+				}
+				return toRelease, nil
+			}
+
+			err = command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(readerPaths).To(Equal([]string{"/from.tgz", "/to.tgz"}))
+			Expect(jobReader.ReadCallCount()).To(Equal(0))
+
+			Expect(ui.Tables).To(HaveLen(3))
+
+			Expect(ui.Tables[0].Content).To(Equal("jobs"))
+			Expect(ui.Tables[0].Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("job"),
+					boshtbl.NewValueString("changed"),
+					boshtbl.NewValueString("fp1"),
+					boshtbl.NewValueString("fp2"),
+				},
+			}))
+
+			Expect(ui.Tables[1].Content).To(Equal("packages"))
+			Expect(ui.Tables[1].Rows).To(BeEmpty())
+
+			Expect(ui.Tables[2].Content).To(Equal("spec changes"))
+			Expect(ui.Tables[2].Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("job"),
+					boshtbl.NewValueString("property"),
+					boshtbl.NewValueString("port"),
+					boshtbl.NewValueString("default changed"),
+					boshtbl.NewValueString("80"),
+					boshtbl.NewValueString("8080"),
+				},
+			}))
+
+			Expect(fromRelease.CleanUpCallCount()).To(Equal(1))
+			Expect(toRelease.CleanUpCallCount()).To(Equal(1))
+		})
+
+		It("finds releases in the release index when versions are given", func() {
+			opts.Args = DiffReleasesArgs{From: "1", To: "2+dev.1"}
+
+			releaseDir.FindReleaseReturnsOnCall(0, newRelease(), nil)
+			releaseDir.FindReleaseReturnsOnCall(1, newRelease(), nil)
+
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(readerPaths).To(BeEmpty())
+			Expect(releaseDirs).To(Equal([]DirOrCWDArg{{Path: "/release-dir"}, {Path: "/release-dir"}}))
+
+			Expect(releaseDir.FindReleaseCallCount()).To(Equal(2))
+
+			name, version := releaseDir.FindReleaseArgsForCall(0)
+			Expect(name).To(Equal("rel"))
+			Expect(version).To(Equal(semver.MustNewVersionFromString("1")))
+
+			name, version = releaseDir.FindReleaseArgsForCall(1)
+			Expect(name).To(Equal("rel"))
+			Expect(version).To(Equal(semver.MustNewVersionFromString("2+dev.1")))
+		})
+
+		It("reads job specs from job archives when releases do not include them", func() {
+			opts.Args = DiffReleasesArgs{From: "1", To: "2"}
+
+			fromJob := newJob("job", "fp1", nil)
+			fromJob.Consumes = nil
+			releaseDir.FindReleaseReturnsOnCall(0, newRelease(fromJob), nil)
+			releaseDir.FindReleaseReturnsOnCall(1, newRelease(newJob("job", "fp2", map[string]boshjob.PropertyDefinition{})), nil)
+
+			extractedJob := newJob("job", "fp1", map[string]boshjob.PropertyDefinition{})
+			extractedJob.Consumes = []boshjob.LinkDefinition{{Name: "db", Type: "database"}}
+			jobReader.ReadReturns(extractedJob, nil)
+
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(jobReader.ReadCallCount()).To(Equal(1))
+
+			ref, path := jobReader.ReadArgsForCall(0)
+			Expect(ref).To(Equal(boshman.JobRef{Name: "job", Fingerprint: "fp1", SHA1: "job-sha1"}))
+			Expect(path).To(Equal("/job-fp1.tgz"))
+
+			Expect(ui.Tables[2].Rows).To(Equal([][]boshtbl.Value{
+				{
+					boshtbl.NewValueString("job"),
+					boshtbl.NewValueString("consumes"),
+					boshtbl.NewValueString("db"),
+					boshtbl.NewValueString("removed"),
+					boshtbl.NewValueString("database"),
+					boshtbl.NewValueString(""),
+				},
+			}))
+		})
+
+		It("returns error if argument is neither a path nor a version", func() {
+			opts.Args = DiffReleasesArgs{From: "/missing.tgz", To: "1"}
+
+			err := command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected '/missing.tgz' to be a release tarball, release directory or release version"))
+		})
+
+		It("returns error if release cannot be read", func() {
+			err := fs.WriteFileString("/from.tgz", "")
+			Expect(err).ToNot(HaveOccurred())
+
+			releaseReader.ReadReturns(nil, errors.New("fake-err"))
+
+			err = command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading release '/from.tgz'"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if release version cannot be found", func() {
+			opts.Args = DiffReleasesArgs{From: "1", To: "2"}
+
+			releaseDir.FindReleaseReturns(nil, errors.New("fake-err"))
+
+			err := command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Finding release version '1'"))
+		})
+	})
+})
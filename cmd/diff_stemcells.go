@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+type DiffStemcellsCmd struct {
+	archiveFactory func(string) boshinsp.Archive
+	ui             boshui.UI
+}
+
+func NewDiffStemcellsCmd(archiveFactory func(string) boshinsp.Archive, ui boshui.UI) DiffStemcellsCmd {
+	return DiffStemcellsCmd{archiveFactory: archiveFactory, ui: ui}
+}
+
+func (c DiffStemcellsCmd) Run(opts DiffStemcellsOpts) error {
+	fromPkgs, err := c.archiveFactory(opts.Args.From).Packages()
+	if err != nil {
+		return err
+	}
+
+	toPkgs, err := c.archiveFactory(opts.Args.To).Packages()
+	if err != nil {
+		return err
+	}
+
+	table := boshtbl.Table{
+		Content: "package changes",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Package"),
+			boshtbl.NewHeader("Change"),
+			boshtbl.NewHeader("From"),
+			boshtbl.NewHeader("To"),
+		},
+	}
+
+	for _, change := range boshinsp.DiffPackages(fromPkgs, toPkgs) {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(change.Name),
+			boshtbl.NewValueString(change.Change),
+			boshtbl.NewValueString(change.Before),
+			boshtbl.NewValueString(change.After),
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	fakeinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect/inspectfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("DiffStemcellsCmd", func() {
+	var (
+		fromArchive *fakeinsp.FakeArchive
+		toArchive   *fakeinsp.FakeArchive
+		ui          *fakeui.FakeUI
+		command     DiffStemcellsCmd
+	)
+
+	BeforeEach(func() {
+		fromArchive = &fakeinsp.FakeArchive{}
+		toArchive = &fakeinsp.FakeArchive{}
+		ui = &fakeui.FakeUI{}
+
+		archiveFactory := func(path string) boshinsp.Archive {
+			return map[string]boshinsp.Archive{"/from.tgz": fromArchive, "/to.tgz": toArchive}[path]
+		}
+
+		command = NewDiffStemcellsCmd(archiveFactory, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts DiffStemcellsOpts
+		)
+
+		BeforeEach(func() {
+			opts = DiffStemcellsOpts{Args: DiffStemcellsArgs{From: "/from.tgz", To: "/to.tgz"}}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("shows package changes between stemcells", func() {
+			fromArchive.PackagesReturns([]boshinsp.Package{
+				{Name: "openssl", Version: "3.0.2-0ubuntu1.10"},
+				{Name: "telnet", Version: "0.17"},
+			}, nil)
+
+			toArchive.PackagesReturns([]boshinsp.Package{
+				{Name: "curl", Version: "7.81.0"},
+				{Name: "openssl", Version: "3.0.2-0ubuntu1.12"},
+			}, nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Table).To(Equal(boshtbl.Table{
+				Content: "package changes",
+
+				Header: []boshtbl.Header{
+					boshtbl.NewHeader("Package"),
+					boshtbl.NewHeader("Change"),
+					boshtbl.NewHeader("From"),
+					boshtbl.NewHeader("To"),
+				},
+
+				Rows: [][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("curl"),
+						boshtbl.NewValueString("added"),
+						boshtbl.NewValueString(""),
+						boshtbl.NewValueString("7.81.0"),
+					},
+					{
+						boshtbl.NewValueString("openssl"),
+						boshtbl.NewValueString("updated"),
+						boshtbl.NewValueString("3.0.2-0ubuntu1.10"),
+						boshtbl.NewValueString("3.0.2-0ubuntu1.12"),
+					},
+					{
+						boshtbl.NewValueString("telnet"),
+						boshtbl.NewValueString("removed"),
+						boshtbl.NewValueString("0.17"),
+						boshtbl.NewValueString(""),
+					},
+				},
+			}))
+		})
+
+		It("returns error if reading packages of first stemcell fails", func() {
+			fromArchive.PackagesReturns(nil, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+			Expect(toArchive.PackagesCallCount()).To(Equal(0))
+		})
+
+		It("returns error if reading packages of second stemcell fails", func() {
+			toArchive.PackagesReturns(nil, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
@@ -33,6 +33,7 @@ import (
 	bistemcell "github.com/cloudfoundry/bosh-cli/v7/stemcell"
 	bitemplate "github.com/cloudfoundry/bosh-cli/v7/templatescompiler"
 	bitemplateerb "github.com/cloudfoundry/bosh-cli/v7/templatescompiler/erbrenderer"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
 )
 
 type envFactory struct {
@@ -71,7 +72,9 @@ func NewEnvFactory(
 	manifestVars boshtpl.Variables,
 	manifestOp patch.Op,
 	recreatePersistentDisks bool,
+	dryRun bool,
 	packageDir string,
+	tracer tracing.Tracer,
 ) *envFactory {
 	f := envFactory{
 		deps:         deps,
@@ -111,8 +114,13 @@ func NewEnvFactory(
 		}
 	}
 
-	f.deploymentStateService = biconfig.NewFileSystemDeploymentStateService(
-		deps.FS, deps.UUIDGen, deps.Logger, biconfig.DeploymentStatePath(manifestPath, statePath))
+	if dryRun {
+		f.deploymentStateService = biconfig.NewDryRunDeploymentStateService(
+			deps.FS, deps.UUIDGen, deps.Logger, biconfig.DeploymentStatePath(manifestPath, statePath))
+	} else {
+		f.deploymentStateService = biconfig.NewFileSystemDeploymentStateService(
+			deps.FS, deps.UUIDGen, deps.Logger, biconfig.DeploymentStatePath(manifestPath, statePath))
+	}
 
 	{
 		installerFactory := boshinst.NewInstallerFactory(
@@ -149,17 +157,18 @@ func NewEnvFactory(
 		f.blobstoreFactory = biblobstore.NewBlobstoreFactory(deps.UUIDGen, deps.FS, deps.Logger)
 		f.deploymentFactory = bidepl.NewFactory(10*time.Second, 500*time.Millisecond)
 		f.agentClientFactory = bihttpagent.NewAgentClientFactory(1*time.Second, deps.Logger)
-		f.cloudFactory = bicloud.NewFactory(deps.FS, deps.CmdRunner, deps.Logger)
+		f.cloudFactory = bicloud.NewFactory(deps.FS, deps.CmdRunner, os.Getenv, tracer, deps.Logger)
 	}
 
 	{
 		erbRenderer := bitemplateerb.NewERBRenderer(deps.FS, deps.CmdRunner, deps.Logger)
 		jobRenderer := bitemplate.NewJobRenderer(erbRenderer, deps.FS, deps.UUIDGen, deps.Logger)
+		jobListRenderer := bitemplate.NewJobListRenderer(jobRenderer, deps.Logger)
 
 		builderFactory := biinstancestate.NewBuilderFactory(
 			bistatepkg.NewCompiledPackageRepo(biindex.NewInMemoryIndex()),
 			releaseJobResolver,
-			bitemplate.NewJobListRenderer(jobRenderer, deps.Logger),
+			jobListRenderer,
 			bitemplate.NewRenderedJobListCompressor(deps.FS, deps.Compressor, deps.DigestCalculator, deps.Logger),
 			deps.Logger,
 		)
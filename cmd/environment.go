@@ -1,18 +1,24 @@
 package cmd
 
 import (
+	cmdconf "github.com/cloudfoundry/bosh-cli/v7/cmd/config"
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshuaa "github.com/cloudfoundry/bosh-cli/v7/uaa"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
 )
 
 type EnvironmentCmd struct {
 	ui       boshui.UI
 	director boshdir.Director
+
+	// Credentials are fetched after talking to the Director
+	// since access token might have been refreshed and saved
+	credsFunc func() cmdconf.Creds
 }
 
-func NewEnvironmentCmd(ui boshui.UI, director boshdir.Director) EnvironmentCmd {
-	return EnvironmentCmd{ui: ui, director: director}
+func NewEnvironmentCmd(ui boshui.UI, director boshdir.Director, credsFunc func() cmdconf.Creds) EnvironmentCmd {
+	return EnvironmentCmd{ui: ui, director: director, credsFunc: credsFunc}
 }
 
 func (c EnvironmentCmd) Run(opts EnvironmentOpts) error {
@@ -21,7 +27,13 @@ func (c EnvironmentCmd) Run(opts EnvironmentOpts) error {
 		return err
 	}
 
-	InfoTable{info, c.ui}.Print()
+	infoTable := InfoTable{Info: info, UI: c.ui}
+
+	if c.credsFunc != nil {
+		infoTable.TokenExpiresAt = boshuaa.NewTokenExpiryFromValue(c.credsFunc().AccessToken)
+	}
+
+	infoTable.Print()
 
 	if opts.Details {
 		certificatesInfo, err := c.director.CertificateExpiry()
@@ -1,13 +1,17 @@
 package cmd_test
 
 import (
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/fatih/color"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/cloudfoundry/bosh-cli/v7/cmd"
+	cmdconf "github.com/cloudfoundry/bosh-cli/v7/cmd/config"
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
@@ -19,6 +23,7 @@ var _ = Describe("EnvironmentCmd", func() {
 	var (
 		ui              *fakeui.FakeUI
 		director        *fakedir.FakeDirector
+		creds           cmdconf.Creds
 		command         cmd.EnvironmentCmd
 		environmentOpts opts.EnvironmentOpts
 	)
@@ -26,7 +31,8 @@ var _ = Describe("EnvironmentCmd", func() {
 	BeforeEach(func() {
 		ui = &fakeui.FakeUI{}
 		director = &fakedir.FakeDirector{}
-		command = cmd.NewEnvironmentCmd(ui, director)
+		creds = cmdconf.Creds{}
+		command = cmd.NewEnvironmentCmd(ui, director, func() cmdconf.Creds { return creds })
 		environmentOpts = opts.EnvironmentOpts{}
 	})
 
@@ -150,6 +156,31 @@ var _ = Describe("EnvironmentCmd", func() {
 					boshtbl.NewValueStrings([]string{"feature-1: enabled"}),
 				))
 			})
+
+			It("shows token expiration when logged in with a token", func() {
+				expiresAt := time.Unix(time.Now().Add(time.Hour).Unix(), 0).UTC()
+				claims := fmt.Sprintf(`{"user_name":"admin","exp":%d}`, expiresAt.Unix())
+				creds.AccessToken = "seg." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".seg"
+
+				director.InfoReturns(boshdir.Info{User: "admin"}, nil)
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table.Header).To(ContainElement(boshtbl.NewHeader("Token Expires")))
+				Expect(ui.Table.Rows[0]).To(ContainElement(boshtbl.NewValueTime(expiresAt)))
+			})
+
+			It("does not show token expiration when token is opaque", func() {
+				creds.AccessToken = "opaque-token"
+
+				director.InfoReturns(boshdir.Info{User: "admin"}, nil)
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table.Header).ToNot(ContainElement(boshtbl.NewHeader("Token Expires")))
+			})
 		})
 
 		Context("When details flag is passed", func() {
@@ -1,22 +1,66 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
 	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
 )
 
+const eventsFollowInterval = 5 * time.Second
+
 type EventsCmd struct {
-	ui       boshui.UI
-	director boshdir.Director
+	ui          boshui.UI
+	director    boshdir.Director
+	timeService clock.Clock
+}
+
+// eventExport uses same keys as the Director API so that exported events can be
+// processed the same way as events retrieved directly from the Director
+type eventExport struct {
+	ID             string                 `json:"id"`
+	ParentID       string                 `json:"parent_id,omitempty"`
+	Timestamp      int64                  `json:"timestamp"`
+	Time           string                 `json:"time"`
+	User           string                 `json:"user"`
+	Action         string                 `json:"action"`
+	ObjectType     string                 `json:"object_type"`
+	ObjectName     string                 `json:"object_name"`
+	TaskID         string                 `json:"task"`
+	DeploymentName string                 `json:"deployment"`
+	Instance       string                 `json:"instance"`
+	Context        map[string]interface{} `json:"context"`
+	Error          string                 `json:"error"`
 }
 
-func NewEventsCmd(ui boshui.UI, director boshdir.Director) EventsCmd {
-	return EventsCmd{ui: ui, director: director}
+func NewEventsCmd(ui boshui.UI, director boshdir.Director, timeService clock.Clock) EventsCmd {
+	return EventsCmd{ui: ui, director: director, timeService: timeService}
 }
 
 func (c EventsCmd) Run(opts EventsOpts) error {
+	switch opts.Format {
+	case "text", "ndjson", "csv":
+	default:
+		return bosherr.Errorf("Unknown format '%s', expected 'text', 'ndjson' or 'csv'", opts.Format)
+	}
+
+	if opts.Follow && opts.Format == "text" {
+		return bosherr.Errorf("Expected '--follow' to be used with 'ndjson' or 'csv' format")
+	}
+
+	if opts.Since.IsSet() && len(opts.After) > 0 {
+		return bosherr.Errorf("Expected only one of '--since' or '--after'")
+	}
+
 	filter := boshdir.EventsFilter{
 		BeforeID:   opts.BeforeID,
 		Before:     opts.Before,
@@ -30,11 +74,275 @@ func (c EventsCmd) Run(opts EventsOpts) error {
 		ObjectName: opts.ObjectName,
 	}
 
-	events, err := c.director.Events(filter)
+	startedAt := c.timeService.Now()
+
+	if opts.Since.IsSet() {
+		filter.After = formatEventsFilterTime(startedAt.Add(-opts.Since.Duration()))
+	}
+
+	if opts.Format == "text" {
+		var events []boshdir.Event
+
+		err := fetchEvents(c.director, filter, opts.All, "", func(page []boshdir.Event) error {
+			events = append(events, page...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		c.printTable(events)
+
+		return nil
+	}
+
+	var newest boshdir.Event
+
+	if opts.All {
+		var err error
+
+		newest, err = c.exportAll(filter, opts.Format)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := fetchEvents(c.director, filter, false, "", func(page []boshdir.Event) error {
+			if len(page) > 0 {
+				newest = page[0]
+			}
+
+			return c.export(page, opts.Format, true)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !opts.Follow {
+		return nil
+	}
+
+	return c.follow(filter, newest, startedAt, opts.Format)
+}
+
+// exportAll exports all events oldest first without keeping them in memory. Since Director returns
+// pages newest first, IDs bounding older pages are collected before these pages are requested again
+// in reverse order. Newest page is kept so that events created in the meantime do not shift it.
+func (c EventsCmd) exportAll(filter boshdir.EventsFilter, format string) (boshdir.Event, error) {
+	newestPage, err := c.director.Events(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each older page consists of events before the oldest event of the newer page
+	var beforeIDs []string
+
+	for page := newestPage; len(page) > 0; {
+		filter.BeforeID = page[len(page)-1].ID()
+
+		page, err = c.director.Events(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page) > 0 {
+			beforeIDs = append(beforeIDs, filter.BeforeID)
+		}
+	}
+
+	withHeader := true
+
+	for i := len(beforeIDs) - 1; i >= 0; i-- {
+		filter.BeforeID = beforeIDs[i]
+
+		page, err := c.director.Events(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		// Older events were already exported with the older page
+		if i+1 < len(beforeIDs) {
+			for j, e := range page {
+				if isNewerEventID(beforeIDs[i+1], e.ID()) {
+					page = page[:j]
+					break
+				}
+			}
+		}
+
+		err = c.export(page, format, withHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		withHeader = false
+	}
+
+	err = c.export(newestPage, format, withHeader)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	if len(newestPage) == 0 {
+		return nil, nil
+	}
+
+	return newestPage[0], nil
+}
+
+// follow polls for events newer than the last seen event until interrupted or Director request fails;
+// events are filtered by ID since multiple events can have the same timestamp
+func (c EventsCmd) follow(filter boshdir.EventsFilter, newest boshdir.Event, startedAt time.Time, format string) error {
+	filter.BeforeID = ""
+	filter.Before = ""
+	filter.After = formatEventsFilterTime(startedAt)
+
+	var lastID string
+
+	if newest != nil {
+		lastID = newest.ID()
+		filter.After = formatEventsFilterTime(newest.Timestamp().Add(-time.Second))
+	}
+
+	for {
+		c.timeService.Sleep(eventsFollowInterval)
+
+		// Events appearing between polls are few, hence exported all at once oldest first
+		var newEvents []boshdir.Event
+
+		err := fetchEvents(c.director, filter, true, lastID, func(page []boshdir.Event) error {
+			newEvents = append(newEvents, page...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(newEvents) == 0 {
+			continue
+		}
+
+		err = c.export(newEvents, format, false)
+		if err != nil {
+			return err
+		}
+
+		lastID = newEvents[0].ID()
+		filter.After = formatEventsFilterTime(newEvents[0].Timestamp().Add(-time.Second))
+	}
+}
+
+// fetchEvents passes pages of events newest first to pageFn; when all is set it keeps requesting
+// older pages until Director returns no more events or afterID is reached
+func fetchEvents(director boshdir.Director, filter boshdir.EventsFilter, all bool, afterID string, pageFn func([]boshdir.Event) error) error {
+	for first := true; ; first = false {
+		events, err := director.Events(filter)
+		if err != nil {
+			return err
+		}
+
+		page := events
+
+		for i, e := range events {
+			if len(afterID) > 0 && !isNewerEventID(e.ID(), afterID) {
+				page = events[:i]
+				break
+			}
+		}
+
+		if len(page) > 0 || first {
+			err = pageFn(page)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !all || len(page) == 0 || len(page) < len(events) {
+			return nil
+		}
+
+		filter.BeforeID = events[len(events)-1].ID()
+	}
+}
+
+// export prints given events oldest first so that output can be appended to as new events appear
+func (c EventsCmd) export(events []boshdir.Event, format string, withHeader bool) error {
+	buf := &bytes.Buffer{}
+
+	var csvWriter *csv.Writer
+
+	if format == "csv" {
+		csvWriter = csv.NewWriter(buf)
+
+		if withHeader {
+			err := csvWriter.Write([]string{"id", "parent_id", "time", "user", "action", "object_type",
+				"object_name", "task", "deployment", "instance", "context", "error"})
+			if err != nil {
+				return bosherr.WrapError(err, "Writing events")
+			}
+		}
+	}
+
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+
+		record := eventExport{
+			ID:             e.ID(),
+			ParentID:       e.ParentID(),
+			Timestamp:      e.Timestamp().Unix(),
+			Time:           e.Timestamp().UTC().Format(time.RFC3339),
+			User:           e.User(),
+			Action:         e.Action(),
+			ObjectType:     e.ObjectType(),
+			ObjectName:     e.ObjectName(),
+			TaskID:         e.TaskID(),
+			DeploymentName: e.DeploymentName(),
+			Instance:       e.Instance(),
+			Context:        e.Context(),
+			Error:          e.Error(),
+		}
+
+		if format == "ndjson" {
+			line, err := json.Marshal(record)
+			if err != nil {
+				return bosherr.WrapError(err, "Marshaling event")
+			}
+
+			buf.Write(line)
+			buf.WriteString("\n")
+
+			continue
+		}
+
+		context, err := json.Marshal(record.Context)
+		if err != nil {
+			return bosherr.WrapError(err, "Marshaling event context")
+		}
+
+		err = csvWriter.Write([]string{record.ID, record.ParentID, record.Time, record.User, record.Action,
+			record.ObjectType, record.ObjectName, record.TaskID, record.DeploymentName, record.Instance,
+			string(context), record.Error})
+		if err != nil {
+			return bosherr.WrapError(err, "Writing events")
+		}
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+
+		if err := csvWriter.Error(); err != nil {
+			return bosherr.WrapError(err, "Writing events")
+		}
+	}
+
+	if buf.Len() > 0 {
+		c.ui.PrintBlock(buf.Bytes())
+	}
+
+	return nil
+}
+
+func (c EventsCmd) printTable(events []boshdir.Event) {
 	table := boshtbl.Table{
 		Content: "events",
 		Header: []boshtbl.Header{
@@ -75,6 +383,21 @@ func (c EventsCmd) Run(opts EventsOpts) error {
 	}
 
 	c.ui.PrintTable(table)
+}
 
-	return nil
+// isNewerEventID compares event IDs numerically since Director assigns them sequentially
+func isNewerEventID(id, otherID string) bool {
+	idNum, err1 := strconv.ParseInt(id, 10, 64)
+	otherNum, err2 := strconv.ParseInt(otherID, 10, 64)
+
+	if err1 != nil || err2 != nil {
+		return id > otherID
+	}
+
+	return idNum > otherNum
+}
+
+// formatEventsFilterTime uses format accepted by Director for after_time and before_time
+func formatEventsFilterTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
 }
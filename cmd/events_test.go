@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"code.cloudfoundry.org/clock/fakeclock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -17,16 +18,18 @@ import (
 
 var _ = Describe("EventsCmd", func() {
 	var (
-		ui       *fakeui.FakeUI
-		director *fakedir.FakeDirector
-		command  cmd.EventsCmd
-		events   []boshdir.Event
+		ui          *fakeui.FakeUI
+		director    *fakedir.FakeDirector
+		timeService *fakeclock.FakeClock
+		command     cmd.EventsCmd
+		events      []boshdir.Event
 	)
 
 	BeforeEach(func() {
 		ui = &fakeui.FakeUI{}
 		director = &fakedir.FakeDirector{}
-		command = cmd.NewEventsCmd(ui, director)
+		timeService = fakeclock.NewFakeClock(time.Date(2020, time.May, 10, 12, 0, 0, 0, time.UTC))
+		command = cmd.NewEventsCmd(ui, director, timeService)
 		events = []boshdir.Event{
 			&fakedir.FakeEvent{
 				IDStub:        func() string { return "4" },
@@ -67,7 +70,7 @@ var _ = Describe("EventsCmd", func() {
 			eventsOpts opts.EventsOpts
 		)
 		BeforeEach(func() {
-			eventsOpts = opts.EventsOpts{}
+			eventsOpts = opts.EventsOpts{Format: "text"}
 		})
 
 		It("lists events", func() {
@@ -164,5 +167,187 @@ var _ = Describe("EventsCmd", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-err"))
 		})
+
+		Context("when paging and exporting events", func() {
+			newEvent := func(id string, ts time.Time) boshdir.Event {
+				return &fakedir.FakeEvent{
+					IDStub:             func() string { return id },
+					TimestampStub:      func() time.Time { return ts },
+					UserStub:           func() string { return "admin" },
+					ActionStub:         func() string { return "update" },
+					ObjectTypeStub:     func() string { return "deployment" },
+					ObjectNameStub:     func() string { return "dep" },
+					TaskIDStub:         func() string { return "t" + id },
+					DeploymentNameStub: func() string { return "dep" },
+					ContextStub:        func() map[string]interface{} { return map[string]interface{}{"a": "b,c"} },
+				}
+			}
+
+			var (
+				now = time.Date(2020, time.May, 10, 12, 0, 0, 0, time.UTC)
+			)
+
+			It("requests events since given duration", func() {
+				eventsOpts.Since = opts.DurationArg(2 * 24 * time.Hour)
+				director.EventsReturns(nil, nil)
+
+				err := command.Run(eventsOpts)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(director.EventsArgsForCall(0)).To(Equal(boshdir.EventsFilter{After: "2020-05-08 12:00:00 UTC"}))
+			})
+
+			It("pages through all events", func() {
+				eventsOpts.All = true
+				eventsOpts.Deployment = "dep"
+
+				director.EventsReturnsOnCall(0, []boshdir.Event{newEvent("30", now), newEvent("29", now)}, nil)
+				director.EventsReturnsOnCall(1, []boshdir.Event{newEvent("28", now)}, nil)
+				director.EventsReturnsOnCall(2, nil, nil)
+
+				err := command.Run(eventsOpts)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(director.EventsCallCount()).To(Equal(3))
+				Expect(director.EventsArgsForCall(0)).To(Equal(boshdir.EventsFilter{Deployment: "dep"}))
+				Expect(director.EventsArgsForCall(1)).To(Equal(boshdir.EventsFilter{Deployment: "dep", BeforeID: "29"}))
+				Expect(director.EventsArgsForCall(2)).To(Equal(boshdir.EventsFilter{Deployment: "dep", BeforeID: "28"}))
+
+				Expect(ui.Table.Rows).To(HaveLen(3))
+				Expect(ui.Table.Rows[2][0]).To(Equal(boshtbl.NewValueString("28")))
+			})
+
+			It("exports events oldest first as NDJSON", func() {
+				eventsOpts.Format = "ndjson"
+				director.EventsReturns([]boshdir.Event{newEvent("2", now), newEvent("1", now.Add(-time.Hour))}, nil)
+
+				err := command.Run(eventsOpts)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Blocks).To(Equal([]string{
+					`{"id":"1","timestamp":1589108400,"time":"2020-05-10T11:00:00Z","user":"admin","action":"update","object_type":"deployment","object_name":"dep","task":"t1","deployment":"dep","instance":"","context":{"a":"b,c"},"error":""}` + "\n" +
+						`{"id":"2","timestamp":1589112000,"time":"2020-05-10T12:00:00Z","user":"admin","action":"update","object_type":"deployment","object_name":"dep","task":"t2","deployment":"dep","instance":"","context":{"a":"b,c"},"error":""}` + "\n",
+				}))
+				Expect(ui.Table).To(Equal(boshtbl.Table{}))
+			})
+
+			It("exports all events oldest first across pages with a single CSV header", func() {
+				eventsOpts.Format = "csv"
+				eventsOpts.All = true
+
+				requested := map[string]int{}
+
+				director.EventsStub = func(filter boshdir.EventsFilter) ([]boshdir.Event, error) {
+					requested[filter.BeforeID]++
+
+					switch filter.BeforeID {
+					case "":
+						return []boshdir.Event{newEvent("6", now), newEvent("5", now)}, nil
+					case "5":
+						if requested["5"] > 1 {
+							// Event 2 of the older page is returned again since event 3 was deleted meanwhile
+							return []boshdir.Event{newEvent("4", now), newEvent("2", now)}, nil
+						}
+						return []boshdir.Event{newEvent("4", now), newEvent("3", now)}, nil
+					case "3":
+						return []boshdir.Event{newEvent("2", now), newEvent("1", now)}, nil
+					default:
+						return nil, nil
+					}
+				}
+
+				err := command.Run(eventsOpts)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(requested).To(Equal(map[string]int{"": 1, "5": 2, "3": 2, "1": 1}))
+
+				row := func(id string) string {
+					return id + `,,2020-05-10T12:00:00Z,admin,update,deployment,dep,t` + id + `,dep,,"{""a"":""b,c""}",` + "\n"
+				}
+
+				Expect(ui.Blocks).To(Equal([]string{
+					"id,parent_id,time,user,action,object_type,object_name,task,deployment,instance,context,error\n" +
+						row("1") + row("2"),
+					row("4"),
+					row("5") + row("6"),
+				}))
+			})
+
+			It("exports events as CSV with header", func() {
+				eventsOpts.Format = "csv"
+				director.EventsReturns([]boshdir.Event{newEvent("1", now)}, nil)
+
+				err := command.Run(eventsOpts)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Blocks).To(Equal([]string{
+					"id,parent_id,time,user,action,object_type,object_name,task,deployment,instance,context,error\n" +
+						`1,,2020-05-10T12:00:00Z,admin,update,deployment,dep,t1,dep,,"{""a"":""b,c""}",` + "\n",
+				}))
+			})
+
+			It("follows new events until events cannot be retrieved", func() {
+				eventsOpts.Format = "csv"
+
+				director.EventsReturnsOnCall(0, []boshdir.Event{newEvent("10", now)}, nil)
+				// First poll returns already seen event
+				director.EventsReturnsOnCall(1, []boshdir.Event{newEvent("10", now)}, nil)
+				// Second poll returns more events than fit into one page
+				director.EventsReturnsOnCall(2, []boshdir.Event{newEvent("13", now.Add(time.Minute)), newEvent("12", now)}, nil)
+				director.EventsReturnsOnCall(3, []boshdir.Event{newEvent("11", now), newEvent("10", now)}, nil)
+				director.EventsReturnsOnCall(4, nil, errors.New("fake-err"))
+
+				eventsOpts.Follow = true
+
+				errCh := make(chan error)
+				go func() { errCh <- command.Run(eventsOpts) }()
+
+				for i := 0; i < 3; i++ {
+					Eventually(timeService.WatcherCount).Should(Equal(1))
+					timeService.WaitForWatcherAndIncrement(5 * time.Second)
+				}
+
+				var err error
+				Eventually(errCh).Should(Receive(&err))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(director.EventsArgsForCall(1)).To(Equal(boshdir.EventsFilter{After: "2020-05-10 11:59:59 UTC"}))
+				Expect(director.EventsArgsForCall(3)).To(Equal(boshdir.EventsFilter{After: "2020-05-10 11:59:59 UTC", BeforeID: "12"}))
+				Expect(director.EventsArgsForCall(4)).To(Equal(boshdir.EventsFilter{After: "2020-05-10 12:00:59 UTC"}))
+
+				Expect(ui.Blocks).To(HaveLen(2))
+				Expect(ui.Blocks[1]).To(Equal(
+					`11,,2020-05-10T12:00:00Z,admin,update,deployment,dep,t11,dep,,"{""a"":""b,c""}",` + "\n" +
+						`12,,2020-05-10T12:00:00Z,admin,update,deployment,dep,t12,dep,,"{""a"":""b,c""}",` + "\n" +
+						`13,,2020-05-10T12:01:00Z,admin,update,deployment,dep,t13,dep,,"{""a"":""b,c""}",` + "\n",
+				))
+			})
+
+			It("returns error if follow is used with text format", func() {
+				eventsOpts.Follow = true
+
+				err := command.Run(eventsOpts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected '--follow' to be used with 'ndjson' or 'csv' format"))
+				Expect(director.EventsCallCount()).To(Equal(0))
+			})
+
+			It("returns error if both since and after are set", func() {
+				eventsOpts.Since = opts.DurationArg(time.Hour)
+				eventsOpts.After = "2020-05-08 12:00:00"
+
+				err := command.Run(eventsOpts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected only one of '--since' or '--after'"))
+			})
+
+			It("returns error if format is unknown", func() {
+				eventsOpts.Format = "xml"
+
+				err := command.Run(eventsOpts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Unknown format 'xml', expected 'text', 'ndjson' or 'csv'"))
+			})
+		})
 	})
 })
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshmetrics "github.com/cloudfoundry/bosh-cli/v7/director/metrics"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+// Each scrape fetches VM vitals which creates a director task per deployment
+const exportMetricsMinInterval = 10 * time.Second
+
+type ExportMetricsCmd struct {
+	ui          boshui.UI
+	director    boshdir.Director
+	timeService clock.Clock
+	logger      boshlog.Logger
+}
+
+func NewExportMetricsCmd(ui boshui.UI, director boshdir.Director, timeService clock.Clock, logger boshlog.Logger) ExportMetricsCmd {
+	return ExportMetricsCmd{ui: ui, director: director, timeService: timeService, logger: logger}
+}
+
+func (c ExportMetricsCmd) Run(opts ExportMetricsOpts) error {
+	interval := opts.Interval.Duration()
+
+	if interval < exportMetricsMinInterval {
+		return bosherr.Errorf("Expected interval to be at least %s", exportMetricsMinInterval)
+	}
+
+	listener, err := net.Listen("tcp", opts.Listen)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Listening on '%s'", opts.Listen)
+	}
+
+	exporter := boshmetrics.NewExporter(boshmetrics.NewCollector(c.director, c.logger), c.timeService)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	stop := make(chan struct{})
+	pollErr := make(chan error, 1)
+
+	go func() {
+		err := exporter.Poll(interval, stop)
+		if err != nil {
+			_ = server.Close()
+		}
+		pollErr <- err
+	}()
+
+	c.ui.PrintLinef("Serving metrics on 'http://%s/metrics' collected every %s", listener.Addr(), interval)
+
+	serveErr := server.Serve(listener)
+
+	close(stop)
+
+	err = <-pollErr
+	if err != nil {
+		return bosherr.WrapError(err, "Collecting metrics")
+	}
+
+	return bosherr.WrapError(serveErr, "Serving metrics")
+}
@@ -0,0 +1,81 @@
+package cmd_test
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-cli/v7/cmd"
+	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("ExportMetricsCmd", func() {
+	var (
+		ui         *fakeui.FakeUI
+		director   *fakedir.FakeDirector
+		command    cmd.ExportMetricsCmd
+		exportOpts opts.ExportMetricsOpts
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		director = &fakedir.FakeDirector{}
+		timeService := fakeclock.NewFakeClock(time.Date(2020, time.May, 1, 10, 0, 0, 0, time.UTC))
+		command = cmd.NewExportMetricsCmd(ui, director, timeService, boshlog.NewLogger(boshlog.LevelNone))
+		exportOpts = opts.ExportMetricsOpts{Listen: "127.0.0.1:0", Interval: opts.DurationArg(time.Minute)}
+	})
+
+	It("serves collected metrics over HTTP", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+
+		exportOpts.Listen = listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+
+		go func() {
+			defer GinkgoRecover()
+			_ = command.Run(exportOpts)
+		}()
+
+		url := "http://" + exportOpts.Listen + "/metrics"
+
+		body := func() string {
+			resp, err := http.Get(url)
+			if err != nil {
+				return ""
+			}
+			defer resp.Body.Close()
+
+			bytes, err := io.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+
+			return string(bytes)
+		}
+
+		Eventually(body).Should(ContainSubstring("bosh_orphaned_vms 0\n"))
+		Expect(director.ListDeploymentsCallCount()).To(Equal(1))
+	})
+
+	It("returns error if interval is too short", func() {
+		exportOpts.Interval = opts.DurationArg(5 * time.Second)
+
+		Expect(command.Run(exportOpts)).To(MatchError("Expected interval to be at least 10s"))
+		Expect(director.ListDeploymentsCallCount()).To(Equal(0))
+	})
+
+	It("returns error if address cannot be listened on", func() {
+		exportOpts.Listen = "127.0.0.1:not-a-port"
+
+		err := command.Run(exportOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Listening on '127.0.0.1:not-a-port'"))
+	})
+})
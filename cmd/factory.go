@@ -63,6 +63,14 @@ func (f Factory) New(args []string) (Cmd, error) {
 			opts.Deployment = boshOpts.DeploymentOpt
 		}
 
+		if opts, ok := command.(*HistoryOpts); ok {
+			opts.Deployment = boshOpts.DeploymentOpt
+		}
+
+		if opts, ok := command.(*TopOpts); ok {
+			opts.Deployment = boshOpts.DeploymentOpt
+		}
+
 		if opts, ok := command.(*VMsOpts); ok {
 			opts.Deployment = boshOpts.DeploymentOpt
 		}
@@ -97,6 +105,7 @@ func (f Factory) New(args []string) (Cmd, error) {
 	boshOpts.SCP.GatewayFlags.UUIDGen = f.deps.UUIDGen
 	boshOpts.Logs.GatewayFlags.UUIDGen = f.deps.UUIDGen
 	boshOpts.Pcap.GatewayFlags.UUIDGen = f.deps.UUIDGen
+	boshOpts.Top.GatewayFlags.UUIDGen = f.deps.UUIDGen
 
 	helpText := bytes.NewBufferString("")
 	parser.WriteHelp(helpText)
@@ -274,6 +274,26 @@ var _ = Describe("Factory", func() {
 		})
 	})
 
+	Describe("history command", func() {
+		It("is passed the deployment flag", func() {
+			cmd, err := factory.New([]string{"history", "--deployment", "deployment"})
+			Expect(err).ToNot(HaveOccurred())
+
+			historyOpts := cmd.Opts.(*opts.HistoryOpts)
+			Expect(historyOpts.Deployment).To(Equal("deployment"))
+		})
+	})
+
+	Describe("top command", func() {
+		It("is passed the deployment flag", func() {
+			cmd, err := factory.New([]string{"top", "--deployment", "deployment"})
+			Expect(err).ToNot(HaveOccurred())
+
+			topOpts := cmd.Opts.(*opts.TopOpts)
+			Expect(topOpts.Deployment).To(Equal("deployment"))
+		})
+	})
+
 	Describe("vms command", func() {
 		It("is passed the deployment flag", func() {
 			cmd, err := factory.New([]string{"vms", "--deployment", "deployment"})
@@ -370,11 +390,18 @@ var _ = Describe("Factory", func() {
 			boshOpts.RunErrand = opts.RunErrandOpts{}
 			boshOpts.Logs = opts.LogsOpts{}
 			boshOpts.Interpolate = opts.InterpolateOpts{}
+			boshOpts.ManifestSchema = opts.ManifestSchemaOpts{}
+			boshOpts.DiffManifests = opts.DiffManifestsOpts{}
+			boshOpts.Events = opts.EventsOpts{}
+			boshOpts.History = opts.HistoryOpts{}
+			boshOpts.Top = opts.TopOpts{}
+			boshOpts.ExportMetrics = opts.ExportMetricsOpts{}
 			boshOpts.InitRelease = opts.InitReleaseOpts{}
 			boshOpts.ResetRelease = opts.ResetReleaseOpts{}
 			boshOpts.GenerateJob = opts.GenerateJobOpts{}
 			boshOpts.GeneratePackage = opts.GeneratePackageOpts{}
 			boshOpts.VendorPackage = opts.VendorPackageOpts{}
+			boshOpts.LintRelease = opts.LintReleaseOpts{}
 			boshOpts.CreateRelease = opts.CreateReleaseOpts{}
 			boshOpts.FinalizeRelease = opts.FinalizeReleaseOpts{}
 			boshOpts.Blobs = opts.BlobsOpts{}
@@ -394,6 +421,8 @@ var _ = Describe("Factory", func() {
 			boshOpts.UpdateConfig = opts.UpdateConfigOpts{}
 			boshOpts.DeleteConfig = opts.DeleteConfigOpts{}
 			boshOpts.Curl = opts.CurlOpts{}
+			boshOpts.DiffReleases = opts.DiffReleasesOpts{}
+			boshOpts.ReleaseSBOM = opts.ReleaseSBOMOpts{}
 			return boshOpts
 		}
 
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshmandiff "github.com/cloudfoundry/bosh-cli/v7/director/manifestdiff"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+type GenerateOpsCmd struct {
+	ui boshui.UI
+}
+
+func NewGenerateOpsCmd(ui boshui.UI) GenerateOpsCmd {
+	return GenerateOpsCmd{ui: ui}
+}
+
+func (c GenerateOpsCmd) Run(opts GenerateOpsOpts) error {
+	opDefs, err := boshmandiff.GenerateOps(opts.Args.Base.Bytes, opts.Args.Modified.Bytes)
+	if err != nil {
+		return bosherr.WrapError(err, "Generating operations")
+	}
+
+	bytes, err := yaml.Marshal(opDefs)
+	if err != nil {
+		return bosherr.WrapError(err, "Marshaling operations")
+	}
+
+	c.ui.PrintBlock(bytes)
+
+	return nil
+}
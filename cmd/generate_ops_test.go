@@ -0,0 +1,79 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("GenerateOpsCmd", func() {
+	var (
+		ui      *fakeui.FakeUI
+		command GenerateOpsCmd
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		command = NewGenerateOpsCmd(ui)
+	})
+
+	Describe("Run", func() {
+		It("prints ops file that turns base manifest into modified manifest", func() {
+			err := command.Run(GenerateOpsOpts{
+				Args: GenerateOpsArgs{
+					Base: FileBytesArg{Bytes: []byte(`
+instance_groups:
+- name: web
+  instances: 1
+  vm_type: small
+`)},
+					Modified: FileBytesArg{Bytes: []byte(`
+instance_groups:
+- name: web
+  instances: 2
+- name: worker
+  instances: 1
+`)},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(Equal([]string{`- type: replace
+  path: /instance_groups/name=web/instances
+  value: 2
+- type: remove
+  path: /instance_groups/name=web/vm_type
+- type: replace
+  path: /instance_groups/-
+  value:
+    name: worker
+    instances: 1
+`}))
+		})
+
+		It("prints empty ops file when manifests are equal", func() {
+			err := command.Run(GenerateOpsOpts{
+				Args: GenerateOpsArgs{
+					Base:     FileBytesArg{Bytes: []byte("name: dep")},
+					Modified: FileBytesArg{Bytes: []byte("name: dep")},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ui.Blocks).To(Equal([]string{"[]\n"}))
+		})
+
+		It("returns error if manifest cannot be parsed", func() {
+			err := command.Run(GenerateOpsOpts{
+				Args: GenerateOpsArgs{
+					Base:     FileBytesArg{Bytes: []byte("name: [")},
+					Modified: FileBytesArg{Bytes: []byte("name: dep")},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Generating operations"))
+		})
+	})
+})
@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+// Configs are not filtered by deployment, so history of all of them is needed to find versions in effect
+const historyConfigsLimit = 1000
+
+type HistoryCmd struct {
+	ui       boshui.UI
+	director boshdir.Director
+}
+
+type historyConfig struct {
+	boshdir.Config
+
+	createdAt time.Time
+}
+
+func NewHistoryCmd(ui boshui.UI, director boshdir.Director) HistoryCmd {
+	return HistoryCmd{ui: ui, director: director}
+}
+
+func (c HistoryCmd) Run(opts HistoryOpts) error {
+	if len(opts.Deployment) == 0 {
+		return bosherr.Error("Expected non-empty deployment name")
+	}
+
+	tasks, err := c.director.RecentTasks(opts.Recent, boshdir.TasksFilter{Deployment: opts.Deployment})
+	if err != nil {
+		return err
+	}
+
+	eventsByTask, err := c.eventsByTask(tasks, opts.Deployment)
+	if err != nil {
+		return err
+	}
+
+	configs, err := c.director.ListConfigs(historyConfigsLimit, boshdir.ConfigsFilter{})
+	if err != nil {
+		return err
+	}
+
+	var history []historyConfig
+
+	for _, config := range configs {
+		createdAt, err := parseConfigTime(config.CreatedAt)
+		if err == nil {
+			history = append(history, historyConfig{Config: config, createdAt: createdAt})
+		}
+	}
+
+	if opts.Diff > 0 {
+		return c.printDiff(opts, tasks, eventsByTask, history)
+	}
+
+	table := boshtbl.Table{
+		Content: "history",
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Task"),
+			boshtbl.NewHeader("Started"),
+			boshtbl.NewHeader("Duration"),
+			boshtbl.NewHeader("User"),
+			boshtbl.NewHeader("Action"),
+			boshtbl.NewHeader("State"),
+			boshtbl.NewHeader("Configs"),
+		},
+		Notes: []string{"Configs show IDs of latest config versions created before each task started"},
+	}
+
+	for _, task := range tasks {
+		var configStrs []string
+
+		inEffect := configsInEffect(history, task.StartedAt())
+
+		for _, key := range sortedConfigKeys(inEffect) {
+			configStrs = append(configStrs, fmt.Sprintf("%s: %s", key, inEffect[key].ID))
+		}
+
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueInt(task.ID()),
+			boshtbl.NewValueTime(task.StartedAt()),
+			boshtbl.NewValueString(c.duration(task)),
+			boshtbl.NewValueString(task.User()),
+			boshtbl.NewValueStrings(c.actions(task, eventsByTask[strconv.Itoa(task.ID())])),
+			boshtbl.NewValueFmt(boshtbl.NewValueString(task.State()), task.IsError()),
+			boshtbl.NewValueStrings(configStrs),
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	return nil
+}
+
+// eventsByTask retrieves all deployment events since the oldest task started
+func (c HistoryCmd) eventsByTask(tasks []boshdir.Task, deployment string) (map[string][]boshdir.Event, error) {
+	result := map[string][]boshdir.Event{}
+
+	if len(tasks) == 0 {
+		return result, nil
+	}
+
+	oldest := tasks[0].StartedAt()
+
+	for _, task := range tasks {
+		if task.StartedAt().Before(oldest) {
+			oldest = task.StartedAt()
+		}
+	}
+
+	filter := boshdir.EventsFilter{
+		Deployment: deployment,
+		After:      formatEventsFilterTime(oldest.Add(-time.Second)),
+	}
+
+	err := fetchEvents(c.director, filter, true, "", func(events []boshdir.Event) error {
+		for _, event := range events {
+			result[event.TaskID()] = append(result[event.TaskID()], event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// printDiff shows changes to configs since the previous task and
+// changes to releases and stemcells recorded in deployment events
+func (c HistoryCmd) printDiff(opts HistoryOpts, tasks []boshdir.Task, eventsByTask map[string][]boshdir.Event, history []historyConfig) error {
+	taskIdx := -1
+
+	for i, task := range tasks {
+		if task.ID() == opts.Diff {
+			taskIdx = i
+		}
+	}
+
+	if taskIdx == -1 {
+		return bosherr.Errorf("Expected to find task '%d' in recent tasks of deployment '%s'", opts.Diff, opts.Deployment)
+	}
+
+	var changed bool
+
+	current := configsInEffect(history, tasks[taskIdx].StartedAt())
+	previous := map[string]historyConfig{}
+
+	if taskIdx+1 < len(tasks) {
+		previous = configsInEffect(history, tasks[taskIdx+1].StartedAt())
+	} else {
+		// Without a previous task every config in effect would be reported as added
+		previous = current
+	}
+
+	keys := map[string]struct{}{}
+	for key := range current {
+		keys[key] = struct{}{}
+	}
+	for key := range previous {
+		keys[key] = struct{}{}
+	}
+
+	var sortedKeys []string
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		cur, curFound := current[key]
+		prev, prevFound := previous[key]
+
+		switch {
+		case curFound && prevFound && cur.ID != prev.ID:
+			configDiff, err := c.director.DiffConfigByIDOrContent(prev.ID, nil, cur.ID, nil)
+			if err != nil {
+				return bosherr.WrapErrorf(err, "Diffing config '%s'", key)
+			}
+
+			c.ui.PrintLinef("Config '%s' (ID %s -> %s):", key, prev.ID, cur.ID)
+			NewDiff(configDiff.Diff).Print(c.ui)
+			changed = true
+
+		case curFound && !prevFound:
+			c.ui.PrintLinef("Config '%s' was added (ID %s)", key, cur.ID)
+			changed = true
+
+		case !curFound && prevFound:
+			c.ui.PrintLinef("Config '%s' was deleted (ID %s)", key, prev.ID)
+			changed = true
+		}
+	}
+
+	for _, event := range eventsByTask[strconv.Itoa(opts.Diff)] {
+		before, beforeFound := event.Context()["before"]
+		after, afterFound := event.Context()["after"]
+
+		if event.ObjectType() != "deployment" || !beforeFound || !afterFound {
+			continue
+		}
+
+		beforeBytes, err := yaml.Marshal(before)
+		if err != nil {
+			return bosherr.WrapError(err, "Marshaling deployment event context")
+		}
+
+		afterBytes, err := yaml.Marshal(after)
+		if err != nil {
+			return bosherr.WrapError(err, "Marshaling deployment event context")
+		}
+
+		if string(beforeBytes) == string(afterBytes) {
+			continue
+		}
+
+		configDiff, err := c.director.DiffConfigByIDOrContent("", beforeBytes, "", afterBytes)
+		if err != nil {
+			return bosherr.WrapError(err, "Diffing deployment event context")
+		}
+
+		c.ui.PrintLinef("Deployment '%s' (%s):", event.ObjectName(), event.Action())
+		NewDiff(configDiff.Diff).Print(c.ui)
+		changed = true
+	}
+
+	if !changed {
+		c.ui.PrintLinef("No changes found for task '%d'", opts.Diff)
+	}
+
+	return nil
+}
+
+// actions summarizes events of a task; tasks without events are described by the task itself
+func (c HistoryCmd) actions(task boshdir.Task, events []boshdir.Event) []string {
+	var labels []string
+
+	counts := map[string]int{}
+
+	// Events are newest first
+	for i := len(events) - 1; i >= 0; i-- {
+		label := events[i].Action() + " " + events[i].ObjectType()
+
+		if counts[label] == 0 {
+			labels = append(labels, label)
+		}
+
+		counts[label]++
+	}
+
+	if len(labels) == 0 {
+		return []string{task.Description()}
+	}
+
+	for i, label := range labels {
+		if counts[label] > 1 {
+			labels[i] = fmt.Sprintf("%s (x%d)", label, counts[label])
+		}
+	}
+
+	return labels
+}
+
+func (c HistoryCmd) duration(task boshdir.Task) string {
+	if task.FinishedAt().IsZero() || task.FinishedAt().Before(task.StartedAt()) {
+		return ""
+	}
+
+	return task.FinishedAt().Sub(task.StartedAt()).Round(time.Second).String()
+}
+
+// configsInEffect returns latest version of each config created before given time keyed by 'type/name'
+func configsInEffect(history []historyConfig, at time.Time) map[string]historyConfig {
+	result := map[string]historyConfig{}
+
+	for _, config := range history {
+		if config.createdAt.After(at) {
+			continue
+		}
+
+		key := config.Type + "/" + config.Name
+
+		existing, found := result[key]
+		if !found || configIDLess(existing.ID, config.ID) {
+			result[key] = config
+		}
+	}
+
+	return result
+}
+
+func sortedConfigKeys(configs map[string]historyConfig) []string {
+	var keys []string
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func configIDLess(id, otherID string) bool {
+	idNum, err1 := strconv.Atoi(id)
+	otherNum, err2 := strconv.Atoi(otherID)
+
+	if err1 != nil || err2 != nil {
+		return id < otherID
+	}
+
+	return idNum < otherNum
+}
+
+// parseConfigTime accepts formats used by Director for created_at
+func parseConfigTime(str string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700", time.RFC3339} {
+		t, err := time.Parse(layout, strings.TrimSpace(str))
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, bosherr.Errorf("Expected config creation time '%s' to be a timestamp", str)
+}
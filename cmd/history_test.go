@@ -0,0 +1,245 @@
+package cmd_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-cli/v7/cmd"
+	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("HistoryCmd", func() {
+	var (
+		ui          *fakeui.FakeUI
+		director    *fakedir.FakeDirector
+		command     cmd.HistoryCmd
+		historyOpts opts.HistoryOpts
+
+		t0 = time.Date(2020, time.May, 1, 10, 0, 0, 0, time.UTC)
+	)
+
+	newEvent := func(id, taskID, action, objectType string, context map[string]interface{}) boshdir.Event {
+		return &fakedir.FakeEvent{
+			IDStub:         func() string { return id },
+			TaskIDStub:     func() string { return taskID },
+			ActionStub:     func() string { return action },
+			ObjectTypeStub: func() string { return objectType },
+			ObjectNameStub: func() string { return "dep" },
+			ContextStub:    func() map[string]interface{} { return context },
+		}
+	}
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		director = &fakedir.FakeDirector{}
+		command = cmd.NewHistoryCmd(ui, director)
+		historyOpts = opts.HistoryOpts{Recent: 30, Deployment: "dep"}
+
+		director.RecentTasksReturns([]boshdir.Task{
+			&fakedir.FakeTask{
+				IDStub:         func() int { return 12 },
+				StartedAtStub:  func() time.Time { return t0.Add(4 * time.Hour) },
+				FinishedAtStub: func() time.Time { return t0.Add(4*time.Hour + 90*time.Second) },
+				UserStub:       func() string { return "admin" },
+				StateStub:      func() string { return "done" },
+			},
+			&fakedir.FakeTask{
+				IDStub:          func() int { return 11 },
+				StartedAtStub:   func() time.Time { return t0.Add(2 * time.Hour) },
+				FinishedAtStub:  func() time.Time { return t0.Add(2*time.Hour + 10*time.Second) },
+				UserStub:        func() string { return "ci" },
+				StateStub:       func() string { return "error" },
+				IsErrorStub:     func() bool { return true },
+				DescriptionStub: func() string { return "run errand smoke-tests" },
+			},
+		}, nil)
+
+		director.EventsReturnsOnCall(0, []boshdir.Event{
+			newEvent("103", "12", "update", "deployment", map[string]interface{}{
+				"before": map[string]interface{}{"releases": []interface{}{"app/1"}},
+				"after":  map[string]interface{}{"releases": []interface{}{"app/2"}},
+			}),
+			newEvent("102", "12", "recreate", "instance", nil),
+			newEvent("101", "12", "recreate", "instance", nil),
+		}, nil)
+		director.EventsReturnsOnCall(1, nil, nil)
+
+		director.ListConfigsReturns([]boshdir.Config{
+			{ID: "3", Type: "cloud", Name: "default", CreatedAt: "2020-05-01 13:00:00 UTC"},
+			{ID: "2", Type: "runtime", Name: "dns", CreatedAt: "2020-05-01 11:00:00 UTC"},
+			{ID: "1", Type: "cloud", Name: "default", CreatedAt: "2020-05-01 10:00:00 UTC"},
+			{ID: "4", Type: "cloud", Name: "default", CreatedAt: "2020-05-01 20:00:00 UTC"},
+		}, nil)
+	})
+
+	act := func() error { return command.Run(historyOpts) }
+
+	It("shows tasks of the deployment with actions and configs in effect", func() {
+		err := act()
+		Expect(err).ToNot(HaveOccurred())
+
+		limit, filter := director.RecentTasksArgsForCall(0)
+		Expect(limit).To(Equal(30))
+		Expect(filter).To(Equal(boshdir.TasksFilter{Deployment: "dep"}))
+
+		Expect(director.EventsArgsForCall(0)).To(Equal(boshdir.EventsFilter{
+			Deployment: "dep",
+			After:      "2020-05-01 11:59:59 UTC",
+		}))
+
+		limit, configsFilter := director.ListConfigsArgsForCall(0)
+		Expect(limit).To(Equal(1000))
+		Expect(configsFilter).To(Equal(boshdir.ConfigsFilter{}))
+
+		Expect(ui.Table).To(Equal(boshtbl.Table{
+			Content: "history",
+			Header: []boshtbl.Header{
+				boshtbl.NewHeader("Task"),
+				boshtbl.NewHeader("Started"),
+				boshtbl.NewHeader("Duration"),
+				boshtbl.NewHeader("User"),
+				boshtbl.NewHeader("Action"),
+				boshtbl.NewHeader("State"),
+				boshtbl.NewHeader("Configs"),
+			},
+			Rows: [][]boshtbl.Value{
+				{
+					boshtbl.NewValueInt(12),
+					boshtbl.NewValueTime(t0.Add(4 * time.Hour)),
+					boshtbl.NewValueString("1m30s"),
+					boshtbl.NewValueString("admin"),
+					boshtbl.NewValueStrings([]string{"recreate instance (x2)", "update deployment"}),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("done"), false),
+					boshtbl.NewValueStrings([]string{"cloud/default: 3", "runtime/dns: 2"}),
+				},
+				{
+					boshtbl.NewValueInt(11),
+					boshtbl.NewValueTime(t0.Add(2 * time.Hour)),
+					boshtbl.NewValueString("10s"),
+					boshtbl.NewValueString("ci"),
+					boshtbl.NewValueStrings([]string{"run errand smoke-tests"}),
+					boshtbl.NewValueFmt(boshtbl.NewValueString("error"), true),
+					boshtbl.NewValueStrings([]string{"cloud/default: 1", "runtime/dns: 2"}),
+				},
+			},
+			Notes: []string{"Configs show IDs of latest config versions created before each task started"},
+		}))
+	})
+
+	It("shows what changed in the given task", func() {
+		historyOpts.Diff = 12
+
+		director.DiffConfigByIDOrContentReturnsOnCall(0, boshdir.ConfigDiff{Diff: [][]interface{}{
+			{"vm_types:", nil},
+			{"- name: small", "removed"},
+		}}, nil)
+		director.DiffConfigByIDOrContentReturnsOnCall(1, boshdir.ConfigDiff{Diff: [][]interface{}{
+			{"releases:", nil},
+			{"- app/1", "removed"},
+			{"- app/2", "added"},
+		}}, nil)
+
+		err := act()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(director.DiffConfigByIDOrContentCallCount()).To(Equal(2))
+
+		fromID, fromContent, toID, toContent := director.DiffConfigByIDOrContentArgsForCall(0)
+		Expect(fromID).To(Equal("1"))
+		Expect(fromContent).To(BeNil())
+		Expect(toID).To(Equal("3"))
+		Expect(toContent).To(BeNil())
+
+		fromID, fromContent, toID, toContent = director.DiffConfigByIDOrContentArgsForCall(1)
+		Expect(fromID).To(Equal(""))
+		Expect(string(fromContent)).To(Equal("releases:\n- app/1\n"))
+		Expect(toID).To(Equal(""))
+		Expect(string(toContent)).To(Equal("releases:\n- app/2\n"))
+
+		Expect(ui.Said).To(Equal([]string{
+			"Config 'cloud/default' (ID 1 -> 3):",
+			"  vm_types:\n",
+			"- - name: small\n",
+			"Deployment 'dep' (update):",
+			"  releases:\n",
+			"- - app/1\n",
+			"+ - app/2\n",
+		}))
+		Expect(ui.Table).To(Equal(boshtbl.Table{}))
+	})
+
+	It("reports added configs in the given task", func() {
+		historyOpts.Diff = 12
+
+		director.ListConfigsReturns([]boshdir.Config{
+			{ID: "5", Type: "runtime", Name: "new", CreatedAt: "2020-05-01 13:00:00 UTC"},
+		}, nil)
+
+		err := act()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ui.Said).To(ContainElement("Config 'runtime/new' was added (ID 5)"))
+	})
+
+	It("shows that nothing changed when there are no changes", func() {
+		historyOpts.Diff = 11
+
+		err := act()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(director.DiffConfigByIDOrContentCallCount()).To(Equal(0))
+		Expect(ui.Said).To(Equal([]string{"No changes found for task '11'"}))
+	})
+
+	It("returns error if task is not found in recent tasks", func() {
+		historyOpts.Diff = 5
+
+		err := act()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected to find task '5' in recent tasks of deployment 'dep'"))
+	})
+
+	It("returns error if config diff fails", func() {
+		historyOpts.Diff = 12
+		director.DiffConfigByIDOrContentReturns(boshdir.ConfigDiff{}, errors.New("fake-err"))
+
+		err := act()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Diffing config 'cloud/default'"))
+	})
+
+	It("returns error if deployment is not specified", func() {
+		historyOpts.Deployment = ""
+
+		err := act()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected non-empty deployment name"))
+		Expect(director.RecentTasksCallCount()).To(Equal(0))
+	})
+
+	It("returns error if tasks cannot be retrieved", func() {
+		director.RecentTasksReturns(nil, errors.New("fake-err"))
+
+		err := act()
+		Expect(err).To(MatchError("fake-err"))
+	})
+
+	It("returns error if events cannot be retrieved", func() {
+		director.EventsReturnsOnCall(0, nil, errors.New("fake-err"))
+
+		err := act()
+		Expect(err).To(MatchError("fake-err"))
+	})
+
+	It("returns error if configs cannot be retrieved", func() {
+		director.ListConfigsReturns(nil, errors.New("fake-err"))
+
+		err := act()
+		Expect(err).To(MatchError("fake-err"))
+	})
+})
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
@@ -12,6 +13,9 @@ import (
 type InfoTable struct {
 	Info boshdir.Info
 	UI   boshui.UI
+
+	// Only shown when known (i.e. user is logged in with a token)
+	TokenExpiresAt time.Time
 }
 
 func (t InfoTable) Print() {
@@ -72,6 +76,12 @@ func (t InfoTable) Print() {
 		})
 	}
 
+	if !t.TokenExpiresAt.IsZero() {
+		table = table.AddColumn("Token Expires", []boshtbl.Value{
+			boshtbl.NewValueTime(t.TokenExpiresAt),
+		})
+	}
+
 	t.UI.PrintTable(table)
 }
 
@@ -1,31 +1,70 @@
 package cmd
 
 import (
+	"fmt"
+	"path/filepath"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	"github.com/cppforlife/go-patch/patch"
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshpropcheck "github.com/cloudfoundry/bosh-cli/v7/director/propcheck"
 	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
 )
 
 type InterpolateCmd struct {
-	ui boshui.UI
+	ui             boshui.UI
+	propsValidator boshpropcheck.Validator
+	fs             boshsys.FileSystem
 }
 
-func NewInterpolateCmd(ui boshui.UI) InterpolateCmd {
-	return InterpolateCmd{ui: ui}
+func NewInterpolateCmd(ui boshui.UI, propsValidator boshpropcheck.Validator, fs boshsys.FileSystem) InterpolateCmd {
+	return InterpolateCmd{ui: ui, propsValidator: propsValidator, fs: fs}
 }
 
 func (c InterpolateCmd) Run(opts InterpolateOpts) error {
-	tpl := boshtpl.NewTemplate(opts.Args.Manifest.Bytes)
-
 	vars := opts.VarFlags.AsVariables()
+
+	tplBytes, err := c.evaluateTemplateEngine(opts, vars)
+	if err != nil {
+		return err
+	}
+
+	tpl := boshtpl.NewTemplate(tplBytes)
 	op := opts.OpsFlags.AsOp()
 	evalOpts := boshtpl.EvaluateOpts{
 		ExpectAllKeys:     opts.VarErrors,
 		ExpectAllVarsUsed: opts.VarErrorsUnused,
 	}
 
+	if opts.Explain || opts.Blame.IsSet() {
+		return c.explain(tpl, opts)
+	}
+
+	if len(opts.Validate) > 0 || opts.ValidateProperties {
+		bytes, err := tpl.Evaluate(vars, op, boshtpl.EvaluateOpts{})
+		if err != nil {
+			return err
+		}
+
+		if len(opts.Validate) > 0 {
+			err = validateManifest(opts.Validate, bytes, c.ui)
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.ValidateProperties {
+			err = validateProperties(c.propsValidator, bytes, c.ui)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	if opts.Path.IsSet() {
 		evalOpts.PostVarSubstitutionOp = patch.FindOp{Path: opts.Path}
 
@@ -42,3 +81,128 @@ func (c InterpolateCmd) Run(opts InterpolateOpts) error {
 
 	return nil
 }
+
+// evaluateTemplateEngine resolves directives before operations so that
+// operations and variables apply to a plain manifest
+func (c InterpolateCmd) evaluateTemplateEngine(opts InterpolateOpts, vars boshtpl.Variables) ([]byte, error) {
+	switch opts.TemplateEngine {
+	case "":
+		return opts.Args.Manifest.Bytes, nil
+
+	case boshtpl.TemplateEngineLite:
+		dir := "."
+		if len(opts.Args.Manifest.Path) > 0 {
+			dir = filepath.Dir(opts.Args.Manifest.Path)
+		}
+
+		bytes, err := boshtpl.NewLiteEngine(c.fs).Evaluate(opts.Args.Manifest.Bytes, dir, vars)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Evaluating template engine directives")
+		}
+
+		return bytes, nil
+
+	default:
+		return nil, bosherr.Errorf("Unknown template engine '%s', expected '%s'", opts.TemplateEngine, boshtpl.TemplateEngineLite)
+	}
+}
+
+// explain shows values before variables are interpolated so that secrets are not revealed
+func (c InterpolateCmd) explain(tpl boshtpl.Template, opts InterpolateOpts) error {
+	ops := opts.OpsFlags.AsTracedOps()
+
+	if opts.Explain {
+		traces, err := tpl.TraceOps(ops)
+
+		c.printTraces(traces)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Blame.IsSet() {
+		blame, err := tpl.Blame(ops, opts.Blame)
+		if err != nil {
+			return err
+		}
+
+		if !blame.Found && blame.Op == nil && len(blame.Vars) == 0 {
+			return bosherr.Errorf("Expected to find '%s' in template after applying operations", opts.Blame.String())
+		}
+
+		c.printBlame(opts.Blame, blame)
+	}
+
+	return nil
+}
+
+func (c InterpolateCmd) printTraces(traces []boshtpl.OpTrace) {
+	table := boshtbl.Table{
+		Content: "operations",
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("File"),
+			boshtbl.NewHeader("Index"),
+			boshtbl.NewHeader("Type"),
+			boshtbl.NewHeader("Path"),
+			boshtbl.NewHeader("Matched"),
+			boshtbl.NewHeader("Status"),
+			boshtbl.NewHeader("Before"),
+			boshtbl.NewHeader("After"),
+		},
+		Notes: []string{"Values are shown before variables are interpolated"},
+	}
+
+	for _, trace := range traces {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(trace.File),
+			boshtbl.NewValueInt(trace.Index),
+			boshtbl.NewValueString(trace.Type),
+			boshtbl.NewValueString(trace.Path),
+			boshtbl.NewValueBool(trace.Matched),
+			boshtbl.NewValueString(trace.Status),
+			tracedValue(trace.Before, trace.BeforeFound),
+			tracedValue(trace.After, trace.AfterFound),
+		})
+	}
+
+	c.ui.PrintTable(table)
+}
+
+func (c InterpolateCmd) printBlame(path patch.Pointer, blame boshtpl.Blame) {
+	setBy := "template"
+
+	if blame.Op != nil {
+		setBy = fmt.Sprintf("operation [%d] in %s (%s %s)", blame.Op.Index, blame.Op.File, blame.Op.Type, blame.Op.Path)
+	}
+
+	table := boshtbl.Table{
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Path"),
+			boshtbl.NewHeader("Set by"),
+			boshtbl.NewHeader("Variables"),
+			boshtbl.NewHeader("Value"),
+		},
+		Rows: [][]boshtbl.Value{{
+			boshtbl.NewValueString(path.String()),
+			boshtbl.NewValueString(setBy),
+			boshtbl.NewValueStrings(blame.Vars),
+			tracedValue(blame.Value, blame.Found),
+		}},
+		Transpose: true,
+	}
+
+	c.ui.PrintTable(table)
+}
+
+func tracedValue(val interface{}, found bool) boshtbl.Value {
+	if !found {
+		return boshtbl.ValueNone{}
+	}
+
+	if val == nil {
+		return boshtbl.NewValueString("null")
+	}
+
+	return boshtbl.NewValueInterface(val)
+}
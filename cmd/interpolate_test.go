@@ -1,25 +1,33 @@
 package cmd_test
 
 import (
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	"github.com/cppforlife/go-patch/patch"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/cloudfoundry/bosh-cli/v7/cmd"
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakepropcheck "github.com/cloudfoundry/bosh-cli/v7/director/propcheck/propcheckfakes"
+	boshsch "github.com/cloudfoundry/bosh-cli/v7/director/schema"
 	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
 	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
 )
 
 var _ = Describe("InterpolateCmd", func() {
 	var (
-		ui      *fakeui.FakeUI
-		command cmd.InterpolateCmd
+		ui             *fakeui.FakeUI
+		propsValidator *fakepropcheck.FakeValidator
+		fs             *fakesys.FakeFileSystem
+		command        cmd.InterpolateCmd
 	)
 
 	BeforeEach(func() {
 		ui = &fakeui.FakeUI{}
-		command = cmd.NewInterpolateCmd(ui)
+		propsValidator = &fakepropcheck.FakeValidator{}
+		fs = fakesys.NewFakeFileSystem()
+		command = cmd.NewInterpolateCmd(ui, propsValidator, fs)
 	})
 
 	Describe("Run", func() {
@@ -149,5 +157,257 @@ var _ = Describe("InterpolateCmd", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("Expected to use variables: name3"))
 		})
+
+		Context("when validate flag is set", func() {
+			BeforeEach(func() {
+				interpolateOpts.Validate = "cloud-config"
+			})
+
+			It("shows manifest and warnings if it does not have structural errors", func() {
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{
+					Bytes: []byte("vm_types:\n- name: ((name))\n  cloud_propertes: {}\n"),
+				}
+
+				interpolateOpts.VarKVs = []boshtpl.VarKV{{Name: "name", Value: "small"}}
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Blocks).To(Equal([]string{"vm_types:\n- cloud_propertes: {}\n  name: small\n"}))
+				Expect(ui.Errors).To(Equal([]string{
+					"Warning: '/vm_types/name=small/cloud_propertes': Unknown key 'cloud_propertes' (did you mean 'cloud_properties'?)",
+				}))
+			})
+
+			It("validates whole manifest even if path is given", func() {
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{
+					Bytes: []byte("vm_types: {}\ndisk_types: []\n"),
+				}
+
+				interpolateOpts.Path = patch.MustNewPointerFromString("/disk_types")
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Found 1 error(s) in cloud-config manifest"))
+
+				Expect(ui.Errors).To(Equal([]string{"Error: '/vm_types': Expected array but got object"}))
+				Expect(ui.Blocks).To(BeEmpty())
+			})
+
+			It("returns error if manifest type is unknown", func() {
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{Bytes: []byte("name: dep")}
+				interpolateOpts.Validate = "unknown"
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Unknown manifest type 'unknown'"))
+			})
+		})
+
+		Context("when validate properties flag is set", func() {
+			BeforeEach(func() {
+				interpolateOpts.ValidateProperties = true
+			})
+
+			It("validates whole manifest and shows it if there are only warnings", func() {
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{Bytes: []byte("name: dep\nport: ((port))\n")}
+				interpolateOpts.VarKVs = []boshtpl.VarKV{{Name: "port", Value: 80}}
+				interpolateOpts.Path = patch.MustNewPointerFromString("/port")
+
+				propsValidator.ValidateReturns([]boshsch.Problem{
+					{Level: boshsch.LevelWarning, Path: "/releases/name=rel", Message: "Skipping validation"},
+				}, nil)
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(propsValidator.ValidateCallCount()).To(Equal(1))
+				Expect(propsValidator.ValidateArgsForCall(0)).To(Equal([]byte("name: dep\nport: 80\n")))
+
+				Expect(ui.Errors).To(Equal([]string{"Warning: '/releases/name=rel': Skipping validation"}))
+				Expect(ui.Blocks).To(Equal([]string{"80\n"}))
+			})
+
+			It("does not show manifest if job properties have errors", func() {
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{Bytes: []byte("name: dep")}
+
+				propsValidator.ValidateReturns([]boshsch.Problem{
+					{Level: boshsch.LevelError, Path: "/properties/port", Message: "Expected number"},
+					{Level: boshsch.LevelError, Path: "/properties/host", Message: "Expected string"},
+				}, nil)
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Found 2 error(s) in job properties"))
+
+				Expect(ui.Blocks).To(BeEmpty())
+			})
+		})
+
+		Context("when template engine is set", func() {
+			BeforeEach(func() {
+				interpolateOpts.TemplateEngine = "lite"
+			})
+
+			It("evaluates directives before operations and variables", func() {
+				err := fs.WriteFileString("/dir/web.yml", "name: web\ninstances: ((instances))\n")
+				Expect(err).ToNot(HaveOccurred())
+
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{
+					Bytes: []byte(`
+instance_groups:
+- $for: az
+  $in: azs
+  $do:
+    $include: web.yml
+`),
+					Path: "/dir/manifest.yml",
+				}
+				interpolateOpts.VarKVs = []boshtpl.VarKV{
+					{Name: "azs", Value: []interface{}{"z1", "z2"}},
+					{Name: "instances", Value: 2},
+				}
+				interpolateOpts.OpsFiles = []opts.OpsFileArg{
+					{Ops: patch.Ops{patch.RemoveOp{Path: patch.MustNewPointerFromString("/instance_groups/1")}}},
+				}
+
+				err = act()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ui.Blocks).To(Equal([]string{"instance_groups:\n- instances: 2\n  name: web\n"}))
+			})
+
+			It("returns error if directives cannot be evaluated", func() {
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{
+					Bytes: []byte("update: {$include: missing.yml}\n"),
+					Path:  "/dir/manifest.yml",
+				}
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Evaluating template engine directives"))
+				Expect(err.Error()).To(ContainSubstring("Including '/dir/missing.yml'"))
+			})
+
+			It("returns error if template engine is unknown", func() {
+				interpolateOpts.TemplateEngine = "unknown"
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{Bytes: []byte("name: dep")}
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Unknown template engine 'unknown', expected 'lite'"))
+			})
+		})
+
+		Context("when explaining operations", func() {
+			var fs *fakesys.FakeFileSystem
+
+			opsFile := func(path, content string) opts.OpsFileArg {
+				Expect(fs.WriteFileString(path, content)).To(Succeed())
+
+				arg := opts.OpsFileArg{FS: fs}
+				Expect((&arg).UnmarshalFlag(path)).To(Succeed())
+
+				return arg
+			}
+
+			BeforeEach(func() {
+				fs = fakesys.NewFakeFileSystem()
+
+				interpolateOpts.Args.Manifest = opts.FileBytesArg{
+					Bytes: []byte("name: dep\ninstance_groups:\n- name: web\n  instances: 1\n"),
+				}
+
+				interpolateOpts.OpsFiles = []opts.OpsFileArg{
+					opsFile("/ops1.yml", "- type: replace\n  path: /instance_groups/name=web/instances\n  value: ((count))\n"),
+					opsFile("/ops2.yml", "- type: remove\n  path: /update?\n- type: replace\n  path: /name\n  value: new\n"),
+				}
+			})
+
+			It("shows table of operations instead of the result", func() {
+				interpolateOpts.Explain = true
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Blocks).To(BeEmpty())
+				Expect(ui.Table.Content).To(Equal("operations"))
+				Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("/ops1.yml"),
+						boshtbl.NewValueInt(0),
+						boshtbl.NewValueString("replace"),
+						boshtbl.NewValueString("/instance_groups/name=web/instances"),
+						boshtbl.NewValueBool(true),
+						boshtbl.NewValueString("applied"),
+						boshtbl.NewValueInterface(1),
+						boshtbl.NewValueInterface("((count))"),
+					},
+					{
+						boshtbl.NewValueString("/ops2.yml"),
+						boshtbl.NewValueInt(0),
+						boshtbl.NewValueString("remove"),
+						boshtbl.NewValueString("/update?"),
+						boshtbl.NewValueBool(false),
+						boshtbl.NewValueString("skipped"),
+						boshtbl.ValueNone{},
+						boshtbl.ValueNone{},
+					},
+					{
+						boshtbl.NewValueString("/ops2.yml"),
+						boshtbl.NewValueInt(1),
+						boshtbl.NewValueString("replace"),
+						boshtbl.NewValueString("/name"),
+						boshtbl.NewValueBool(true),
+						boshtbl.NewValueString("applied"),
+						boshtbl.NewValueInterface("dep"),
+						boshtbl.NewValueInterface("new"),
+					},
+				}))
+			})
+
+			It("shows operations applied before failing operation and returns error", func() {
+				interpolateOpts.Explain = true
+				interpolateOpts.OpsFiles = append(interpolateOpts.OpsFiles,
+					opsFile("/ops3.yml", "- type: replace\n  path: /missing/key\n  value: 1\n"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("operation [0] in /ops3.yml failed"))
+
+				Expect(ui.Table.Rows).To(HaveLen(3))
+			})
+
+			It("shows operation and variables that last set the value at the path", func() {
+				interpolateOpts.Blame = patch.MustNewPointerFromString("/instance_groups/name=web/instances")
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Blocks).To(BeEmpty())
+				Expect(ui.Table.Rows).To(Equal([][]boshtbl.Value{{
+					boshtbl.NewValueString("/instance_groups/name=web/instances"),
+					boshtbl.NewValueString("operation [0] in /ops1.yml (replace /instance_groups/name=web/instances)"),
+					boshtbl.NewValueStrings([]string{"count"}),
+					boshtbl.NewValueInterface("((count))"),
+				}}))
+			})
+
+			It("shows that value comes from the template", func() {
+				interpolateOpts.Blame = patch.MustNewPointerFromString("/instance_groups/0/name")
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(ui.Table.Rows[0][1]).To(Equal(boshtbl.NewValueString("template")))
+			})
+
+			It("returns error if path is not found", func() {
+				interpolateOpts.Blame = patch.MustNewPointerFromString("/unknown")
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected to find '/unknown' in template after applying operations"))
+			})
+		})
 	})
 })
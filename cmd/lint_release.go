@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshlint "github.com/cloudfoundry/bosh-cli/v7/releasedir/lint"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+type LintReleaseCmd struct {
+	linterFactory func(DirOrCWDArg) boshlint.Linter
+	ui            boshui.UI
+}
+
+func NewLintReleaseCmd(linterFactory func(DirOrCWDArg) boshlint.Linter, ui boshui.UI) LintReleaseCmd {
+	return LintReleaseCmd{linterFactory: linterFactory, ui: ui}
+}
+
+func (c LintReleaseCmd) Run(opts LintReleaseOpts) error {
+	if opts.Format != "text" && opts.Format != "sarif" {
+		return bosherr.Errorf("Unknown format '%s', expected 'text' or 'sarif'", opts.Format)
+	}
+
+	findings, err := c.linterFactory(opts.Directory).Lint()
+	if err != nil {
+		return bosherr.WrapError(err, "Linting release directory")
+	}
+
+	if opts.Format == "sarif" {
+		doc, err := boshlint.SARIFDocument(findings)
+		if err != nil {
+			return err
+		}
+
+		c.ui.PrintBlock(doc)
+	} else {
+		c.printTable(findings)
+	}
+
+	var errCount int
+
+	for _, finding := range findings {
+		if finding.Rule.Level == boshlint.LevelError {
+			errCount++
+		}
+	}
+
+	if errCount > 0 {
+		return bosherr.Errorf("Found %d error(s) in release directory", errCount)
+	}
+
+	return nil
+}
+
+func (c LintReleaseCmd) printTable(findings []boshlint.Finding) {
+	table := boshtbl.Table{
+		Content: "findings",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Location"),
+			boshtbl.NewHeader("Level"),
+			boshtbl.NewHeader("Rule"),
+			boshtbl.NewHeader("Message"),
+		},
+	}
+
+	for _, finding := range findings {
+		loc := finding.Location
+
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(fmt.Sprintf("%s:%d:%d", loc.Path, loc.Line, loc.Column)),
+			boshtbl.NewValueString(finding.Rule.Level),
+			boshtbl.NewValueString(finding.Rule.ID),
+			boshtbl.NewValueString(finding.Message),
+		})
+	}
+
+	c.ui.PrintTable(table)
+}
@@ -0,0 +1,138 @@
+package cmd_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshlint "github.com/cloudfoundry/bosh-cli/v7/releasedir/lint"
+	fakelint "github.com/cloudfoundry/bosh-cli/v7/releasedir/lint/lintfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("LintReleaseCmd", func() {
+	var (
+		linter  *fakelint.FakeLinter
+		ui      *fakeui.FakeUI
+		command LintReleaseCmd
+
+		linterDirs []DirOrCWDArg
+	)
+
+	BeforeEach(func() {
+		linter = &fakelint.FakeLinter{}
+		ui = &fakeui.FakeUI{}
+
+		linterDirs = nil
+
+		linterFactory := func(dir DirOrCWDArg) boshlint.Linter {
+			linterDirs = append(linterDirs, dir)
+			return linter
+		}
+
+		command = NewLintReleaseCmd(linterFactory, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts LintReleaseOpts
+		)
+
+		BeforeEach(func() {
+			opts = LintReleaseOpts{
+				Directory: DirOrCWDArg{Path: "/dir"},
+				Format:    "text",
+			}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		warning := boshlint.Finding{
+			Rule:     boshlint.RuleUnusedBlob,
+			Message:  "warning-msg",
+			Location: boshlint.Location{Path: "config/blobs.yml", Line: 2, Column: 1},
+		}
+
+		failure := boshlint.Finding{
+			Rule:     boshlint.RuleMissingPackage,
+			Message:  "error-msg",
+			Location: boshlint.Location{Path: "jobs/job/spec", Line: 4, Column: 3},
+		}
+
+		It("lints release directory and shows findings", func() {
+			linter.LintReturns([]boshlint.Finding{warning}, nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(linterDirs).To(Equal([]DirOrCWDArg{{Path: "/dir"}}))
+
+			Expect(ui.Table).To(Equal(boshtbl.Table{
+				Content: "findings",
+
+				Header: []boshtbl.Header{
+					boshtbl.NewHeader("Location"),
+					boshtbl.NewHeader("Level"),
+					boshtbl.NewHeader("Rule"),
+					boshtbl.NewHeader("Message"),
+				},
+
+				Rows: [][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("config/blobs.yml:2:1"),
+						boshtbl.NewValueString("warning"),
+						boshtbl.NewValueString("unused-blob"),
+						boshtbl.NewValueString("warning-msg"),
+					},
+				},
+			}))
+		})
+
+		It("returns error if any finding is an error", func() {
+			linter.LintReturns([]boshlint.Finding{failure, warning}, nil)
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Found 1 error(s) in release directory"))
+
+			Expect(ui.Table.Rows).To(HaveLen(2))
+		})
+
+		It("shows SARIF document when format is sarif", func() {
+			opts.Format = "sarif"
+
+			linter.LintReturns([]boshlint.Finding{warning}, nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			expectedDoc, err := boshlint.SARIFDocument([]boshlint.Finding{warning})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(Equal([]string{string(expectedDoc)}))
+			Expect(ui.Table).To(Equal(boshtbl.Table{}))
+		})
+
+		It("returns error if format is unknown", func() {
+			opts.Format = "xml"
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Unknown format 'xml', expected 'text' or 'sarif'"))
+
+			Expect(linter.LintCallCount()).To(Equal(0))
+		})
+
+		It("returns error if linting fails", func() {
+			linter.LintReturns(nil, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
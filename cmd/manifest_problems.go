@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshpropcheck "github.com/cloudfoundry/bosh-cli/v7/director/propcheck"
+	boshsch "github.com/cloudfoundry/bosh-cli/v7/director/schema"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+// validateManifest prints problems to stderr so that stdout could still be used
+// for the manifest itself. Line numbers are not shown since they refer to
+// the evaluated manifest instead of the original file.
+func validateManifest(manifestType string, bytes []byte, ui boshui.UI) error {
+	schema, err := boshsch.ForType(manifestType)
+	if err != nil {
+		return err
+	}
+
+	problems, err := schema.Validate(bytes)
+	if err != nil {
+		return bosherr.WrapError(err, "Validating manifest")
+	}
+
+	return printManifestProblems(problems, manifestType+" manifest", ui)
+}
+
+// validateProperties checks job properties against job specs of releases
+// that are available locally (release tarballs, directories or downloaded tarballs)
+func validateProperties(validator boshpropcheck.Validator, bytes []byte, ui boshui.UI) error {
+	problems, err := validator.Validate(bytes)
+	if err != nil {
+		return bosherr.WrapError(err, "Validating job properties")
+	}
+
+	return printManifestProblems(problems, "job properties", ui)
+}
+
+func printManifestProblems(problems []boshsch.Problem, what string, ui boshui.UI) error {
+	var errCount int
+
+	for _, problem := range problems {
+		if problem.Level == boshsch.LevelError {
+			errCount++
+		}
+
+		level := strings.ToUpper(problem.Level[:1]) + problem.Level[1:]
+
+		ui.ErrorLinef("%s: '%s': %s", level, problem.Path, problem.Message)
+	}
+
+	if errCount > 0 {
+		return bosherr.Errorf("Found %d error(s) in %s", errCount, what)
+	}
+
+	return nil
+}
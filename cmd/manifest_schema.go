@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshsch "github.com/cloudfoundry/bosh-cli/v7/director/schema"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+type ManifestSchemaCmd struct {
+	ui boshui.UI
+}
+
+func NewManifestSchemaCmd(ui boshui.UI) ManifestSchemaCmd {
+	return ManifestSchemaCmd{ui: ui}
+}
+
+func (c ManifestSchemaCmd) Run(opts ManifestSchemaOpts) error {
+	schema, err := boshsch.ForType(opts.Type)
+	if err != nil {
+		return err
+	}
+
+	bytes, err := schema.JSONSchema("BOSH " + opts.Type + " manifest")
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintBlock(bytes)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("ManifestSchemaCmd", func() {
+	var (
+		ui      *fakeui.FakeUI
+		command ManifestSchemaCmd
+	)
+
+	BeforeEach(func() {
+		ui = &fakeui.FakeUI{}
+		command = NewManifestSchemaCmd(ui)
+	})
+
+	Describe("Run", func() {
+		It("prints JSON schema for given manifest type", func() {
+			err := command.Run(ManifestSchemaOpts{Type: "runtime-config"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(HaveLen(1))
+			Expect(ui.Blocks[0]).To(ContainSubstring(`"$schema": "http://json-schema.org/draft-07/schema#"`))
+			Expect(ui.Blocks[0]).To(ContainSubstring(`"title": "BOSH runtime-config manifest"`))
+			Expect(ui.Blocks[0]).To(ContainSubstring(`"addons": {`))
+		})
+
+		It("returns error if manifest type is unknown", func() {
+			err := command.Run(ManifestSchemaOpts{Type: "unknown"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unknown manifest type 'unknown'"))
+		})
+	})
+})
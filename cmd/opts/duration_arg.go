@@ -0,0 +1,42 @@
+package opts
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// DurationArg accepts days and weeks (ex: 7d, 2w) in addition to Go durations (ex: 12h, 30m)
+type DurationArg time.Duration
+
+func (a *DurationArg) UnmarshalFlag(data string) error {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+	for suffix, unit := range units {
+		if strings.HasSuffix(data, suffix) {
+			num, err := strconv.Atoi(strings.TrimSuffix(data, suffix))
+			if err != nil || num < 0 {
+				return bosherr.Errorf("Expected duration '%s' to be like '7d', '12h' or '30m'", data)
+			}
+
+			*a = DurationArg(time.Duration(num) * unit)
+
+			return nil
+		}
+	}
+
+	dur, err := time.ParseDuration(data)
+	if err != nil || dur < 0 {
+		return bosherr.Errorf("Expected duration '%s' to be like '7d', '12h' or '30m'", data)
+	}
+
+	*a = DurationArg(dur)
+
+	return nil
+}
+
+func (a DurationArg) Duration() time.Duration { return time.Duration(a) }
+
+func (a DurationArg) IsSet() bool { return a != 0 }
@@ -0,0 +1,51 @@
+package opts_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+)
+
+var _ = Describe("DurationArg", func() {
+	Describe("UnmarshalFlag", func() {
+		var (
+			arg DurationArg
+		)
+
+		BeforeEach(func() {
+			arg = DurationArg(0)
+		})
+
+		It("returns parsed days and weeks", func() {
+			err := (&arg).UnmarshalFlag("7d")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Duration()).To(Equal(7 * 24 * time.Hour))
+
+			err = (&arg).UnmarshalFlag("2w")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Duration()).To(Equal(14 * 24 * time.Hour))
+			Expect(arg.IsSet()).To(BeTrue())
+		})
+
+		It("returns parsed go durations", func() {
+			err := (&arg).UnmarshalFlag("1h30m")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Duration()).To(Equal(90 * time.Minute))
+		})
+
+		It("is not set by default", func() {
+			Expect(arg.IsSet()).To(BeFalse())
+		})
+
+		It("returns error if it cannot be parsed", func() {
+			for _, data := range []string{"xd", "-1d", "7", "-1h", "abc"} {
+				err := (&arg).UnmarshalFlag(data)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected duration '" + data + "' to be like '7d', '12h' or '30m'"))
+			}
+		})
+	})
+})
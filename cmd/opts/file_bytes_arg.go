@@ -13,6 +13,7 @@ type FileBytesArg struct {
 	FS boshsys.FileSystem
 
 	Bytes []byte
+	Path  string
 }
 
 func (a *FileBytesArg) UnmarshalFlag(data string) error {
@@ -46,6 +47,7 @@ func (a *FileBytesArg) UnmarshalFlag(data string) error {
 	}
 
 	(*a).Bytes = bytes
+	(*a).Path = absPath
 
 	return nil
 }
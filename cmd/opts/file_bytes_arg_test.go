@@ -39,6 +39,7 @@ var _ = Describe("FileBytesArg", func() {
 				err = (&arg).UnmarshalFlag("-")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(arg.Bytes).To(Equal([]byte("content")))
+				Expect(arg.Path).To(BeEmpty())
 			})
 
 			It("returns error if reading from stdin fails", func() {
@@ -58,6 +59,7 @@ var _ = Describe("FileBytesArg", func() {
 				err = (&arg).UnmarshalFlag("/some/path")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(arg.Bytes).To(Equal([]byte("content")))
+				Expect(arg.Path).To(Equal("/some/path"))
 			})
 
 			It("returns an error if expanding path fails", func() {
@@ -14,6 +14,10 @@ type OpsFileArg struct {
 	FS boshsys.FileSystem
 
 	Ops patch.Ops
+
+	// Path and Definitions are kept for explaining how ops were applied
+	Path        string
+	Definitions []patch.OpDefinition
 }
 
 func (a *OpsFileArg) UnmarshalFlag(filePath string) error {
@@ -48,6 +52,8 @@ func (a *OpsFileArg) UnmarshalFlag(filePath string) error {
 	}
 
 	(*a).Ops = ops
+	(*a).Path = filePath
+	(*a).Definitions = opDefs
 
 	return nil
 }
@@ -49,6 +49,11 @@ var _ = Describe("OpsFileArg", func() {
 					ErrorMsg: "operation [1] in /some/path failed",
 				},
 			}))
+
+			Expect(arg.Path).To(Equal("/some/path"))
+			Expect(arg.Definitions).To(HaveLen(2))
+			Expect(arg.Definitions[1].Type).To(Equal("remove"))
+			Expect(*arg.Definitions[1].Path).To(Equal("/b"))
 		})
 
 		It("returns an error if operations are not valid", func() {
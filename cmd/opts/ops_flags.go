@@ -2,6 +2,8 @@ package opts
 
 import (
 	"github.com/cppforlife/go-patch/patch"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
 )
 
 // Shared
@@ -18,3 +20,26 @@ func (f OpsFlags) AsOp() patch.Op {
 
 	return ops
 }
+
+// AsTracedOps returns ops together with files they were loaded from
+func (f OpsFlags) AsTracedOps() []boshtpl.TracedOp {
+	var ops []boshtpl.TracedOp
+
+	for _, opsFile := range f.OpsFiles {
+		for i, op := range opsFile.Ops {
+			tracedOp := boshtpl.TracedOp{Op: op, File: opsFile.Path, Index: i}
+
+			if i < len(opsFile.Definitions) {
+				tracedOp.Type = opsFile.Definitions[i].Type
+
+				if opsFile.Definitions[i].Path != nil {
+					tracedOp.Path = *opsFile.Definitions[i].Path
+				}
+			}
+
+			ops = append(ops, tracedOp)
+		}
+	}
+
+	return ops
+}
@@ -6,6 +6,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
 )
 
 var _ = Describe("OpsFlags", func() {
@@ -34,4 +35,33 @@ var _ = Describe("OpsFlags", func() {
 			}))
 		})
 	})
+
+	Describe("AsTracedOps", func() {
+		It("returns ops with files and definitions they came from", func() {
+			pathA, pathX := "/a", "/x"
+
+			removeA := patch.RemoveOp{Path: patch.MustNewPointerFromString("/a")}
+			removeX := patch.RemoveOp{Path: patch.MustNewPointerFromString("/x")}
+
+			flags := OpsFlags{
+				OpsFiles: []OpsFileArg{
+					{
+						Ops:         patch.Ops{removeA},
+						Path:        "ops1.yml",
+						Definitions: []patch.OpDefinition{{Type: "remove", Path: &pathA}},
+					},
+					{
+						Ops:         patch.Ops{removeX},
+						Path:        "ops2.yml",
+						Definitions: []patch.OpDefinition{{Type: "remove", Path: &pathX}},
+					},
+				},
+			}
+
+			Expect(flags.AsTracedOps()).To(Equal([]boshtpl.TracedOp{
+				{Op: removeA, File: "ops1.yml", Index: 0, Type: "remove", Path: "/a"},
+				{Op: removeX, File: "ops2.yml", Index: 0, Type: "remove", Path: "/x"},
+			}))
+		})
+	})
 })
@@ -35,6 +35,12 @@ type BoshOpts struct {
 	NoColorOpt        bool        `long:"no-color"                  description:"Toggle colorized output"`
 	NonInteractiveOpt bool        `long:"non-interactive" short:"n" description:"Don't ask for user input" env:"BOSH_NON_INTERACTIVE"`
 
+	// Director HTTP client
+	HTTPMaxAttemptsOpt int           `long:"http-max-attempts" description:"Max number of attempts for idempotent Director requests" env:"BOSH_HTTP_MAX_ATTEMPTS"`
+	HTTPRetryDelayOpt  time.Duration `long:"http-retry-delay"  description:"Initial delay between Director request attempts, doubled for each retry (e.g. 500ms)" env:"BOSH_HTTP_RETRY_DELAY"`
+	HTTPTimeoutOpt     time.Duration `long:"http-timeout"      description:"Timeout waiting for Director response per attempt (e.g. 30s)" env:"BOSH_HTTP_TIMEOUT"`
+	TraceHTTPOpt       string        `long:"trace-http"        description:"Write sanitized Director request/response timings as NDJSON to a file" env:"BOSH_TRACE_HTTP"`
+
 	Help       HelpOpts `command:"help" description:"Show this help message"`
 	Completion NoOpts   `command:"completion" description:"Generate the autocompletion script for bosh for the specified shell."`
 
@@ -64,6 +70,9 @@ type BoshOpts struct {
 	Locks   LocksOpts   `command:"locks"    description:"List current locks"`
 	CleanUp CleanUpOpts `command:"clean-up" description:"Clean up old unused resources except orphaned disks"`
 	Curl    CurlOpts    `command:"curl"     description:"Make an HTTP request to the Director"`
+	Top     TopOpts     `command:"top"      description:"Show live view of deployments, instances, tasks and locks"`
+
+	ExportMetrics ExportMetricsOpts `command:"export-metrics" description:"Serve director state as Prometheus metrics"`
 
 	// Config
 	Config       ConfigOpts       `command:"config" alias:"c" description:"Show current config for either ID or both type and name"`
@@ -91,8 +100,13 @@ type BoshOpts struct {
 
 	Deploy   DeployOpts   `command:"deploy"   alias:"d"   description:"Update deployment"`
 	Manifest ManifestOpts `command:"manifest" alias:"man" description:"Show deployment manifest"`
+	History  HistoryOpts  `command:"history" description:"Show deployment tasks with their actions, outcome and configs in effect"`
 
-	Interpolate InterpolateOpts `command:"interpolate" alias:"int" description:"Interpolates variables into a manifest"`
+	Interpolate     InterpolateOpts     `command:"interpolate" alias:"int" description:"Interpolates variables into a manifest"`
+	ManifestSchema  ManifestSchemaOpts  `command:"manifest-schema"         description:"Show JSON schema of manifests for editor integration"`
+	DiffManifests   DiffManifestsOpts   `command:"diff-manifests"          description:"Show differences between two manifests"`
+	GenerateOps     GenerateOpsOpts     `command:"generate-ops"            description:"Generate ops file that turns one manifest into another"`
+	VendorArtifacts VendorArtifactsOpts `command:"vendor-artifacts"        description:"Download releases and stemcells referenced by a manifest for use without internet access"`
 
 	// Events
 	Events EventsOpts `command:"events" description:"List events"`
@@ -104,6 +118,10 @@ type BoshOpts struct {
 	UploadStemcell       UploadStemcellOpts         `command:"upload-stemcell" alias:"us"   description:"Upload stemcell"`
 	DeleteStemcell       DeleteStemcellOpts         `command:"delete-stemcell" alias:"dels" description:"Delete stemcell"`
 	RepackStemcell       RepackStemcellOpts         `command:"repack-stemcell"              description:"Repack stemcell"`
+	StemcellFiles        StemcellFilesOpts          `command:"stemcell-files"               description:"List files in root filesystem of stemcell image"`
+	StemcellPackages     StemcellPackagesOpts       `command:"stemcell-packages"            description:"Show packages included in stemcell"`
+	DiffStemcells        DiffStemcellsOpts          `command:"diff-stemcells"               description:"Show package differences between two stemcells"`
+	ValidateStemcell     ValidateStemcellOpts       `command:"validate-stemcell"            description:"Validate stemcell metadata and image digest"`
 
 	// Releases
 	Releases            ReleasesOpts            `command:"releases"        alias:"rs"   description:"List releases"`
@@ -111,6 +129,8 @@ type BoshOpts struct {
 	ExportRelease       ExportReleaseOpts       `command:"export-release"               description:"Export the compiled release to a tarball"`
 	InspectRelease      InspectReleaseOpts      `command:"inspect-release"              description:"List release contents such as jobs"`
 	InspectLocalRelease InspectLocalReleaseOpts `command:"inspect-local-release"     description:"Display information from release metadata"`
+	DiffReleases        DiffReleasesOpts        `command:"diff-releases"             description:"Show differences between two releases"`
+	ReleaseSBOM         ReleaseSBOMOpts         `command:"release-sbom"              description:"Show software bill of materials for a release"`
 	DeleteRelease       DeleteReleaseOpts       `command:"delete-release"  alias:"delr" description:"Delete release"`
 
 	// Errands
@@ -166,12 +186,15 @@ type BoshOpts struct {
 	GeneratePackage GeneratePackageOpts `command:"generate-package"            description:"Generate package"`
 	CreateRelease   CreateReleaseOpts   `command:"create-release"   alias:"cr" description:"Create release"`
 	VendorPackage   VendorPackageOpts   `command:"vendor-package"              description:"Vendor package"`
+	LintRelease     LintReleaseOpts     `command:"lint-release"                description:"Check release directory for common authoring problems"`
 
 	Sha1ifyRelease Sha1ifyReleaseOpts `command:"sha1ify-release"  description:"Convert release tarball to use SHA1"`
 	Sha2ifyRelease Sha2ifyReleaseOpts `command:"sha2ify-release"  description:"Convert release tarball to use SHA256"`
 
 	FinalizeRelease FinalizeReleaseOpts `command:"finalize-release"               description:"Create final release from dev release tarball"`
 
+	Sign SignOpts `command:"sign" description:"Create detached signature for a release or stemcell tarball"`
+
 	// Blob management
 	Blobs       BlobsOpts       `command:"blobs"        description:"List blobs"`
 	AddBlob     AddBlobOpts     `command:"add-blob"     description:"Add blob"`
@@ -198,6 +221,8 @@ type CreateEnvOpts struct {
 	Recreate                bool   `long:"recreate" description:"Recreate VM in deployment"`
 	RecreatePersistentDisks bool   `long:"recreate-persistent-disks" description:"Recreate persistent disks in the deployment"`
 	PackageDir              string `long:"package-dir" value-name:"DIR" description:"Package cache location override"`
+	OTLPTraces              string `long:"otlp-traces" value-name:"PATH|URL" description:"Export OTLP traces of stages and CPI calls to a file or collector URL (e.g. http://localhost:4318)" env:"BOSH_OTLP_TRACES"`
+	DryRun                  bool   `long:"dry-run" description:"Show CPI calls that would be made without calling the CPI or the agent"`
 	cmd
 }
 
@@ -212,6 +237,7 @@ type DeleteEnvOpts struct {
 	SkipDrain  bool   `long:"skip-drain" description:"Skip running drain and pre-stop scripts"`
 	StatePath  string `long:"state" value-name:"PATH" description:"State file path"`
 	PackageDir string `long:"package-dir" value-name:"DIR" description:"Package cache location override"`
+	OTLPTraces string `long:"otlp-traces" value-name:"PATH|URL" description:"Export OTLP traces of stages and CPI calls to a file or collector URL (e.g. http://localhost:4318)" env:"BOSH_OTLP_TRACES"`
 	cmd
 }
 
@@ -329,6 +355,22 @@ type LocksOpts struct {
 	cmd
 }
 
+type TopOpts struct {
+	Interval   DurationArg `long:"interval" description:"Refresh interval, at least 10s (ex: 30s, 1m)" default:"10s"`
+	Deployment string
+
+	GatewayFlags
+
+	cmd
+}
+
+type ExportMetricsOpts struct {
+	Listen   string      `long:"listen" description:"Address to serve metrics on" default:":9190"`
+	Interval DurationArg `long:"interval" description:"How often to collect metrics from the director (ex: 30s, 5m)" default:"1m"`
+
+	cmd
+}
+
 type CleanUpOpts struct {
 	All               bool `long:"all" description:"Clean up all unused resources including all orphaned disks"`
 	DryRun            bool `long:"dry-run" description:"Print out the resources that will be deleted but does not delete anything"`
@@ -350,6 +392,15 @@ type AttachDiskArgs struct {
 	DiskCID string               `positional-arg-name:"DISK-CID"`
 }
 
+type HistoryOpts struct {
+	Recent int `long:"recent" short:"r" description:"Number of recent tasks to show" default:"30"`
+	Diff   int `long:"diff" value-name:"TASK-ID" description:"Show what changed in the given task instead of the timeline"`
+
+	Deployment string
+
+	cmd
+}
+
 type InterpolateOpts struct {
 	Args InterpolateArgs `positional-args:"true" required:"true"`
 
@@ -359,6 +410,14 @@ type InterpolateOpts struct {
 	Path            patch.Pointer `long:"path" value-name:"OP-PATH" description:"Extract value out of template (e.g.: /private_key)"`
 	VarErrors       bool          `long:"var-errs"                  description:"Expect all variables to be found, otherwise error"`
 	VarErrorsUnused bool          `long:"var-errs-unused"           description:"Expect all variables to be used, otherwise error"`
+	Validate        string        `long:"validate" value-name:"TYPE" description:"Validate structure of evaluated manifest (deployment, runtime-config, cloud-config, cpi-config)" optional:"true" optional-value:"deployment"`
+
+	ValidateProperties bool `long:"validate-properties" description:"Validate job properties against job specs of locally available releases"`
+
+	TemplateEngine string `long:"template-engine" value-name:"ENGINE" description:"Evaluate includes, loops and conditionals before operations and variables (lite)"`
+
+	Explain bool          `long:"explain" description:"Show how each operation changed the template instead of the result"`
+	Blame   patch.Pointer `long:"blame" value-name:"OP-PATH" description:"Show which operation and variables last set the value at the path instead of the result"`
 
 	cmd
 }
@@ -367,6 +426,54 @@ type InterpolateArgs struct {
 	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a template that will be interpolated"`
 }
 
+type ManifestSchemaOpts struct {
+	Type string `long:"type" value-name:"TYPE" description:"Manifest type (deployment, runtime-config, cloud-config, cpi-config)" default:"deployment"`
+
+	cmd
+}
+
+type DiffManifestsOpts struct {
+	Args DiffManifestsArgs `positional-args:"true" required:"true"`
+
+	NoRedact bool   `long:"no-redact" description:"Show values that look like credentials"`
+	Format   string `long:"format" description:"Output format (text, json)" default:"text"`
+
+	cmd
+}
+
+type DiffManifestsArgs struct {
+	From FileBytesArg `positional-arg-name:"FROM" description:"Path to a manifest"`
+	To   FileBytesArg `positional-arg-name:"TO" description:"Path to a manifest"`
+}
+
+type GenerateOpsOpts struct {
+	Args GenerateOpsArgs `positional-args:"true" required:"true"`
+
+	cmd
+}
+
+type GenerateOpsArgs struct {
+	Base     FileBytesArg `positional-arg-name:"BASE" description:"Path to a manifest that operations will apply to"`
+	Modified FileBytesArg `positional-arg-name:"MODIFIED" description:"Path to a manifest that operations will produce"`
+}
+
+type VendorArtifactsOpts struct {
+	Args VendorArtifactsArgs `positional-args:"true" required:"true"`
+
+	VarFlags
+	OpsFlags
+
+	To         string  `long:"to"           value-name:"DIR"  description:"Directory to download releases and stemcells into" required:"true"`
+	MirrorURL  string  `long:"mirror-url"   value-name:"URL"  description:"URL prefix serving contents of the directory (defaults to file:// URLs)"`
+	OpsFileOut FileArg `long:"ops-file-out" value-name:"PATH" description:"Write ops file at path (defaults to DIR/vendor-ops.yml)"`
+
+	cmd
+}
+
+type VendorArtifactsArgs struct {
+	Manifest FileBytesArg `positional-arg-name:"PATH" description:"Path to a manifest"`
+}
+
 // Config
 
 type ConfigOpts struct {
@@ -515,6 +622,8 @@ type DeployOpts struct {
 	DryRun               bool `long:"dry-run" description:"Renders job templates without altering deployment"`
 	ForceLatestVariables bool `long:"force-latest-variables" description:"Retrieve the latest variable values from the config server regardless of their update strategy"`
 
+	ValidateProperties bool `long:"validate-properties" description:"Validate job properties against job specs of locally available releases before uploading releases"`
+
 	cmd
 }
 
@@ -545,6 +654,11 @@ type EventsOpts struct {
 	ObjectType string `long:"object-type"  description:"Show events with given object type"`
 	ObjectName string `long:"object-name"  description:"Show events with given object name"`
 
+	Since  DurationArg `long:"since" description:"Show events newer than the given duration (ex: 7d, 12h)"`
+	All    bool        `long:"all" description:"Page through all matching events instead of showing only the most recent ones"`
+	Follow bool        `long:"follow" description:"Keep polling for new events (requires ndjson or csv format)"`
+	Format string      `long:"format" description:"Output format (text, ndjson, csv)" default:"text"`
+
 	cmd
 }
 
@@ -574,6 +688,9 @@ type UploadStemcellOpts struct {
 
 	SHA1 string `long:"sha1" description:"SHA1 of the remote stemcell (is not used with local files)"`
 
+	VerifyKey FileBytesArg `long:"verify-key" description:"Refuse to upload stemcell tarball unless signed by PEM encoded public key (applies to local files)"`
+	Signature FileArg      `long:"signature"  description:"Path to detached signature (defaults to PATH.sig)"`
+
 	cmd
 }
 
@@ -618,6 +735,38 @@ type InspectStemcellTarballArgs struct {
 	PathToStemcell string `positional-arg-name:"PATH-TO-STEMCELL" description:"Path to stemcell"`
 }
 
+type StemcellFilesOpts struct {
+	Args StemcellArgs `positional-args:"true" required:"true"`
+	cmd
+}
+
+type StemcellPackagesOpts struct {
+	Args StemcellArgs `positional-args:"true" required:"true"`
+
+	DevTools bool `long:"dev-tools" description:"Show files listed in dev_tools_file_list instead of packages"`
+
+	cmd
+}
+
+type DiffStemcellsOpts struct {
+	Args DiffStemcellsArgs `positional-args:"true" required:"true"`
+	cmd
+}
+
+type DiffStemcellsArgs struct {
+	From string `positional-arg-name:"FROM" description:"Path to stemcell"`
+	To   string `positional-arg-name:"TO" description:"Path to stemcell"`
+}
+
+type ValidateStemcellOpts struct {
+	Args StemcellArgs `positional-args:"true" required:"true"`
+	cmd
+}
+
+type StemcellArgs struct {
+	PathToStemcell string `positional-arg-name:"PATH-TO-STEMCELL" description:"Path to stemcell"`
+}
+
 // Releases
 
 type ReleasesOpts struct {
@@ -640,6 +789,9 @@ type UploadReleaseOpts struct {
 
 	Stemcell boshdir.OSVersionSlug `long:"stemcell" value-name:"OS/VERSION" description:"Stemcell that the release is compiled against (applies to remote releases)"`
 
+	VerifyKey FileBytesArg `long:"verify-key" description:"Refuse to upload release tarball unless signed by PEM encoded public key (applies to local files)"`
+	Signature FileArg      `long:"signature"  description:"Path to detached signature (defaults to PATH.sig)"`
+
 	Release boshrel.Release
 
 	cmd
@@ -693,6 +845,36 @@ type InspectLocalReleaseArgs struct {
 	PathToRelease string `positional-arg-name:"PATH-TO-RELEASE" description:"Path to release"`
 }
 
+type DiffReleasesOpts struct {
+	Args DiffReleasesArgs `positional-args:"true" required:"true"`
+
+	Directory DirOrCWDArg `long:"dir" description:"Release directory path if not current working directory" default:"."`
+	Name      string      `long:"name" description:"Release name used to look up versions in the release directory"`
+
+	cmd
+}
+
+type ReleaseSBOMOpts struct {
+	Args ReleaseSBOMArgs `positional-args:"true"`
+
+	Directory DirOrCWDArg `long:"dir" description:"Release directory path if not current working directory" default:"."`
+
+	Name    string     `long:"name"    description:"Release name used to look up version in the release directory"`
+	Version VersionArg `long:"version" description:"Release version in the release directory (defaults to latest)"`
+	Format  string     `long:"format"  description:"Software bill of materials format (spdx, cyclonedx)" default:"spdx"`
+
+	cmd
+}
+
+type ReleaseSBOMArgs struct {
+	PathToRelease string `positional-arg-name:"PATH" description:"Release tarball, release directory or release manifest (defaults to latest release in the release directory)"`
+}
+
+type DiffReleasesArgs struct {
+	From string `positional-arg-name:"FROM" description:"Release tarball, release directory or release version"`
+	To   string `positional-arg-name:"TO" description:"Release tarball, release directory or release version"`
+}
+
 // Errands
 
 type ErrandsOpts struct {
@@ -834,7 +1016,15 @@ type CreateRecoveryPlanArgs struct {
 }
 
 type RecoverOpts struct {
-	Args RecoverArgs `positional-args:"true" required:"true"`
+	Args RecoverArgs `positional-args:"true"`
+
+	Plan           FileArg     `long:"plan"            value-name:"PATH"     description:"Path to a recovery plan file"`
+	AllDeployments bool        `long:"all-deployments"                       description:"Recover all deployments instead of the one given with '--deployment'"`
+	DefaultPlan    bool        `long:"default-plan"                          description:"Apply 'instance_groups_plan' to deployments not listed in 'deployments_plan' when recovering all deployments"`
+	Watch          DurationArg `long:"watch"           value-name:"INTERVAL" description:"Scan for and resolve problems on every interval (ex: 10m, 1h)"`
+	MaxResolutions int         `long:"max-resolutions"                       description:"Maximum number of problems to resolve per run (0 for unlimited)"`
+	NotifyOnly     bool        `long:"notify-only"                           description:"Only report planned resolutions; don't resolve problems"`
+	Report         FileArg     `long:"report"          value-name:"PATH"     description:"Append a JSON report of each run to a file instead of printing it"`
 	cmd
 }
 
@@ -1087,6 +1277,13 @@ type VendorPackageArgs struct {
 	URL         DirOrCWDArg `positional-arg-name:"SRC-DIR" default:"."`
 }
 
+type LintReleaseOpts struct {
+	Directory DirOrCWDArg `long:"dir"    description:"Release directory path if not current working directory" default:"."`
+	Format    string      `long:"format" description:"Output format (text, sarif)" default:"text"`
+
+	cmd
+}
+
 type Sha1ifyReleaseOpts struct {
 	Args RedigestReleaseArgs `positional-args:"true"`
 
@@ -1104,6 +1301,19 @@ type RedigestReleaseArgs struct {
 	Destination FileArg `positional-arg-name:"DESTINATION"`
 }
 
+type SignOpts struct {
+	Args SignArgs `positional-args:"true" required:"true"`
+
+	Key       FileBytesArg `long:"key"       required:"true" description:"Path to PEM encoded ed25519 or ECDSA private key"`
+	Signature FileArg      `long:"signature"                 description:"Write signature at path (defaults to PATH.sig)"`
+
+	cmd
+}
+
+type SignArgs struct {
+	Path FileArg `positional-arg-name:"PATH" description:"Path to release or stemcell tarball"`
+}
+
 type CreateReleaseOpts struct {
 	Args CreateReleaseArgs `positional-args:"true"`
 
@@ -1117,6 +1327,11 @@ type CreateReleaseOpts struct {
 	Tarball FileArg `long:"tarball" description:"Create release tarball at path (e.g. /tmp/release.tgz)"`
 	Force   bool    `long:"force"   description:"Ignore Git dirty state check"`
 
+	SBOM       FileArg `long:"sbom"        description:"Write software bill of materials at path (e.g. /tmp/release.spdx.json)"`
+	SBOMFormat string  `long:"sbom-format" description:"Software bill of materials format (spdx, cyclonedx)" default:"spdx"`
+
+	SignKey FileBytesArg `long:"sign-key" description:"Sign release tarball with PEM encoded ed25519 or ECDSA private key (requires --tarball)"`
+
 	cmd
 }
 
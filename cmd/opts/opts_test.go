@@ -92,6 +92,7 @@ var _ = Describe("Opts", func() {
 				"Command 'UploadStemcellOpts' shadows global long option 'version'",
 				"Command 'RepackStemcellOpts' shadows global long option 'version'",
 				"Command 'UploadReleaseOpts' shadows global long option 'version'",
+				"Command 'ReleaseSBOMOpts' shadows global long option 'version'",
 				"Command 'CreateReleaseOpts' shadows global long option 'version'",
 				"Command 'FinalizeReleaseOpts' shadows global long option 'version'",
 			}))
@@ -201,6 +202,38 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("HTTPMaxAttemptsOpt", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("HTTPMaxAttemptsOpt", opts)).To(Equal(
+					`long:"http-max-attempts" description:"Max number of attempts for idempotent Director requests" env:"BOSH_HTTP_MAX_ATTEMPTS"`,
+				))
+			})
+		})
+
+		Describe("HTTPRetryDelayOpt", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("HTTPRetryDelayOpt", opts)).To(Equal(
+					`long:"http-retry-delay" description:"Initial delay between Director request attempts, doubled for each retry (e.g. 500ms)" env:"BOSH_HTTP_RETRY_DELAY"`,
+				))
+			})
+		})
+
+		Describe("HTTPTimeoutOpt", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("HTTPTimeoutOpt", opts)).To(Equal(
+					`long:"http-timeout" description:"Timeout waiting for Director response per attempt (e.g. 30s)" env:"BOSH_HTTP_TIMEOUT"`,
+				))
+			})
+		})
+
+		Describe("TraceHTTPOpt", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("TraceHTTPOpt", opts)).To(Equal(
+					`long:"trace-http" description:"Write sanitized Director request/response timings as NDJSON to a file" env:"BOSH_TRACE_HTTP"`,
+				))
+			})
+		})
+
 		Describe("CreateEnv", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("CreateEnv", opts)).To(Equal(
@@ -305,6 +338,22 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("Top", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Top", opts)).To(Equal(
+					`command:"top" description:"Show live view of deployments, instances, tasks and locks"`,
+				))
+			})
+		})
+
+		Describe("ExportMetrics", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ExportMetrics", opts)).To(Equal(
+					`command:"export-metrics" description:"Serve director state as Prometheus metrics"`,
+				))
+			})
+		})
+
 		Describe("CleanUp", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("CleanUp", opts)).To(Equal(
@@ -321,6 +370,46 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("ManifestSchema", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ManifestSchema", opts)).To(Equal(
+					`command:"manifest-schema" description:"Show JSON schema of manifests for editor integration"`,
+				))
+			})
+		})
+
+		Describe("History", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("History", opts)).To(Equal(
+					`command:"history" description:"Show deployment tasks with their actions, outcome and configs in effect"`,
+				))
+			})
+		})
+
+		Describe("DiffManifests", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DiffManifests", opts)).To(Equal(
+					`command:"diff-manifests" description:"Show differences between two manifests"`,
+				))
+			})
+		})
+
+		Describe("GenerateOps", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("GenerateOps", opts)).To(Equal(
+					`command:"generate-ops" description:"Generate ops file that turns one manifest into another"`,
+				))
+			})
+		})
+
+		Describe("VendorArtifacts", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("VendorArtifacts", opts)).To(Equal(
+					`command:"vendor-artifacts" description:"Download releases and stemcells referenced by a manifest for use without internet access"`,
+				))
+			})
+		})
+
 		Describe("Config", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Config", opts)).To(Equal(
@@ -481,6 +570,38 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("StemcellFiles", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("StemcellFiles", opts)).To(Equal(
+					`command:"stemcell-files" description:"List files in root filesystem of stemcell image"`,
+				))
+			})
+		})
+
+		Describe("StemcellPackages", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("StemcellPackages", opts)).To(Equal(
+					`command:"stemcell-packages" description:"Show packages included in stemcell"`,
+				))
+			})
+		})
+
+		Describe("DiffStemcells", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DiffStemcells", opts)).To(Equal(
+					`command:"diff-stemcells" description:"Show package differences between two stemcells"`,
+				))
+			})
+		})
+
+		Describe("ValidateStemcell", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ValidateStemcell", opts)).To(Equal(
+					`command:"validate-stemcell" description:"Validate stemcell metadata and image digest"`,
+				))
+			})
+		})
+
 		Describe("Releases", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Releases", opts)).To(Equal(
@@ -521,6 +642,30 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("DiffReleases", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DiffReleases", opts)).To(Equal(
+					`command:"diff-releases" description:"Show differences between two releases"`,
+				))
+			})
+		})
+
+		Describe("ReleaseSBOM", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ReleaseSBOM", opts)).To(Equal(
+					`command:"release-sbom" description:"Show software bill of materials for a release"`,
+				))
+			})
+		})
+
+		Describe("LintRelease", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("LintRelease", opts)).To(Equal(
+					`command:"lint-release" description:"Check release directory for common authoring problems"`,
+				))
+			})
+		})
+
 		Describe("InspectLocalStemcell", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("InspectLocalStemcell", opts)).To(Equal(
@@ -769,6 +914,14 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("Sign", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Sign", opts)).To(Equal(
+					`command:"sign" description:"Create detached signature for a release or stemcell tarball"`,
+				))
+			})
+		})
+
 		Describe("Blobs", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Blobs", opts)).To(Equal(
@@ -851,6 +1004,18 @@ var _ = Describe("Opts", func() {
 			))
 		})
 
+		It("has --otlp-traces", func() {
+			Expect(getStructTagForName("OTLPTraces", opts)).To(Equal(
+				`long:"otlp-traces" value-name:"PATH|URL" description:"Export OTLP traces of stages and CPI calls to a file or collector URL (e.g. http://localhost:4318)" env:"BOSH_OTLP_TRACES"`,
+			))
+		})
+
+		It("has --dry-run", func() {
+			Expect(getStructTagForName("DryRun", opts)).To(Equal(
+				`long:"dry-run" description:"Show CPI calls that would be made without calling the CPI or the agent"`,
+			))
+		})
+
 		It("has --recreate", func() {
 			Expect(getStructTagForName("Recreate", opts)).To(Equal(
 				`long:"recreate" description:"Recreate VM in deployment"`,
@@ -911,6 +1076,12 @@ var _ = Describe("Opts", func() {
 			))
 		})
 
+		It("has --otlp-traces", func() {
+			Expect(getStructTagForName("OTLPTraces", opts)).To(Equal(
+				`long:"otlp-traces" value-name:"PATH|URL" description:"Export OTLP traces of stages and CPI calls to a file or collector URL (e.g. http://localhost:4318)" env:"BOSH_OTLP_TRACES"`,
+			))
+		})
+
 		It("has --skip-drain", func() {
 			Expect(getStructTagForName("SkipDrain", opts)).To(Equal(
 				`long:"skip-drain" description:"Skip running drain and pre-stop scripts"`,
@@ -1362,6 +1533,46 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
+	Describe("TopOpts", func() {
+		var opts *TopOpts
+
+		BeforeEach(func() {
+			opts = &TopOpts{}
+		})
+
+		Describe("Interval", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Interval", opts)).To(Equal(
+					`long:"interval" description:"Refresh interval, at least 10s (ex: 30s, 1m)" default:"10s"`,
+				))
+			})
+		})
+	})
+
+	Describe("ExportMetricsOpts", func() {
+		var opts *ExportMetricsOpts
+
+		BeforeEach(func() {
+			opts = &ExportMetricsOpts{}
+		})
+
+		Describe("Listen", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Listen", opts)).To(Equal(
+					`long:"listen" description:"Address to serve metrics on" default:":9190"`,
+				))
+			})
+		})
+
+		Describe("Interval", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Interval", opts)).To(Equal(
+					`long:"interval" description:"How often to collect metrics from the director (ex: 30s, 5m)" default:"1m"`,
+				))
+			})
+		})
+	})
+
 	Describe("CleanUpOpts", func() {
 		var opts *CleanUpOpts
 
@@ -1443,6 +1654,154 @@ var _ = Describe("Opts", func() {
 				`long:"var-errs-unused" description:"Expect all variables to be used, otherwise error"`,
 			))
 		})
+
+		It("has Validate", func() {
+			Expect(getStructTagForName("Validate", &opts)).To(Equal(
+				`long:"validate" value-name:"TYPE" description:"Validate structure of evaluated manifest (deployment, runtime-config, cloud-config, cpi-config)" optional:"true" optional-value:"deployment"`,
+			))
+		})
+
+		It("has ValidateProperties", func() {
+			Expect(getStructTagForName("ValidateProperties", &opts)).To(Equal(
+				`long:"validate-properties" description:"Validate job properties against job specs of locally available releases"`,
+			))
+		})
+
+		It("has TemplateEngine", func() {
+			Expect(getStructTagForName("TemplateEngine", &opts)).To(Equal(
+				`long:"template-engine" value-name:"ENGINE" description:"Evaluate includes, loops and conditionals before operations and variables (lite)"`,
+			))
+		})
+
+		It("has Explain", func() {
+			Expect(getStructTagForName("Explain", &opts)).To(Equal(
+				`long:"explain" description:"Show how each operation changed the template instead of the result"`,
+			))
+		})
+
+		It("has Blame", func() {
+			Expect(getStructTagForName("Blame", &opts)).To(Equal(
+				`long:"blame" value-name:"OP-PATH" description:"Show which operation and variables last set the value at the path instead of the result"`,
+			))
+		})
+	})
+
+	Describe("ManifestSchemaOpts", func() {
+		var opts ManifestSchemaOpts
+
+		It("has Type", func() {
+			Expect(getStructTagForName("Type", &opts)).To(Equal(
+				`long:"type" value-name:"TYPE" description:"Manifest type (deployment, runtime-config, cloud-config, cpi-config)" default:"deployment"`,
+			))
+		})
+	})
+
+	Describe("HistoryOpts", func() {
+		var opts HistoryOpts
+
+		It("has Recent", func() {
+			Expect(getStructTagForName("Recent", &opts)).To(Equal(
+				`long:"recent" short:"r" description:"Number of recent tasks to show" default:"30"`,
+			))
+		})
+
+		It("has Diff", func() {
+			Expect(getStructTagForName("Diff", &opts)).To(Equal(
+				`long:"diff" value-name:"TASK-ID" description:"Show what changed in the given task instead of the timeline"`,
+			))
+		})
+	})
+
+	Describe("EventsOpts", func() {
+		var opts EventsOpts
+
+		It("has Since", func() {
+			Expect(getStructTagForName("Since", &opts)).To(Equal(
+				`long:"since" description:"Show events newer than the given duration (ex: 7d, 12h)"`,
+			))
+		})
+
+		It("has All", func() {
+			Expect(getStructTagForName("All", &opts)).To(Equal(
+				`long:"all" description:"Page through all matching events instead of showing only the most recent ones"`,
+			))
+		})
+
+		It("has Follow", func() {
+			Expect(getStructTagForName("Follow", &opts)).To(Equal(
+				`long:"follow" description:"Keep polling for new events (requires ndjson or csv format)"`,
+			))
+		})
+
+		It("has Format", func() {
+			Expect(getStructTagForName("Format", &opts)).To(Equal(
+				`long:"format" description:"Output format (text, ndjson, csv)" default:"text"`,
+			))
+		})
+	})
+
+	Describe("DiffManifestsOpts", func() {
+		var opts DiffManifestsOpts
+
+		It("has Args", func() {
+			Expect(getStructTagForName("Args", &opts)).To(Equal(
+				`positional-args:"true" required:"true"`,
+			))
+		})
+
+		It("has NoRedact", func() {
+			Expect(getStructTagForName("NoRedact", &opts)).To(Equal(
+				`long:"no-redact" description:"Show values that look like credentials"`,
+			))
+		})
+
+		It("has Format", func() {
+			Expect(getStructTagForName("Format", &opts)).To(Equal(
+				`long:"format" description:"Output format (text, json)" default:"text"`,
+			))
+		})
+	})
+
+	Describe("DiffManifestsArgs", func() {
+		var opts DiffManifestsArgs
+
+		It("has From", func() {
+			Expect(getStructTagForName("From", &opts)).To(Equal(
+				`positional-arg-name:"FROM" description:"Path to a manifest"`,
+			))
+		})
+
+		It("has To", func() {
+			Expect(getStructTagForName("To", &opts)).To(Equal(
+				`positional-arg-name:"TO" description:"Path to a manifest"`,
+			))
+		})
+	})
+
+	Describe("GenerateOpsOpts", func() {
+		var opts GenerateOpsOpts
+
+		It("has Args", func() {
+			Expect(getStructTagForName("Args", &opts)).To(Equal(
+				`positional-args:"true" required:"true"`,
+			))
+		})
+	})
+
+	Describe("GenerateOpsArgs", func() {
+		var opts GenerateOpsArgs
+
+		It("has Base", func() {
+			Expect(getStructTagForName("Base", &opts)).To(Equal(
+				`positional-arg-name:"BASE" description:"Path to a manifest that operations will apply to"`,
+			))
+		})
+
+		It("has Modified", func() {
+			Expect(getStructTagForName("Modified", &opts)).To(Equal(
+				`positional-arg-name:"MODIFIED" description:"Path to a manifest that operations will produce"`,
+			))
+		})
 	})
 
 	Describe("InterpolateArgs", func() {
@@ -1461,6 +1820,48 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
+	Describe("VendorArtifactsOpts", func() {
+		var opts VendorArtifactsOpts
+
+		It("has Args", func() {
+			Expect(getStructTagForName("Args", &opts)).To(Equal(`positional-args:"true" required:"true"`))
+		})
+
+		It("has To", func() {
+			Expect(getStructTagForName("To", &opts)).To(Equal(
+				`long:"to" value-name:"DIR" description:"Directory to download releases and stemcells into" required:"true"`,
+			))
+		})
+
+		It("has MirrorURL", func() {
+			Expect(getStructTagForName("MirrorURL", &opts)).To(Equal(
+				`long:"mirror-url" value-name:"URL" description:"URL prefix serving contents of the directory (defaults to file:// URLs)"`,
+			))
+		})
+
+		It("has OpsFileOut", func() {
+			Expect(getStructTagForName("OpsFileOut", &opts)).To(Equal(
+				`long:"ops-file-out" value-name:"PATH" description:"Write ops file at path (defaults to DIR/vendor-ops.yml)"`,
+			))
+		})
+	})
+
+	Describe("VendorArtifactsArgs", func() {
+		var opts *VendorArtifactsArgs
+
+		BeforeEach(func() {
+			opts = &VendorArtifactsArgs{}
+		})
+
+		Describe("Manifest", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Manifest", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Path to a manifest"`,
+				))
+			})
+		})
+	})
+
 	Describe("CloudConfigOpts", func() {
 		var opts *CloudConfigOpts
 
@@ -1650,6 +2051,14 @@ var _ = Describe("Opts", func() {
 			})
 		})
 
+		Describe("ValidateProperties", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("ValidateProperties", opts)).To(Equal(
+					`long:"validate-properties" description:"Validate job properties against job specs of locally available releases before uploading releases"`,
+				))
+			})
+		})
+
 		Describe("FixReleases", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("FixReleases", opts)).To(Equal(
@@ -1735,6 +2144,22 @@ var _ = Describe("Opts", func() {
 				))
 			})
 		})
+
+		Describe("VerifyKey", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("VerifyKey", opts)).To(Equal(
+					`long:"verify-key" description:"Refuse to upload stemcell tarball unless signed by PEM encoded public key (applies to local files)"`,
+				))
+			})
+		})
+
+		Describe("Signature", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Signature", opts)).To(Equal(
+					`long:"signature" description:"Path to detached signature (defaults to PATH.sig)"`,
+				))
+			})
+		})
 	})
 
 	Describe("UploadStemcellArgs", func() {
@@ -1791,11 +2216,11 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
-	Describe("RepackStemcellOpts", func() {
-		var opts *RepackStemcellOpts
+	Describe("StemcellFilesOpts", func() {
+		var opts *StemcellFilesOpts
 
 		BeforeEach(func() {
-			opts = &RepackStemcellOpts{}
+			opts = &StemcellFilesOpts{}
 		})
 
 		Describe("Args", func() {
@@ -1803,12 +2228,116 @@ var _ = Describe("Opts", func() {
 				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
 			})
 		})
+	})
 
-		It("has --version", func() {
-			Expect(getStructTagForName("Version", opts)).To(Equal(
-				`long:"version" description:"Repacked stemcell version"`,
-			))
-		})
+	Describe("StemcellPackagesOpts", func() {
+		var opts *StemcellPackagesOpts
+
+		BeforeEach(func() {
+			opts = &StemcellPackagesOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		Describe("DevTools", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DevTools", opts)).To(Equal(
+					`long:"dev-tools" description:"Show files listed in dev_tools_file_list instead of packages"`,
+				))
+			})
+		})
+	})
+
+	Describe("DiffStemcellsOpts", func() {
+		var opts *DiffStemcellsOpts
+
+		BeforeEach(func() {
+			opts = &DiffStemcellsOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+	})
+
+	Describe("DiffStemcellsArgs", func() {
+		var opts *DiffStemcellsArgs
+
+		BeforeEach(func() {
+			opts = &DiffStemcellsArgs{}
+		})
+
+		Describe("From", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("From", opts)).To(Equal(
+					`positional-arg-name:"FROM" description:"Path to stemcell"`,
+				))
+			})
+		})
+
+		Describe("To", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("To", opts)).To(Equal(
+					`positional-arg-name:"TO" description:"Path to stemcell"`,
+				))
+			})
+		})
+	})
+
+	Describe("ValidateStemcellOpts", func() {
+		var opts *ValidateStemcellOpts
+
+		BeforeEach(func() {
+			opts = &ValidateStemcellOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+	})
+
+	Describe("StemcellArgs", func() {
+		var opts *StemcellArgs
+
+		BeforeEach(func() {
+			opts = &StemcellArgs{}
+		})
+
+		Describe("PathToStemcell", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("PathToStemcell", opts)).To(Equal(
+					`positional-arg-name:"PATH-TO-STEMCELL" description:"Path to stemcell"`,
+				))
+			})
+		})
+	})
+
+	Describe("RepackStemcellOpts", func() {
+		var opts *RepackStemcellOpts
+
+		BeforeEach(func() {
+			opts = &RepackStemcellOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		It("has --version", func() {
+			Expect(getStructTagForName("Version", opts)).To(Equal(
+				`long:"version" description:"Repacked stemcell version"`,
+			))
+		})
 
 		It("has --name", func() {
 			Expect(getStructTagForName("Name", opts)).To(Equal(
@@ -1934,6 +2463,22 @@ var _ = Describe("Opts", func() {
 				))
 			})
 		})
+
+		Describe("VerifyKey", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("VerifyKey", opts)).To(Equal(
+					`long:"verify-key" description:"Refuse to upload release tarball unless signed by PEM encoded public key (applies to local files)"`,
+				))
+			})
+		})
+
+		Describe("Signature", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Signature", opts)).To(Equal(
+					`long:"signature" description:"Path to detached signature (defaults to PATH.sig)"`,
+				))
+			})
+		})
 	})
 
 	Describe("UploadReleaseArgs", func() {
@@ -2105,6 +2650,122 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
+	Describe("DiffReleasesOpts", func() {
+		var opts *DiffReleasesOpts
+
+		BeforeEach(func() {
+			opts = &DiffReleasesOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		Describe("Directory", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Directory", opts)).To(Equal(
+					`long:"dir" description:"Release directory path if not current working directory" default:"."`,
+				))
+			})
+		})
+
+		Describe("Name", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Name", opts)).To(Equal(
+					`long:"name" description:"Release name used to look up versions in the release directory"`,
+				))
+			})
+		})
+	})
+
+	Describe("DiffReleasesArgs", func() {
+		var opts *DiffReleasesArgs
+
+		BeforeEach(func() {
+			opts = &DiffReleasesArgs{}
+		})
+
+		Describe("From", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("From", opts)).To(Equal(
+					`positional-arg-name:"FROM" description:"Release tarball, release directory or release version"`,
+				))
+			})
+		})
+
+		Describe("To", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("To", opts)).To(Equal(
+					`positional-arg-name:"TO" description:"Release tarball, release directory or release version"`,
+				))
+			})
+		})
+	})
+
+	Describe("ReleaseSBOMOpts", func() {
+		var opts *ReleaseSBOMOpts
+
+		BeforeEach(func() {
+			opts = &ReleaseSBOMOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true"`))
+			})
+		})
+
+		Describe("Directory", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Directory", opts)).To(Equal(
+					`long:"dir" description:"Release directory path if not current working directory" default:"."`,
+				))
+			})
+		})
+
+		Describe("Name", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Name", opts)).To(Equal(
+					`long:"name" description:"Release name used to look up version in the release directory"`,
+				))
+			})
+		})
+
+		Describe("Version", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Version", opts)).To(Equal(
+					`long:"version" description:"Release version in the release directory (defaults to latest)"`,
+				))
+			})
+		})
+
+		Describe("Format", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Format", opts)).To(Equal(
+					`long:"format" description:"Software bill of materials format (spdx, cyclonedx)" default:"spdx"`,
+				))
+			})
+		})
+	})
+
+	Describe("ReleaseSBOMArgs", func() {
+		var opts *ReleaseSBOMArgs
+
+		BeforeEach(func() {
+			opts = &ReleaseSBOMArgs{}
+		})
+
+		Describe("PathToRelease", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("PathToRelease", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Release tarball, release directory or release manifest (defaults to latest release in the release directory)"`,
+				))
+			})
+		})
+	})
+
 	Describe("InstanceGroupOrInstanceSlugFlags", func() {
 		var opts *InstanceGroupOrInstanceSlugFlags
 
@@ -2501,7 +3162,63 @@ var _ = Describe("Opts", func() {
 		Describe("Args", func() {
 			It("contains desired values", func() {
 				Expect(getStructTagForName("Args", opts)).To(Equal(
-					`positional-args:"true" required:"true"`,
+					`positional-args:"true"`,
+				))
+			})
+		})
+
+		Describe("Plan", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Plan", opts)).To(Equal(
+					`long:"plan" value-name:"PATH" description:"Path to a recovery plan file"`,
+				))
+			})
+		})
+
+		Describe("AllDeployments", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("AllDeployments", opts)).To(Equal(
+					`long:"all-deployments" description:"Recover all deployments instead of the one given with '--deployment'"`,
+				))
+			})
+		})
+
+		Describe("DefaultPlan", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("DefaultPlan", opts)).To(Equal(
+					`long:"default-plan" description:"Apply 'instance_groups_plan' to deployments not listed in 'deployments_plan' when recovering all deployments"`,
+				))
+			})
+		})
+
+		Describe("Watch", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Watch", opts)).To(Equal(
+					`long:"watch" value-name:"INTERVAL" description:"Scan for and resolve problems on every interval (ex: 10m, 1h)"`,
+				))
+			})
+		})
+
+		Describe("MaxResolutions", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("MaxResolutions", opts)).To(Equal(
+					`long:"max-resolutions" description:"Maximum number of problems to resolve per run (0 for unlimited)"`,
+				))
+			})
+		})
+
+		Describe("NotifyOnly", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("NotifyOnly", opts)).To(Equal(
+					`long:"notify-only" description:"Only report planned resolutions; don't resolve problems"`,
+				))
+			})
+		})
+
+		Describe("Report", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Report", opts)).To(Equal(
+					`long:"report" value-name:"PATH" description:"Append a JSON report of each run to a file instead of printing it"`,
 				))
 			})
 		})
@@ -3143,6 +3860,30 @@ var _ = Describe("Opts", func() {
 		})
 	})
 
+	Describe("LintReleaseOpts", func() {
+		var opts *LintReleaseOpts
+
+		BeforeEach(func() {
+			opts = &LintReleaseOpts{}
+		})
+
+		Describe("Directory", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Directory", opts)).To(Equal(
+					`long:"dir" description:"Release directory path if not current working directory" default:"."`,
+				))
+			})
+		})
+
+		Describe("Format", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Format", opts)).To(Equal(
+					`long:"format" description:"Output format (text, sarif)" default:"text"`,
+				))
+			})
+		})
+	})
+
 	Describe("CreateReleaseOpts", func() {
 		var opts *CreateReleaseOpts
 
@@ -3218,6 +3959,76 @@ var _ = Describe("Opts", func() {
 				))
 			})
 		})
+
+		Describe("SBOM", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SBOM", opts)).To(Equal(
+					`long:"sbom" description:"Write software bill of materials at path (e.g. /tmp/release.spdx.json)"`,
+				))
+			})
+		})
+
+		Describe("SBOMFormat", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SBOMFormat", opts)).To(Equal(
+					`long:"sbom-format" description:"Software bill of materials format (spdx, cyclonedx)" default:"spdx"`,
+				))
+			})
+		})
+
+		Describe("SignKey", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("SignKey", opts)).To(Equal(
+					`long:"sign-key" description:"Sign release tarball with PEM encoded ed25519 or ECDSA private key (requires --tarball)"`,
+				))
+			})
+		})
+	})
+
+	Describe("SignOpts", func() {
+		var opts *SignOpts
+
+		BeforeEach(func() {
+			opts = &SignOpts{}
+		})
+
+		Describe("Args", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Args", opts)).To(Equal(`positional-args:"true" required:"true"`))
+			})
+		})
+
+		Describe("Key", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Key", opts)).To(Equal(
+					`long:"key" required:"true" description:"Path to PEM encoded ed25519 or ECDSA private key"`,
+				))
+			})
+		})
+
+		Describe("Signature", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Signature", opts)).To(Equal(
+					`long:"signature" description:"Write signature at path (defaults to PATH.sig)"`,
+				))
+			})
+		})
+	})
+
+	Describe("SignArgs", func() {
+		var opts *SignArgs
+
+		BeforeEach(func() {
+			opts = &SignArgs{}
+		})
+
+		Describe("Path", func() {
+			It("contains desired values", func() {
+				Expect(getStructTagForName("Path", opts)).To(Equal(
+					`positional-arg-name:"PATH" description:"Path to release or stemcell tarball"`,
+				))
+			})
+		})
 	})
 
 	Describe("Sha2ifyReleaseOpts", func() {
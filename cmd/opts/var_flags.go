@@ -4,6 +4,7 @@ import (
 	cfgtypes "github.com/cloudfoundry/config-server/types"
 
 	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	boshvarsrc "github.com/cloudfoundry/bosh-cli/v7/varsource"
 )
 
 // Shared
@@ -13,9 +14,25 @@ type VarFlags struct {
 	VarsFiles   []boshtpl.VarsFileArg `long:"vars-file"  short:"l" value-name:"PATH"      description:"Load variables from a YAML file"`
 	VarsEnvs    []boshtpl.VarsEnvArg  `long:"vars-env"             value-name:"PREFIX"    description:"Load variables from environment variables (e.g.: 'MY' to load MY_var=value)"`
 	VarsFSStore VarsFSStore           `long:"vars-store"           value-name:"PATH"      description:"Load/save variables from/to a YAML file"`
+
+	VarsSources     []VarsSourceArg    `long:"vars-source"       value-name:"SOURCE" description:"Load variables from external source (e.g.: 'credhub:/bosh/env', 'vault:secret/bosh/env', 'exec:PATH')"`
+	VarsSourcesFile VarsSourcesFileArg `long:"vars-sources-file" value-name:"PATH"   description:"Load variables from external sources listed in a YAML file" env:"BOSH_VARS_SOURCES_FILE"`
 }
 
+// AsVariables looks up variables in static variables, then external sources, then variables store
 func (f VarFlags) AsVariables() boshtpl.Variables {
+	return f.asVariables(false)
+}
+
+// AsGeneratingVariables additionally generates missing variables that have a type
+// (e.g. for create-env). Generated values are saved to variables store when it's configured,
+// otherwise to the first writable external source. Values are only generated once
+// all other variables were consulted so that existing values are never replaced.
+func (f VarFlags) AsGeneratingVariables() boshtpl.Variables {
+	return f.asVariables(true)
+}
+
+func (f VarFlags) asVariables(generate bool) boshtpl.Variables {
 	var firstToUse []boshtpl.Variables
 
 	staticVars := boshtpl.StaticVariables{}
@@ -44,17 +61,43 @@ func (f VarFlags) AsVariables() boshtpl.Variables {
 
 	firstToUse = append(firstToUse, staticVars)
 
+	var generating *boshvarsrc.GeneratingSource
+
+	// External sources are consulted before variables store
+	// so that the store only generates values that are not found elsewhere
+	for _, source := range f.externalSources() {
+		if writable, ok := source.(boshvarsrc.WritableSource); ok && generate && generating == nil {
+			generating = boshvarsrc.NewGeneratingSource(writable)
+		}
+
+		firstToUse = append(firstToUse, source)
+	}
+
 	store := &f.VarsFSStore
 
 	if f.VarsFSStore.IsSet() {
 		firstToUse = append(firstToUse, store)
+	} else if generating != nil {
+		firstToUse = append(firstToUse, generating)
 	}
 
 	vars := boshtpl.NewMultiVars(firstToUse)
 
 	if f.VarsFSStore.IsSet() {
 		store.ValueGeneratorFactory = cfgtypes.NewValueGeneratorConcrete(NewVarsCertLoader(vars))
+	} else if generating != nil {
+		generating.ValueGeneratorFactory = cfgtypes.NewValueGeneratorConcrete(NewVarsCertLoader(vars))
 	}
 
 	return vars
 }
+
+func (f VarFlags) externalSources() []boshtpl.Variables {
+	var sources []boshtpl.Variables
+
+	for _, arg := range f.VarsSources {
+		sources = append(sources, arg.Source)
+	}
+
+	return append(sources, f.VarsSourcesFile.Sources...)
+}
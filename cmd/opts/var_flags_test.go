@@ -10,6 +10,7 @@ import (
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
 	. "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	"github.com/cloudfoundry/bosh-cli/v7/varsource/varsourcefakes"
 )
 
 var _ = Describe("VarFlags", func() {
@@ -178,5 +179,149 @@ env: store
 			Expect(err).ToNot(HaveOccurred())
 			Expect(valRaw["ca"].(string)).To(Equal(caCert))
 		})
+
+		It("consults external sources after static variables and before vars store", func() {
+			varsStore := &VarsFSStore{FS: fakesys.NewFakeFileSystem()}
+
+			err := varsStore.UnmarshalFlag("/file")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = varsStore.FS.WriteFileString("/file", "store: store\nsource: store\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			source := &varsourcefakes.FakeWritableSource{}
+			source.GetStub = func(varDef VariableDefinition) (interface{}, bool, error) {
+				if varDef.Name == "source" || varDef.Name == "kv" {
+					return "source", true, nil
+				}
+				return nil, false, nil
+			}
+
+			flags := VarFlags{
+				VarKVs:          []VarKV{{Name: "kv", Value: "kv"}},
+				VarsSources:     []VarsSourceArg{{Source: source}},
+				VarsSourcesFile: VarsSourcesFileArg{Sources: []Variables{StaticVariables{"file_source": "file_source"}}},
+				VarsFSStore:     *varsStore,
+			}
+
+			vars := flags.AsVariables()
+
+			expectedVals := map[string]string{
+				"kv":          "kv",
+				"source":      "source",
+				"file_source": "file_source",
+				"store":       "store",
+			}
+
+			for key, expectedVal := range expectedVals {
+				val, found, err := vars.Get(VariableDefinition{Name: key})
+				Expect(val).To(Equal(expectedVal), fmt.Sprintf("Expecting key '%s' value to match", key))
+				Expect(found).To(BeTrue())
+				Expect(err).ToNot(HaveOccurred())
+			}
+		})
+
+		It("does not generate missing variables in external sources", func() {
+			source := &varsourcefakes.FakeWritableSource{}
+
+			flags := VarFlags{VarsSources: []VarsSourceArg{{Source: source}}}
+
+			_, found, err := flags.AsVariables().Get(VariableDefinition{Name: "pass", Type: "password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			Expect(source.SetCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("AsGeneratingVariables", func() {
+		It("generates missing variables and saves them to writable external sources", func() {
+			source := &varsourcefakes.FakeWritableSource{}
+
+			flags := VarFlags{VarsSources: []VarsSourceArg{{Source: source}}}
+
+			val, found, err := flags.AsGeneratingVariables().Get(VariableDefinition{Name: "pass", Type: "password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(HaveLen(20))
+
+			Expect(source.SetCallCount()).To(Equal(1))
+
+			varDef, setVal := source.SetArgsForCall(0)
+			Expect(varDef.Name).To(Equal("pass"))
+			Expect(setVal).To(Equal(val))
+		})
+
+		It("generates missing variables only after consulting all external sources", func() {
+			first := &varsourcefakes.FakeWritableSource{}
+			second := &varsourcefakes.FakeWritableSource{}
+			second.GetReturns("second", true, nil)
+
+			flags := VarFlags{VarsSources: []VarsSourceArg{{Source: first}, {Source: second}}}
+
+			val, found, err := flags.AsGeneratingVariables().Get(VariableDefinition{Name: "pass", Type: "password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("second"))
+
+			Expect(first.SetCallCount()).To(Equal(0))
+			Expect(second.SetCallCount()).To(Equal(0))
+		})
+
+		It("keeps values in vars store and generates missing variables there instead of writable external sources", func() {
+			varsStore := &VarsFSStore{FS: fakesys.NewFakeFileSystem()}
+
+			err := varsStore.UnmarshalFlag("/file")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = varsStore.FS.WriteFileString("/file", "pass: store\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			source := &varsourcefakes.FakeWritableSource{}
+
+			flags := VarFlags{
+				VarsSources: []VarsSourceArg{{Source: source}},
+				VarsFSStore: *varsStore,
+			}
+
+			vars := flags.AsGeneratingVariables()
+
+			val, found, err := vars.Get(VariableDefinition{Name: "pass", Type: "password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("store"))
+
+			_, found, err = vars.Get(VariableDefinition{Name: "other-pass", Type: "password"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			Expect(source.SetCallCount()).To(Equal(0))
+			Expect(varsStore.FS.ReadFileString("/file")).To(ContainSubstring("other-pass: "))
+		})
+
+		It("generates certificates signed by CA found in other variables", func() {
+			source := &varsourcefakes.FakeWritableSource{}
+
+			caVal, found, err := (&VarFlags{VarsSources: []VarsSourceArg{{Source: source}}}).AsGeneratingVariables().Get(
+				VariableDefinition{Name: "ca", Type: "certificate", Options: map[interface{}]interface{}{"is_ca": true, "common_name": "ca"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+
+			source = &varsourcefakes.FakeWritableSource{}
+
+			flags := VarFlags{
+				VarKVs:      []VarKV{{Name: "ca", Value: caVal}},
+				VarsSources: []VarsSourceArg{{Source: source}},
+			}
+
+			val, found, err := flags.AsGeneratingVariables().Get(VariableDefinition{
+				Name:    "cert",
+				Type:    "certificate",
+				Options: map[interface{}]interface{}{"common_name": "cert", "ca": "ca"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val.(map[interface{}]interface{})["ca"]).To(Equal(caVal.(map[interface{}]interface{})["certificate"]))
+		})
 	})
 })
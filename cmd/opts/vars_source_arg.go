@@ -0,0 +1,104 @@
+package opts
+
+import (
+	"os"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	boshvarsrc "github.com/cloudfoundry/bosh-cli/v7/varsource"
+)
+
+type VarsSourceArg struct {
+	FS        boshsys.FileSystem
+	CmdRunner boshsys.CmdRunner
+	Getenv    func(string) string
+
+	Source boshtpl.Variables
+}
+
+func (a *VarsSourceArg) UnmarshalFlag(spec string) error {
+	config, err := boshvarsrc.NewConfigFromSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	sources, err := newVarsSources([]boshvarsrc.Config{config}, a.FS, a.CmdRunner, a.Getenv)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Configuring variables source '%s'", spec)
+	}
+
+	(*a).Source = sources[0]
+
+	return nil
+}
+
+type VarsSourcesFileArg struct {
+	FS        boshsys.FileSystem
+	CmdRunner boshsys.CmdRunner
+	Getenv    func(string) string
+
+	Sources []boshtpl.Variables
+}
+
+func (a *VarsSourcesFileArg) UnmarshalFlag(filePath string) error {
+	if a.FS == nil {
+		a.FS = boshsys.NewOsFileSystemWithStrictTempRoot(boshlog.NewLogger(boshlog.LevelNone))
+	}
+
+	if len(filePath) == 0 {
+		return bosherr.Errorf("Expected file path to be non-empty")
+	}
+
+	bytes, err := a.FS.ReadFile(filePath)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading variables sources file '%s'", filePath)
+	}
+
+	configs, err := boshvarsrc.NewConfigsFromBytes(bytes)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing variables sources file '%s'", filePath)
+	}
+
+	sources, err := newVarsSources(configs, a.FS, a.CmdRunner, a.Getenv)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Configuring variables sources from '%s'", filePath)
+	}
+
+	(*a).Sources = sources
+
+	return nil
+}
+
+func newVarsSources(configs []boshvarsrc.Config, fs boshsys.FileSystem, cmdRunner boshsys.CmdRunner, getenv func(string) string) ([]boshtpl.Variables, error) {
+	logger := boshlog.NewLogger(boshlog.LevelNone)
+
+	if fs == nil {
+		fs = boshsys.NewOsFileSystemWithStrictTempRoot(logger)
+	}
+
+	if cmdRunner == nil {
+		cmdRunner = boshsys.NewExecCmdRunner(logger)
+	}
+
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	factory := boshvarsrc.NewFactory(fs, cmdRunner, logger)
+
+	var sources []boshtpl.Variables
+
+	for i, config := range configs {
+		source, err := factory.New(config.WithEnv(getenv))
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Source %d", i+1)
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
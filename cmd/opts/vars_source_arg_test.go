@@ -0,0 +1,123 @@
+package opts_test
+
+import (
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshvarsrc "github.com/cloudfoundry/bosh-cli/v7/varsource"
+)
+
+var _ = Describe("VarsSourceArg", func() {
+	var (
+		arg VarsSourceArg
+		env map[string]string
+	)
+
+	BeforeEach(func() {
+		env = map[string]string{}
+		arg = VarsSourceArg{
+			FS:        fakesys.NewFakeFileSystem(),
+			CmdRunner: fakesys.NewFakeCmdRunner(),
+			Getenv:    func(name string) string { return env[name] },
+		}
+	})
+
+	Describe("UnmarshalFlag", func() {
+		It("configures source with settings from environment", func() {
+			env["VAULT_ADDR"] = "http://vault:8200"
+			env["VAULT_TOKEN"] = "token"
+
+			err := (&arg).UnmarshalFlag("vault:secret/bosh/env")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Source).To(BeAssignableToTypeOf(boshvarsrc.Vault{}))
+		})
+
+		It("configures exec source", func() {
+			err := (&arg).UnmarshalFlag("exec:/bin/plugin")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Source).To(BeAssignableToTypeOf(boshvarsrc.Exec{}))
+		})
+
+		It("returns error if source is not recognized", func() {
+			err := (&arg).UnmarshalFlag("unknown")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected variables source 'unknown' to be one of"))
+		})
+
+		It("returns error if source is missing settings", func() {
+			err := (&arg).UnmarshalFlag("credhub")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Configuring variables source 'credhub'"))
+			Expect(err.Error()).To(ContainSubstring("Expected CredHub variables source to specify URL (or CREDHUB_SERVER)"))
+		})
+
+		It("returns error if CA certificate cannot be read", func() {
+			env["CREDHUB_SERVER"] = "https://credhub"
+			env["CREDHUB_CLIENT"] = "client"
+			env["CREDHUB_CA_CERT"] = "/ca.pem"
+
+			err := (&arg).UnmarshalFlag("credhub")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading CA certificate '/ca.pem'"))
+		})
+	})
+})
+
+var _ = Describe("VarsSourcesFileArg", func() {
+	var (
+		fs  *fakesys.FakeFileSystem
+		arg VarsSourcesFileArg
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		arg = VarsSourcesFileArg{
+			FS:        fs,
+			CmdRunner: fakesys.NewFakeCmdRunner(),
+			Getenv:    func(string) string { return "" },
+		}
+	})
+
+	Describe("UnmarshalFlag", func() {
+		It("configures sources listed in the file", func() {
+			err := fs.WriteFileString("/sources.yml", `
+sources:
+- type: vault
+  url: http://vault:8200
+  token: token
+- type: exec
+  path: /bin/plugin
+`)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = (&arg).UnmarshalFlag("/sources.yml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(arg.Sources).To(HaveLen(2))
+			Expect(arg.Sources[0]).To(BeAssignableToTypeOf(boshvarsrc.Vault{}))
+			Expect(arg.Sources[1]).To(BeAssignableToTypeOf(boshvarsrc.Exec{}))
+		})
+
+		It("returns error if source is invalid", func() {
+			err := fs.WriteFileString("/sources.yml", "sources:\n- type: exec\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = (&arg).UnmarshalFlag("/sources.yml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Configuring variables sources from '/sources.yml'"))
+			Expect(err.Error()).To(ContainSubstring("Source 1: Expected exec variables source to specify path"))
+		})
+
+		It("returns error if file cannot be read", func() {
+			err := (&arg).UnmarshalFlag("/sources.yml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading variables sources file '/sources.yml'"))
+		})
+
+		It("returns error if file path is empty", func() {
+			err := (&arg).UnmarshalFlag("")
+			Expect(err).To(MatchError("Expected file path to be non-empty"))
+		})
+	})
+})
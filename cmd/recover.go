@@ -5,6 +5,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
@@ -81,7 +82,20 @@ func getAnswersFromPlan(problems []boshdir.Problem, instanceGroupPlan InstanceGr
 }
 
 func (c RecoverCmd) readPlan(opts RecoverOpts) (*RecoveryPlan, error) {
-	planContents, err := c.fs.ReadFile(opts.Args.RecoveryPlan.ExpandedPath)
+	return readRecoveryPlan(c.fs, opts)
+}
+
+func readRecoveryPlan(fs boshsys.FileSystem, opts RecoverOpts) (*RecoveryPlan, error) {
+	path := opts.Args.RecoveryPlan.ExpandedPath
+	if opts.Plan.ExpandedPath != "" {
+		path = opts.Plan.ExpandedPath
+	}
+
+	if path == "" {
+		return nil, bosherr.Error("Expected recovery plan to be given as PATH or with '--plan'")
+	}
+
+	planContents, err := fs.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -127,11 +141,5 @@ func (c RecoverCmd) printPlanSummary(problemsByInstanceGroup map[string][]boshdi
 }
 
 func getPlanForInstanceGroup(instanceGroup string, plan *RecoveryPlan) InstanceGroupPlan {
-	for _, p := range plan.InstanceGroupsPlan {
-		if p.Name == instanceGroup {
-			return p
-		}
-	}
-
-	return InstanceGroupPlan{}
+	return findInstanceGroupPlan(instanceGroup, plan.InstanceGroupsPlan)
 }
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+const (
+	RecoveryActionResolved = "resolved"
+	RecoveryActionNotified = "notified"
+	RecoveryActionSkipped  = "skipped"
+	RecoveryActionFailed   = "failed"
+)
+
+type RecoveryReport struct {
+	StartedAt   time.Time                  `json:"started_at"`
+	NotifyOnly  bool                       `json:"notify_only"`
+	Problems    int                        `json:"problems"`
+	Resolved    int                        `json:"resolved"`
+	Error       string                     `json:"error,omitempty"`
+	Deployments []RecoveryDeploymentReport `json:"deployments"`
+}
+
+type RecoveryDeploymentReport struct {
+	Name     string                  `json:"name"`
+	Error    string                  `json:"error,omitempty"`
+	Problems []RecoveryProblemReport `json:"problems"`
+}
+
+type RecoveryProblemReport struct {
+	ID            int    `json:"id"`
+	Type          string `json:"type"`
+	InstanceGroup string `json:"instance_group"`
+	Description   string `json:"description"`
+	Resolution    string `json:"resolution,omitempty"`
+	Action        string `json:"action"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// AutoRecoverCmd applies plan-approved resolutions without prompting
+// for each problem, optionally across deployments and on an interval
+type AutoRecoverCmd struct {
+	deployments func() ([]boshdir.Deployment, error)
+	ui          boshui.UI
+	fs          boshsys.FileSystem
+	timeService clock.Clock
+}
+
+func NewAutoRecoverCmd(
+	deployments func() ([]boshdir.Deployment, error),
+	ui boshui.UI,
+	fs boshsys.FileSystem,
+	timeService clock.Clock,
+) AutoRecoverCmd {
+	return AutoRecoverCmd{deployments: deployments, ui: ui, fs: fs, timeService: timeService}
+}
+
+// IsAutoRecovery returns true when opts ask for anything beyond interactively applying a plan
+func IsAutoRecovery(opts RecoverOpts) bool {
+	return opts.AllDeployments || opts.Watch > 0 || opts.NotifyOnly ||
+		opts.MaxResolutions > 0 || opts.Report.ExpandedPath != ""
+}
+
+func (c AutoRecoverCmd) Run(opts RecoverOpts) error {
+	if opts.MaxResolutions < 0 {
+		return bosherr.Error("Expected max resolutions to be a non-negative number")
+	}
+
+	plan, err := readRecoveryPlan(c.fs, opts)
+	if err != nil {
+		return err
+	}
+
+	limiter, err := newRecoveryRateLimiter(plan.RateLimits)
+	if err != nil {
+		return err
+	}
+
+	if !opts.NotifyOnly {
+		if opts.Watch > 0 {
+			c.ui.PrintLinef("Problems will be resolved according to the recovery plan every %s", opts.Watch.Duration())
+		} else {
+			c.ui.PrintLinef("Problems will be resolved according to the recovery plan")
+		}
+
+		err = c.ui.AskForConfirmation()
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Watch == 0 {
+		report, err := c.runOnce(opts, *plan, limiter)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		if report.Error != "" {
+			errs = append(errs, bosherr.Error(report.Error))
+		}
+
+		for _, dep := range report.Deployments {
+			if dep.Error != "" {
+				errs = append(errs, bosherr.Errorf("Recovering deployment '%s': %s", dep.Name, dep.Error))
+			}
+		}
+
+		if len(errs) > 0 {
+			return bosherr.NewMultiError(errs...)
+		}
+
+		return nil
+	}
+
+	ticker := c.timeService.NewTicker(opts.Watch.Duration())
+	defer ticker.Stop()
+
+	for {
+		_, err := c.runOnce(opts, *plan, limiter)
+		if err != nil {
+			return err
+		}
+
+		<-ticker.C()
+	}
+}
+
+// runOnce only returns errors that prevent further runs;
+// director failures are part of the report
+func (c AutoRecoverCmd) runOnce(opts RecoverOpts, plan RecoveryPlan, limiter *recoveryRateLimiter) (RecoveryReport, error) {
+	report := RecoveryReport{
+		StartedAt:   c.timeService.Now().UTC(),
+		NotifyOnly:  opts.NotifyOnly,
+		Deployments: []RecoveryDeploymentReport{},
+	}
+
+	deployments, err := c.deployments()
+	if err != nil {
+		report.Error = bosherr.WrapError(err, "Listing deployments").Error()
+		c.ui.ErrorLinef("Recovery run failed: %s", report.Error)
+		return report, c.writeReport(opts, report)
+	}
+
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].Name() < deployments[j].Name() })
+
+	remaining := opts.MaxResolutions
+
+	for _, dep := range deployments {
+		depReport := c.recoverDeployment(dep, plan, limiter, opts, &remaining)
+
+		for _, p := range depReport.Problems {
+			report.Problems++
+			if p.Action == RecoveryActionResolved {
+				report.Resolved++
+			}
+		}
+
+		report.Deployments = append(report.Deployments, depReport)
+	}
+
+	c.ui.PrintLinef("Found %d problem(s) in %d deployment(s), resolved %d", report.Problems, len(report.Deployments), report.Resolved)
+
+	return report, c.writeReport(opts, report)
+}
+
+func (c AutoRecoverCmd) recoverDeployment(
+	dep boshdir.Deployment,
+	plan RecoveryPlan,
+	limiter *recoveryRateLimiter,
+	opts RecoverOpts,
+	remaining *int,
+) RecoveryDeploymentReport {
+	depReport := RecoveryDeploymentReport{Name: dep.Name(), Problems: []RecoveryProblemReport{}}
+
+	problems, err := dep.ScanForProblems()
+	if err != nil {
+		depReport.Error = bosherr.WrapError(err, "Scanning for problems").Error()
+		return depReport
+	}
+
+	if anyProblemsHaveNoInstanceGroups(problems) {
+		depReport.Error = "Director does not support this command.  Try 'bosh cloud-check' instead"
+		return depReport
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].ID < problems[j].ID })
+
+	instanceGroupsPlan, listed := plan.instanceGroupsPlanFor(dep.Name())
+
+	unplanned := opts.AllDeployments && !listed && !opts.DefaultPlan
+	if unplanned {
+		instanceGroupsPlan = nil
+	}
+
+	var answers []boshdir.ProblemAnswer
+	var resolving []int
+
+	maxInFlightOverrides := map[string]string{}
+	now := c.timeService.Now()
+
+	for _, p := range problems {
+		igPlan := findInstanceGroupPlan(p.InstanceGroup, instanceGroupsPlan)
+		resolutionName := igPlan.resolutionName(p)
+
+		problemReport := RecoveryProblemReport{
+			ID:            p.ID,
+			Type:          p.Type,
+			InstanceGroup: p.InstanceGroup,
+			Description:   p.Description,
+			Resolution:    resolutionName,
+			Action:        RecoveryActionSkipped,
+		}
+
+		switch {
+		case unplanned:
+			problemReport.Reason = "Deployment is not listed in deployments plan"
+		case resolutionName == "":
+			problemReport.Reason = "No resolution planned"
+		case !hasResolution(p, resolutionName):
+			problemReport.Reason = fmt.Sprintf("Planned resolution '%s' is not available", resolutionName)
+		case resolutionName == *boshdir.ProblemResolutionSkip.Name:
+			problemReport.Reason = "Planned to be ignored"
+		case opts.MaxResolutions > 0 && *remaining == 0:
+			problemReport.Reason = "Maximum number of resolutions per run reached"
+		case !limiter.Allow(p.Type, now):
+			problemReport.Reason = fmt.Sprintf("Rate limit for '%s' reached", p.Type)
+		case opts.NotifyOnly:
+			problemReport.Action = RecoveryActionNotified
+			*remaining--
+		default:
+			problemReport.Action = RecoveryActionResolved
+
+			limiter.Record(p.Type, now)
+			*remaining--
+
+			answers = append(answers, boshdir.ProblemAnswer{
+				ProblemID:  p.ID,
+				Resolution: boshdir.ProblemResolution{Name: &resolutionName, Plan: igPlan.resolutionPlan(p)},
+			})
+
+			resolving = append(resolving, len(depReport.Problems))
+
+			if igPlan.MaxInFlightOverride != "" {
+				maxInFlightOverrides[igPlan.Name] = igPlan.MaxInFlightOverride
+			}
+		}
+
+		depReport.Problems = append(depReport.Problems, problemReport)
+	}
+
+	if len(answers) == 0 {
+		return depReport
+	}
+
+	err = dep.ResolveProblems(answers, maxInFlightOverrides)
+	if err != nil {
+		depReport.Error = bosherr.WrapError(err, "Resolving problems").Error()
+
+		for _, i := range resolving {
+			depReport.Problems[i].Action = RecoveryActionFailed
+		}
+	}
+
+	return depReport
+}
+
+func (c AutoRecoverCmd) writeReport(opts RecoverOpts, report RecoveryReport) error {
+	bytes, err := json.Marshal(report)
+	if err != nil {
+		return bosherr.WrapError(err, "Marshaling recovery report")
+	}
+
+	bytes = append(bytes, '\n')
+
+	if opts.Report.ExpandedPath == "" {
+		c.ui.PrintBlock(bytes)
+		return nil
+	}
+
+	file, err := c.fs.OpenFile(opts.Report.ExpandedPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Opening recovery report '%s'", opts.Report.ExpandedPath)
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(bytes)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing recovery report '%s'", opts.Report.ExpandedPath)
+	}
+
+	return nil
+}
+
+func findInstanceGroupPlan(instanceGroup string, plans []InstanceGroupPlan) InstanceGroupPlan {
+	for _, p := range plans {
+		if p.Name == instanceGroup {
+			return p
+		}
+	}
+
+	return InstanceGroupPlan{}
+}
+
+func hasResolution(problem boshdir.Problem, name string) bool {
+	for _, r := range problem.Resolutions {
+		if r.Name != nil && *r.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recoveryRateLimiter remembers resolutions across runs
+// so that limits apply over a sliding window
+type recoveryRateLimiter struct {
+	limits  map[string]recoveryLimit
+	history map[string][]time.Time
+}
+
+type recoveryLimit struct {
+	max int
+	per time.Duration
+}
+
+func newRecoveryRateLimiter(rateLimits map[string]RecoveryRateLimit) (*recoveryRateLimiter, error) {
+	limits := map[string]recoveryLimit{}
+
+	for problemType, limit := range rateLimits {
+		per, err := time.ParseDuration(limit.Per)
+		if err != nil || per <= 0 {
+			return nil, bosherr.Errorf("Expected rate limit window '%s' for problem type '%s' to be like '30m' or '1h'", limit.Per, problemType)
+		}
+
+		if limit.Max < 0 {
+			return nil, bosherr.Errorf("Expected rate limit maximum for problem type '%s' to be a non-negative number", problemType)
+		}
+
+		limits[problemType] = recoveryLimit{max: limit.Max, per: per}
+	}
+
+	return &recoveryRateLimiter{limits: limits, history: map[string][]time.Time{}}, nil
+}
+
+func (l *recoveryRateLimiter) Allow(problemType string, now time.Time) bool {
+	limit, found := l.limits[problemType]
+	if !found {
+		return true
+	}
+
+	var recent []time.Time
+	for _, t := range l.history[problemType] {
+		if now.Sub(t) < limit.per {
+			recent = append(recent, t)
+		}
+	}
+
+	l.history[problemType] = recent
+
+	return len(recent) < limit.max
+}
+
+func (l *recoveryRateLimiter) Record(problemType string, now time.Time) {
+	if _, found := l.limits[problemType]; found {
+		l.history[problemType] = append(l.history[problemType], now)
+	}
+}
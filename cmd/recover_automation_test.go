@@ -0,0 +1,359 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-cli/v7/cmd"
+	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("AutoRecoverCmd", func() {
+	skipResolution := createResolution("ignore", "Skip for now")
+	recreateResolution := createResolution("recreate_vm", "Recreate VM")
+	deleteVMReferenceResolution := createResolution("delete_vm_reference", "Delete VM reference")
+
+	var (
+		redis       *fakedir.FakeDeployment
+		cf          *fakedir.FakeDeployment
+		deployments []boshdir.Deployment
+		listErr     error
+		ui          *fakeui.FakeUI
+		fakeFS      *fakesys.FakeFileSystem
+		timeService *fakeclock.FakeClock
+		recoverOpts opts.RecoverOpts
+		command     cmd.AutoRecoverCmd
+	)
+
+	newProblem := func(id int, typ, instanceGroup string) boshdir.Problem {
+		return boshdir.Problem{
+			ID:            id,
+			Type:          typ,
+			Description:   "problem-desc",
+			InstanceGroup: instanceGroup,
+			Resolutions:   []boshdir.ProblemResolution{skipResolution, recreateResolution, deleteVMReferenceResolution},
+		}
+	}
+
+	writePlan := func(plan string) {
+		err := fakeFS.WriteFileString("/tmp/plan.yml", plan)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	lastReport := func() cmd.RecoveryReport {
+		var report cmd.RecoveryReport
+		Expect(ui.Blocks).ToNot(BeEmpty())
+		Expect(json.Unmarshal([]byte(ui.Blocks[len(ui.Blocks)-1]), &report)).To(Succeed())
+		return report
+	}
+
+	BeforeEach(func() {
+		redis = &fakedir.FakeDeployment{NameStub: func() string { return "redis" }}
+		cf = &fakedir.FakeDeployment{NameStub: func() string { return "cf" }}
+		deployments = []boshdir.Deployment{redis, cf}
+		listErr = nil
+
+		ui = &fakeui.FakeUI{}
+		fakeFS = fakesys.NewFakeFileSystem()
+		timeService = fakeclock.NewFakeClock(time.Date(2020, time.May, 1, 10, 0, 0, 0, time.UTC))
+
+		command = cmd.NewAutoRecoverCmd(
+			func() ([]boshdir.Deployment, error) { return deployments, listErr },
+			ui, fakeFS, timeService,
+		)
+
+		recoverOpts = opts.RecoverOpts{
+			Plan:           opts.FileArg{ExpandedPath: "/tmp/plan.yml", FS: fakeFS},
+			AllDeployments: true,
+			DefaultPlan:    true,
+		}
+
+		writePlan(`
+instance_groups_plan:
+- name: redis
+  planned_resolutions:
+    unresponsive_agent: recreate_vm
+    missing_vm: ignore
+deployments_plan:
+- name: cf
+  instance_groups_plan:
+  - name: router
+    max_in_flight_override: 10%
+    planned_resolutions:
+      unresponsive_agent: delete_vm_reference
+`)
+
+		redis.ScanForProblemsReturns([]boshdir.Problem{
+			newProblem(2, "missing_vm", "redis"),
+			newProblem(1, "unresponsive_agent", "redis"),
+			newProblem(3, "unresponsive_agent", "sentinel"),
+		}, nil)
+
+		cf.ScanForProblemsReturns([]boshdir.Problem{
+			newProblem(7, "unresponsive_agent", "router"),
+		}, nil)
+	})
+
+	Describe("IsAutoRecovery", func() {
+		It("returns false when only a plan is given", func() {
+			Expect(cmd.IsAutoRecovery(opts.RecoverOpts{Plan: recoverOpts.Plan})).To(BeFalse())
+		})
+
+		It("returns true when any automation option is given", func() {
+			Expect(cmd.IsAutoRecovery(opts.RecoverOpts{AllDeployments: true})).To(BeTrue())
+			Expect(cmd.IsAutoRecovery(opts.RecoverOpts{Watch: opts.DurationArg(time.Minute)})).To(BeTrue())
+			Expect(cmd.IsAutoRecovery(opts.RecoverOpts{NotifyOnly: true})).To(BeTrue())
+			Expect(cmd.IsAutoRecovery(opts.RecoverOpts{MaxResolutions: 1})).To(BeTrue())
+		})
+	})
+
+	Describe("Run", func() {
+		It("resolves problems of each deployment according to its plan", func() {
+			err := command.Run(recoverOpts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.AskedConfirmationCalled).To(BeTrue())
+
+			Expect(cf.ResolveProblemsCallCount()).To(Equal(1))
+			answers, overrides := cf.ResolveProblemsArgsForCall(0)
+			Expect(answers).To(Equal([]boshdir.ProblemAnswer{
+				{ProblemID: 7, Resolution: deleteVMReferenceResolution},
+			}))
+			Expect(overrides).To(Equal(map[string]string{"router": "10%"}))
+
+			Expect(redis.ResolveProblemsCallCount()).To(Equal(1))
+			answers, overrides = redis.ResolveProblemsArgsForCall(0)
+			Expect(answers).To(Equal([]boshdir.ProblemAnswer{
+				{ProblemID: 1, Resolution: recreateResolution},
+			}))
+			Expect(overrides).To(BeEmpty())
+
+			Expect(ui.Said).To(ContainElement("Found 4 problem(s) in 2 deployment(s), resolved 2"))
+
+			report := lastReport()
+			Expect(report.StartedAt).To(Equal(timeService.Now()))
+			Expect(report.Problems).To(Equal(4))
+			Expect(report.Resolved).To(Equal(2))
+			Expect(report.Deployments).To(HaveLen(2))
+			Expect(report.Deployments[0].Name).To(Equal("cf"))
+			Expect(report.Deployments[1]).To(Equal(cmd.RecoveryDeploymentReport{
+				Name: "redis",
+				Problems: []cmd.RecoveryProblemReport{
+					{ID: 1, Type: "unresponsive_agent", InstanceGroup: "redis", Description: "problem-desc", Resolution: "recreate_vm", Action: "resolved"},
+					{ID: 2, Type: "missing_vm", InstanceGroup: "redis", Description: "problem-desc", Resolution: "ignore", Action: "skipped", Reason: "Planned to be ignored"},
+					{ID: 3, Type: "unresponsive_agent", InstanceGroup: "sentinel", Description: "problem-desc", Action: "skipped", Reason: "No resolution planned"},
+				},
+			}))
+		})
+
+		It("skips deployments not listed in deployments plan unless default plan is applied", func() {
+			recoverOpts.DefaultPlan = false
+
+			err := command.Run(recoverOpts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cf.ResolveProblemsCallCount()).To(Equal(1))
+			Expect(redis.ResolveProblemsCallCount()).To(Equal(0))
+
+			report := lastReport()
+			Expect(report.Resolved).To(Equal(1))
+			Expect(report.Deployments[1].Problems).To(HaveLen(3))
+
+			for _, p := range report.Deployments[1].Problems {
+				Expect(p.Action).To(Equal("skipped"))
+				Expect(p.Resolution).To(BeEmpty())
+				Expect(p.Reason).To(Equal("Deployment is not listed in deployments plan"))
+			}
+		})
+
+		It("applies top-level plan when recovering a single deployment", func() {
+			recoverOpts.AllDeployments = false
+			recoverOpts.DefaultPlan = false
+			deployments = []boshdir.Deployment{redis}
+
+			err := command.Run(recoverOpts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(redis.ResolveProblemsCallCount()).To(Equal(1))
+		})
+
+		It("only reports planned resolutions in notify-only mode", func() {
+			recoverOpts.NotifyOnly = true
+
+			err := command.Run(recoverOpts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.AskedConfirmationCalled).To(BeFalse())
+			Expect(redis.ResolveProblemsCallCount()).To(Equal(0))
+			Expect(cf.ResolveProblemsCallCount()).To(Equal(0))
+
+			report := lastReport()
+			Expect(report.NotifyOnly).To(BeTrue())
+			Expect(report.Resolved).To(Equal(0))
+			Expect(report.Deployments[0].Problems[0].Action).To(Equal("notified"))
+			Expect(report.Deployments[1].Problems[0].Action).To(Equal("notified"))
+		})
+
+		It("limits number of resolutions per run", func() {
+			recoverOpts.MaxResolutions = 1
+
+			err := command.Run(recoverOpts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cf.ResolveProblemsCallCount()).To(Equal(1))
+			Expect(redis.ResolveProblemsCallCount()).To(Equal(0))
+
+			report := lastReport()
+			Expect(report.Deployments[1].Problems[0].Action).To(Equal("skipped"))
+			Expect(report.Deployments[1].Problems[0].Reason).To(Equal("Maximum number of resolutions per run reached"))
+		})
+
+		It("skips problems whose planned resolution is not offered by the director", func() {
+			cf.ScanForProblemsReturns([]boshdir.Problem{
+				{ID: 7, Type: "unresponsive_agent", InstanceGroup: "router", Resolutions: []boshdir.ProblemResolution{skipResolution}},
+			}, nil)
+
+			err := command.Run(recoverOpts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cf.ResolveProblemsCallCount()).To(Equal(0))
+			Expect(lastReport().Deployments[0].Problems[0].Reason).To(Equal("Planned resolution 'delete_vm_reference' is not available"))
+		})
+
+		It("appends report to a file", func() {
+			recoverOpts.Report = opts.FileArg{ExpandedPath: "/tmp/report.json", FS: fakeFS}
+
+			err := command.Run(recoverOpts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ui.Blocks).To(BeEmpty())
+			Expect(fakeFS.GetFileTestStat("/tmp/report.json").Flags).To(Equal(os.O_CREATE | os.O_APPEND | os.O_WRONLY))
+
+			contents, err := fakeFS.ReadFileString("/tmp/report.json")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(HaveSuffix("\n"))
+
+			var report cmd.RecoveryReport
+			Expect(json.Unmarshal([]byte(contents), &report)).To(Succeed())
+			Expect(report.Resolved).To(Equal(2))
+		})
+
+		It("keeps recovering other deployments when one fails and returns errors", func() {
+			cf.ScanForProblemsReturns(nil, errors.New("fake-scan-err"))
+			redis.ResolveProblemsReturns(errors.New("fake-resolve-err"))
+
+			err := command.Run(recoverOpts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Recovering deployment 'cf': Scanning for problems: fake-scan-err"))
+			Expect(err.Error()).To(ContainSubstring("Recovering deployment 'redis': Resolving problems: fake-resolve-err"))
+
+			report := lastReport()
+			Expect(report.Resolved).To(Equal(0))
+			Expect(report.Deployments[1].Problems[0].Action).To(Equal("failed"))
+		})
+
+		It("returns error if deployments cannot be listed", func() {
+			listErr = errors.New("fake-list-err")
+
+			err := command.Run(recoverOpts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Listing deployments: fake-list-err"))
+			Expect(lastReport().Error).To(ContainSubstring("fake-list-err"))
+		})
+
+		It("does not resolve anything if confirmation is declined", func() {
+			ui.AskedConfirmationErr = errors.New("stop")
+
+			err := command.Run(recoverOpts)
+			Expect(err).To(Equal(errors.New("stop")))
+
+			Expect(redis.ScanForProblemsCallCount()).To(Equal(0))
+		})
+
+		It("returns error if plan is not given", func() {
+			recoverOpts.Plan = opts.FileArg{}
+
+			err := command.Run(recoverOpts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected recovery plan to be given as PATH or with '--plan'"))
+		})
+
+		It("returns error if rate limit is invalid", func() {
+			writePlan(`
+rate_limits:
+  unresponsive_agent: {max: 1, per: often}
+`)
+
+			err := command.Run(recoverOpts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected rate limit window 'often' for problem type 'unresponsive_agent'"))
+		})
+
+		It("returns error if max resolutions is negative", func() {
+			recoverOpts.MaxResolutions = -1
+
+			err := command.Run(recoverOpts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected max resolutions to be a non-negative number"))
+		})
+
+		Context("when watching", func() {
+			It("runs on every interval and applies rate limits across runs", func() {
+				writePlan(`
+instance_groups_plan:
+- name: redis
+  planned_resolutions:
+    unresponsive_agent: recreate_vm
+rate_limits:
+  unresponsive_agent: {max: 1, per: 90m}
+`)
+
+				deployments = []boshdir.Deployment{redis}
+				redis.ScanForProblemsReturns([]boshdir.Problem{newProblem(1, "unresponsive_agent", "redis")}, nil)
+
+				recoverOpts.Watch = opts.DurationArg(time.Hour)
+				recoverOpts.Report = opts.FileArg{ExpandedPath: "/tmp/report.json", FS: fakeFS}
+
+				// Stop watching on the fourth run by failing to write its report
+				runs := 0
+				started := make(chan struct{}, 4)
+
+				command = cmd.NewAutoRecoverCmd(func() ([]boshdir.Deployment, error) {
+					runs++
+					if runs == 4 {
+						fakeFS.OpenFileErr = errors.New("fake-open-err")
+					}
+					started <- struct{}{}
+					return deployments, nil
+				}, ui, fakeFS, timeService)
+
+				errCh := make(chan error)
+				go func() { errCh <- command.Run(recoverOpts) }()
+
+				for i := 0; i < 3; i++ {
+					Eventually(started).Should(Receive())
+					timeService.WaitForWatcherAndIncrement(time.Hour)
+				}
+
+				var err error
+				Eventually(errCh).Should(Receive(&err))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-open-err"))
+
+				// Resolved on first and third run, rate limited on second and fourth
+				Expect(redis.ResolveProblemsCallCount()).To(Equal(2))
+				Expect(redis.ScanForProblemsCallCount()).To(Equal(4))
+			})
+		})
+	})
+})
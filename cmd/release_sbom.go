@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	semver "github.com/cppforlife/go-semi-semantic/version"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshsbom "github.com/cloudfoundry/bosh-cli/v7/release/sbom"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+type ReleaseSBOMCmd struct {
+	releaseReaderFactory func(path string) boshrel.Reader
+	releaseDirFactory    func(DirOrCWDArg) boshreldir.ReleaseDir
+	sbomGenerator        boshsbom.Generator
+	fs                   boshsys.FileSystem
+	ui                   boshui.UI
+}
+
+func NewReleaseSBOMCmd(
+	releaseReaderFactory func(path string) boshrel.Reader,
+	releaseDirFactory func(DirOrCWDArg) boshreldir.ReleaseDir,
+	sbomGenerator boshsbom.Generator,
+	fs boshsys.FileSystem,
+	ui boshui.UI,
+) ReleaseSBOMCmd {
+	return ReleaseSBOMCmd{
+		releaseReaderFactory: releaseReaderFactory,
+		releaseDirFactory:    releaseDirFactory,
+		sbomGenerator:        sbomGenerator,
+		fs:                   fs,
+		ui:                   ui,
+	}
+}
+
+func (c ReleaseSBOMCmd) Run(opts ReleaseSBOMOpts) error {
+	var release boshrel.Release
+	var releaseDirPath string
+	var err error
+
+	path := opts.Args.PathToRelease
+
+	if len(path) > 0 {
+		release, err = c.releaseReaderFactory(path).Read(path)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading release '%s'", path)
+		}
+
+		stat, err := c.fs.Stat(path)
+		if err == nil && stat.IsDir() {
+			releaseDirPath = path
+		}
+	} else {
+		release, err = c.releaseDirFactory(opts.Directory).FindRelease(opts.Name, semver.Version(opts.Version))
+		if err != nil {
+			return bosherr.WrapError(err, "Finding release in release directory")
+		}
+
+		releaseDirPath = opts.Directory.Path
+	}
+
+	defer release.CleanUp() //nolint:errcheck
+
+	sbomBytes, err := c.sbomGenerator.Generate(release, releaseDirPath, opts.Format)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Generating software bill of materials")
+	}
+
+	c.ui.PrintBlock(sbomBytes)
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package cmd_test
+
+import (
+	"errors"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	semver "github.com/cppforlife/go-semi-semantic/version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	fakerel "github.com/cloudfoundry/bosh-cli/v7/release/releasefakes"
+	fakesbom "github.com/cloudfoundry/bosh-cli/v7/release/sbom/sbomfakes"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+	fakereldir "github.com/cloudfoundry/bosh-cli/v7/releasedir/releasedirfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("ReleaseSBOMCmd", func() {
+	var (
+		releaseReader *fakerel.FakeReader
+		releaseDir    *fakereldir.FakeReleaseDir
+		sbomGenerator *fakesbom.FakeGenerator
+		fs            *fakesys.FakeFileSystem
+		ui            *fakeui.FakeUI
+		command       ReleaseSBOMCmd
+
+		readerPaths []string
+		releaseDirs []DirOrCWDArg
+	)
+
+	BeforeEach(func() {
+		releaseReader = &fakerel.FakeReader{}
+		releaseDir = &fakereldir.FakeReleaseDir{}
+		sbomGenerator = &fakesbom.FakeGenerator{}
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+
+		readerPaths = nil
+		releaseDirs = nil
+
+		releaseReaderFactory := func(path string) boshrel.Reader {
+			readerPaths = append(readerPaths, path)
+			return releaseReader
+		}
+
+		releaseDirFactory := func(dir DirOrCWDArg) boshreldir.ReleaseDir {
+			releaseDirs = append(releaseDirs, dir)
+			return releaseDir
+		}
+
+		command = NewReleaseSBOMCmd(releaseReaderFactory, releaseDirFactory, sbomGenerator, fs, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts    ReleaseSBOMOpts
+			release *fakerel.FakeRelease
+		)
+
+		BeforeEach(func() {
+			opts = ReleaseSBOMOpts{
+				Directory: DirOrCWDArg{Path: "/dir"},
+				Format:    "cyclonedx",
+			}
+
+			release = &fakerel.FakeRelease{}
+			sbomGenerator.GenerateReturns([]byte("sbom-content"), nil)
+		})
+
+		Context("when path is given", func() {
+			BeforeEach(func() {
+				releaseReader.ReadReturns(release, nil)
+			})
+
+			It("generates document for release tarball without release directory", func() {
+				opts.Args.PathToRelease = "/release.tgz"
+				err := fs.WriteFileString("/release.tgz", "")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = command.Run(opts)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(readerPaths).To(Equal([]string{"/release.tgz"}))
+				Expect(releaseReader.ReadArgsForCall(0)).To(Equal("/release.tgz"))
+
+				rel, dirPath, format := sbomGenerator.GenerateArgsForCall(0)
+				Expect(rel).To(Equal(release))
+				Expect(dirPath).To(Equal(""))
+				Expect(format).To(Equal("cyclonedx"))
+
+				Expect(ui.Blocks).To(Equal([]string{"sbom-content"}))
+				Expect(release.CleanUpCallCount()).To(Equal(1))
+			})
+
+			It("generates document for release directory including its blobs", func() {
+				opts.Args.PathToRelease = "/other-dir"
+				err := fs.MkdirAll("/other-dir", 0755)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = command.Run(opts)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, dirPath, _ := sbomGenerator.GenerateArgsForCall(0)
+				Expect(dirPath).To(Equal("/other-dir"))
+			})
+
+			It("returns error if reading release fails", func() {
+				opts.Args.PathToRelease = "/release.tgz"
+				releaseReader.ReadReturns(nil, errors.New("fake-err"))
+
+				err := command.Run(opts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+				Expect(sbomGenerator.GenerateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when path is not given", func() {
+			It("generates document for release found in release directory", func() {
+				opts.Name = "rel"
+				opts.Version = VersionArg(semver.MustNewVersionFromString("1.2"))
+				releaseDir.FindReleaseReturns(release, nil)
+
+				err := command.Run(opts)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(releaseDirs).To(Equal([]DirOrCWDArg{{Path: "/dir"}}))
+
+				name, version := releaseDir.FindReleaseArgsForCall(0)
+				Expect(name).To(Equal("rel"))
+				Expect(version.String()).To(Equal("1.2"))
+
+				rel, dirPath, _ := sbomGenerator.GenerateArgsForCall(0)
+				Expect(rel).To(Equal(release))
+				Expect(dirPath).To(Equal("/dir"))
+
+				Expect(ui.Blocks).To(Equal([]string{"sbom-content"}))
+			})
+
+			It("returns error if finding release fails", func() {
+				releaseDir.FindReleaseReturns(nil, errors.New("fake-err"))
+
+				err := command.Run(opts)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+			})
+		})
+
+		It("returns error if generating document fails", func() {
+			releaseDir.FindReleaseReturns(release, nil)
+			sbomGenerator.GenerateReturns(nil, errors.New("fake-err"))
+
+			err := command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+			Expect(ui.Blocks).To(BeEmpty())
+		})
+	})
+})
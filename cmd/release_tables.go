@@ -11,8 +11,9 @@ import (
 )
 
 type ReleaseTables struct {
-	Release     boshrel.Release
-	ArchivePath string
+	Release       boshrel.Release
+	ArchivePath   string
+	SignaturePath string
 }
 
 func (t ReleaseTables) Print(ui boshui.UI) {
@@ -38,6 +39,12 @@ func (t ReleaseTables) Print(ui boshui.UI) {
 		})
 	}
 
+	if len(t.SignaturePath) > 0 {
+		summaryTable = summaryTable.AddColumn("Signature", []boshtbl.Value{
+			boshtbl.NewValueString(t.SignaturePath),
+		})
+	}
+
 	jobsTable := boshtbl.Table{
 		Content: "jobs",
 		Header: []boshtbl.Header{
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"io"
+	"time"
+
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 
@@ -8,9 +11,14 @@ import (
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	boshuaa "github.com/cloudfoundry/bosh-cli/v7/uaa"
 	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshuifmt "github.com/cloudfoundry/bosh-cli/v7/ui/fmt"
 	boshuit "github.com/cloudfoundry/bosh-cli/v7/ui/task"
 )
 
+// refreshTokenExpiryWarning is how far in advance users are warned
+// that their refresh token expires and they will have to log in again.
+const refreshTokenExpiryWarning = 24 * time.Hour
+
 type SessionImpl struct {
 	context SessionContext
 
@@ -20,6 +28,9 @@ type SessionImpl struct {
 
 	logger boshlog.Logger
 
+	// Shared by all sessions of a command; owned by Cmd
+	httpTraceWriter io.Writer
+
 	// Memoized
 	director        boshdir.Director
 	directorInfo    boshdir.Info
@@ -31,6 +42,7 @@ func NewSessionImpl(
 	ui boshui.UI,
 	printEnvironment bool,
 	printDeployment bool,
+	httpTraceWriter io.Writer,
 	logger boshlog.Logger,
 ) *SessionImpl {
 	return &SessionImpl{
@@ -39,6 +51,7 @@ func NewSessionImpl(
 		ui:               ui,
 		printEnvironment: printEnvironment,
 		printDeployment:  printDeployment,
+		httpTraceWriter:  httpTraceWriter,
 
 		logger: logger,
 	}
@@ -83,13 +96,11 @@ func (c *SessionImpl) Director() (boshdir.Director, error) {
 		return c.director, nil
 	}
 
-	dirConfig, err := boshdir.NewConfigFromURL(c.Environment())
+	dirConfig, err := c.directorConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	dirConfig.CACert = c.context.CACert()
-
 	creds := c.Credentials()
 
 	err = c.setDirectorInfo()
@@ -109,6 +120,8 @@ func (c *SessionImpl) Director() (boshdir.Director, error) {
 		if creds.IsUAAClient() {
 			dirConfig.TokenFunc = boshuaa.NewClientTokenSession(uaa).TokenFunc
 		} else {
+			c.warnIfRefreshTokenExpiring(creds)
+
 			origToken := boshuaa.NewRefreshableAccessToken(creds.AccessTokenType, creds.AccessToken, creds.RefreshToken)
 			dirConfig.TokenFunc = boshuaa.NewAccessTokenSession(uaa, origToken, c.context.Config(), c.Environment()).TokenFunc
 		}
@@ -132,6 +145,22 @@ func (c *SessionImpl) Director() (boshdir.Director, error) {
 	return c.director, nil
 }
 
+func (c *SessionImpl) warnIfRefreshTokenExpiring(creds cmdconf.Creds) {
+	if !boshuaa.TokenExpiresWithin(creds.RefreshToken, refreshTokenExpiryWarning) {
+		return
+	}
+
+	expiresAt := boshuaa.NewTokenExpiryFromValue(creds.RefreshToken)
+
+	if expiresAt.Before(time.Now()) {
+		c.ui.ErrorLinef("Warning: Refresh token for environment '%s' expired at %s, please log in again",
+			c.Environment(), expiresAt.Format(boshuifmt.TimeFullFmt))
+	} else {
+		c.ui.ErrorLinef("Warning: Refresh token for environment '%s' expires at %s, log in again to avoid interruptions",
+			c.Environment(), expiresAt.Format(boshuifmt.TimeFullFmt))
+	}
+}
+
 func (c *SessionImpl) setDirectorInfo() error {
 	if c.directorInfoSet {
 		return nil
@@ -153,14 +182,26 @@ func (c *SessionImpl) setDirectorInfo() error {
 }
 
 func (c *SessionImpl) AnonymousDirector() (boshdir.Director, error) {
-	dirConfig, err := boshdir.NewConfigFromURL(c.Environment())
+	dirConfig, err := c.directorConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	return boshdir.NewFactory(c.logger).New(dirConfig, nil, nil)
+}
+
+func (c *SessionImpl) directorConfig() (boshdir.FactoryConfig, error) {
+	dirConfig, err := boshdir.NewConfigFromURL(c.Environment())
+	if err != nil {
+		return boshdir.FactoryConfig{}, err
+	}
+
 	dirConfig.CACert = c.context.CACert()
+	dirConfig.RetryPolicy = c.context.RetryPolicy()
 
-	return boshdir.NewFactory(c.logger).New(dirConfig, nil, nil)
+	dirConfig.HTTPTraceWriter = c.httpTraceWriter
+
+	return dirConfig, nil
 }
 
 func (c *SessionImpl) Deployment() (boshdir.Deployment, error) {
@@ -5,6 +5,7 @@ import (
 
 	cmdconf "github.com/cloudfoundry/bosh-cli/v7/cmd/config"
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 )
 
 // SessionContextImpl prefers options over config values
@@ -53,3 +54,24 @@ func (c SessionContextImpl) CACert() string {
 func (c SessionContextImpl) Deployment() string {
 	return c.opts.DeploymentOpt
 }
+
+func (c SessionContextImpl) RetryPolicy() boshdir.RetryPolicy {
+	return boshdir.RetryPolicy{
+		MaxAttempts:  c.opts.HTTPMaxAttemptsOpt,
+		InitialDelay: c.opts.HTTPRetryDelayOpt,
+		Timeout:      c.opts.HTTPTimeoutOpt,
+	}.WithDefaults()
+}
+
+func (c SessionContextImpl) HTTPTracePath() string {
+	if len(c.opts.TraceHTTPOpt) == 0 {
+		return ""
+	}
+
+	path, err := c.fs.ExpandPath(c.opts.TraceHTTPOpt)
+	if err != nil {
+		return c.opts.TraceHTTPOpt
+	}
+
+	return path
+}
@@ -1,6 +1,8 @@
 package cmd_test
 
 import (
+	"time"
+
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -9,6 +11,7 @@ import (
 	cmdconf "github.com/cloudfoundry/bosh-cli/v7/cmd/config"
 	fakeconf "github.com/cloudfoundry/bosh-cli/v7/cmd/config/configfakes"
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 )
 
 var _ = Describe("SessionContextImpl", func() {
@@ -119,4 +122,36 @@ var _ = Describe("SessionContextImpl", func() {
 			Expect(build().Deployment()).To(Equal(""))
 		})
 	})
+
+	Describe("RetryPolicy", func() {
+		It("returns default policy if global options are not set", func() {
+			Expect(build().RetryPolicy()).To(Equal(boshdir.DefaultRetryPolicy()))
+		})
+
+		It("returns policy configured with global options", func() {
+			boshOpts.HTTPMaxAttemptsOpt = 3
+			boshOpts.HTTPRetryDelayOpt = 2 * time.Second
+			boshOpts.HTTPTimeoutOpt = 30 * time.Second
+
+			Expect(build().RetryPolicy()).To(Equal(boshdir.RetryPolicy{
+				MaxAttempts:  3,
+				InitialDelay: 2 * time.Second,
+				MaxDelay:     10 * time.Second,
+				Timeout:      30 * time.Second,
+			}))
+		})
+	})
+
+	Describe("HTTPTracePath", func() {
+		It("returns empty string if global option is not set", func() {
+			Expect(build().HTTPTracePath()).To(Equal(""))
+		})
+
+		It("returns expanded path of global option", func() {
+			fs.ExpandPathExpanded = "/expanded/trace.ndjson"
+			boshOpts.TraceHTTPOpt = "~/trace.ndjson"
+
+			Expect(build().HTTPTracePath()).To(Equal("/expanded/trace.ndjson"))
+		})
+	})
 })
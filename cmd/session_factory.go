@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"io"
+
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
@@ -16,9 +18,10 @@ func NewSessionFromOpts(
 	printEnvironment bool,
 	printDeployment bool,
 	fs boshsys.FileSystem,
+	httpTraceWriter io.Writer,
 	logger boshlog.Logger,
 ) Session {
 	context := NewSessionContextImpl(opts, config, fs)
 
-	return NewSessionImpl(context, ui, printEnvironment, printDeployment, logger)
+	return NewSessionImpl(context, ui, printEnvironment, printDeployment, httpTraceWriter, logger)
 }
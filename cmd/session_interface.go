@@ -15,6 +15,8 @@ type SessionContext interface {
 	Credentials() cmdconf.Creds
 
 	Deployment() string
+
+	RetryPolicy() boshdir.RetryPolicy
 }
 
 //counterfeiter:generate . Session
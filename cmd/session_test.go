@@ -1,8 +1,10 @@
 package cmd_test
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"time"
 
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
 	. "github.com/onsi/ginkgo/v2"
@@ -35,7 +37,7 @@ var _ = Describe("SessionImpl", func() {
 		printEnvironment = false
 		printDeployment = false
 		logger = boshlog.NewLogger(boshlog.LevelNone)
-		sess = cmd.NewSessionImpl(context, ui, printEnvironment, printDeployment, logger)
+		sess = cmd.NewSessionImpl(context, ui, printEnvironment, printDeployment, nil, logger)
 	})
 
 	Describe("UAA", func() {
@@ -250,6 +252,33 @@ var _ = Describe("SessionImpl", func() {
 					_, err = dir.Locks()
 					Expect(err).ToNot(HaveOccurred())
 				})
+
+				It("warns when refresh token is about to expire", func() {
+					server, caCert = BuildSSLServer()
+					defer server.Close()
+
+					claims := fmt.Sprintf(`{"user_name":"admin","exp":%d}`, time.Now().Add(time.Hour).Unix())
+					refreshToken := "seg." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".seg"
+
+					context.EnvironmentReturns(server.URL())
+					context.CACertReturns(caCert)
+					context.CredentialsReturns(cmdconf.Creds{RefreshToken: refreshToken})
+
+					server.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest("GET", "/info"),
+							ghttp.RespondWith(http.StatusOK, fmt.Sprintf(
+								`{"user_authentication":{"type":"uaa","options":{"url":"%s"}}}`, server.URL())),
+						),
+					)
+
+					_, err := sess.Director()
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(ui.Errors).To(HaveLen(1))
+					Expect(ui.Errors[0]).To(ContainSubstring("Warning: Refresh token for environment"))
+					Expect(ui.Errors[0]).To(ContainSubstring("log in again to avoid interruptions"))
+				})
 			})
 		})
 
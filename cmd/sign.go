@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	bicrypto "github.com/cloudfoundry/bosh-cli/v7/crypto"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+)
+
+type SignCmd struct {
+	signer bicrypto.ArtifactSigner
+	fs     boshsys.FileSystem
+	ui     boshui.UI
+}
+
+func NewSignCmd(signer bicrypto.ArtifactSigner, fs boshsys.FileSystem, ui boshui.UI) SignCmd {
+	return SignCmd{signer: signer, fs: fs, ui: ui}
+}
+
+func (c SignCmd) Run(opts SignOpts) error {
+	sigPath, err := signArtifact(c.signer, c.fs, opts.Args.Path.ExpandedPath, opts.Signature.ExpandedPath, opts.Key.Bytes)
+	if err != nil {
+		return err
+	}
+
+	c.ui.PrintLinef("Wrote signature for '%s' to '%s'", opts.Args.Path.ExpandedPath, sigPath)
+
+	return nil
+}
+
+// signArtifact writes detached signature next to the artifact unless signature path is given
+func signArtifact(signer bicrypto.ArtifactSigner, fs boshsys.FileSystem, artifactPath, sigPath string, key []byte) (string, error) {
+	if len(sigPath) == 0 {
+		sigPath = bicrypto.SignaturePath(artifactPath)
+	}
+
+	sig, err := signer.Sign(artifactPath, key)
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Signing '%s'", artifactPath)
+	}
+
+	err = fs.WriteFile(sigPath, sig)
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Writing signature to '%s'", sigPath)
+	}
+
+	return sigPath, nil
+}
+
+// verifiedArtifactCopy copies an artifact to a temporary file and verifies its detached signature
+// against that copy, so that the artifact cannot be swapped between verification and upload.
+// Artifacts without a signature or with one that does not match are refused.
+// Caller is responsible for removing the returned copy.
+func verifiedArtifactCopy(signer bicrypto.ArtifactSigner, fs boshsys.FileSystem, artifactPath, sigPath string, key []byte) (string, error) {
+	if len(sigPath) == 0 {
+		sigPath = bicrypto.SignaturePath(artifactPath)
+	}
+
+	if !fs.FileExists(sigPath) {
+		return "", bosherr.Errorf("Expected '%s' to be signed but signature '%s' does not exist", artifactPath, sigPath)
+	}
+
+	sig, err := fs.ReadFile(sigPath)
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Reading signature '%s'", sigPath)
+	}
+
+	copyFile, err := fs.TempFile("bosh-verified-artifact")
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Creating temporary file for '%s'", artifactPath)
+	}
+
+	copyPath := copyFile.Name()
+
+	err = copyFile.Close()
+	if err == nil {
+		err = fs.CopyFile(artifactPath, copyPath)
+	}
+	if err != nil {
+		fs.RemoveAll(copyPath) //nolint:errcheck
+		return "", bosherr.WrapErrorf(err, "Copying '%s'", artifactPath)
+	}
+
+	err = signer.Verify(copyPath, sig, key)
+	if err != nil {
+		fs.RemoveAll(copyPath) //nolint:errcheck
+		return "", bosherr.WrapErrorf(err, "Verifying signature of '%s'", artifactPath)
+	}
+
+	return copyPath, nil
+}
@@ -0,0 +1,84 @@
+package cmd_test
+
+import (
+	"errors"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakecrypto "github.com/cloudfoundry/bosh-cli/v7/crypto/cryptofakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("SignCmd", func() {
+	var (
+		signer  *fakecrypto.FakeArtifactSigner
+		fs      *fakesys.FakeFileSystem
+		ui      *fakeui.FakeUI
+		command SignCmd
+	)
+
+	BeforeEach(func() {
+		signer = &fakecrypto.FakeArtifactSigner{}
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+		command = NewSignCmd(signer, fs, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts SignOpts
+		)
+
+		BeforeEach(func() {
+			opts = SignOpts{
+				Args: SignArgs{Path: FileArg{ExpandedPath: "/stemcell.tgz"}},
+				Key:  FileBytesArg{Bytes: []byte("private-key")},
+			}
+
+			signer.SignReturns([]byte("signature"), nil)
+		})
+
+		It("writes signature next to the artifact", func() {
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			path, key := signer.SignArgsForCall(0)
+			Expect(path).To(Equal("/stemcell.tgz"))
+			Expect(key).To(Equal([]byte("private-key")))
+
+			Expect(fs.ReadFileString("/stemcell.tgz.sig")).To(Equal("signature"))
+			Expect(ui.Said).To(Equal([]string{"Wrote signature for '/stemcell.tgz' to '/stemcell.tgz.sig'"}))
+		})
+
+		It("writes signature to given path", func() {
+			opts.Signature = FileArg{ExpandedPath: "/sigs/stemcell.sig"}
+
+			err := command.Run(opts)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.ReadFileString("/sigs/stemcell.sig")).To(Equal("signature"))
+			Expect(fs.FileExists("/stemcell.tgz.sig")).To(BeFalse())
+		})
+
+		It("returns error if signing fails", func() {
+			signer.SignReturns(nil, errors.New("fake-err"))
+
+			err := command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+			Expect(fs.FileExists("/stemcell.tgz.sig")).To(BeFalse())
+		})
+
+		It("returns error if writing signature fails", func() {
+			fs.WriteFileError = errors.New("fake-err")
+
+			err := command.Run(opts)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Writing signature to '/stemcell.tgz.sig'"))
+		})
+	})
+})
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+type StemcellFilesCmd struct {
+	archiveFactory func(string) boshinsp.Archive
+	ui             boshui.UI
+}
+
+func NewStemcellFilesCmd(archiveFactory func(string) boshinsp.Archive, ui boshui.UI) StemcellFilesCmd {
+	return StemcellFilesCmd{archiveFactory: archiveFactory, ui: ui}
+}
+
+func (c StemcellFilesCmd) Run(opts StemcellFilesOpts) error {
+	files, err := c.archiveFactory(opts.Args.PathToStemcell).Files()
+	if err != nil {
+		return err
+	}
+
+	table := boshtbl.Table{
+		Content: "files",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Mode"),
+			boshtbl.NewHeader("Owner"),
+			boshtbl.NewHeader("Size"),
+			boshtbl.NewHeader("Path"),
+		},
+	}
+
+	for _, file := range files {
+		path := file.Path
+		if len(file.LinkTarget) > 0 {
+			path += " -> " + file.LinkTarget
+		}
+
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(fileModeString(file.Mode)),
+			boshtbl.NewValueString(fmt.Sprintf("%d:%d", file.UID, file.GID)),
+			boshtbl.NewValueString(strconv.FormatInt(file.Size, 10)),
+			boshtbl.NewValueString(path),
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	return nil
+}
+
+// fileModeString formats mode the way 'ls -l' does (e.g. '-rwsr-xr-x')
+func fileModeString(mode os.FileMode) string {
+	buf := []byte("----------")
+
+	switch {
+	case mode&os.ModeDir != 0:
+		buf[0] = 'd'
+	case mode&os.ModeSymlink != 0:
+		buf[0] = 'l'
+	case mode&os.ModeNamedPipe != 0:
+		buf[0] = 'p'
+	case mode&os.ModeSocket != 0:
+		buf[0] = 's'
+	case mode&os.ModeCharDevice != 0:
+		buf[0] = 'c'
+	case mode&os.ModeDevice != 0:
+		buf[0] = 'b'
+	}
+
+	const rwx = "rwxrwxrwx"
+
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			buf[i+1] = rwx[i]
+		}
+	}
+
+	special := []struct {
+		flag  os.FileMode
+		index int
+		char  byte
+	}{
+		{os.ModeSetuid, 3, 's'},
+		{os.ModeSetgid, 6, 's'},
+		{os.ModeSticky, 9, 't'},
+	}
+
+	for _, s := range special {
+		if mode&s.flag == 0 {
+			continue
+		}
+
+		// Uppercase indicates that execute bit is not set
+		if buf[s.index] == 'x' {
+			buf[s.index] = s.char
+		} else {
+			buf[s.index] = s.char - 'a' + 'A'
+		}
+	}
+
+	return string(buf)
+}
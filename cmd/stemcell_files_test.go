@@ -0,0 +1,117 @@
+package cmd_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	fakeinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect/inspectfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("StemcellFilesCmd", func() {
+	var (
+		archive      *fakeinsp.FakeArchive
+		ui           *fakeui.FakeUI
+		command      StemcellFilesCmd
+		archivePaths []string
+	)
+
+	BeforeEach(func() {
+		archive = &fakeinsp.FakeArchive{}
+		ui = &fakeui.FakeUI{}
+		archivePaths = nil
+
+		archiveFactory := func(path string) boshinsp.Archive {
+			archivePaths = append(archivePaths, path)
+			return archive
+		}
+
+		command = NewStemcellFilesCmd(archiveFactory, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts StemcellFilesOpts
+		)
+
+		BeforeEach(func() {
+			opts = StemcellFilesOpts{Args: StemcellArgs{PathToStemcell: "/stemcell.tgz"}}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("shows files in stemcell image", func() {
+			archive.FilesReturns([]boshinsp.File{
+				{Path: "/etc", Mode: os.ModeDir | 0755, Size: 4096},
+				{Path: "/etc/sudoers", Mode: 0440, Size: 10},
+				{Path: "/tmp", Mode: os.ModeDir | os.ModeSticky | 0777, Size: 4096},
+				{Path: "/usr/bin/passwd", Mode: os.ModeSetuid | 0755, UID: 1, GID: 2, Size: 20},
+				{Path: "/usr/bin/sh", Mode: os.ModeSymlink | 0777, Size: 4, LinkTarget: "dash"},
+			}, nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(archivePaths).To(Equal([]string{"/stemcell.tgz"}))
+
+			Expect(ui.Table).To(Equal(boshtbl.Table{
+				Content: "files",
+
+				Header: []boshtbl.Header{
+					boshtbl.NewHeader("Mode"),
+					boshtbl.NewHeader("Owner"),
+					boshtbl.NewHeader("Size"),
+					boshtbl.NewHeader("Path"),
+				},
+
+				Rows: [][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("drwxr-xr-x"),
+						boshtbl.NewValueString("0:0"),
+						boshtbl.NewValueString("4096"),
+						boshtbl.NewValueString("/etc"),
+					},
+					{
+						boshtbl.NewValueString("-r--r-----"),
+						boshtbl.NewValueString("0:0"),
+						boshtbl.NewValueString("10"),
+						boshtbl.NewValueString("/etc/sudoers"),
+					},
+					{
+						boshtbl.NewValueString("drwxrwxrwt"),
+						boshtbl.NewValueString("0:0"),
+						boshtbl.NewValueString("4096"),
+						boshtbl.NewValueString("/tmp"),
+					},
+					{
+						boshtbl.NewValueString("-rwsr-xr-x"),
+						boshtbl.NewValueString("1:2"),
+						boshtbl.NewValueString("20"),
+						boshtbl.NewValueString("/usr/bin/passwd"),
+					},
+					{
+						boshtbl.NewValueString("lrwxrwxrwx"),
+						boshtbl.NewValueString("0:0"),
+						boshtbl.NewValueString("4"),
+						boshtbl.NewValueString("/usr/bin/sh -> dash"),
+					},
+				},
+			}))
+		})
+
+		It("returns error if listing files fails", func() {
+			archive.FilesReturns(nil, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+type StemcellPackagesCmd struct {
+	archiveFactory func(string) boshinsp.Archive
+	ui             boshui.UI
+}
+
+func NewStemcellPackagesCmd(archiveFactory func(string) boshinsp.Archive, ui boshui.UI) StemcellPackagesCmd {
+	return StemcellPackagesCmd{archiveFactory: archiveFactory, ui: ui}
+}
+
+func (c StemcellPackagesCmd) Run(opts StemcellPackagesOpts) error {
+	archive := c.archiveFactory(opts.Args.PathToStemcell)
+
+	if opts.DevTools {
+		return c.printDevTools(archive)
+	}
+
+	pkgs, err := archive.Packages()
+	if err != nil {
+		return err
+	}
+
+	table := boshtbl.Table{
+		Content: "packages",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Name"),
+			boshtbl.NewHeader("Version"),
+			boshtbl.NewHeader("Architecture"),
+		},
+	}
+
+	for _, pkg := range pkgs {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(pkg.Name),
+			boshtbl.NewValueString(pkg.Version),
+			boshtbl.NewValueString(pkg.Architecture),
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	return nil
+}
+
+func (c StemcellPackagesCmd) printDevTools(archive boshinsp.Archive) error {
+	paths, err := archive.DevToolsFiles()
+	if err != nil {
+		return err
+	}
+
+	table := boshtbl.Table{
+		Content: "files",
+		Header:  []boshtbl.Header{boshtbl.NewHeader("Path")},
+	}
+
+	for _, path := range paths {
+		table.Rows = append(table.Rows, []boshtbl.Value{boshtbl.NewValueString(path)})
+	}
+
+	c.ui.PrintTable(table)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	fakeinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect/inspectfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("StemcellPackagesCmd", func() {
+	var (
+		archive      *fakeinsp.FakeArchive
+		ui           *fakeui.FakeUI
+		command      StemcellPackagesCmd
+		archivePaths []string
+	)
+
+	BeforeEach(func() {
+		archive = &fakeinsp.FakeArchive{}
+		ui = &fakeui.FakeUI{}
+		archivePaths = nil
+
+		archiveFactory := func(path string) boshinsp.Archive {
+			archivePaths = append(archivePaths, path)
+			return archive
+		}
+
+		command = NewStemcellPackagesCmd(archiveFactory, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts StemcellPackagesOpts
+		)
+
+		BeforeEach(func() {
+			opts = StemcellPackagesOpts{Args: StemcellArgs{PathToStemcell: "/stemcell.tgz"}}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("shows packages included in stemcell", func() {
+			archive.PackagesReturns([]boshinsp.Package{
+				{Name: "adduser", Version: "3.118ubuntu5", Architecture: "all"},
+			}, nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(archivePaths).To(Equal([]string{"/stemcell.tgz"}))
+
+			Expect(ui.Table).To(Equal(boshtbl.Table{
+				Content: "packages",
+
+				Header: []boshtbl.Header{
+					boshtbl.NewHeader("Name"),
+					boshtbl.NewHeader("Version"),
+					boshtbl.NewHeader("Architecture"),
+				},
+
+				Rows: [][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("adduser"),
+						boshtbl.NewValueString("3.118ubuntu5"),
+						boshtbl.NewValueString("all"),
+					},
+				},
+			}))
+		})
+
+		It("shows dev tools files when requested", func() {
+			opts.DevTools = true
+
+			archive.DevToolsFilesReturns([]string{"/usr/bin/gcc"}, nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(archive.PackagesCallCount()).To(Equal(0))
+
+			Expect(ui.Table).To(Equal(boshtbl.Table{
+				Content: "files",
+				Header:  []boshtbl.Header{boshtbl.NewHeader("Path")},
+				Rows:    [][]boshtbl.Value{{boshtbl.NewValueString("/usr/bin/gcc")}},
+			}))
+		})
+
+		It("returns error if reading packages fails", func() {
+			archive.PackagesReturns(nil, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if reading dev tools files fails", func() {
+			opts.DevTools = true
+
+			archive.DevToolsFilesReturns(nil, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
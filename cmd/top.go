@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshssh "github.com/cloudfoundry/bosh-cli/v7/ssh"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtop "github.com/cloudfoundry/bosh-cli/v7/ui/top"
+)
+
+// Each refresh fetches instance vitals which creates a director task
+const topMinInterval = 10 * time.Second
+
+type TopCmd struct {
+	director    boshdir.Director
+	screen      boshtop.Screen
+	actions     boshtop.Actions
+	timeService clock.Clock
+	logger      boshlog.Logger
+}
+
+func NewTopCmd(
+	director boshdir.Director,
+	screen boshtop.Screen,
+	actions boshtop.Actions,
+	timeService clock.Clock,
+	logger boshlog.Logger,
+) TopCmd {
+	return TopCmd{
+		director:    director,
+		screen:      screen,
+		actions:     actions,
+		timeService: timeService,
+		logger:      logger,
+	}
+}
+
+func (c TopCmd) Run(opts TopOpts) (err error) {
+	defer func() {
+		closeErr := c.screen.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}()
+
+	if opts.Interval.Duration() < topMinInterval {
+		return bosherr.Errorf("Expected interval to be at least %s", topMinInterval)
+	}
+
+	dashboard := boshtop.NewDashboard(c.director, c.screen, c.actions, c.timeService, opts.Interval.Duration(), c.logger)
+
+	return dashboard.Run(opts.Deployment)
+}
+
+// TopActions runs restart and SSH the same way as their commands do
+type TopActions struct {
+	director     boshdir.Director
+	ui           boshui.UI
+	gatewayFlags GatewayFlags
+
+	intSSHRunner     boshssh.Runner
+	nonIntSSHRunner  boshssh.Runner
+	resultsSSHRunner boshssh.Runner
+	hostBuilder      boshssh.HostBuilder
+}
+
+func NewTopActions(
+	director boshdir.Director,
+	ui boshui.UI,
+	gatewayFlags GatewayFlags,
+	intSSHRunner boshssh.Runner,
+	nonIntSSHRunner boshssh.Runner,
+	resultsSSHRunner boshssh.Runner,
+	hostBuilder boshssh.HostBuilder,
+) TopActions {
+	return TopActions{
+		director:         director,
+		ui:               ui,
+		gatewayFlags:     gatewayFlags,
+		intSSHRunner:     intSSHRunner,
+		nonIntSSHRunner:  nonIntSSHRunner,
+		resultsSSHRunner: resultsSSHRunner,
+		hostBuilder:      hostBuilder,
+	}
+}
+
+func (a TopActions) Restart(deploymentName string, slug boshdir.InstanceSlug) error {
+	deployment, err := a.director.FindDeployment(deploymentName)
+	if err != nil {
+		return err
+	}
+
+	restartOpts, err := newRestartOpts(RestartOpts{})
+	if err != nil {
+		return err
+	}
+
+	return deployment.Restart(boshdir.NewAllOrInstanceGroupOrInstanceSlug(slug.Name(), slug.IndexOrID()), restartOpts)
+}
+
+func (a TopActions) SSH(deploymentName string, slug boshdir.InstanceSlug) error {
+	sshOpts := SSHOpts{
+		Args:         SshSlugArgs{Slug: boshdir.NewAllOrInstanceGroupOrInstanceSlug(slug.Name(), slug.IndexOrID())},
+		GatewayFlags: a.gatewayFlags,
+	}
+
+	deploymentFetcher := func() (boshdir.Deployment, error) {
+		return a.director.FindDeployment(deploymentName)
+	}
+
+	sshCmd := NewSSHCmd(a.intSSHRunner, a.nonIntSSHRunner, a.resultsSSHRunner, a.ui, a.hostBuilder)
+
+	return sshCmd.Run(sshOpts, deploymentFetcher)
+}
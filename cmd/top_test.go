@@ -0,0 +1,155 @@
+package cmd_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-cli/v7/cmd"
+	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	fakessh "github.com/cloudfoundry/bosh-cli/v7/ssh/sshfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtop "github.com/cloudfoundry/bosh-cli/v7/ui/top"
+	faketop "github.com/cloudfoundry/bosh-cli/v7/ui/top/topfakes"
+)
+
+var _ = Describe("Top", func() {
+	var (
+		director   *fakedir.FakeDirector
+		deployment *fakedir.FakeDeployment
+	)
+
+	BeforeEach(func() {
+		director = &fakedir.FakeDirector{}
+		deployment = &fakedir.FakeDeployment{}
+		director.FindDeploymentReturns(deployment, nil)
+	})
+
+	Describe("TopCmd", func() {
+		var (
+			screen  *faketop.FakeScreen
+			keys    chan boshtop.Key
+			command cmd.TopCmd
+			topOpts opts.TopOpts
+		)
+
+		BeforeEach(func() {
+			screen = &faketop.FakeScreen{}
+			keys = make(chan boshtop.Key)
+			screen.KeysReturns(keys)
+			screen.SizeReturns(80, 24)
+
+			timeService := fakeclock.NewFakeClock(time.Date(2020, time.May, 1, 10, 0, 0, 0, time.UTC))
+			command = cmd.NewTopCmd(director, screen, &faketop.FakeActions{}, timeService, boshlog.NewLogger(boshlog.LevelNone))
+			topOpts = opts.TopOpts{Interval: opts.DurationArg(10 * time.Second)}
+		})
+
+		It("shows dashboard until quit and closes the screen", func() {
+			close(keys)
+
+			Expect(command.Run(topOpts)).ToNot(HaveOccurred())
+			Expect(screen.DrawCallCount()).To(Equal(1))
+			Eventually(director.DeploymentsCallCount).Should(Equal(1))
+			Expect(screen.CloseCallCount()).To(Equal(1))
+		})
+
+		It("starts with instances of the given deployment", func() {
+			close(keys)
+			topOpts.Deployment = "dep"
+
+			Expect(command.Run(topOpts)).ToNot(HaveOccurred())
+			Eventually(deployment.InstanceInfosCallCount).Should(Equal(1))
+			Expect(director.FindDeploymentArgsForCall(0)).To(Equal("dep"))
+		})
+
+		It("returns error if interval is too short", func() {
+			topOpts.Interval = opts.DurationArg(5 * time.Second)
+
+			err := command.Run(topOpts)
+			Expect(err).To(MatchError("Expected interval to be at least 10s"))
+			Expect(director.DeploymentsCallCount()).To(Equal(0))
+			Expect(screen.CloseCallCount()).To(Equal(1))
+		})
+
+		It("returns error if closing the screen fails", func() {
+			close(keys)
+			screen.CloseReturns(errors.New("fake-err"))
+
+			Expect(command.Run(topOpts)).To(MatchError("fake-err"))
+		})
+	})
+
+	Describe("TopActions", func() {
+		var (
+			ui           *fakeui.FakeUI
+			intSSHRunner *fakessh.FakeRunner
+			actions      cmd.TopActions
+			slug         boshdir.InstanceSlug
+		)
+
+		BeforeEach(func() {
+			ui = &fakeui.FakeUI{Interactive: true}
+			intSSHRunner = &fakessh.FakeRunner{}
+			uuidGen := &fakeuuid.FakeGenerator{GeneratedUUID: "8c5ff117-9572-45c5-8564-8bcf076ecafa"}
+
+			actions = cmd.NewTopActions(
+				director, ui, opts.GatewayFlags{UUIDGen: uuidGen},
+				intSSHRunner, &fakessh.FakeRunner{}, &fakessh.FakeRunner{}, &fakessh.FakeHostBuilder{},
+			)
+
+			slug = boshdir.NewInstanceSlug("redis", "abc")
+		})
+
+		Describe("Restart", func() {
+			It("restarts the instance the same way as the restart command", func() {
+				Expect(actions.Restart("dep", slug)).ToNot(HaveOccurred())
+
+				Expect(director.FindDeploymentArgsForCall(0)).To(Equal("dep"))
+
+				restartSlug, restartOpts := deployment.RestartArgsForCall(0)
+				Expect(restartSlug).To(Equal(boshdir.NewAllOrInstanceGroupOrInstanceSlug("redis", "abc")))
+				Expect(restartOpts).To(Equal(boshdir.RestartOpts{Converge: true}))
+			})
+
+			It("returns error if deployment cannot be found", func() {
+				director.FindDeploymentReturns(nil, errors.New("fake-err"))
+
+				Expect(actions.Restart("dep", slug)).To(MatchError("fake-err"))
+				Expect(deployment.RestartCallCount()).To(Equal(0))
+			})
+
+			It("returns error if restarting fails", func() {
+				deployment.RestartReturns(errors.New("fake-err"))
+
+				Expect(actions.Restart("dep", slug)).To(MatchError("fake-err"))
+			})
+		})
+
+		Describe("SSH", func() {
+			It("runs interactive SSH session to the instance", func() {
+				Expect(actions.SSH("dep", slug)).ToNot(HaveOccurred())
+
+				Expect(director.FindDeploymentArgsForCall(0)).To(Equal("dep"))
+
+				setupSlug, _ := deployment.SetUpSSHArgsForCall(0)
+				Expect(setupSlug).To(Equal(boshdir.NewAllOrInstanceGroupOrInstanceSlug("redis", "abc")))
+
+				Expect(intSSHRunner.RunCallCount()).To(Equal(1))
+				Expect(deployment.CleanUpSSHCallCount()).To(Equal(1))
+			})
+
+			It("returns error if SSH session fails", func() {
+				intSSHRunner.RunReturns(errors.New("fake-err"))
+
+				Expect(actions.SSH("dep", slug)).To(MatchError(ContainSubstring("fake-err")))
+			})
+		})
+	})
+})
@@ -6,6 +6,7 @@ import (
 	semver "github.com/cppforlife/go-semi-semantic/version"
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	bicrypto "github.com/cloudfoundry/bosh-cli/v7/crypto"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
 	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
@@ -19,6 +20,8 @@ type UploadReleaseCmd struct {
 	director              boshdir.Director
 	releaseArchiveFactory func(string) boshdir.ReleaseArchive
 
+	signer bicrypto.ArtifactSigner
+
 	cmdRunner boshsys.CmdRunner
 	fs        boshsys.FileSystem
 	ui        boshui.UI
@@ -29,6 +32,7 @@ func NewUploadReleaseCmd(
 	releaseArchiveWriter boshrel.Writer,
 	director boshdir.Director,
 	releaseArchiveFactory func(string) boshdir.ReleaseArchive,
+	signer bicrypto.ArtifactSigner,
 	cmdRunner boshsys.CmdRunner,
 	fs boshsys.FileSystem,
 	ui boshui.UI,
@@ -40,6 +44,8 @@ func NewUploadReleaseCmd(
 		director:              director,
 		releaseArchiveFactory: releaseArchiveFactory,
 
+		signer: signer,
+
 		cmdRunner: cmdRunner,
 		fs:        fs,
 		ui:        ui,
@@ -47,6 +53,17 @@ func NewUploadReleaseCmd(
 }
 
 func (c UploadReleaseCmd) Run(opts UploadReleaseOpts) error {
+	if len(opts.VerifyKey.Bytes) > 0 {
+		path, err := c.verifiedCopy(opts)
+		if err != nil {
+			return err
+		}
+
+		defer c.fs.RemoveAll(path) //nolint:errcheck
+
+		opts.Args.URL = URLArg(path)
+	}
+
 	switch {
 	case opts.Args.URL.IsRemote():
 		return c.uploadIfNecessary(opts, c.uploadRemote)
@@ -57,6 +74,16 @@ func (c UploadReleaseCmd) Run(opts UploadReleaseOpts) error {
 	}
 }
 
+func (c UploadReleaseCmd) verifiedCopy(opts UploadReleaseOpts) (string, error) {
+	path := opts.Args.URL.FilePath()
+
+	if opts.Args.URL.IsRemote() || opts.Args.URL.IsGit() || len(path) == 0 {
+		return "", bosherr.Errorf("Verifying release signature requires a local release tarball")
+	}
+
+	return verifiedArtifactCopy(c.signer, c.fs, path, opts.Signature.ExpandedPath, opts.VerifyKey.Bytes)
+}
+
 func (c UploadReleaseCmd) uploadRemote(opts UploadReleaseOpts) error {
 	return c.director.UploadReleaseURL(string(opts.Args.URL), opts.SHA1, opts.Rebase, opts.Fix)
 }
@@ -3,6 +3,7 @@ package cmd_test
 import (
 	"errors"
 
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	semver "github.com/cppforlife/go-semi-semantic/version"
 	. "github.com/onsi/ginkgo/v2"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/cloudfoundry/bosh-cli/v7/cmd"
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakecrypto "github.com/cloudfoundry/bosh-cli/v7/crypto/cryptofakes"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
 	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
@@ -26,6 +28,7 @@ var _ = Describe("UploadReleaseCmd", func() {
 		releaseWriter *fakerel.FakeWriter
 		releaseDir    *fakereldir.FakeReleaseDir
 		director      *fakedir.FakeDirector
+		signer        *fakecrypto.FakeArtifactSigner
 		cmdRunner     *fakesys.FakeCmdRunner
 		fs            *fakesys.FakeFileSystem
 		archive       *fakedir.FakeReleaseArchive
@@ -46,6 +49,7 @@ var _ = Describe("UploadReleaseCmd", func() {
 		releaseWriter.WriteReturns("/archive-path", nil)
 
 		director = &fakedir.FakeDirector{}
+		signer = &fakecrypto.FakeArtifactSigner{}
 		cmdRunner = fakesys.NewFakeCmdRunner()
 		fs = fakesys.NewFakeFileSystem()
 
@@ -62,7 +66,7 @@ var _ = Describe("UploadReleaseCmd", func() {
 
 		ui = &fakeui.FakeUI{}
 
-		command = cmd.NewUploadReleaseCmd(releaseDirFactory, releaseWriter, director, releaseArchiveFactory, cmdRunner, fs, ui)
+		command = cmd.NewUploadReleaseCmd(releaseDirFactory, releaseWriter, director, releaseArchiveFactory, signer, cmdRunner, fs, ui)
 	})
 
 	Describe("Run", func() {
@@ -97,7 +101,7 @@ var _ = Describe("UploadReleaseCmd", func() {
 			})
 
 			It("uploads given release even if reader is nil", func() {
-				command = cmd.NewUploadReleaseCmd(nil, nil, director, nil, nil, nil, ui)
+				command = cmd.NewUploadReleaseCmd(nil, nil, director, nil, nil, nil, nil, ui)
 
 				err := command.Run(uploadReleaseOpts)
 				Expect(err).ToNot(HaveOccurred())
@@ -240,7 +244,7 @@ var _ = Describe("UploadReleaseCmd", func() {
 			})
 
 			It("returns an error if reader is nil", func() {
-				command = cmd.NewUploadReleaseCmd(nil, nil, director, nil, nil, nil, ui)
+				command = cmd.NewUploadReleaseCmd(nil, nil, director, nil, nil, nil, nil, ui)
 
 				err := command.Run(uploadReleaseOpts)
 				Expect(err).To(HaveOccurred())
@@ -430,7 +434,7 @@ var _ = Describe("UploadReleaseCmd", func() {
 			})
 
 			It("returns an error if reader is nil", func() {
-				command = cmd.NewUploadReleaseCmd(nil, nil, director, nil, cmdRunner, fs, ui)
+				command = cmd.NewUploadReleaseCmd(nil, nil, director, nil, nil, cmdRunner, fs, ui)
 
 				err := command.Run(uploadReleaseOpts)
 				Expect(err).To(HaveOccurred())
@@ -659,5 +663,113 @@ var _ = Describe("UploadReleaseCmd", func() {
 				Expect(err.Error()).To(ContainSubstring("fake-err"))
 			})
 		})
+
+		Context("when verify key is given", func() {
+			BeforeEach(func() {
+				uploadReleaseOpts.Args.URL = "/release.tgz"
+				uploadReleaseOpts.VerifyKey = opts.FileBytesArg{Bytes: []byte("public-key")}
+
+				err := fs.WriteFileString("/release.tgz", "release")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = fs.WriteFileString("/release.tgz.sig", "signature")
+				Expect(err).ToNot(HaveOccurred())
+
+				fs.ReturnTempFilesByPrefix = map[string]boshsys.File{
+					"bosh-verified-artifact": fakesys.NewFakeFile("/verified-release.tgz", fs),
+				}
+
+				releaseReader.ReadReturns(&fakerel.FakeRelease{}, nil)
+			})
+
+			It("verifies signature next to the release tarball against a copy of it", func() {
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(signer.VerifyCallCount()).To(Equal(1))
+				path, sig, key := signer.VerifyArgsForCall(0)
+				Expect(path).To(Equal("/verified-release.tgz"))
+				Expect(sig).To(Equal([]byte("signature")))
+				Expect(key).To(Equal([]byte("public-key")))
+			})
+
+			It("reads release from the verified copy and removes it afterwards", func() {
+				var copyContents string
+
+				signer.VerifyStub = func(path string, _, _ []byte) error {
+					var err error
+					copyContents, err = fs.ReadFileString(path)
+					return err
+				}
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(copyContents).To(Equal("release"))
+
+				Expect(releaseReader.ReadCallCount()).To(Equal(1))
+				Expect(releaseReader.ReadArgsForCall(0)).To(Equal("/verified-release.tgz"))
+				Expect(director.UploadReleaseFileCallCount()).To(Equal(1))
+
+				Expect(fs.FileExists("/verified-release.tgz")).To(BeFalse())
+				Expect(fs.FileExists("/release.tgz")).To(BeTrue())
+			})
+
+			It("verifies signature at custom path", func() {
+				uploadReleaseOpts.Signature = opts.FileArg{ExpandedPath: "/custom.sig"}
+
+				err := fs.WriteFileString("/custom.sig", "custom-signature")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = act()
+				Expect(err).ToNot(HaveOccurred())
+
+				_, sig, _ := signer.VerifyArgsForCall(0)
+				Expect(sig).To(Equal([]byte("custom-signature")))
+			})
+
+			It("refuses unsigned release", func() {
+				err := fs.RemoveAll("/release.tgz.sig")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Expected '/release.tgz' to be signed but signature '/release.tgz.sig' does not exist"))
+
+				Expect(signer.VerifyCallCount()).To(Equal(0))
+				Expect(releaseReader.ReadCallCount()).To(Equal(0))
+				Expect(director.UploadReleaseFileCallCount()).To(Equal(0))
+			})
+
+			It("refuses release with invalid signature", func() {
+				signer.VerifyReturns(errors.New("fake-err"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(releaseReader.ReadCallCount()).To(Equal(0))
+				Expect(director.UploadReleaseFileCallCount()).To(Equal(0))
+				Expect(fs.FileExists("/verified-release.tgz")).To(BeFalse())
+			})
+
+			It("refuses remote releases", func() {
+				uploadReleaseOpts.Args.URL = "https://some-file.tzg"
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Verifying release signature requires a local release tarball"))
+
+				Expect(director.UploadReleaseURLCallCount()).To(Equal(0))
+			})
+
+			It("refuses releases from release directory", func() {
+				uploadReleaseOpts.Args.URL = ""
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Verifying release signature requires a local release tarball"))
+			})
+		})
 	})
 })
@@ -2,9 +2,11 @@ package cmd
 
 import (
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	semver "github.com/cppforlife/go-semi-semantic/version"
 
 	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	bicrypto "github.com/cloudfoundry/bosh-cli/v7/crypto"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	biui "github.com/cloudfoundry/bosh-cli/v7/ui"
 )
@@ -13,22 +15,44 @@ type UploadStemcellCmd struct {
 	director               boshdir.Director
 	stemcellArchiveFactory func(string) boshdir.StemcellArchive
 
+	signer bicrypto.ArtifactSigner
+	fs     boshsys.FileSystem
+
 	ui biui.UI
 }
 
 func NewUploadStemcellCmd(
 	director boshdir.Director,
 	stemcellArchiveFactory func(string) boshdir.StemcellArchive,
+	signer bicrypto.ArtifactSigner,
+	fs boshsys.FileSystem,
 	ui biui.UI,
 ) UploadStemcellCmd {
 	return UploadStemcellCmd{
 		director:               director,
 		stemcellArchiveFactory: stemcellArchiveFactory,
+		signer:                 signer,
+		fs:                     fs,
 		ui:                     ui,
 	}
 }
 
 func (c UploadStemcellCmd) Run(opts UploadStemcellOpts) error {
+	if len(opts.VerifyKey.Bytes) > 0 {
+		if opts.Args.URL.IsRemote() {
+			return bosherr.Errorf("Verifying stemcell signature requires a local stemcell tarball")
+		}
+
+		path, err := verifiedArtifactCopy(c.signer, c.fs, opts.Args.URL.FilePath(), opts.Signature.ExpandedPath, opts.VerifyKey.Bytes)
+		if err != nil {
+			return err
+		}
+
+		defer c.fs.RemoveAll(path) //nolint:errcheck
+
+		return c.uploadFile(path, opts.Fix)
+	}
+
 	if opts.Args.URL.IsRemote() {
 		return c.uploadRemote(string(opts.Args.URL), opts)
 	}
@@ -3,6 +3,7 @@ package cmd_test
 import (
 	"errors"
 
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	semver "github.com/cppforlife/go-semi-semantic/version"
 	. "github.com/onsi/ginkgo/v2"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/cloudfoundry/bosh-cli/v7/cmd"
 	"github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	fakecrypto "github.com/cloudfoundry/bosh-cli/v7/crypto/cryptofakes"
 	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
 	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
 	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
@@ -20,6 +22,7 @@ var _ = Describe("UploadStemcellCmd", func() {
 		director         *fakedir.FakeDirector
 		fs               *fakesys.FakeFileSystem
 		archive          *fakedir.FakeStemcellArchive
+		signer           *fakecrypto.FakeArtifactSigner
 		ui               *fakeui.FakeUI
 		command          cmd.UploadStemcellCmd
 		existingInfo     boshdir.StemcellInfo
@@ -30,6 +33,7 @@ var _ = Describe("UploadStemcellCmd", func() {
 		director = &fakedir.FakeDirector{}
 		fs = fakesys.NewFakeFileSystem()
 		archive = &fakedir.FakeStemcellArchive{}
+		signer = &fakecrypto.FakeArtifactSigner{}
 		ui = &fakeui.FakeUI{}
 		existingInfo = boshdir.StemcellInfo{Name: "existing-name", Version: "existing-ver"}
 		existingMetadata = boshdir.StemcellMetadata{Name: "existing-name", Version: "existing-ver"}
@@ -43,7 +47,7 @@ var _ = Describe("UploadStemcellCmd", func() {
 			return archive
 		}
 
-		command = cmd.NewUploadStemcellCmd(director, stemcellArchiveFactory, ui)
+		command = cmd.NewUploadStemcellCmd(director, stemcellArchiveFactory, signer, fs, ui)
 	})
 
 	Describe("Run", func() {
@@ -287,5 +291,91 @@ var _ = Describe("UploadStemcellCmd", func() {
 				Expect(director.UploadStemcellFileCallCount()).To(Equal(0))
 			})
 		})
+
+		Context("when verify key is given", func() {
+			BeforeEach(func() {
+				uploadStemcellOpts.Args.URL = "/stemcell.tgz"
+				uploadStemcellOpts.VerifyKey = opts.FileBytesArg{Bytes: []byte("public-key")}
+
+				err := fs.WriteFileString("/stemcell.tgz", "stemcell")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = fs.WriteFileString("/stemcell.tgz.sig", "signature")
+				Expect(err).ToNot(HaveOccurred())
+
+				fs.ReturnTempFilesByPrefix = map[string]boshsys.File{
+					"bosh-verified-artifact": fakesys.NewFakeFile("/verified-stemcell.tgz", fs),
+				}
+
+				archive.InfoReturns(existingMetadata, nil)
+				director.StemcellNeedsUploadReturns(true, nil)
+			})
+
+			It("verifies signature next to the stemcell tarball against a copy of it", func() {
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(signer.VerifyCallCount()).To(Equal(1))
+				path, sig, key := signer.VerifyArgsForCall(0)
+				Expect(path).To(Equal("/verified-stemcell.tgz"))
+				Expect(sig).To(Equal([]byte("signature")))
+				Expect(key).To(Equal([]byte("public-key")))
+			})
+
+			It("uploads the verified copy and removes it afterwards", func() {
+				var copyContents string
+
+				signer.VerifyStub = func(path string, _, _ []byte) error {
+					var err error
+					copyContents, err = fs.ReadFileString(path)
+					return err
+				}
+
+				err := act()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(copyContents).To(Equal("stemcell"))
+
+				Expect(director.UploadStemcellFileCallCount()).To(Equal(1))
+				file, _ := director.UploadStemcellFileArgsForCall(0)
+				Expect(file.(*fakesys.FakeFile).Name()).To(Equal("/verified-stemcell.tgz"))
+
+				Expect(fs.FileExists("/verified-stemcell.tgz")).To(BeFalse())
+				Expect(fs.FileExists("/stemcell.tgz")).To(BeTrue())
+			})
+
+			It("refuses unsigned stemcell", func() {
+				err := fs.RemoveAll("/stemcell.tgz.sig")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("signature '/stemcell.tgz.sig' does not exist"))
+
+				Expect(director.UploadStemcellFileCallCount()).To(Equal(0))
+			})
+
+			It("refuses stemcell with invalid signature", func() {
+				signer.VerifyReturns(errors.New("fake-err"))
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+
+				Expect(archive.InfoCallCount()).To(Equal(0))
+				Expect(director.UploadStemcellFileCallCount()).To(Equal(0))
+				Expect(fs.FileExists("/verified-stemcell.tgz")).To(BeFalse())
+			})
+
+			It("refuses remote stemcells", func() {
+				uploadStemcellOpts.Args.URL = "https://some-file.tzg"
+
+				err := act()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Verifying stemcell signature requires a local stemcell tarball"))
+
+				Expect(director.UploadStemcellURLCallCount()).To(Equal(0))
+			})
+		})
 	})
 })
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+type ValidateStemcellCmd struct {
+	archiveFactory func(string) boshinsp.Archive
+	ui             boshui.UI
+}
+
+func NewValidateStemcellCmd(archiveFactory func(string) boshinsp.Archive, ui boshui.UI) ValidateStemcellCmd {
+	return ValidateStemcellCmd{archiveFactory: archiveFactory, ui: ui}
+}
+
+func (c ValidateStemcellCmd) Run(opts ValidateStemcellOpts) error {
+	problems, err := c.archiveFactory(opts.Args.PathToStemcell).Validate()
+	if err != nil {
+		return bosherr.WrapError(err, "Validating stemcell")
+	}
+
+	table := boshtbl.Table{
+		Content: "problems",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Level"),
+			boshtbl.NewHeader("Field"),
+			boshtbl.NewHeader("Message"),
+		},
+	}
+
+	var errCount int
+
+	for _, problem := range problems {
+		if problem.Level == boshinsp.LevelError {
+			errCount++
+		}
+
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(problem.Level),
+			boshtbl.NewValueString(problem.Field),
+			boshtbl.NewValueString(problem.Message),
+		})
+	}
+
+	c.ui.PrintTable(table)
+
+	if errCount > 0 {
+		return bosherr.Errorf("Found %d error(s) in stemcell", errCount)
+	}
+
+	return nil
+}
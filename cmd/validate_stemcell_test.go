@@ -0,0 +1,99 @@
+package cmd_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+	fakeinsp "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect/inspectfakes"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("ValidateStemcellCmd", func() {
+	var (
+		archive      *fakeinsp.FakeArchive
+		ui           *fakeui.FakeUI
+		command      ValidateStemcellCmd
+		archivePaths []string
+	)
+
+	BeforeEach(func() {
+		archive = &fakeinsp.FakeArchive{}
+		ui = &fakeui.FakeUI{}
+		archivePaths = nil
+
+		archiveFactory := func(path string) boshinsp.Archive {
+			archivePaths = append(archivePaths, path)
+			return archive
+		}
+
+		command = NewValidateStemcellCmd(archiveFactory, ui)
+	})
+
+	Describe("Run", func() {
+		var (
+			opts ValidateStemcellOpts
+		)
+
+		BeforeEach(func() {
+			opts = ValidateStemcellOpts{Args: StemcellArgs{PathToStemcell: "/stemcell.tgz"}}
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		It("shows warnings without failing", func() {
+			archive.ValidateReturns([]boshinsp.Problem{
+				{Level: boshinsp.LevelWarning, Field: "extra", Message: "Unknown field"},
+			}, nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(archivePaths).To(Equal([]string{"/stemcell.tgz"}))
+
+			Expect(ui.Table).To(Equal(boshtbl.Table{
+				Content: "problems",
+
+				Header: []boshtbl.Header{
+					boshtbl.NewHeader("Level"),
+					boshtbl.NewHeader("Field"),
+					boshtbl.NewHeader("Message"),
+				},
+
+				Rows: [][]boshtbl.Value{
+					{
+						boshtbl.NewValueString("warning"),
+						boshtbl.NewValueString("extra"),
+						boshtbl.NewValueString("Unknown field"),
+					},
+				},
+			}))
+		})
+
+		It("returns error if any problem is an error", func() {
+			archive.ValidateReturns([]boshinsp.Problem{
+				{Level: boshinsp.LevelError, Field: "name", Message: "Expected non-empty string"},
+				{Level: boshinsp.LevelWarning, Field: "extra", Message: "Unknown field"},
+			}, nil)
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Found 1 error(s) in stemcell"))
+
+			Expect(ui.Table.Rows).To(HaveLen(2))
+		})
+
+		It("returns error if validating fails", func() {
+			archive.ValidateReturns(nil, errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+	})
+})
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	boshcrypto "github.com/cloudfoundry/bosh-utils/crypto"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	bitarball "github.com/cloudfoundry/bosh-cli/v7/installation/tarball"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+const vendorArtifactsOpsFileName = "vendor-ops.yml"
+
+type VendorArtifactsCmd struct {
+	tarballProvider bitarball.Provider
+	stage           boshui.Stage
+	fs              boshsys.FileSystem
+	ui              boshui.UI
+}
+
+type vendorArtifactsManifest struct {
+	Releases []struct {
+		Name     string
+		Version  string
+		URL      string
+		SHA1     string
+		Stemcell struct {
+			OS      string
+			Version string
+		}
+	}
+
+	Stemcells []struct {
+		Alias string
+		URL   string
+		SHA1  string
+	}
+
+	ResourcePools []struct {
+		Name     string
+		Stemcell struct {
+			URL  string
+			SHA1 string
+		}
+	} `yaml:"resource_pools"`
+}
+
+// vendoredArtifact is a release or stemcell tarball referenced
+// from one or more places in the manifest by the same URL
+type vendoredArtifact struct {
+	kind     string
+	name     string
+	url      string
+	sha1     string
+	fileName string
+	opPaths  []string
+}
+
+func (a vendoredArtifact) GetURL() string  { return a.url }
+func (a vendoredArtifact) GetSHA1() string { return a.sha1 }
+
+func (a vendoredArtifact) Description() string {
+	return fmt.Sprintf("%s '%s'", a.kind, a.name)
+}
+
+func NewVendorArtifactsCmd(
+	tarballProvider bitarball.Provider,
+	stage boshui.Stage,
+	fs boshsys.FileSystem,
+	ui boshui.UI,
+) VendorArtifactsCmd {
+	return VendorArtifactsCmd{tarballProvider: tarballProvider, stage: stage, fs: fs, ui: ui}
+}
+
+func (c VendorArtifactsCmd) Run(opts VendorArtifactsOpts) error {
+	tpl := boshtpl.NewTemplate(opts.Args.Manifest.Bytes)
+
+	bytes, err := tpl.Evaluate(opts.VarFlags.AsVariables(), opts.OpsFlags.AsOp(), boshtpl.EvaluateOpts{})
+	if err != nil {
+		return bosherr.WrapError(err, "Evaluating manifest")
+	}
+
+	var manifest vendorArtifactsManifest
+
+	err = yaml.Unmarshal(bytes, &manifest)
+	if err != nil {
+		return bosherr.WrapError(err, "Unmarshalling manifest")
+	}
+
+	artifacts, err := c.collectArtifacts(manifest)
+	if err != nil {
+		return err
+	}
+
+	if len(artifacts) == 0 {
+		return bosherr.Error("Expected manifest to reference at least one release or stemcell by URL")
+	}
+
+	toDir, err := c.fs.ExpandPath(opts.To)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Expanding directory path '%s'", opts.To)
+	}
+
+	err = c.fs.MkdirAll(toDir, 0755)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Creating directory '%s'", toDir)
+	}
+
+	var opDefs []patch.OpDefinition
+
+	for _, artifact := range artifacts {
+		dstPath := filepath.Join(toDir, artifact.fileName)
+
+		err := c.vendor(artifact, dstPath)
+		if err != nil {
+			return err
+		}
+
+		var newURL interface{} = "file://" + dstPath
+		if len(opts.MirrorURL) > 0 {
+			newURL = strings.TrimSuffix(opts.MirrorURL, "/") + "/" + artifact.fileName
+		}
+
+		for _, opPath := range artifact.opPaths {
+			opDefs = append(opDefs, patch.OpDefinition{Type: "replace", Path: &opPath, Value: &newURL})
+		}
+	}
+
+	opsBytes, err := yaml.Marshal(opDefs)
+	if err != nil {
+		return bosherr.WrapError(err, "Marshalling ops file")
+	}
+
+	opsPath := opts.OpsFileOut.ExpandedPath
+	if len(opsPath) == 0 {
+		opsPath = filepath.Join(toDir, vendorArtifactsOpsFileName)
+	}
+
+	err = c.fs.WriteFile(opsPath, opsBytes)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing ops file '%s'", opsPath)
+	}
+
+	c.printTable(artifacts)
+
+	c.ui.PrintLinef("Wrote ops file to '%s'", opsPath)
+
+	return nil
+}
+
+func (c VendorArtifactsCmd) collectArtifacts(manifest vendorArtifactsManifest) ([]*vendoredArtifact, error) {
+	var artifacts []*vendoredArtifact
+
+	byURL := map[string]*vendoredArtifact{}
+	byFileName := map[string]*vendoredArtifact{}
+
+	add := func(candidate vendoredArtifact, opPath string) error {
+		if len(candidate.url) == 0 {
+			return nil
+		}
+
+		if artifact, found := byURL[candidate.url]; found {
+			artifact.opPaths = append(artifact.opPaths, opPath)
+			return nil
+		}
+
+		if len(candidate.sha1) == 0 {
+			return bosherr.Errorf("Expected %s to specify sha1 so that it can be verified", candidate.Description())
+		}
+
+		if other, found := byFileName[candidate.fileName]; found {
+			return bosherr.Errorf("Expected %s and %s to have different file names but both are '%s'",
+				other.Description(), candidate.Description(), candidate.fileName)
+		}
+
+		candidate.opPaths = []string{opPath}
+
+		artifacts = append(artifacts, &candidate)
+		byURL[candidate.url] = &candidate
+		byFileName[candidate.fileName] = &candidate
+
+		return nil
+	}
+
+	for _, rel := range manifest.Releases {
+		fileName := urlFileName(rel.URL)
+
+		if len(rel.Name) > 0 && len(rel.Version) > 0 && rel.Version != "latest" {
+			fileName = fmt.Sprintf("%s-%s.tgz", rel.Name, rel.Version)
+
+			if len(rel.Stemcell.OS) > 0 {
+				fileName = fmt.Sprintf("%s-%s-%s-%s.tgz", rel.Name, rel.Version, rel.Stemcell.OS, rel.Stemcell.Version)
+			}
+		}
+
+		if len(rel.URL) > 0 && len(rel.Name) == 0 {
+			return nil, bosherr.Errorf("Expected release with URL '%s' to specify name", rel.URL)
+		}
+
+		artifact := vendoredArtifact{kind: "release", name: rel.Name, url: rel.URL, sha1: rel.SHA1, fileName: fileName}
+
+		err := add(artifact, vendoredArtifactOpPath("releases", "name", rel.Name, "url"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, stemcell := range manifest.Stemcells {
+		if len(stemcell.URL) > 0 && len(stemcell.Alias) == 0 {
+			return nil, bosherr.Errorf("Expected stemcell with URL '%s' to specify alias", stemcell.URL)
+		}
+
+		artifact := vendoredArtifact{
+			kind: "stemcell", name: stemcell.Alias,
+			url: stemcell.URL, sha1: stemcell.SHA1, fileName: urlFileName(stemcell.URL),
+		}
+
+		err := add(artifact, vendoredArtifactOpPath("stemcells", "alias", stemcell.Alias, "url"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pool := range manifest.ResourcePools {
+		if len(pool.Stemcell.URL) > 0 && len(pool.Name) == 0 {
+			return nil, bosherr.Errorf("Expected resource pool with stemcell URL '%s' to specify name", pool.Stemcell.URL)
+		}
+
+		artifact := vendoredArtifact{
+			kind: "stemcell", name: pool.Name,
+			url: pool.Stemcell.URL, sha1: pool.Stemcell.SHA1, fileName: urlFileName(pool.Stemcell.URL),
+		}
+
+		err := add(artifact, vendoredArtifactOpPath("resource_pools", "name", pool.Name, "stemcell", "url"))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return artifacts, nil
+}
+
+// vendoredArtifactOpPath returns escaped path such as /releases/name=?/url
+func vendoredArtifactOpPath(collection, nameKey, name string, keys ...string) string {
+	tokens := []patch.Token{
+		patch.RootToken{},
+		patch.KeyToken{Key: collection},
+		patch.MatchingIndexToken{Key: nameKey, Value: name},
+	}
+
+	for _, key := range keys {
+		tokens = append(tokens, patch.KeyToken{Key: key})
+	}
+
+	return patch.NewPointer(tokens).String()
+}
+
+func (c VendorArtifactsCmd) vendor(artifact *vendoredArtifact, dstPath string) error {
+	digest, err := boshcrypto.ParseMultipleDigest(artifact.sha1)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Parsing sha1 of %s", artifact.Description())
+	}
+
+	if c.fs.FileExists(dstPath) && digest.VerifyFilePath(dstPath, c.fs) == nil {
+		return c.stage.Perform(fmt.Sprintf("Copying %s", artifact.Description()), func() error {
+			return boshui.NewSkipStageError(bosherr.Error("Already vendored"), "Found in destination directory")
+		})
+	}
+
+	srcPath, err := c.tarballProvider.Get(artifact, c.stage)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Fetching %s", artifact.Description())
+	}
+
+	return c.stage.Perform(fmt.Sprintf("Copying %s", artifact.Description()), func() error {
+		// Local file sources are not verified by the tarball provider
+		err := digest.VerifyFilePath(srcPath, c.fs)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Verifying digest of %s", artifact.Description())
+		}
+
+		err = c.fs.CopyFile(srcPath, dstPath)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Copying %s to '%s'", artifact.Description(), dstPath)
+		}
+
+		return nil
+	})
+}
+
+func (c VendorArtifactsCmd) printTable(artifacts []*vendoredArtifact) {
+	table := boshtbl.Table{
+		Content: "artifacts",
+
+		Header: []boshtbl.Header{
+			boshtbl.NewHeader("Type"),
+			boshtbl.NewHeader("Name"),
+			boshtbl.NewHeader("File"),
+			boshtbl.NewHeader("Source"),
+		},
+	}
+
+	for _, artifact := range artifacts {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(artifact.kind),
+			boshtbl.NewValueString(artifact.name),
+			boshtbl.NewValueString(artifact.fileName),
+			boshtbl.NewValueString(artifact.url),
+		})
+	}
+
+	c.ui.PrintTable(table)
+}
+
+// urlFileName derives tarball file name from URL
+// (e.g. 'https://bosh.io/d/stemcells/bosh-warden-boshlite-ubuntu-jammy-go_agent?v=1.2' becomes
+// 'bosh-warden-boshlite-ubuntu-jammy-go_agent-1.2.tgz')
+func urlFileName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return path.Base(rawURL)
+	}
+
+	fileName := path.Base(u.Path)
+
+	if version := u.Query().Get("v"); len(version) > 0 {
+		fileName += "-" + version
+	}
+
+	if !strings.HasSuffix(fileName, ".tgz") && !strings.HasSuffix(fileName, ".tar.gz") {
+		fileName += ".tgz"
+	}
+
+	return fileName
+}
@@ -0,0 +1,290 @@
+package cmd_test
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd"
+	. "github.com/cloudfoundry/bosh-cli/v7/cmd/opts"
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	bitarball "github.com/cloudfoundry/bosh-cli/v7/installation/tarball"
+	mock_tarball "github.com/cloudfoundry/bosh-cli/v7/installation/tarball/mocks"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+var _ = Describe("VendorArtifactsCmd", func() {
+	var (
+		mockCtrl        *gomock.Controller
+		tarballProvider *mock_tarball.MockProvider
+		stage           *fakeui.FakeStage
+		fs              *fakesys.FakeFileSystem
+		ui              *fakeui.FakeUI
+		command         VendorArtifactsCmd
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		tarballProvider = mock_tarball.NewMockProvider(mockCtrl)
+		stage = fakeui.NewFakeStage()
+		fs = fakesys.NewFakeFileSystem()
+		ui = &fakeui.FakeUI{}
+		command = NewVendorArtifactsCmd(tarballProvider, stage, fs, ui)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			opts VendorArtifactsOpts
+		)
+
+		digestOf := func(content string) string {
+			return fmt.Sprintf("%x", sha1.Sum([]byte(content)))
+		}
+
+		BeforeEach(func() {
+			opts = VendorArtifactsOpts{
+				Args: VendorArtifactsArgs{
+					Manifest: FileBytesArg{Bytes: []byte(fmt.Sprintf(`
+releases:
+- name: rel1
+  version: "1"
+  url: https://example.com/rel1.tgz
+  sha1: %s
+- name: rel2
+  version: "2"
+  url: ((rel2_url))
+  sha1: %s
+  stemcell: {os: ubuntu-jammy, version: "1.10"}
+- name: rel3
+  version: create
+stemcells:
+- alias: default
+  url: https://bosh.io/d/stemcells/bosh-warden-boshlite-ubuntu-jammy-go_agent?v=1.10
+  sha1: sha256:%x
+`, digestOf("rel1"), digestOf("rel2"), sha256.Sum256(nil)))},
+				},
+				To: "/vendor",
+			}
+
+			opts.VarKVs = []boshtpl.VarKV{{Name: "rel2_url", Value: "file:///local/rel2.tgz"}}
+
+			fs.WriteFileString("/downloads/rel1", "rel1")           //nolint:errcheck
+			fs.WriteFileString("/local/rel2.tgz", "rel2")           //nolint:errcheck
+			fs.WriteFileString("/downloads/stemcell", "")           //nolint:errcheck
+			fs.WriteFileString("/downloads/stemcell-invalid", "sc") //nolint:errcheck
+		})
+
+		act := func() error { return command.Run(opts) }
+
+		expectDownloads := func() {
+			tarballProvider.EXPECT().Get(gomock.Any(), stage).DoAndReturn(
+				func(source bitarball.Source, _ boshui.Stage) (string, error) {
+					switch source.GetURL() {
+					case "https://example.com/rel1.tgz":
+						return "/downloads/rel1", nil
+					case "file:///local/rel2.tgz":
+						return "/local/rel2.tgz", nil
+					default:
+						return "/downloads/stemcell", nil
+					}
+				}).Times(3)
+		}
+
+		It("copies releases and stemcells into directory and writes ops file pointing to them", func() {
+			expectDownloads()
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.ReadFileString("/vendor/rel1-1.tgz")).To(Equal("rel1"))
+			Expect(fs.ReadFileString("/vendor/rel2-2-ubuntu-jammy-1.10.tgz")).To(Equal("rel2"))
+			Expect(fs.FileExists("/vendor/bosh-warden-boshlite-ubuntu-jammy-go_agent-1.10.tgz")).To(BeTrue())
+
+			Expect(fs.ReadFileString("/vendor/vendor-ops.yml")).To(Equal(`- type: replace
+  path: /releases/name=rel1/url
+  value: file:///vendor/rel1-1.tgz
+- type: replace
+  path: /releases/name=rel2/url
+  value: file:///vendor/rel2-2-ubuntu-jammy-1.10.tgz
+- type: replace
+  path: /stemcells/alias=default/url
+  value: file:///vendor/bosh-warden-boshlite-ubuntu-jammy-go_agent-1.10.tgz
+`))
+
+			Expect(ui.Said).To(ContainElement("Wrote ops file to '/vendor/vendor-ops.yml'"))
+
+			Expect(ui.Table.Content).To(Equal("artifacts"))
+			Expect(ui.Table.Rows).To(HaveLen(3))
+			Expect(ui.Table.Rows[0]).To(Equal([]boshtbl.Value{
+				boshtbl.NewValueString("release"),
+				boshtbl.NewValueString("rel1"),
+				boshtbl.NewValueString("rel1-1.tgz"),
+				boshtbl.NewValueString("https://example.com/rel1.tgz"),
+			}))
+		})
+
+		It("points ops file to mirror URL prefix if given", func() {
+			expectDownloads()
+
+			opts.MirrorURL = "https://mirror.internal/bosh/"
+			opts.OpsFileOut = FileArg{ExpandedPath: "/ops.yml"}
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.FileExists("/vendor/vendor-ops.yml")).To(BeFalse())
+			Expect(fs.ReadFileString("/ops.yml")).To(ContainSubstring(
+				"value: https://mirror.internal/bosh/rel2-2-ubuntu-jammy-1.10.tgz\n"))
+		})
+
+		It("replaces stemcell URLs of resource pools in create-env manifests", func() {
+			opts.Args.Manifest.Bytes = []byte(fmt.Sprintf(`
+resource_pools:
+- name: vms
+  stemcell:
+    url: https://example.com/stemcell.tgz
+    sha1: %s
+- name: other-vms
+  stemcell:
+    url: https://example.com/stemcell.tgz
+    sha1: %s
+`, digestOf(""), digestOf("")))
+
+			tarballProvider.EXPECT().Get(gomock.Any(), stage).Return("/downloads/stemcell", nil).Times(1)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.ReadFileString("/vendor/vendor-ops.yml")).To(Equal(`- type: replace
+  path: /resource_pools/name=vms/stemcell/url
+  value: file:///vendor/stemcell.tgz
+- type: replace
+  path: /resource_pools/name=other-vms/stemcell/url
+  value: file:///vendor/stemcell.tgz
+`))
+		})
+
+		It("skips artifacts already present in directory with matching digest", func() {
+			fs.WriteFileString("/vendor/rel1-1.tgz", "rel1") //nolint:errcheck
+
+			tarballProvider.EXPECT().Get(gomock.Any(), stage).Return("/local/rel2.tgz", nil).Times(1)
+			tarballProvider.EXPECT().Get(gomock.Any(), stage).Return("/downloads/stemcell", nil).Times(1)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(stage.PerformCalls[0].Name).To(Equal("Copying release 'rel1'"))
+			Expect(stage.PerformCalls[0].SkipError).To(HaveOccurred())
+		})
+
+		It("returns error if local file does not match digest", func() {
+			tarballProvider.EXPECT().Get(gomock.Any(), stage).Return("/downloads/stemcell-invalid", nil)
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Verifying digest of release 'rel1'"))
+
+			Expect(fs.FileExists("/vendor/rel1-1.tgz")).To(BeFalse())
+		})
+
+		It("returns error if fetching fails", func() {
+			tarballProvider.EXPECT().Get(gomock.Any(), stage).Return("", errors.New("fake-err"))
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Fetching release 'rel1'"))
+			Expect(err.Error()).To(ContainSubstring("fake-err"))
+		})
+
+		It("returns error if artifact does not specify sha1", func() {
+			opts.Args.Manifest.Bytes = []byte("releases:\n- name: rel1\n  url: https://example.com/rel1.tgz\n")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected release 'rel1' to specify sha1 so that it can be verified"))
+		})
+
+		It("escapes names in ops file paths", func() {
+			opts.Args.Manifest.Bytes = []byte(fmt.Sprintf(`
+stemcells:
+- alias: jammy/latest
+  url: https://example.com/stemcell.tgz
+  sha1: %s
+`, digestOf("")))
+
+			tarballProvider.EXPECT().Get(gomock.Any(), stage).Return("/downloads/stemcell", nil)
+
+			err := act()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.ReadFileString("/vendor/vendor-ops.yml")).To(ContainSubstring("path: /stemcells/alias=jammy~1latest/url\n"))
+		})
+
+		It("returns error if release with URL does not specify name", func() {
+			opts.Args.Manifest.Bytes = []byte("releases:\n- url: https://example.com/rel1.tgz\n  sha1: abc\n")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected release with URL 'https://example.com/rel1.tgz' to specify name"))
+		})
+
+		It("returns error if stemcell with URL does not specify alias", func() {
+			opts.Args.Manifest.Bytes = []byte("stemcells:\n- url: https://example.com/stemcell.tgz\n  sha1: abc\n")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected stemcell with URL 'https://example.com/stemcell.tgz' to specify alias"))
+		})
+
+		It("returns error if resource pool with stemcell URL does not specify name", func() {
+			opts.Args.Manifest.Bytes = []byte("resource_pools:\n- stemcell: {url: https://example.com/stemcell.tgz, sha1: abc}\n")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected resource pool with stemcell URL 'https://example.com/stemcell.tgz' to specify name"))
+		})
+
+		It("returns error if artifacts would be saved under same file name", func() {
+			opts.Args.Manifest.Bytes = []byte(`
+stemcells:
+- alias: a
+  url: https://example.com/a/stemcell.tgz
+  sha1: abc
+- alias: b
+  url: https://example.com/b/stemcell.tgz
+  sha1: abc
+`)
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("both are 'stemcell.tgz'"))
+		})
+
+		It("returns error if manifest does not reference any URLs", func() {
+			opts.Args.Manifest.Bytes = []byte("releases:\n- name: rel1\n  version: create\n")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected manifest to reference at least one release or stemcell"))
+		})
+
+		It("returns error if manifest cannot be evaluated", func() {
+			opts.Args.Manifest.Bytes = []byte("releases: [")
+
+			err := act()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,65 @@
+package config
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-utils/uuid"
+)
+
+// dryRunDeploymentStateService starts from the deployment state file,
+// if there is one, but keeps all changes in memory
+type dryRunDeploymentStateService struct {
+	fileService     *fileSystemDeploymentStateService
+	deploymentState *DeploymentState
+}
+
+func NewDryRunDeploymentStateService(fs boshsys.FileSystem, uuidGenerator boshuuid.Generator, logger boshlog.Logger, deploymentStatePath string) DeploymentStateService {
+	return &dryRunDeploymentStateService{
+		fileService: &fileSystemDeploymentStateService{
+			configPath:    deploymentStatePath,
+			fs:            fs,
+			uuidGenerator: uuidGenerator,
+			logger:        logger,
+			logTag:        "config",
+		},
+	}
+}
+
+func (s *dryRunDeploymentStateService) Path() string {
+	return s.fileService.Path()
+}
+
+func (s *dryRunDeploymentStateService) Exists() bool {
+	return s.deploymentState != nil || s.fileService.Exists()
+}
+
+func (s *dryRunDeploymentStateService) Load() (DeploymentState, error) {
+	if s.deploymentState == nil {
+		deploymentState, err := s.fileService.read()
+		if err != nil {
+			return DeploymentState{}, err
+		}
+
+		if deploymentState.DirectorID == "" {
+			deploymentState.DirectorID, err = s.fileService.uuidGenerator.Generate()
+			if err != nil {
+				return DeploymentState{}, bosherr.WrapError(err, "Generating DirectorID")
+			}
+		}
+
+		s.deploymentState = &deploymentState
+	}
+
+	return *s.deploymentState, nil
+}
+
+func (s *dryRunDeploymentStateService) Save(deploymentState DeploymentState) error {
+	s.deploymentState = &deploymentState
+	return nil
+}
+
+func (s *dryRunDeploymentStateService) Cleanup() error {
+	s.deploymentState = &DeploymentState{}
+	return nil
+}
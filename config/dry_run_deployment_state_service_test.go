@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/config"
+)
+
+var _ = Describe("dryRunDeploymentStateService", func() {
+	var (
+		service             DeploymentStateService
+		deploymentStatePath string
+		fakeFs              *fakesys.FakeFileSystem
+	)
+
+	BeforeEach(func() {
+		fakeFs = fakesys.NewFakeFileSystem()
+		deploymentStatePath = "/some/deployment.json"
+		logger := boshlog.NewLogger(boshlog.LevelNone)
+		service = NewDryRunDeploymentStateService(fakeFs, fakeuuid.NewFakeGenerator(), logger, deploymentStatePath)
+	})
+
+	Describe("Load", func() {
+		It("reads the given config file", func() {
+			err := fakeFs.WriteFileString(deploymentStatePath, `{"director_id":"fake-director-id","current_vm_cid":"fake-vm-cid"}`)
+			Expect(err).ToNot(HaveOccurred())
+
+			deploymentState, err := service.Load()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deploymentState.DirectorID).To(Equal("fake-director-id"))
+			Expect(deploymentState.CurrentVMCID).To(Equal("fake-vm-cid"))
+		})
+
+		Context("when the config does not exist", func() {
+			It("returns a new DeploymentState with generated defaults without writing the file", func() {
+				deploymentState, err := service.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deploymentState).To(Equal(DeploymentState{
+					DirectorID: "fake-uuid-0",
+				}))
+
+				Expect(fakeFs.FileExists(deploymentStatePath)).To(BeFalse())
+
+				deploymentState, err = service.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deploymentState.DirectorID).To(Equal("fake-uuid-0"))
+			})
+		})
+
+		Context("when the config is invalid", func() {
+			It("returns an error", func() {
+				err := fakeFs.WriteFileString(deploymentStatePath, "some invalid content")
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = service.Load()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Unmarshalling deployment state file '/some/deployment.json'"))
+			})
+		})
+	})
+
+	Describe("Save", func() {
+		It("keeps the deployment state in memory", func() {
+			err := fakeFs.WriteFileString(deploymentStatePath, `{"director_id":"fake-director-id"}`)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = service.Save(DeploymentState{DirectorID: "fake-director-id", CurrentVMCID: "fake-vm-cid"})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploymentState, err := service.Load()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deploymentState.CurrentVMCID).To(Equal("fake-vm-cid"))
+
+			Expect(fakeFs.ReadFileString(deploymentStatePath)).To(Equal(`{"director_id":"fake-director-id"}`))
+		})
+	})
+
+	Describe("Cleanup", func() {
+		It("does not delete the deployment file", func() {
+			err := fakeFs.WriteFileString(deploymentStatePath, `{"director_id":"fake-director-id"}`)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = service.Cleanup()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeFs.FileExists(deploymentStatePath)).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,191 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"hash"
+	"io"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . ArtifactSigner
+
+// ArtifactSigner produces and checks detached signatures of release and stemcell tarballs.
+//
+// Keys are PEM encoded ed25519 or ECDSA keys (PKCS#8/SEC1 private keys, PKIX public keys).
+// Encrypted private keys are not supported. Signatures are base64 encoded.
+// ECDSA signatures are made over SHA-256 digest of the artifact.
+// ed25519 signatures use ed25519ph (SHA-512 pre-hash) so that large artifacts
+// do not need to be read into memory.
+type ArtifactSigner interface {
+	Sign(artifactPath string, privateKeyPEM []byte) ([]byte, error)
+	Verify(artifactPath string, signature []byte, publicKeyPEM []byte) error
+}
+
+const SignatureExt = ".sig"
+
+// SignaturePath returns default location of the detached signature for an artifact
+func SignaturePath(artifactPath string) string {
+	return artifactPath + SignatureExt
+}
+
+type artifactSigner struct {
+	fs boshsys.FileSystem
+}
+
+func NewArtifactSigner(fs boshsys.FileSystem) ArtifactSigner {
+	return artifactSigner{fs: fs}
+}
+
+func (s artifactSigner) Sign(artifactPath string, privateKeyPEM []byte) ([]byte, error) {
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	var sig []byte
+
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		digest, err := s.digest(artifactPath, sha512.New())
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err = key.Sign(nil, digest, &ed25519.Options{Hash: crypto.SHA512})
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Signing artifact")
+		}
+
+	case *ecdsa.PrivateKey:
+		digest, err := s.digest(artifactPath, sha256.New())
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err = ecdsa.SignASN1(rand.Reader, key, digest)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Signing artifact")
+		}
+
+	default:
+		return nil, bosherr.Errorf("Unsupported private key type '%T', expected ed25519 or ECDSA key", key)
+	}
+
+	return []byte(base64.StdEncoding.EncodeToString(sig)), nil
+}
+
+func (s artifactSigner) Verify(artifactPath string, signature []byte, publicKeyPEM []byte) error {
+	key, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return bosherr.WrapError(err, "Decoding signature")
+	}
+
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		digest, err := s.digest(artifactPath, sha512.New())
+		if err != nil {
+			return err
+		}
+
+		err = ed25519.VerifyWithOptions(key, digest, sig, &ed25519.Options{Hash: crypto.SHA512})
+		if err != nil {
+			return bosherr.Errorf("Signature verification failed for '%s'", artifactPath)
+		}
+
+	case *ecdsa.PublicKey:
+		digest, err := s.digest(artifactPath, sha256.New())
+		if err != nil {
+			return err
+		}
+
+		if !ecdsa.VerifyASN1(key, digest, sig) {
+			return bosherr.Errorf("Signature verification failed for '%s'", artifactPath)
+		}
+
+	default:
+		return bosherr.Errorf("Unsupported public key type '%T', expected ed25519 or ECDSA key", key)
+	}
+
+	return nil
+}
+
+func (s artifactSigner) digest(artifactPath string, h hash.Hash) ([]byte, error) {
+	file, err := s.fs.OpenFile(artifactPath, 0, 0)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Opening artifact '%s'", artifactPath)
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading artifact '%s'", artifactPath)
+	}
+
+	return h.Sum(nil), nil
+}
+
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, bosherr.Error("Parsing private key: expected PEM encoded key")
+	}
+
+	if strings.HasPrefix(block.Type, "ENCRYPTED") {
+		return nil, bosherr.Errorf("Parsing private key: encrypted keys ('%s') are not supported, use unencrypted PKCS#8 key", block.Type)
+	}
+
+	if block.Type == "EC PRIVATE KEY" {
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Parsing private key")
+		}
+
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Parsing private key")
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, bosherr.Errorf("Unsupported private key type '%T', expected ed25519 or ECDSA key", key)
+	}
+
+	return signer, nil
+}
+
+func parsePublicKey(keyPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, bosherr.Error("Parsing public key: expected PEM encoded key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Parsing public key")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,205 @@
+package crypto_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/crypto"
+)
+
+var _ = Describe("ArtifactSigner", func() {
+	var (
+		artifactPath string
+		signer       ArtifactSigner
+	)
+
+	encodePrivateKey := func(key interface{}) []byte {
+		keyBytes, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+	}
+
+	encodePublicKey := func(key interface{}) []byte {
+		keyBytes, err := x509.MarshalPKIXPublicKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: keyBytes})
+	}
+
+	BeforeEach(func() {
+		artifactPath = filepath.Join(GinkgoT().TempDir(), "release.tgz")
+		Expect(os.WriteFile(artifactPath, []byte("release-content"), 0600)).To(Succeed())
+
+		signer = NewArtifactSigner(boshsys.NewOsFileSystem(boshlog.NewLogger(boshlog.LevelNone)))
+	})
+
+	Describe("SignaturePath", func() {
+		It("returns path next to the artifact", func() {
+			Expect(SignaturePath("/tmp/release.tgz")).To(Equal("/tmp/release.tgz.sig"))
+		})
+	})
+
+	Context("with ed25519 keys", func() {
+		var (
+			privateKeyPEM []byte
+			publicKeyPEM  []byte
+		)
+
+		BeforeEach(func() {
+			pub, priv, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			privateKeyPEM = encodePrivateKey(priv)
+			publicKeyPEM = encodePublicKey(pub)
+		})
+
+		It("produces signature that can be verified", func() {
+			sig, err := signer.Sign(artifactPath, privateKeyPEM)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(signer.Verify(artifactPath, sig, publicKeyPEM)).To(Succeed())
+		})
+
+		It("refuses tampered artifact", func() {
+			sig, err := signer.Sign(artifactPath, privateKeyPEM)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.WriteFile(artifactPath, []byte("tampered-content"), 0600)).To(Succeed())
+
+			err = signer.Verify(artifactPath, sig, publicKeyPEM)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Signature verification failed for '" + artifactPath + "'"))
+		})
+
+		It("refuses signature made by another key", func() {
+			_, otherPriv, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			sig, err := signer.Sign(artifactPath, encodePrivateKey(otherPriv))
+			Expect(err).ToNot(HaveOccurred())
+
+			err = signer.Verify(artifactPath, sig, publicKeyPEM)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Signature verification failed"))
+		})
+	})
+
+	Context("with ECDSA keys", func() {
+		var (
+			privateKey    *ecdsa.PrivateKey
+			privateKeyPEM []byte
+			publicKeyPEM  []byte
+		)
+
+		BeforeEach(func() {
+			var err error
+
+			privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			privateKeyPEM = encodePrivateKey(privateKey)
+			publicKeyPEM = encodePublicKey(&privateKey.PublicKey)
+		})
+
+		It("produces signature that can be verified", func() {
+			sig, err := signer.Sign(artifactPath, privateKeyPEM)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(signer.Verify(artifactPath, sig, publicKeyPEM)).To(Succeed())
+		})
+
+		It("accepts SEC1 encoded private keys", func() {
+			keyBytes, err := x509.MarshalECPrivateKey(privateKey)
+			Expect(err).ToNot(HaveOccurred())
+
+			sig, err := signer.Sign(artifactPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}))
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(signer.Verify(artifactPath, sig, publicKeyPEM)).To(Succeed())
+		})
+
+		It("verifies base64 encoded signatures made directly over SHA-256 digest of the artifact", func() {
+			digest := sha256.Sum256([]byte("release-content"))
+
+			sig, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+			Expect(err).ToNot(HaveOccurred())
+
+			encodedSig := []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+
+			Expect(signer.Verify(artifactPath, encodedSig, publicKeyPEM)).To(Succeed())
+		})
+
+		It("refuses tampered artifact", func() {
+			sig, err := signer.Sign(artifactPath, privateKeyPEM)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.WriteFile(artifactPath, []byte("tampered-content"), 0600)).To(Succeed())
+
+			err = signer.Verify(artifactPath, sig, publicKeyPEM)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Signature verification failed"))
+		})
+	})
+
+	Describe("Sign", func() {
+		It("returns error if key is not PEM encoded", func() {
+			_, err := signer.Sign(artifactPath, []byte("not-a-key"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Parsing private key: expected PEM encoded key"))
+		})
+
+		It("returns error for encrypted keys", func() {
+			_, err := signer.Sign(artifactPath, pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("x")}))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("encrypted keys ('ENCRYPTED PRIVATE KEY') are not supported"))
+		})
+
+		It("returns error for unsupported key types", func() {
+			key, err := rsa.GenerateKey(rand.Reader, 1024)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = signer.Sign(artifactPath, encodePrivateKey(key))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Unsupported private key type '*rsa.PrivateKey', expected ed25519 or ECDSA key"))
+		})
+
+		It("returns error if artifact cannot be read", func() {
+			_, priv, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = signer.Sign(artifactPath+"-missing", encodePrivateKey(priv))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Opening artifact"))
+		})
+	})
+
+	Describe("Verify", func() {
+		It("returns error if signature is not base64 encoded", func() {
+			pub, _, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = signer.Verify(artifactPath, []byte("!!!"), encodePublicKey(pub))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Decoding signature"))
+		})
+
+		It("returns error if public key is not PEM encoded", func() {
+			err := signer.Verify(artifactPath, []byte("c2ln"), []byte("not-a-key"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Parsing public key: expected PEM encoded key"))
+		})
+	})
+})
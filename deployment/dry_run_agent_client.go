@@ -0,0 +1,71 @@
+package deployment
+
+import (
+	biagentclient "github.com/cloudfoundry/bosh-agent/agentclient"
+	"github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// dryRunAgentClient stands in for the agent on VMs created by a dry run cloud.
+// It accepts every request and reports jobs as running,
+// so that the deployer can be run without a VM.
+type dryRunAgentClient struct{}
+
+func NewDryRunAgentClient() biagentclient.AgentClient {
+	return dryRunAgentClient{}
+}
+
+func (c dryRunAgentClient) Ping() (string, error) { return "pong", nil }
+
+func (c dryRunAgentClient) Stop() error { return nil }
+
+func (c dryRunAgentClient) Drain(string) (int64, error) { return 0, nil }
+
+func (c dryRunAgentClient) Apply(applyspec.ApplySpec) error { return nil }
+
+func (c dryRunAgentClient) Start() error { return nil }
+
+func (c dryRunAgentClient) GetState() (biagentclient.AgentState, error) {
+	return biagentclient.AgentState{JobState: "running"}, nil
+}
+
+func (c dryRunAgentClient) AddPersistentDisk(string, interface{}) error { return nil }
+
+func (c dryRunAgentClient) RemovePersistentDisk(string) error { return nil }
+
+func (c dryRunAgentClient) MountDisk(string) error { return nil }
+
+func (c dryRunAgentClient) UnmountDisk(string) error { return nil }
+
+func (c dryRunAgentClient) ListDisk() ([]string, error) { return []string{}, nil }
+
+func (c dryRunAgentClient) MigrateDisk() error { return nil }
+
+// CompilePackage returns the package source as if it was compiled
+func (c dryRunAgentClient) CompilePackage(packageSource biagentclient.BlobRef, _ []biagentclient.BlobRef) (biagentclient.BlobRef, error) {
+	return packageSource, nil
+}
+
+func (c dryRunAgentClient) DeleteARPEntries([]string) error { return nil }
+
+func (c dryRunAgentClient) SyncDNS(string, string, uint64) (string, error) { return "synced", nil }
+
+func (c dryRunAgentClient) RunScript(string, map[string]interface{}) error { return nil }
+
+func (c dryRunAgentClient) SetUpSSH(string, string) (biagentclient.SSHResult, error) {
+	return biagentclient.SSHResult{}, c.notSupported("ssh")
+}
+
+func (c dryRunAgentClient) CleanUpSSH(string) (biagentclient.SSHResult, error) {
+	return biagentclient.SSHResult{}, c.notSupported("ssh")
+}
+
+func (c dryRunAgentClient) BundleLogs(string, string, []string) (biagentclient.BundleLogsResult, error) {
+	return biagentclient.BundleLogsResult{}, c.notSupported("bundle_logs")
+}
+
+func (c dryRunAgentClient) RemoveFile(string) error { return nil }
+
+func (c dryRunAgentClient) notSupported(method string) error {
+	return bosherr.Errorf("Agent '%s' is not supported in a dry run", method)
+}
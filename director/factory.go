@@ -5,7 +5,6 @@ import (
 	"net"
 	"net/http"
 	"net/url"
-	"time"
 
 	"github.com/cloudfoundry/bosh-utils/httpclient"
 
@@ -78,7 +77,13 @@ func (f Factory) httpClient(factoryConfig FactoryConfig, taskReporter TaskReport
 		return nil
 	}
 
-	retryClient := httpclient.NewNetworkSafeRetryClient(rawClient, 5, 500*time.Millisecond, f.logger)
+	var tracedClient AdjustedClient = rawClient
+
+	if factoryConfig.HTTPTraceWriter != nil {
+		tracedClient = NewTracingClient(rawClient, factoryConfig.HTTPTraceWriter, nil)
+	}
+
+	retryClient := NewRetryClient(tracedClient, factoryConfig.RetryPolicy, nil, f.logger)
 
 	authedClient := NewAdjustableClient(retryClient, authAdjustment)
 
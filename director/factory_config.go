@@ -2,6 +2,7 @@ package director
 
 import (
 	"crypto/x509"
+	"io"
 	gonet "net"
 	gourl "net/url"
 	"strconv"
@@ -22,6 +23,12 @@ type FactoryConfig struct {
 	ClientSecret string
 
 	TokenFunc func(bool) (string, error)
+
+	// Unset values are filled in from DefaultRetryPolicy
+	RetryPolicy RetryPolicy
+
+	// Optional; receives sanitized request/response timings as NDJSON
+	HTTPTraceWriter io.Writer
 }
 
 func NewConfigFromURL(url string) (FactoryConfig, error) {
@@ -0,0 +1,396 @@
+package manifestdiff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+
+	redactedValue = "<redacted>"
+)
+
+var sensitiveKeyWords = map[string]bool{
+	"password": true, "passwd": true, "secret": true, "token": true, "key": true,
+	"cert": true, "certificate": true, "credential": true, "credentials": true, "private": true,
+}
+
+// Change is a single difference; From and To are not set for added and removed values respectively
+type Change struct {
+	Path string      `json:"path"`
+	Type string      `json:"type"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Line is formatted like director diff lines; State is 'added', 'removed' or empty for context
+type Line struct {
+	Text  string
+	State string
+}
+
+type Result struct {
+	Lines   []Line
+	Changes []Change
+}
+
+type Opts struct {
+	Redact bool
+}
+
+type differ struct {
+	opts   Opts
+	result Result
+}
+
+// Compare matches items of lists of hashes by 'name' (or 'alias' for stemcells)
+// so that reordering instance groups, jobs, releases, etc. is not shown as a difference
+func Compare(from, to []byte, opts Opts) (Result, error) {
+	var fromObj, toObj yaml.MapSlice
+
+	err := yaml.Unmarshal(from, &fromObj)
+	if err != nil {
+		return Result{}, bosherr.WrapError(err, "Unmarshalling first manifest")
+	}
+
+	err = yaml.Unmarshal(to, &toObj)
+	if err != nil {
+		return Result{}, bosherr.WrapError(err, "Unmarshalling second manifest")
+	}
+
+	d := &differ{opts: opts}
+	d.diffHashes(fromObj, toObj, []patch.Token{patch.RootToken{}}, "", false, func() {})
+
+	return d.result, nil
+}
+
+// diffHashes emits context lines lazily via emitParent so that only parents of changed values are shown
+func (d *differ) diffHashes(from, to yaml.MapSlice, path []patch.Token, indent string, sensitive bool, emitParent func()) {
+	changed := false
+
+	emitContext := func() {
+		if !changed {
+			emitParent()
+			changed = true
+		}
+	}
+
+	fromVals := map[interface{}]interface{}{}
+	for _, item := range from {
+		fromVals[item.Key] = item.Value
+	}
+
+	toKeys := map[interface{}]bool{}
+
+	for _, item := range to {
+		toKeys[item.Key] = true
+
+		keyPath := appendToken(path, patch.KeyToken{Key: fmt.Sprintf("%v", item.Key)})
+		keySensitive := sensitive || isSensitiveKey(item.Key)
+
+		fromVal, found := fromVals[item.Key]
+		if !found {
+			emitContext()
+			d.added(keyPath, indent, item.Key, item.Value, keySensitive)
+			continue
+		}
+
+		d.diffValues(fromVal, item.Value, keyPath, indent, item.Key, keySensitive, emitContext)
+	}
+
+	for _, item := range from {
+		if !toKeys[item.Key] {
+			emitContext()
+			d.removed(appendToken(path, patch.KeyToken{Key: fmt.Sprintf("%v", item.Key)}), indent, item.Key, item.Value, sensitive || isSensitiveKey(item.Key))
+		}
+	}
+
+}
+
+func (d *differ) diffValues(from, to interface{}, path []patch.Token, indent string, key interface{}, sensitive bool, emitParent func()) {
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+
+	emitKey := d.contextEmitter(emitParent, fmt.Sprintf("%s%v:", indent, key))
+
+	fromHash, fromIsHash := from.(yaml.MapSlice)
+	toHash, toIsHash := to.(yaml.MapSlice)
+
+	if fromIsHash && toIsHash {
+		d.diffHashes(fromHash, toHash, path, indent+"  ", sensitive, emitKey)
+		return
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+
+	if fromIsList && toIsList {
+		fromNamed, fromKey := namedItems(fromList)
+		toNamed, toKey := namedItems(toList)
+
+		if fromNamed != nil && toNamed != nil && (fromKey == toKey || len(fromList) == 0 || len(toList) == 0) {
+			if len(fromKey) == 0 {
+				fromKey = toKey
+			}
+
+			d.diffNamedLists(fromList, toList, fromNamed, toNamed, fromKey, path, indent, sensitive, emitKey)
+			return
+		}
+	}
+
+	emitParent()
+
+	d.result.Changes = append(d.result.Changes, Change{
+		Path: patch.NewPointer(path).String(),
+		Type: ChangeChanged,
+		From: plain(d.redact(from, sensitive)),
+		To:   plain(d.redact(to, sensitive)),
+	})
+
+	d.appendYAML(indent, key, from, sensitive, "removed")
+	d.appendYAML(indent, key, to, sensitive, "added")
+}
+
+func (d *differ) diffNamedLists(fromList, toList []interface{}, fromNamed, toNamed map[string]yaml.MapSlice, nameKey string, path []patch.Token, indent string, sensitive bool, emitParent func()) {
+	for _, item := range toList {
+		toItem := item.(yaml.MapSlice)
+		name := itemName(toItem, nameKey)
+		itemPath := appendToken(path, patch.MatchingIndexToken{Key: nameKey, Value: name})
+
+		fromItem, found := fromNamed[name]
+		if !found {
+			emitParent()
+			d.result.Changes = append(d.result.Changes, Change{Path: patch.NewPointer(itemPath).String(), Type: ChangeAdded, To: plain(d.redact(toItem, sensitive))})
+			d.appendListItem(indent, toItem, sensitive, "added")
+			continue
+		}
+
+		emitItem := d.contextEmitter(emitParent, fmt.Sprintf("%s- %s: %s", indent, nameKey, name))
+
+		d.diffHashes(withoutKey(fromItem, nameKey), withoutKey(toItem, nameKey), itemPath, indent+"  ", sensitive, emitItem)
+	}
+
+	for _, item := range fromList {
+		fromItem := item.(yaml.MapSlice)
+		name := itemName(fromItem, nameKey)
+
+		if _, found := toNamed[name]; !found {
+			emitParent()
+
+			itemPath := appendToken(path, patch.MatchingIndexToken{Key: nameKey, Value: name})
+
+			d.result.Changes = append(d.result.Changes, Change{Path: patch.NewPointer(itemPath).String(), Type: ChangeRemoved, From: plain(d.redact(fromItem, sensitive))})
+			d.appendListItem(indent, fromItem, sensitive, "removed")
+		}
+	}
+}
+
+// contextEmitter returns a func that adds the context line (after its parents) only once
+func (d *differ) contextEmitter(emitParent func(), text string) func() {
+	emitted := false
+
+	return func() {
+		if !emitted {
+			emitParent()
+			d.result.Lines = append(d.result.Lines, Line{Text: text})
+			emitted = true
+		}
+	}
+}
+
+func (d *differ) added(path []patch.Token, indent string, key, val interface{}, sensitive bool) {
+	d.result.Changes = append(d.result.Changes, Change{Path: patch.NewPointer(path).String(), Type: ChangeAdded, To: plain(d.redact(val, sensitive))})
+	d.appendYAML(indent, key, val, sensitive, "added")
+}
+
+func (d *differ) removed(path []patch.Token, indent string, key, val interface{}, sensitive bool) {
+	d.result.Changes = append(d.result.Changes, Change{Path: patch.NewPointer(path).String(), Type: ChangeRemoved, From: plain(d.redact(val, sensitive))})
+	d.appendYAML(indent, key, val, sensitive, "removed")
+}
+
+func (d *differ) appendYAML(indent string, key, val interface{}, sensitive bool, state string) {
+	bytes, err := yaml.Marshal(yaml.MapSlice{{Key: key, Value: d.redact(val, sensitive)}})
+	if err != nil {
+		bytes = []byte(fmt.Sprintf("%v: <serialization error>", key))
+	}
+
+	d.appendLines(indent, string(bytes), state)
+}
+
+func (d *differ) appendListItem(indent string, item yaml.MapSlice, sensitive bool, state string) {
+	bytes, err := yaml.Marshal([]interface{}{d.redact(item, sensitive)})
+	if err != nil {
+		bytes = []byte("- <serialization error>")
+	}
+
+	d.appendLines(indent, string(bytes), state)
+}
+
+func (d *differ) appendLines(indent, text, state string) {
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		d.result.Lines = append(d.result.Lines, Line{Text: indent + line, State: state})
+	}
+}
+
+// redact replaces values under sensitive keys and values that look like
+// PEM encoded keys or certificates; variable references are kept since they are not secret
+func (d *differ) redact(val interface{}, sensitive bool) interface{} {
+	if !d.opts.Redact {
+		return val
+	}
+
+	switch typedVal := val.(type) {
+	case yaml.MapSlice:
+		result := yaml.MapSlice{}
+
+		for _, item := range typedVal {
+			result = append(result, yaml.MapItem{
+				Key: item.Key, Value: d.redact(item.Value, sensitive || isSensitiveKey(item.Key))})
+		}
+
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, len(typedVal))
+
+		for i, item := range typedVal {
+			result[i] = d.redact(item, sensitive)
+		}
+
+		return result
+
+	case nil:
+		return nil
+
+	case string:
+		if boshtpl.IsVariable(typedVal) {
+			return typedVal
+		}
+
+		if sensitive || strings.Contains(typedVal, "-----BEGIN") {
+			return redactedValue
+		}
+
+		return typedVal
+
+	default:
+		if sensitive {
+			return redactedValue
+		}
+
+		return typedVal
+	}
+}
+
+// plain converts ordered hashes so that changes can be serialized as JSON
+func plain(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case yaml.MapSlice:
+		result := map[string]interface{}{}
+
+		for _, item := range typedVal {
+			result[fmt.Sprintf("%v", item.Key)] = plain(item.Value)
+		}
+
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, len(typedVal))
+
+		for i, item := range typedVal {
+			result[i] = plain(item)
+		}
+
+		return result
+
+	default:
+		return val
+	}
+}
+
+func isSensitiveKey(key interface{}) bool {
+	str, ok := key.(string)
+	if !ok {
+		return false
+	}
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(str), func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	}) {
+		if sensitiveKeyWords[word] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namedItems returns nil unless every item is a hash with a unique name (or alias)
+func namedItems(list []interface{}) (map[string]yaml.MapSlice, string) {
+	if len(list) == 0 {
+		return map[string]yaml.MapSlice{}, ""
+	}
+
+	for _, nameKey := range []string{"name", "alias"} {
+		named := map[string]yaml.MapSlice{}
+
+		for _, item := range list {
+			hash, ok := item.(yaml.MapSlice)
+			if !ok {
+				return nil, ""
+			}
+
+			name := itemName(hash, nameKey)
+			if len(name) == 0 {
+				break
+			}
+
+			if _, found := named[name]; found {
+				break
+			}
+
+			named[name] = hash
+		}
+
+		if len(named) == len(list) {
+			return named, nameKey
+		}
+	}
+
+	return nil, ""
+}
+
+func itemName(item yaml.MapSlice, nameKey string) string {
+	for _, pair := range item {
+		if pair.Key == nameKey {
+			if name, ok := pair.Value.(string); ok {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+func withoutKey(hash yaml.MapSlice, key string) yaml.MapSlice {
+	var result yaml.MapSlice
+
+	for _, item := range hash {
+		if item.Key != key {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,220 @@
+package manifestdiff_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/manifestdiff"
+)
+
+var _ = Describe("Compare", func() {
+	lines := func(result Result) []string {
+		var strs []string
+		for _, line := range result.Lines {
+			prefix := "  "
+			switch line.State {
+			case "added":
+				prefix = "+ "
+			case "removed":
+				prefix = "- "
+			}
+			strs = append(strs, prefix+line.Text)
+		}
+		return strs
+	}
+
+	It("returns no differences for equal manifests", func() {
+		result, err := Compare([]byte("name: dep\n"), []byte("name: dep\n"), Opts{Redact: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Lines).To(BeEmpty())
+		Expect(result.Changes).To(BeEmpty())
+	})
+
+	It("matches instance groups and jobs by name regardless of order", func() {
+		from := `
+instance_groups:
+- name: web
+  instances: 1
+  jobs:
+  - name: nginx
+    release: nginx
+  - name: route
+    release: routing
+- name: db
+  instances: 1
+`
+		to := `
+instance_groups:
+- name: db
+  instances: 1
+- name: web
+  instances: 2
+  jobs:
+  - name: route
+    release: routing
+  - name: nginx
+    release: nginx
+`
+		result, err := Compare([]byte(from), []byte(to), Opts{Redact: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(lines(result)).To(Equal([]string{
+			"  instance_groups:",
+			"  - name: web",
+			"-   instances: 1",
+			"+   instances: 2",
+		}))
+
+		Expect(result.Changes).To(Equal([]Change{{
+			Path: "/instance_groups/name=web/instances",
+			Type: "changed",
+			From: 1,
+			To:   2,
+		}}))
+	})
+
+	It("shows added and removed named items and keys", func() {
+		from := `
+releases:
+- name: old
+  version: 1
+variables:
+- name: pass
+  type: password
+`
+		to := `
+releases:
+- name: new
+  version: 2
+variables:
+- name: pass
+  type: password
+update:
+  canaries: 1
+`
+		result, err := Compare([]byte(from), []byte(to), Opts{Redact: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(lines(result)).To(Equal([]string{
+			"  releases:",
+			"+ - name: new",
+			"+   version: 2",
+			"- - name: old",
+			"-   version: 1",
+			"+ update:",
+			"+   canaries: 1",
+		}))
+
+		Expect(result.Changes).To(HaveLen(3))
+		Expect(result.Changes[0].Path).To(Equal("/releases/name=new"))
+		Expect(result.Changes[0].Type).To(Equal("added"))
+		Expect(result.Changes[1].Path).To(Equal("/releases/name=old"))
+		Expect(result.Changes[1].Type).To(Equal("removed"))
+		Expect(result.Changes[2].Path).To(Equal("/update"))
+	})
+
+	It("matches stemcells by alias", func() {
+		from := "stemcells:\n- alias: default\n  version: 1\n- alias: other\n  version: 1\n"
+		to := "stemcells:\n- alias: other\n  version: 1\n- alias: default\n  version: 2\n"
+
+		result, err := Compare([]byte(from), []byte(to), Opts{Redact: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Changes).To(HaveLen(1))
+		Expect(result.Changes[0].Path).To(Equal("/stemcells/alias=default/version"))
+	})
+
+	It("escapes names and keys in change paths like ops file paths", func() {
+		from := "variables:\n- name: a/b:c\n  options: {\"x~y\": 1}\n"
+		to := "variables:\n- name: a/b:c\n  options: {\"x~y\": 2}\n"
+
+		result, err := Compare([]byte(from), []byte(to), Opts{Redact: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Changes).To(HaveLen(1))
+		Expect(result.Changes[0].Path).To(Equal("/variables/name=a~1b~7c/options/x~0y"))
+	})
+
+	It("compares lists without unique names as a whole", func() {
+		from := "azs: [z1, z2]\n"
+		to := "azs: [z1]\n"
+
+		result, err := Compare([]byte(from), []byte(to), Opts{Redact: true})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lines(result)).To(Equal([]string{
+			"- azs:",
+			"- - z1",
+			"- - z2",
+			"+ azs:",
+			"+ - z1",
+		}))
+	})
+
+	Describe("redaction", func() {
+		from := `
+properties:
+  admin_password: old-pass
+  tls:
+    certificate: old-cert
+  ca: |
+    -----BEGIN CERTIFICATE-----
+    old
+  port: 80
+  db_password: ((db_password))
+`
+		to := `
+properties:
+  admin_password: new-pass
+  tls:
+    certificate: new-cert
+  ca: |
+    -----BEGIN CERTIFICATE-----
+    new
+  port: 8080
+  db_password: ((new_db_password))
+`
+
+		It("redacts values that look like credentials", func() {
+			result, err := Compare([]byte(from), []byte(to), Opts{Redact: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(lines(result)).To(Equal([]string{
+				"  properties:",
+				"-   admin_password: <redacted>",
+				"+   admin_password: <redacted>",
+				"    tls:",
+				"-     certificate: <redacted>",
+				"+     certificate: <redacted>",
+				"-   ca: <redacted>",
+				"+   ca: <redacted>",
+				"-   port: 80",
+				"+   port: 8080",
+				"-   db_password: ((db_password))",
+				"+   db_password: ((new_db_password))",
+			}))
+
+			Expect(result.Changes[0]).To(Equal(Change{
+				Path: "/properties/admin_password",
+				Type: "changed",
+				From: "<redacted>",
+				To:   "<redacted>",
+			}))
+		})
+
+		It("does not redact when disabled", func() {
+			result, err := Compare([]byte(from), []byte(to), Opts{Redact: false})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(lines(result)).To(ContainElement("-   admin_password: old-pass"))
+			Expect(lines(result)).To(ContainElement("+     certificate: new-cert"))
+		})
+	})
+
+	It("returns an error when manifests cannot be parsed", func() {
+		_, err := Compare([]byte("name: ["), []byte("name: dep"), Opts{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling first manifest"))
+
+		_, err = Compare([]byte("name: dep"), []byte("name: ["), Opts{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling second manifest"))
+	})
+})
@@ -0,0 +1,167 @@
+package manifestdiff
+
+import (
+	"fmt"
+	"reflect"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+)
+
+type opsGenerator struct {
+	ops []patch.OpDefinition
+}
+
+// GenerateOps returns operations that turn the first manifest into the second one.
+// Items of lists of hashes are addressed by name (or alias) instead of by index,
+// so that operations keep applying when the first manifest changes.
+func GenerateOps(from, to []byte) ([]patch.OpDefinition, error) {
+	var fromObj, toObj yaml.MapSlice
+
+	err := yaml.Unmarshal(from, &fromObj)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling first manifest")
+	}
+
+	err = yaml.Unmarshal(to, &toObj)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling second manifest")
+	}
+
+	g := &opsGenerator{ops: []patch.OpDefinition{}}
+	g.hashes(fromObj, toObj, []patch.Token{patch.RootToken{}})
+
+	return g.ops, nil
+}
+
+func (g *opsGenerator) hashes(from, to yaml.MapSlice, tokens []patch.Token) {
+	fromVals := map[interface{}]interface{}{}
+	for _, item := range from {
+		fromVals[item.Key] = item.Value
+	}
+
+	toKeys := map[interface{}]bool{}
+
+	for _, item := range to {
+		toKeys[item.Key] = true
+		key := fmt.Sprintf("%v", item.Key)
+
+		fromVal, found := fromVals[item.Key]
+		if !found {
+			g.replace(appendToken(tokens, patch.KeyToken{Key: key, Optional: true}), item.Value)
+			continue
+		}
+
+		g.values(fromVal, item.Value, appendToken(tokens, patch.KeyToken{Key: key}))
+	}
+
+	for _, item := range from {
+		if !toKeys[item.Key] {
+			g.remove(appendToken(tokens, patch.KeyToken{Key: fmt.Sprintf("%v", item.Key)}))
+		}
+	}
+}
+
+func (g *opsGenerator) values(from, to interface{}, tokens []patch.Token) {
+	// Order of hash keys is not significant after evaluation
+	if reflect.DeepEqual(plain(from), plain(to)) {
+		return
+	}
+
+	fromHash, fromIsHash := from.(yaml.MapSlice)
+	toHash, toIsHash := to.(yaml.MapSlice)
+
+	if fromIsHash && toIsHash {
+		g.hashes(fromHash, toHash, tokens)
+		return
+	}
+
+	fromList, fromIsList := from.([]interface{})
+	toList, toIsList := to.([]interface{})
+
+	if fromIsList && toIsList && len(fromList) > 0 && len(toList) > 0 {
+		fromNamed, fromKey := namedItems(fromList)
+		toNamed, toKey := namedItems(toList)
+
+		if fromNamed != nil && toNamed != nil && fromKey == toKey && sameOrder(fromList, toList, fromNamed, toNamed, fromKey) {
+			g.namedLists(fromList, toList, fromNamed, toNamed, fromKey, tokens)
+			return
+		}
+	}
+
+	g.replace(tokens, to)
+}
+
+func (g *opsGenerator) namedLists(fromList, toList []interface{}, fromNamed, toNamed map[string]yaml.MapSlice, nameKey string, tokens []patch.Token) {
+	for i, item := range toList {
+		toItem := item.(yaml.MapSlice)
+		name := itemName(toItem, nameKey)
+
+		fromItem, found := fromNamed[name]
+		if found {
+			itemToken := patch.MatchingIndexToken{Key: nameKey, Value: name}
+			g.hashes(withoutKey(fromItem, nameKey), withoutKey(toItem, nameKey), appendToken(tokens, itemToken))
+			continue
+		}
+
+		// Place new items relative to their predecessor to keep the order of the second manifest
+		var posToken patch.Token
+
+		switch {
+		case i == len(toList)-1:
+			posToken = patch.AfterLastIndexToken{}
+		case i > 0:
+			prevName := itemName(toList[i-1].(yaml.MapSlice), nameKey)
+			posToken = patch.MatchingIndexToken{Key: nameKey, Value: prevName, Modifiers: []patch.Modifier{patch.AfterModifier{}}}
+		default:
+			posToken = patch.IndexToken{Index: 0, Modifiers: []patch.Modifier{patch.BeforeModifier{}}}
+		}
+
+		g.replace(appendToken(tokens, posToken), toItem)
+	}
+
+	for _, item := range fromList {
+		name := itemName(item.(yaml.MapSlice), nameKey)
+
+		if _, found := toNamed[name]; !found {
+			g.remove(appendToken(tokens, patch.MatchingIndexToken{Key: nameKey, Value: name}))
+		}
+	}
+}
+
+func (g *opsGenerator) replace(tokens []patch.Token, val interface{}) {
+	path := patch.NewPointer(tokens).String()
+	g.ops = append(g.ops, patch.OpDefinition{Type: "replace", Path: &path, Value: &val})
+}
+
+func (g *opsGenerator) remove(tokens []patch.Token) {
+	path := patch.NewPointer(tokens).String()
+	g.ops = append(g.ops, patch.OpDefinition{Type: "remove", Path: &path})
+}
+
+// sameOrder checks that items present in both lists are in the same relative order,
+// since operations can only insert and remove named items but not move them
+func sameOrder(fromList, toList []interface{}, fromNamed, toNamed map[string]yaml.MapSlice, nameKey string) bool {
+	var fromNames, toNames []string
+
+	for _, item := range fromList {
+		name := itemName(item.(yaml.MapSlice), nameKey)
+		if _, found := toNamed[name]; found {
+			fromNames = append(fromNames, name)
+		}
+	}
+
+	for _, item := range toList {
+		name := itemName(item.(yaml.MapSlice), nameKey)
+		if _, found := fromNamed[name]; found {
+			toNames = append(toNames, name)
+		}
+	}
+
+	return reflect.DeepEqual(fromNames, toNames)
+}
+
+func appendToken(tokens []patch.Token, token patch.Token) []patch.Token {
+	return append(append([]patch.Token{}, tokens...), token)
+}
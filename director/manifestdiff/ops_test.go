@@ -0,0 +1,152 @@
+package manifestdiff_test
+
+import (
+	"github.com/cppforlife/go-patch/patch"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/manifestdiff"
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+var _ = Describe("GenerateOps", func() {
+	// roundTrip serializes generated operations the same way as an ops file
+	// and applies them to the first manifest
+	roundTrip := func(from, to string) []patch.OpDefinition {
+		opDefs, err := GenerateOps([]byte(from), []byte(to))
+		Expect(err).ToNot(HaveOccurred())
+
+		bytes, err := yaml.Marshal(opDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		var parsedDefs []patch.OpDefinition
+		err = yaml.Unmarshal(bytes, &parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		ops, err := patch.NewOpsFromDefinitions(parsedDefs)
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := boshtpl.NewTemplate([]byte(from)).Evaluate(boshtpl.StaticVariables{}, ops, boshtpl.EvaluateOpts{})
+		Expect(err).ToNot(HaveOccurred())
+
+		var actual, expected interface{}
+		Expect(yaml.Unmarshal(result, &actual)).To(Succeed())
+		Expect(yaml.Unmarshal([]byte(to), &expected)).To(Succeed())
+		Expect(actual).To(Equal(expected))
+
+		return opDefs
+	}
+
+	paths := func(opDefs []patch.OpDefinition) []string {
+		var strs []string
+		for _, opDef := range opDefs {
+			strs = append(strs, opDef.Type+" "+*opDef.Path)
+		}
+		return strs
+	}
+
+	It("returns no operations for equal manifests regardless of key order", func() {
+		opDefs := roundTrip("name: dep\nupdate: {canaries: 1, max_in_flight: 2}\n", "update: {max_in_flight: 2, canaries: 1}\nname: dep\n")
+		Expect(opDefs).To(BeEmpty())
+	})
+
+	It("uses name based paths for changes in instance groups and jobs", func() {
+		from := `
+instance_groups:
+- name: web
+  instances: 1
+  jobs:
+  - name: nginx
+    properties:
+      port: 80
+- name: db
+  instances: 1
+`
+		to := `
+instance_groups:
+- name: web
+  instances: 2
+  jobs:
+  - name: nginx
+    properties:
+      port: 8080
+      tls: true
+- name: db
+  instances: 1
+`
+		opDefs := roundTrip(from, to)
+		Expect(paths(opDefs)).To(Equal([]string{
+			"replace /instance_groups/name=web/instances",
+			"replace /instance_groups/name=web/jobs/name=nginx/properties/port",
+			"replace /instance_groups/name=web/jobs/name=nginx/properties/tls?",
+		}))
+		Expect(*opDefs[0].Value).To(Equal(2))
+	})
+
+	It("inserts and removes named items keeping the order of the second manifest", func() {
+		from := `
+releases:
+- name: a
+- name: x
+- name: c
+`
+		to := `
+releases:
+- name: first
+- name: a
+- name: b
+- name: c
+- name: last
+`
+		opDefs := roundTrip(from, to)
+		Expect(paths(opDefs)).To(Equal([]string{
+			"replace /releases/0:before",
+			"replace /releases/name=a:after",
+			"replace /releases/-",
+			"remove /releases/name=x",
+		}))
+	})
+
+	It("matches stemcells by alias", func() {
+		opDefs := roundTrip("stemcells:\n- alias: default\n  version: 1\n", "stemcells:\n- alias: default\n  version: 2\n")
+		Expect(paths(opDefs)).To(Equal([]string{"replace /stemcells/alias=default/version"}))
+	})
+
+	It("replaces lists whose named items were reordered", func() {
+		opDefs := roundTrip("releases:\n- name: a\n- name: b\n", "releases:\n- name: b\n- name: a\n")
+		Expect(paths(opDefs)).To(Equal([]string{"replace /releases"}))
+	})
+
+	It("replaces lists without names and values of different types", func() {
+		opDefs := roundTrip("azs: [z1, z2]\nupdate: {canaries: 1}\nfeatures: []\n", "azs: [z1]\nupdate: none\nfeatures: [{name: a}]\n")
+		Expect(paths(opDefs)).To(Equal([]string{
+			"replace /azs",
+			"replace /update",
+			"replace /features",
+		}))
+	})
+
+	It("removes keys", func() {
+		opDefs := roundTrip("name: dep\nupdate: {canaries: 1}\n", "name: dep\n")
+		Expect(paths(opDefs)).To(Equal([]string{"remove /update"}))
+	})
+
+	It("escapes names used in paths", func() {
+		opDefs := roundTrip(
+			"variables:\n- name: a/b:c\n  type: password\n",
+			"variables:\n- name: a/b:c\n  type: certificate\n",
+		)
+		Expect(paths(opDefs)).To(Equal([]string{"replace /variables/name=a~1b~7c/type"}))
+	})
+
+	It("returns an error when manifests cannot be parsed", func() {
+		_, err := GenerateOps([]byte("name: ["), []byte("name: dep"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling first manifest"))
+
+		_, err = GenerateOps([]byte("name: dep"), []byte("name: ["))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling second manifest"))
+	})
+})
@@ -0,0 +1,347 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+)
+
+// Task states that are always reported so that series do not disappear when idle
+var currentTaskStates = []string{"queued", "processing", "cancelling"}
+
+type Collector struct {
+	director boshdir.Director
+
+	logTag string
+	logger boshlog.Logger
+}
+
+type source struct {
+	Name    string
+	Collect func(*Registry) error
+}
+
+func NewCollector(director boshdir.Director, logger boshlog.Logger) Collector {
+	return Collector{director: director, logTag: "metrics.Collector", logger: logger}
+}
+
+// Collect queries each source independently; failing sources are reported
+// through bosh_exporter_collector_success instead of failing the whole scrape
+func (c Collector) Collect() []Family {
+	registry := NewRegistry()
+
+	sources := []source{
+		{"deployments", c.collectDeployments},
+		{"instances", c.collectInstances},
+		{"releases", c.collectReleases},
+		{"stemcells", c.collectStemcells},
+		{"tasks", c.collectTasks},
+		{"locks", c.collectLocks},
+		{"certificates", c.collectCertificates},
+		{"orphaned_disks", c.collectOrphanedDisks},
+		{"orphaned_vms", c.collectOrphanedVMs},
+	}
+
+	success := NewRegistry().Gauge(
+		"bosh_exporter_collector_success", "Whether the last collection from the director source succeeded")
+
+	for _, s := range sources {
+		value := 1.0
+
+		err := s.Collect(registry)
+		if err != nil {
+			c.logger.Error(c.logTag, "Collecting '%s': %s", s.Name, err)
+			value = 0
+		}
+
+		success.Add(value, LabelPair{"collector", s.Name})
+	}
+
+	return append(registry.Families(), *success)
+}
+
+func (c Collector) collectDeployments(registry *Registry) error {
+	deployments, err := c.director.ListDeployments()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing deployments")
+	}
+
+	info := registry.Gauge("bosh_deployment_info", "Deployments known to the director")
+	releaseInfo := registry.Gauge("bosh_deployment_release_info", "Releases used by a deployment")
+	stemcellInfo := registry.Gauge("bosh_deployment_stemcell_info", "Stemcells used by a deployment")
+
+	for _, dep := range deployments {
+		info.Add(1, LabelPair{"deployment", dep.Name})
+
+		for _, rel := range dep.Releases {
+			releaseInfo.Add(1, LabelPair{"deployment", dep.Name}, LabelPair{"release", rel.Name}, LabelPair{"version", rel.Version})
+		}
+
+		for _, stemcell := range dep.Stemcells {
+			stemcellInfo.Add(1, LabelPair{"deployment", dep.Name}, LabelPair{"stemcell", stemcell.Name}, LabelPair{"version", stemcell.Version})
+		}
+	}
+
+	return nil
+}
+
+func (c Collector) collectInstances(registry *Registry) error {
+	deployments, err := c.director.Deployments()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing deployments")
+	}
+
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].Name() < deployments[j].Name() })
+
+	processState := registry.Gauge("bosh_instance_process_state", "Process state reported by the instance agent")
+	healthy := registry.Gauge("bosh_instance_healthy", "Whether the instance and all of its processes are running")
+	processRunning := registry.Gauge("bosh_instance_process_running", "Whether a monitored process on the instance is running")
+	cpu := registry.Gauge("bosh_instance_cpu_percent", "CPU usage of the VM")
+	load := registry.Gauge("bosh_instance_load_average", "Load average of the VM")
+	memPercent := registry.Gauge("bosh_instance_memory_percent", "Memory usage of the VM")
+	memBytes := registry.Gauge("bosh_instance_memory_bytes", "Memory used by the VM")
+	swapPercent := registry.Gauge("bosh_instance_swap_percent", "Swap usage of the VM")
+	swapBytes := registry.Gauge("bosh_instance_swap_bytes", "Swap used by the VM")
+	diskPercent := registry.Gauge("bosh_instance_disk_percent", "Disk usage of the VM")
+	diskInodePercent := registry.Gauge("bosh_instance_disk_inode_percent", "Disk inode usage of the VM")
+
+	var errs []error
+
+	for _, dep := range deployments {
+		infos, err := dep.VMInfos()
+		if err != nil {
+			errs = append(errs, bosherr.WrapErrorf(err, "Fetching VMs of deployment '%s'", dep.Name()))
+			continue
+		}
+
+		for _, info := range infos {
+			labels := instanceLabels(dep.Name(), info)
+
+			processState.Add(1, append(labels, LabelPair{"state", info.ProcessState})...)
+			healthy.Add(boolValue(info.IsRunning()), labels...)
+
+			for _, p := range info.Processes {
+				processRunning.Add(boolValue(p.IsRunning()), append(labels, LabelPair{"process", p.Name})...)
+			}
+
+			addParsed(cpu, info.Vitals.CPU.User, append(labels, LabelPair{"mode", "user"})...)
+			addParsed(cpu, info.Vitals.CPU.Sys, append(labels, LabelPair{"mode", "sys"})...)
+			addParsed(cpu, info.Vitals.CPU.Wait, append(labels, LabelPair{"mode", "wait"})...)
+
+			for i, period := range []string{"1m", "5m", "15m"} {
+				if i < len(info.Vitals.Load) {
+					addParsed(load, info.Vitals.Load[i], append(labels, LabelPair{"period", period})...)
+				}
+			}
+
+			addParsed(memPercent, info.Vitals.Mem.Percent, labels...)
+			addKB(memBytes, info.Vitals.Mem.KB, labels...)
+			addParsed(swapPercent, info.Vitals.Swap.Percent, labels...)
+			addKB(swapBytes, info.Vitals.Swap.KB, labels...)
+
+			for _, disk := range []string{"system", "ephemeral", "persistent"} {
+				size, found := info.Vitals.Disk[disk]
+				if found {
+					addParsed(diskPercent, size.Percent, append(labels, LabelPair{"disk", disk})...)
+					addParsed(diskInodePercent, size.InodePercent, append(labels, LabelPair{"disk", disk})...)
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return bosherr.NewMultiError(errs...)
+	}
+
+	return nil
+}
+
+func (c Collector) collectReleases(registry *Registry) error {
+	releases, err := c.director.Releases()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing releases")
+	}
+
+	info := registry.Gauge("bosh_release_info", "Release versions uploaded to the director")
+
+	for _, rel := range releases {
+		info.Add(1,
+			LabelPair{"release", rel.Name()},
+			LabelPair{"version", rel.Version().String()},
+			LabelPair{"deployed", strconv.FormatBool(rel.VersionMark("*") == "*")},
+		)
+	}
+
+	return nil
+}
+
+func (c Collector) collectStemcells(registry *Registry) error {
+	stemcells, err := c.director.Stemcells()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing stemcells")
+	}
+
+	info := registry.Gauge("bosh_stemcell_info", "Stemcell versions uploaded to the director")
+
+	for _, stemcell := range stemcells {
+		info.Add(1,
+			LabelPair{"stemcell", stemcell.Name()},
+			LabelPair{"version", stemcell.Version().String()},
+			LabelPair{"os", stemcell.OSName()},
+			LabelPair{"cpi", stemcell.CPI()},
+			LabelPair{"deployed", strconv.FormatBool(stemcell.VersionMark("*") == "*")},
+		)
+	}
+
+	return nil
+}
+
+func (c Collector) collectTasks(registry *Registry) error {
+	tasks, err := c.director.CurrentTasks(boshdir.TasksFilter{All: true})
+	if err != nil {
+		return bosherr.WrapError(err, "Listing current tasks")
+	}
+
+	counts := map[string]int{}
+	states := append([]string{}, currentTaskStates...)
+
+	for _, t := range tasks {
+		if !contains(states, t.State()) {
+			states = append(states, t.State())
+		}
+		counts[t.State()]++
+	}
+
+	family := registry.Gauge("bosh_tasks", "Current tasks by state")
+
+	for _, state := range states {
+		family.Add(float64(counts[state]), LabelPair{"state", state})
+	}
+
+	return nil
+}
+
+func (c Collector) collectLocks(registry *Registry) error {
+	locks, err := c.director.Locks()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing locks")
+	}
+
+	counts := map[string]int{}
+	for _, l := range locks {
+		counts[l.Type]++
+	}
+
+	family := registry.Gauge("bosh_locks", "Current locks by type")
+
+	for _, typ := range sortedKeys(counts) {
+		family.Add(float64(counts[typ]), LabelPair{"type", typ})
+	}
+
+	return nil
+}
+
+func (c Collector) collectCertificates(registry *Registry) error {
+	certs, err := c.director.CertificateExpiry()
+	if err != nil {
+		return bosherr.WrapError(err, "Fetching certificate expiry")
+	}
+
+	family := registry.Gauge("bosh_certificate_days_left", "Days until a director certificate expires")
+
+	for _, cert := range certs {
+		family.Add(float64(cert.DaysLeft), LabelPair{"path", cert.Path})
+	}
+
+	return nil
+}
+
+func (c Collector) collectOrphanedDisks(registry *Registry) error {
+	disks, err := c.director.OrphanDisks()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing orphaned disks")
+	}
+
+	var size uint64
+	for _, d := range disks {
+		size += d.Size()
+	}
+
+	registry.Gauge("bosh_orphaned_disks", "Orphaned disks").Add(float64(len(disks)))
+	registry.Gauge("bosh_orphaned_disks_bytes", "Total size of orphaned disks").Add(float64(size * 1024 * 1024))
+
+	return nil
+}
+
+func (c Collector) collectOrphanedVMs(registry *Registry) error {
+	vms, err := c.director.OrphanedVMs()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing orphaned VMs")
+	}
+
+	registry.Gauge("bosh_orphaned_vms", "Orphaned VMs").Add(float64(len(vms)))
+
+	return nil
+}
+
+func instanceLabels(deployment string, info boshdir.VMInfo) []LabelPair {
+	var index string
+	if info.Index != nil {
+		index = strconv.Itoa(*info.Index)
+	}
+
+	return []LabelPair{
+		{"deployment", deployment},
+		{"instance_group", info.JobName},
+		{"instance_id", info.ID},
+		{"index", index},
+		{"az", info.AZ},
+		// Instance may briefly have several VMs, e.g. during create-swap-delete
+		{"vm_cid", info.VMID},
+	}
+}
+
+// addParsed skips vitals that agents did not report
+func addParsed(family *Family, str string, labels ...LabelPair) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+	if err == nil {
+		family.Add(value, labels...)
+	}
+}
+
+func addKB(family *Family, str string, labels ...LabelPair) {
+	kb, err := strconv.ParseUint(strings.TrimSpace(str), 10, 64)
+	if err == nil {
+		family.Add(float64(kb*1024), labels...)
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func contains(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(counts map[string]int) []string {
+	var keys []string
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
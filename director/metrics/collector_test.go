@@ -0,0 +1,225 @@
+package metrics_test
+
+import (
+	"bytes"
+	"errors"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	semver "github.com/cppforlife/go-semi-semantic/version"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	. "github.com/cloudfoundry/bosh-cli/v7/director/metrics"
+)
+
+var _ = Describe("Collector", func() {
+	var (
+		director   *fakedir.FakeDirector
+		deployment *fakedir.FakeDeployment
+		collector  Collector
+	)
+
+	BeforeEach(func() {
+		director = &fakedir.FakeDirector{}
+		deployment = &fakedir.FakeDeployment{NameStub: func() string { return "redis" }}
+		collector = NewCollector(director, boshlog.NewLogger(boshlog.LevelNone))
+
+		director.ListDeploymentsReturns([]boshdir.DeploymentResp{
+			{
+				Name:      "redis",
+				Releases:  []boshdir.DeploymentReleaseResp{{Name: "redis", Version: "15"}},
+				Stemcells: []boshdir.DeploymentStemcellResp{{Name: "ubuntu-jammy", Version: "1.200"}},
+			},
+		}, nil)
+
+		director.DeploymentsReturns([]boshdir.Deployment{deployment}, nil)
+
+		index := 0
+		deployment.VMInfosReturns([]boshdir.VMInfo{
+			{
+				JobName:      "redis",
+				ID:           "abc",
+				Index:        &index,
+				AZ:           "z1",
+				VMID:         "vm-abc",
+				ProcessState: "running",
+				Processes: []boshdir.VMInfoProcess{
+					{Name: "redis-server", State: "running"},
+					{Name: "redis-exporter", State: "failing"},
+				},
+				Vitals: boshdir.VMInfoVitals{
+					CPU:  boshdir.VMInfoVitalsCPU{User: "1.5", Sys: "0.5", Wait: ""},
+					Mem:  boshdir.VMInfoVitalsMemSize{KB: "2048", Percent: "12"},
+					Swap: boshdir.VMInfoVitalsMemSize{KB: "0", Percent: "0"},
+					Load: []string{"0.10", "0.20", "0.30"},
+					Disk: map[string]boshdir.VMInfoVitalsDiskSize{
+						"system":     {Percent: "40", InodePercent: "30"},
+						"persistent": {Percent: "5", InodePercent: "1"},
+					},
+				},
+			},
+			{JobName: "redis", ID: "def", ProcessState: "unresponsive agent"},
+		}, nil)
+
+		director.ReleasesReturns([]boshdir.Release{
+			&fakedir.FakeRelease{
+				NameStub:        func() string { return "redis" },
+				VersionStub:     func() semver.Version { return semver.MustNewVersionFromString("15") },
+				VersionMarkStub: func(mark string) string { return mark },
+			},
+			&fakedir.FakeRelease{
+				NameStub:    func() string { return "redis" },
+				VersionStub: func() semver.Version { return semver.MustNewVersionFromString("14") },
+			},
+		}, nil)
+
+		director.StemcellsReturns([]boshdir.Stemcell{
+			&fakedir.FakeStemcell{
+				NameStub:        func() string { return "ubuntu-jammy" },
+				VersionStub:     func() semver.Version { return semver.MustNewVersionFromString("1.200") },
+				OSNameStub:      func() string { return "ubuntu-jammy" },
+				CPIStub:         func() string { return "aws" },
+				VersionMarkStub: func(mark string) string { return mark },
+			},
+		}, nil)
+
+		director.CurrentTasksReturns([]boshdir.Task{
+			&fakedir.FakeTask{StateStub: func() string { return "processing" }},
+			&fakedir.FakeTask{StateStub: func() string { return "processing" }},
+			&fakedir.FakeTask{StateStub: func() string { return "timeout" }},
+		}, nil)
+
+		director.LocksReturns([]boshdir.Lock{
+			{Type: "deployment", Resource: []string{"redis"}},
+			{Type: "release", Resource: []string{"redis"}},
+			{Type: "deployment", Resource: []string{"cf"}},
+		}, nil)
+
+		director.CertificateExpiryReturns([]boshdir.CertificateExpiryInfo{
+			{Path: "director.nats.tls.ca", DaysLeft: 42},
+		}, nil)
+
+		director.OrphanDisksReturns([]boshdir.OrphanDisk{
+			&fakedir.FakeOrphanDisk{SizeStub: func() uint64 { return 1024 }},
+			&fakedir.FakeOrphanDisk{SizeStub: func() uint64 { return 2048 }},
+		}, nil)
+
+		director.OrphanedVMsReturns([]boshdir.OrphanedVM{{CID: "vm-1"}}, nil)
+	})
+
+	collect := func() string {
+		buf := bytes.NewBufferString("")
+		Expect(WriteText(buf, collector.Collect())).To(Succeed())
+		return buf.String()
+	}
+
+	It("exports deployment inventory", func() {
+		text := collect()
+
+		Expect(text).To(ContainSubstring(`bosh_deployment_info{deployment="redis"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_deployment_release_info{deployment="redis",release="redis",version="15"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_deployment_stemcell_info{deployment="redis",stemcell="ubuntu-jammy",version="1.200"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_release_info{release="redis",version="15",deployed="true"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_release_info{release="redis",version="14",deployed="false"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_stemcell_info{stemcell="ubuntu-jammy",version="1.200",os="ubuntu-jammy",cpi="aws",deployed="true"} 1` + "\n"))
+	})
+
+	It("exports instance process states and vitals", func() {
+		text := collect()
+
+		labels := `deployment="redis",instance_group="redis",instance_id="abc",index="0",az="z1",vm_cid="vm-abc"`
+
+		Expect(text).To(ContainSubstring(`bosh_instance_process_state{` + labels + `,state="running"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_healthy{` + labels + `} 0` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_process_running{` + labels + `,process="redis-server"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_process_running{` + labels + `,process="redis-exporter"} 0` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_cpu_percent{` + labels + `,mode="user"} 1.5` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_cpu_percent{` + labels + `,mode="sys"} 0.5` + "\n"))
+		Expect(text).ToNot(ContainSubstring(`mode="wait"`))
+		Expect(text).To(ContainSubstring(`bosh_instance_load_average{` + labels + `,period="15m"} 0.3` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_memory_percent{` + labels + `} 12` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_memory_bytes{` + labels + `} 2.097152e+06` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_swap_bytes{` + labels + `} 0` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_disk_percent{` + labels + `,disk="system"} 40` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_disk_inode_percent{` + labels + `,disk="persistent"} 1` + "\n"))
+		Expect(text).ToNot(ContainSubstring(`disk="ephemeral"`))
+
+		Expect(text).To(ContainSubstring(
+			`bosh_instance_process_state{deployment="redis",instance_group="redis",instance_id="def",index="",az="",vm_cid="",state="unresponsive agent"} 1` + "\n"))
+	})
+
+	It("exports separate series for each VM of an instance", func() {
+		deployment.VMInfosReturns([]boshdir.VMInfo{
+			{JobName: "redis", ID: "abc", VMID: "vm-old", ProcessState: "running"},
+			{JobName: "redis", ID: "abc", VMID: "vm-new", ProcessState: "starting"},
+		}, nil)
+
+		text := collect()
+
+		labels := `deployment="redis",instance_group="redis",instance_id="abc",index="",az=""`
+
+		Expect(text).To(ContainSubstring(`bosh_instance_process_state{` + labels + `,vm_cid="vm-old",state="running"} 1` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_instance_process_state{` + labels + `,vm_cid="vm-new",state="starting"} 1` + "\n"))
+	})
+
+	It("exports task, lock, certificate and orphan counts", func() {
+		text := collect()
+
+		Expect(text).To(ContainSubstring(`bosh_tasks{state="queued"} 0
+bosh_tasks{state="processing"} 2
+bosh_tasks{state="cancelling"} 0
+bosh_tasks{state="timeout"} 1
+`))
+		Expect(text).To(ContainSubstring(`bosh_locks{type="deployment"} 2
+bosh_locks{type="release"} 1
+`))
+		Expect(text).To(ContainSubstring(`bosh_certificate_days_left{path="director.nats.tls.ca"} 42` + "\n"))
+		Expect(text).To(ContainSubstring("bosh_orphaned_disks 2\n"))
+		Expect(text).To(ContainSubstring("bosh_orphaned_disks_bytes 3.221225472e+09\n"))
+		Expect(text).To(ContainSubstring("bosh_orphaned_vms 1\n"))
+
+		Expect(director.CurrentTasksArgsForCall(0)).To(Equal(boshdir.TasksFilter{All: true}))
+	})
+
+	It("reports successful collectors", func() {
+		text := collect()
+
+		Expect(text).To(HaveSuffix(`# HELP bosh_exporter_collector_success Whether the last collection from the director source succeeded
+# TYPE bosh_exporter_collector_success gauge
+bosh_exporter_collector_success{collector="deployments"} 1
+bosh_exporter_collector_success{collector="instances"} 1
+bosh_exporter_collector_success{collector="releases"} 1
+bosh_exporter_collector_success{collector="stemcells"} 1
+bosh_exporter_collector_success{collector="tasks"} 1
+bosh_exporter_collector_success{collector="locks"} 1
+bosh_exporter_collector_success{collector="certificates"} 1
+bosh_exporter_collector_success{collector="orphaned_disks"} 1
+bosh_exporter_collector_success{collector="orphaned_vms"} 1
+`))
+	})
+
+	It("keeps collecting other sources when one fails", func() {
+		director.CertificateExpiryReturns(nil, errors.New("fake-err"))
+
+		text := collect()
+
+		Expect(text).To(ContainSubstring(`bosh_exporter_collector_success{collector="certificates"} 0` + "\n"))
+		Expect(text).To(ContainSubstring(`bosh_exporter_collector_success{collector="orphaned_vms"} 1` + "\n"))
+		Expect(text).ToNot(ContainSubstring("bosh_certificate_days_left"))
+		Expect(text).To(ContainSubstring("bosh_orphaned_vms 1\n"))
+	})
+
+	It("keeps instances of other deployments when one deployment fails", func() {
+		failing := &fakedir.FakeDeployment{NameStub: func() string { return "cf" }}
+		failing.VMInfosReturns(nil, errors.New("fake-err"))
+
+		director.DeploymentsReturns([]boshdir.Deployment{deployment, failing}, nil)
+
+		text := collect()
+
+		Expect(text).To(ContainSubstring(`bosh_exporter_collector_success{collector="instances"} 0` + "\n"))
+		Expect(text).To(ContainSubstring(`instance_id="abc"`))
+	})
+})
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+const textContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Exporter serves metrics from the last scrape so that Prometheus
+// scrapes do not translate into director requests
+type Exporter struct {
+	collector   Collector
+	timeService clock.Clock
+
+	body []byte
+	sync.RWMutex
+}
+
+func NewExporter(collector Collector, timeService clock.Clock) *Exporter {
+	return &Exporter{collector: collector, timeService: timeService}
+}
+
+func (e *Exporter) Scrape() error {
+	startedAt := e.timeService.Now()
+
+	families := e.collector.Collect()
+
+	registry := NewRegistry()
+	registry.Gauge("bosh_exporter_scrape_duration_seconds", "Time it took to collect metrics from the director").
+		Add(e.timeService.Since(startedAt).Seconds())
+	registry.Gauge("bosh_exporter_last_scrape_timestamp_seconds", "When metrics were last collected from the director").
+		Add(float64(startedAt.Unix()))
+
+	var buf bytes.Buffer
+
+	err := WriteText(&buf, append(families, registry.Families()...))
+	if err != nil {
+		return err
+	}
+
+	e.Lock()
+	e.body = buf.Bytes()
+	e.Unlock()
+
+	return nil
+}
+
+// Poll scrapes right away and then on every interval until stopped
+func (e *Exporter) Poll(interval time.Duration, stop <-chan struct{}) error {
+	ticker := e.timeService.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := e.Scrape()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C():
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	e.RLock()
+	body := e.body
+	e.RUnlock()
+
+	if body == nil {
+		http.Error(w, "Metrics have not been collected yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", textContentType)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,76 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	. "github.com/cloudfoundry/bosh-cli/v7/director/metrics"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		director    *fakedir.FakeDirector
+		timeService *fakeclock.FakeClock
+		exporter    *Exporter
+	)
+
+	BeforeEach(func() {
+		director = &fakedir.FakeDirector{}
+		timeService = fakeclock.NewFakeClock(time.Date(2020, time.May, 1, 10, 0, 0, 0, time.UTC))
+		exporter = NewExporter(NewCollector(director, boshlog.NewLogger(boshlog.LevelNone)), timeService)
+	})
+
+	get := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		exporter.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+		return recorder
+	}
+
+	It("is unavailable until metrics are collected", func() {
+		resp := get()
+
+		Expect(resp.Code).To(Equal(http.StatusServiceUnavailable))
+		Expect(resp.Body.String()).To(ContainSubstring("Metrics have not been collected yet"))
+		Expect(director.ListDeploymentsCallCount()).To(Equal(0))
+	})
+
+	It("serves last scraped metrics without querying the director", func() {
+		Expect(exporter.Scrape()).To(Succeed())
+
+		resp := get()
+		resp = get()
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(Equal("text/plain; version=0.0.4; charset=utf-8"))
+		Expect(resp.Body.String()).To(ContainSubstring("bosh_orphaned_vms 0\n"))
+		Expect(resp.Body.String()).To(ContainSubstring("bosh_exporter_scrape_duration_seconds 0\n"))
+		Expect(resp.Body.String()).To(HaveSuffix("bosh_exporter_last_scrape_timestamp_seconds 1.5883272e+09\n"))
+
+		Expect(director.ListDeploymentsCallCount()).To(Equal(1))
+	})
+
+	It("scrapes right away and on every interval until stopped", func() {
+		stop := make(chan struct{})
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- exporter.Poll(time.Minute, stop)
+		}()
+
+		Eventually(director.ListDeploymentsCallCount).Should(Equal(1))
+
+		timeService.WaitForWatcherAndIncrement(time.Minute)
+		Eventually(director.ListDeploymentsCallCount).Should(Equal(2))
+		Eventually(get().Body.String).Should(ContainSubstring("bosh_exporter_last_scrape_timestamp_seconds 1.58832726e+09\n"))
+
+		close(stop)
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+})
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const GaugeType = "gauge"
+
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+type Sample struct {
+	Labels []LabelPair
+	Value  float64
+}
+
+type LabelPair struct {
+	Name  string
+	Value string
+}
+
+func (f *Family) Add(value float64, labels ...LabelPair) {
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: value})
+}
+
+// Registry keeps families in the order they were first requested
+type Registry struct {
+	families []*Family
+	byName   map[string]*Family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{byName: map[string]*Family{}}
+}
+
+func (r *Registry) Gauge(name, help string) *Family {
+	return r.family(name, help, GaugeType)
+}
+
+func (r *Registry) family(name, help, typ string) *Family {
+	if family, found := r.byName[name]; found {
+		return family
+	}
+
+	family := &Family{Name: name, Help: help, Type: typ}
+	r.families = append(r.families, family)
+	r.byName[name] = family
+
+	return family
+}
+
+func (r *Registry) Families() []Family {
+	var families []Family
+
+	for _, family := range r.families {
+		families = append(families, *family)
+	}
+
+	return families
+}
+
+// WriteText writes families in the Prometheus text exposition format (version 0.0.4)
+func WriteText(w io.Writer, families []Family) error {
+	var sb strings.Builder
+
+	for _, family := range families {
+		fmt.Fprintf(&sb, "# HELP %s %s\n", family.Name, escapeHelp(family.Help))
+		fmt.Fprintf(&sb, "# TYPE %s %s\n", family.Name, family.Type)
+
+		for _, sample := range family.Samples {
+			sb.WriteString(family.Name)
+
+			if len(sample.Labels) > 0 {
+				var pairs []string
+				for _, label := range sample.Labels {
+					pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", label.Name, escapeLabelValue(label.Value)))
+				}
+				sb.WriteString("{" + strings.Join(pairs, ",") + "}")
+			}
+
+			sb.WriteString(" " + strconv.FormatFloat(sample.Value, 'g', -1, 64) + "\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+var (
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string { return helpReplacer.Replace(help) }
+
+func escapeLabelValue(value string) string { return labelValueReplacer.Replace(value) }
@@ -0,0 +1,58 @@
+package metrics_test
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/metrics"
+)
+
+var _ = Describe("WriteText", func() {
+	It("writes families in registration order with their samples", func() {
+		registry := NewRegistry()
+
+		registry.Gauge("bosh_b", "Second").Add(1.5, LabelPair{Name: "deployment", Value: "dep"})
+		registry.Gauge("bosh_a", "First").Add(3)
+		registry.Gauge("bosh_b", "ignored").Add(0, LabelPair{Name: "deployment", Value: "other"}, LabelPair{Name: "az", Value: "z1"})
+
+		buf := bytes.NewBufferString("")
+		Expect(WriteText(buf, registry.Families())).To(Succeed())
+
+		Expect(buf.String()).To(Equal(`# HELP bosh_b Second
+# TYPE bosh_b gauge
+bosh_b{deployment="dep"} 1.5
+bosh_b{deployment="other",az="z1"} 0
+# HELP bosh_a First
+# TYPE bosh_a gauge
+bosh_a 3
+`))
+	})
+
+	It("escapes help text and label values", func() {
+		registry := NewRegistry()
+
+		registry.Gauge("bosh_a", "Back\\slash\nnewline").Add(1, LabelPair{Name: "path", Value: "a\\b\"c\"\nd"})
+
+		buf := bytes.NewBufferString("")
+		Expect(WriteText(buf, registry.Families())).To(Succeed())
+
+		Expect(buf.String()).To(Equal(`# HELP bosh_a Back\\slash\nnewline
+# TYPE bosh_a gauge
+bosh_a{path="a\\b\"c\"\nd"} 1
+`))
+	})
+
+	It("writes large and fractional values without exponent loss", func() {
+		registry := NewRegistry()
+
+		registry.Gauge("bosh_a", "A").Add(1073741824)
+		registry.Gauge("bosh_a", "A").Add(0.25)
+
+		buf := bytes.NewBufferString("")
+		Expect(WriteText(buf, registry.Families())).To(Succeed())
+
+		Expect(buf.String()).To(ContainSubstring("bosh_a 1.073741824e+09\nbosh_a 0.25\n"))
+	})
+})
@@ -0,0 +1,197 @@
+package propcheck
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	bitarball "github.com/cloudfoundry/bosh-cli/v7/installation/tarball"
+	boshjobman "github.com/cloudfoundry/bosh-cli/v7/release/job/manifest"
+)
+
+// FSSpecReader finds releases referenced by local paths (tarballs or release
+// directories) and remote releases that were previously downloaded into tarball cache
+type FSSpecReader struct {
+	tarballCache bitarball.Cache
+	fs           boshsys.FileSystem
+}
+
+func NewFSSpecReader(tarballCache bitarball.Cache, fs boshsys.FileSystem) FSSpecReader {
+	return FSSpecReader{tarballCache: tarballCache, fs: fs}
+}
+
+func (r FSSpecReader) ReadJobSpecs(release Release) (map[string]boshjobman.Manifest, bool, error) {
+	if len(release.URL) == 0 {
+		return nil, false, nil
+	}
+
+	if strings.HasPrefix(release.URL, "http://") || strings.HasPrefix(release.URL, "https://") {
+		if len(release.SHA1) == 0 {
+			return nil, false, nil
+		}
+
+		cachedPath, found := r.tarballCache.Get(release)
+		if !found {
+			return nil, false, nil
+		}
+
+		specs, err := r.readTarball(cachedPath)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return specs, true, nil
+	}
+
+	if strings.Contains(release.URL, "://") && !strings.HasPrefix(release.URL, "file://") {
+		return nil, false, nil
+	}
+
+	localPath, err := r.fs.ExpandPath(strings.TrimPrefix(release.URL, "file://"))
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Expanding path of %s", release.Description())
+	}
+
+	if !r.fs.FileExists(localPath) {
+		return nil, false, bosherr.Errorf("Expected %s to exist at '%s'", release.Description(), localPath)
+	}
+
+	stat, err := r.fs.Stat(localPath)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Checking path of %s", release.Description())
+	}
+
+	var specs map[string]boshjobman.Manifest
+
+	if stat.IsDir() {
+		specs, err = r.readDir(localPath)
+	} else {
+		specs, err = r.readTarball(localPath)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return specs, true, nil
+}
+
+func (r FSSpecReader) readDir(dirPath string) (map[string]boshjobman.Manifest, error) {
+	specPaths, err := r.fs.Glob(filepath.Join(dirPath, "jobs", "*", "spec"))
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Finding job specs in '%s'", dirPath)
+	}
+
+	specs := map[string]boshjobman.Manifest{}
+
+	for _, specPath := range specPaths {
+		spec, err := boshjobman.NewManifestFromPath(specPath, r.fs)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(spec.Name) == 0 {
+			spec.Name = filepath.Base(filepath.Dir(specPath))
+		}
+
+		specs[spec.Name] = spec
+	}
+
+	return specs, nil
+}
+
+// readTarball streams release tarball so that jobs do not have to be extracted
+func (r FSSpecReader) readTarball(tarballPath string) (map[string]boshjobman.Manifest, error) {
+	file, err := r.fs.OpenFile(tarballPath, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Opening release tarball '%s'", tarballPath)
+	}
+
+	defer file.Close()
+
+	specs := map[string]boshjobman.Manifest{}
+
+	err = eachTarEntry(file, func(name string, r io.Reader) error {
+		if path.Dir(name) != "jobs" || !strings.HasSuffix(name, ".tgz") {
+			return nil
+		}
+
+		var spec *boshjobman.Manifest
+
+		err := eachTarEntry(r, func(name string, r io.Reader) error {
+			if name != "job.MF" {
+				return nil
+			}
+
+			bytes, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+
+			parsed, err := boshjobman.NewManifestFromBytes(bytes)
+			if err != nil {
+				return bosherr.WrapErrorf(err, "Unmarshalling job spec of '%s'", path.Base(name))
+			}
+
+			spec = &parsed
+
+			return nil
+		})
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading job '%s'", name)
+		}
+
+		if spec == nil {
+			return bosherr.Errorf("Expected job '%s' to contain 'job.MF'", name)
+		}
+
+		if len(spec.Name) == 0 {
+			spec.Name = strings.TrimSuffix(path.Base(name), ".tgz")
+		}
+
+		specs[spec.Name] = *spec
+
+		return nil
+	})
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading release tarball '%s'", tarballPath)
+	}
+
+	return specs, nil
+}
+
+func eachTarEntry(r io.Reader, fn func(name string, r io.Reader) error) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = fn(path.Clean(hdr.Name), tr)
+		if err != nil {
+			return err
+		}
+	}
+}
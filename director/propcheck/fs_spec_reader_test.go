@@ -0,0 +1,153 @@
+package propcheck_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/propcheck"
+	bitarball "github.com/cloudfoundry/bosh-cli/v7/installation/tarball"
+	boshjobman "github.com/cloudfoundry/bosh-cli/v7/release/job/manifest"
+)
+
+func tgz(files map[string][]byte) []byte {
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = tw.Write(content)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(tw.Close()).To(Succeed())
+	Expect(gw.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
+
+var _ = Describe("FSSpecReader", func() {
+	var (
+		fs     *fakesys.FakeFileSystem
+		cache  bitarball.Cache
+		reader FSSpecReader
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		cache = bitarball.NewCache("/cache", fs, boshlog.NewLogger(boshlog.LevelNone))
+		reader = NewFSSpecReader(cache, fs)
+	})
+
+	releaseTarball := func() []byte {
+		return tgz(map[string][]byte{
+			"./release.MF": []byte("name: rel"),
+			"./jobs/web.tgz": tgz(map[string][]byte{
+				"./job.MF":        []byte("name: web\nproperties:\n  port: {default: 8080}\n"),
+				"./templates/ctl": []byte("#!/bin/bash"),
+			}),
+			"./jobs/worker.tgz": tgz(map[string][]byte{
+				"./job.MF": []byte("properties:\n  queue: {example: jobs}\n"),
+			}),
+			"./packages/pkg.tgz": tgz(map[string][]byte{}),
+		})
+	}
+
+	expectedSpecs := map[string]boshjobman.Manifest{
+		"web": {
+			Name:       "web",
+			Properties: map[string]boshjobman.PropertyDefinition{"port": {Default: 8080}},
+		},
+		"worker": {
+			Name:       "worker",
+			Properties: map[string]boshjobman.PropertyDefinition{"queue": {Example: "jobs"}},
+		},
+	}
+
+	It("reads job specs from local release tarball", func() {
+		Expect(fs.WriteFile("/rel.tgz", releaseTarball())).To(Succeed())
+
+		specs, found, err := reader.ReadJobSpecs(Release{Name: "rel", URL: "file:///rel.tgz"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(specs).To(Equal(expectedSpecs))
+	})
+
+	It("reads job specs from release directory", func() {
+		Expect(fs.MkdirAll("/rel-dir", os.ModePerm)).To(Succeed())
+		Expect(fs.WriteFileString("/rel-dir/jobs/web/spec", "name: web\nproperties:\n  port: {default: 8080}\n")).To(Succeed())
+		Expect(fs.WriteFileString("/rel-dir/jobs/worker/spec", "properties:\n  queue: {example: jobs}\n")).To(Succeed())
+
+		fs.SetGlob("/rel-dir/jobs/*/spec", []string{"/rel-dir/jobs/web/spec", "/rel-dir/jobs/worker/spec"})
+
+		specs, found, err := reader.ReadJobSpecs(Release{Name: "rel", Version: "create", URL: "/rel-dir"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(specs).To(Equal(expectedSpecs))
+	})
+
+	It("reads job specs of remote release found in tarball cache", func() {
+		release := Release{Name: "rel", URL: "https://example.com/rel.tgz", SHA1: "abc"}
+
+		Expect(fs.WriteFile(cache.Path(release), releaseTarball())).To(Succeed())
+
+		specs, found, err := reader.ReadJobSpecs(release)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(specs).To(Equal(expectedSpecs))
+	})
+
+	It("does not find remote releases that were not downloaded", func() {
+		_, found, err := reader.ReadJobSpecs(Release{Name: "rel", URL: "https://example.com/rel.tgz", SHA1: "abc"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		_, found, err = reader.ReadJobSpecs(Release{Name: "rel", URL: "https://example.com/rel.tgz"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		_, found, err = reader.ReadJobSpecs(Release{Name: "rel", URL: "git+https://example.com/rel"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("does not find releases without URL since they are already uploaded", func() {
+		_, found, err := reader.ReadJobSpecs(Release{Name: "rel", Version: "1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns error if local release does not exist", func() {
+		_, _, err := reader.ReadJobSpecs(Release{Name: "rel", URL: "file:///missing.tgz"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Expected release 'rel' to exist at '/missing.tgz'"))
+	})
+
+	It("returns error if job does not contain spec", func() {
+		Expect(fs.WriteFile("/rel.tgz", tgz(map[string][]byte{
+			"./jobs/web.tgz": tgz(map[string][]byte{"./monit": []byte("")}),
+		}))).To(Succeed())
+
+		_, _, err := reader.ReadJobSpecs(Release{Name: "rel", URL: "/rel.tgz"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Expected job 'jobs/web.tgz' to contain 'job.MF'"))
+	})
+
+	It("returns error if tarball is not valid", func() {
+		Expect(fs.WriteFileString("/rel.tgz", "not-gzip")).To(Succeed())
+
+		_, _, err := reader.ReadJobSpecs(Release{Name: "rel", URL: "/rel.tgz"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Reading release tarball '/rel.tgz'"))
+	})
+})
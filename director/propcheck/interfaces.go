@@ -0,0 +1,41 @@
+package propcheck
+
+import (
+	"fmt"
+
+	boshsch "github.com/cloudfoundry/bosh-cli/v7/director/schema"
+	boshjobman "github.com/cloudfoundry/bosh-cli/v7/release/job/manifest"
+)
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . Validator
+
+type Validator interface {
+	// Validate checks job properties of instance groups and addons
+	// against specs of jobs from releases that are available locally
+	Validate(manifest []byte) ([]boshsch.Problem, error)
+}
+
+//counterfeiter:generate . SpecReader
+
+type SpecReader interface {
+	// ReadJobSpecs returns job specs by job name;
+	// false is returned when release is not available locally
+	ReadJobSpecs(Release) (map[string]boshjobman.Manifest, bool, error)
+}
+
+type Release struct {
+	Name    string
+	Version string
+	URL     string
+	SHA1    string
+}
+
+func (r Release) GetURL() string  { return r.URL }
+func (r Release) GetSHA1() string { return r.SHA1 }
+
+func (r Release) Description() string {
+	return fmt.Sprintf("release '%s'", r.Name)
+}
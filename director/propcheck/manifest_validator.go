@@ -0,0 +1,307 @@
+package propcheck
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+
+	boshsch "github.com/cloudfoundry/bosh-cli/v7/director/schema"
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	boshjobman "github.com/cloudfoundry/bosh-cli/v7/release/job/manifest"
+)
+
+type ManifestValidator struct {
+	specReader SpecReader
+}
+
+type manifest struct {
+	Releases       []Release
+	InstanceGroups []instanceGroup `yaml:"instance_groups"`
+	Addons         []addon
+	Properties     map[interface{}]interface{}
+}
+
+type instanceGroup struct {
+	Name       string
+	Jobs       []jobRef
+	Properties map[interface{}]interface{}
+}
+
+type addon struct {
+	Name string
+	Jobs []jobRef
+}
+
+type jobRef struct {
+	Name       string
+	Release    string
+	Properties map[interface{}]interface{}
+}
+
+// propertySource is where the director takes job properties from:
+// job level properties, or if they are not set, instance group level or global properties
+type propertySource struct {
+	props map[interface{}]interface{}
+	path  []patch.Token
+
+	// Properties shared between jobs cannot be checked for unknown keys
+	shared bool
+}
+
+type jobCheck struct {
+	spec     boshjobman.Manifest
+	source   propertySource
+	problems []boshsch.Problem
+}
+
+func NewManifestValidator(specReader SpecReader) ManifestValidator {
+	return ManifestValidator{specReader: specReader}
+}
+
+func (v ManifestValidator) Validate(bytes []byte) ([]boshsch.Problem, error) {
+	var man manifest
+
+	err := yaml.Unmarshal(bytes, &man)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling manifest")
+	}
+
+	var problems []boshsch.Problem
+
+	specsByRelease := map[string]map[string]boshjobman.Manifest{}
+
+	for _, release := range man.Releases {
+		specs, found, err := v.specReader.ReadJobSpecs(release)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Reading job specs of %s", release.Description())
+		}
+
+		if !found {
+			problems = append(problems, boshsch.Problem{
+				Level:   boshsch.LevelWarning,
+				Path:    pathString(patch.RootToken{}, patch.KeyToken{Key: "releases"}, patch.MatchingIndexToken{Key: "name", Value: release.Name}),
+				Message: "Skipping validation of job properties since release is not available locally",
+			})
+			continue
+		}
+
+		specsByRelease[release.Name] = specs
+	}
+
+	checkJob := func(job jobRef, jobPath []patch.Token, source propertySource) {
+		specs, found := specsByRelease[job.Release]
+		if !found {
+			return
+		}
+
+		spec, found := specs[job.Name]
+		if !found {
+			problems = append(problems, boshsch.Problem{
+				Level:   boshsch.LevelError,
+				Path:    pathString(jobPath...),
+				Message: fmt.Sprintf("Expected release '%s' to contain job '%s'", job.Release, job.Name),
+			})
+			return
+		}
+
+		check := &jobCheck{spec: spec, source: source}
+		check.Run()
+
+		problems = append(problems, check.problems...)
+	}
+
+	for _, group := range man.InstanceGroups {
+		groupPath := []patch.Token{patch.RootToken{}, patch.KeyToken{Key: "instance_groups"}, patch.MatchingIndexToken{Key: "name", Value: group.Name}}
+
+		for _, job := range group.Jobs {
+			jobPath := appendTokens(groupPath, patch.KeyToken{Key: "jobs"}, patch.MatchingIndexToken{Key: "name", Value: job.Name})
+
+			source := propertySource{props: job.Properties, path: appendTokens(jobPath, patch.KeyToken{Key: "properties"})}
+
+			if job.Properties == nil {
+				if group.Properties != nil {
+					source = propertySource{props: group.Properties, path: appendTokens(groupPath, patch.KeyToken{Key: "properties"}), shared: true}
+				} else if man.Properties != nil {
+					source = propertySource{props: man.Properties, path: []patch.Token{patch.RootToken{}, patch.KeyToken{Key: "properties"}}, shared: true}
+				}
+			}
+
+			checkJob(job, jobPath, source)
+		}
+	}
+
+	for _, addon := range man.Addons {
+		addonPath := []patch.Token{patch.RootToken{}, patch.KeyToken{Key: "addons"}, patch.MatchingIndexToken{Key: "name", Value: addon.Name}}
+
+		for _, job := range addon.Jobs {
+			jobPath := appendTokens(addonPath, patch.KeyToken{Key: "jobs"}, patch.MatchingIndexToken{Key: "name", Value: job.Name})
+			checkJob(job, jobPath, propertySource{props: job.Properties, path: appendTokens(jobPath, patch.KeyToken{Key: "properties"})})
+		}
+	}
+
+	return problems, nil
+}
+
+func (c *jobCheck) Run() {
+	c.walk(c.source.props, "", c.source.path)
+
+	var names []string
+
+	for name, def := range c.spec.Properties {
+		if def.Default == nil && !c.isSet(name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	// Templates may check for presence of properties without defaults (via if_p)
+	// hence missing values are not necessarily errors
+	for _, name := range names {
+		c.add(boshsch.LevelWarning, c.source.path, "Missing value for property '%s' that does not have a default", name)
+	}
+}
+
+func (c *jobCheck) walk(props map[interface{}]interface{}, prefix string, path []patch.Token) {
+	for _, pair := range sortedPairs(props) {
+		key, value := pair.key, pair.value
+
+		name := prefix + key
+		keyPath := appendTokens(path, patch.KeyToken{Key: key})
+
+		if def, found := c.spec.Properties[name]; found {
+			c.checkType(def, value, keyPath)
+			continue
+		}
+
+		if c.hasNested(name) {
+			if nested, ok := value.(map[interface{}]interface{}); ok {
+				c.walk(nested, name+".", keyPath)
+			} else if value != nil && !isVariable(value) {
+				c.add(boshsch.LevelError, keyPath, "Expected hash since job spec defines properties under '%s' but got %s", name, kindOf(value))
+			}
+			continue
+		}
+
+		if !c.source.shared {
+			c.add(boshsch.LevelError, keyPath, "Unknown property '%s' for job '%s'", name, c.spec.Name)
+		}
+	}
+}
+
+// checkType compares kind of value with the spec's default or, if it is not set, example.
+// Only hash and array mismatches are errors since templates commonly
+// accept both strings and numbers; examples are merely documentation.
+func (c *jobCheck) checkType(def boshjobman.PropertyDefinition, value interface{}, path []patch.Token) {
+	if value == nil || isVariable(value) {
+		return
+	}
+
+	expected, basedOn := def.Default, "default"
+	if expected == nil {
+		expected, basedOn = def.Example, "example"
+	}
+
+	if expected == nil {
+		return
+	}
+
+	expectedKind, actualKind := kindOf(expected), kindOf(value)
+	if expectedKind == actualKind {
+		return
+	}
+
+	level := boshsch.LevelWarning
+
+	if basedOn == "default" && (isCollection(expectedKind) || isCollection(actualKind)) {
+		level = boshsch.LevelError
+	}
+
+	c.add(level, path, "Expected %s based on %s value in job spec but got %s", expectedKind, basedOn, actualKind)
+}
+
+func (c *jobCheck) hasNested(name string) bool {
+	for propName := range c.spec.Properties {
+		if strings.HasPrefix(propName, name+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *jobCheck) isSet(name string) bool {
+	var current interface{} = c.source.props
+
+	for _, segment := range strings.Split(name, ".") {
+		hash, ok := current.(map[interface{}]interface{})
+		if !ok {
+			// Value of a parent cannot be inspected (e.g. '((tls))')
+			return isVariable(current)
+		}
+
+		current, ok = hash[segment]
+		if !ok {
+			return false
+		}
+	}
+
+	return current != nil
+}
+
+func (c *jobCheck) add(level string, path []patch.Token, msg string, args ...interface{}) {
+	c.problems = append(c.problems, boshsch.Problem{Level: level, Path: pathString(path...), Message: fmt.Sprintf(msg, args...)})
+}
+
+func kindOf(value interface{}) string {
+	switch value.(type) {
+	case map[interface{}]interface{}:
+		return "hash"
+	case []interface{}:
+		return "array"
+	case bool:
+		return "boolean"
+	case int, int64, uint64, float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func isCollection(kind string) bool {
+	return kind == "hash" || kind == "array"
+}
+
+func isVariable(value interface{}) bool {
+	str, ok := value.(string)
+	return ok && boshtpl.IsVariable(str)
+}
+
+type propertyPair struct {
+	key   string
+	value interface{}
+}
+
+func sortedPairs(props map[interface{}]interface{}) []propertyPair {
+	var pairs []propertyPair
+
+	for key, value := range props {
+		pairs = append(pairs, propertyPair{fmt.Sprintf("%v", key), value})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].key < pairs[j].key })
+
+	return pairs
+}
+
+func appendTokens(tokens []patch.Token, more ...patch.Token) []patch.Token {
+	return append(append([]patch.Token{}, tokens...), more...)
+}
+
+func pathString(tokens ...patch.Token) string {
+	return patch.NewPointer(tokens).String()
+}
@@ -0,0 +1,203 @@
+package propcheck_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/propcheck"
+	"github.com/cloudfoundry/bosh-cli/v7/director/propcheck/propcheckfakes"
+	boshsch "github.com/cloudfoundry/bosh-cli/v7/director/schema"
+	boshjobman "github.com/cloudfoundry/bosh-cli/v7/release/job/manifest"
+)
+
+var _ = Describe("ManifestValidator", func() {
+	var (
+		specReader *propcheckfakes.FakeSpecReader
+		validator  ManifestValidator
+	)
+
+	BeforeEach(func() {
+		specReader = &propcheckfakes.FakeSpecReader{}
+		specReader.ReadJobSpecsStub = func(release Release) (map[string]boshjobman.Manifest, bool, error) {
+			if release.Name != "rel" {
+				return nil, false, nil
+			}
+
+			return map[string]boshjobman.Manifest{
+				"web": {
+					Name: "web",
+					Properties: map[string]boshjobman.PropertyDefinition{
+						"port":          {Default: 8080},
+						"hosts":         {Default: []interface{}{}},
+						"env":           {Default: map[interface{}]interface{}{}},
+						"tls.cert":      {},
+						"tls.key":       {},
+						"admin.name":    {Example: "admin"},
+						"admin.enabled": {Default: false},
+					},
+				},
+			}, true, nil
+		}
+
+		validator = NewManifestValidator(specReader)
+	})
+
+	validate := func(manifest string) []boshsch.Problem {
+		problems, err := validator.Validate([]byte(manifest))
+		Expect(err).ToNot(HaveOccurred())
+		return problems
+	}
+
+	It("returns no problems when properties match job spec", func() {
+		Expect(validate(`
+releases:
+- name: rel
+  version: "1"
+  url: file:///rel.tgz
+instance_groups:
+- name: ig
+  jobs:
+  - name: web
+    release: rel
+    properties:
+      port: 80
+      hosts: [a, b]
+      env: {ANY: thing}
+      tls: ((tls))
+      admin:
+        name: root
+`)).To(BeEmpty())
+
+		Expect(specReader.ReadJobSpecsCallCount()).To(Equal(1))
+		Expect(specReader.ReadJobSpecsArgsForCall(0)).To(Equal(Release{Name: "rel", Version: "1", URL: "file:///rel.tgz"}))
+	})
+
+	It("reports unknown properties, type mismatches and missing values", func() {
+		Expect(validate(`
+releases:
+- name: rel
+instance_groups:
+- name: ig
+  jobs:
+  - name: web
+    release: rel
+    properties:
+      prot: 80
+      port: "80"
+      hosts: a
+      env: ((env))
+      tls: cert
+      admin:
+        name: [root]
+        enabled: yes
+`)).To(Equal([]boshsch.Problem{
+			{Level: boshsch.LevelWarning, Path: "/instance_groups/name=ig/jobs/name=web/properties/admin/name", Message: "Expected string based on example value in job spec but got array"},
+			{Level: boshsch.LevelError, Path: "/instance_groups/name=ig/jobs/name=web/properties/hosts", Message: "Expected array based on default value in job spec but got string"},
+			{Level: boshsch.LevelWarning, Path: "/instance_groups/name=ig/jobs/name=web/properties/port", Message: "Expected number based on default value in job spec but got string"},
+			{Level: boshsch.LevelError, Path: "/instance_groups/name=ig/jobs/name=web/properties/prot", Message: "Unknown property 'prot' for job 'web'"},
+			{Level: boshsch.LevelError, Path: "/instance_groups/name=ig/jobs/name=web/properties/tls", Message: "Expected hash since job spec defines properties under 'tls' but got string"},
+			{Level: boshsch.LevelWarning, Path: "/instance_groups/name=ig/jobs/name=web/properties", Message: "Missing value for property 'tls.cert' that does not have a default"},
+			{Level: boshsch.LevelWarning, Path: "/instance_groups/name=ig/jobs/name=web/properties", Message: "Missing value for property 'tls.key' that does not have a default"},
+		}))
+	})
+
+	It("falls back to instance group and global properties without reporting unknown keys", func() {
+		Expect(validate(`
+releases:
+- name: rel
+properties:
+  other-job-prop: true
+  tls: {cert: c, key: k}
+  admin: {name: root}
+  hosts: a
+instance_groups:
+- name: ig
+  jobs:
+  - name: web
+    release: rel
+- name: legacy
+  jobs:
+  - name: web
+    release: rel
+  properties:
+    tls: {cert: c}
+`)).To(Equal([]boshsch.Problem{
+			{Level: boshsch.LevelError, Path: "/properties/hosts", Message: "Expected array based on default value in job spec but got string"},
+			{Level: boshsch.LevelWarning, Path: "/instance_groups/name=legacy/properties", Message: "Missing value for property 'admin.name' that does not have a default"},
+			{Level: boshsch.LevelWarning, Path: "/instance_groups/name=legacy/properties", Message: "Missing value for property 'tls.key' that does not have a default"},
+		}))
+	})
+
+	It("validates addon jobs and reports jobs missing from releases", func() {
+		Expect(validate(`
+releases:
+- name: rel
+addons:
+- name: addon
+  jobs:
+  - name: web
+    release: rel
+    properties:
+      tls: {cert: c, key: k}
+      admin: {name: root}
+      unknown: {}
+  - name: missing
+    release: rel
+`)).To(Equal([]boshsch.Problem{
+			{Level: boshsch.LevelError, Path: "/addons/name=addon/jobs/name=web/properties/unknown", Message: "Unknown property 'unknown' for job 'web'"},
+			{Level: boshsch.LevelError, Path: "/addons/name=addon/jobs/name=missing", Message: "Expected release 'rel' to contain job 'missing'"},
+		}))
+	})
+
+	It("warns about releases that are not available locally and skips their jobs", func() {
+		Expect(validate(`
+releases:
+- name: remote
+instance_groups:
+- name: ig
+  jobs:
+  - name: other
+    release: remote
+    properties: {anything: 1}
+`)).To(Equal([]boshsch.Problem{
+			{Level: boshsch.LevelWarning, Path: "/releases/name=remote", Message: "Skipping validation of job properties since release is not available locally"},
+		}))
+	})
+
+	It("escapes names and keys used in paths", func() {
+		Expect(validate(`
+releases:
+- name: rel
+- name: remote:v1
+instance_groups:
+- name: z1/web~1
+  jobs:
+  - name: web
+    release: rel
+    properties:
+      tls: {cert: c, key: k}
+      admin: {name: root}
+      "db:url": x
+`)).To(Equal([]boshsch.Problem{
+			{Level: boshsch.LevelWarning, Path: "/releases/name=remote~7v1", Message: "Skipping validation of job properties since release is not available locally"},
+			{Level: boshsch.LevelError, Path: "/instance_groups/name=z1~1web~01/jobs/name=web/properties/db~7url", Message: "Unknown property 'db:url' for job 'web'"},
+		}))
+	})
+
+	It("returns error if reading job specs fails", func() {
+		specReader.ReadJobSpecsStub = nil
+		specReader.ReadJobSpecsReturns(nil, false, errors.New("fake-err"))
+
+		_, err := validator.Validate([]byte("releases: [{name: rel}]"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Reading job specs of release 'rel': fake-err"))
+	})
+
+	It("returns error if manifest cannot be parsed", func() {
+		_, err := validator.Validate([]byte("releases: {"))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unmarshalling manifest"))
+	})
+})
@@ -0,0 +1,122 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package propcheckfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/v7/director/propcheck"
+	"github.com/cloudfoundry/bosh-cli/v7/release/job/manifest"
+)
+
+type FakeSpecReader struct {
+	ReadJobSpecsStub        func(propcheck.Release) (map[string]manifest.Manifest, bool, error)
+	readJobSpecsMutex       sync.RWMutex
+	readJobSpecsArgsForCall []struct {
+		arg1 propcheck.Release
+	}
+	readJobSpecsReturns struct {
+		result1 map[string]manifest.Manifest
+		result2 bool
+		result3 error
+	}
+	readJobSpecsReturnsOnCall map[int]struct {
+		result1 map[string]manifest.Manifest
+		result2 bool
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSpecReader) ReadJobSpecs(arg1 propcheck.Release) (map[string]manifest.Manifest, bool, error) {
+	fake.readJobSpecsMutex.Lock()
+	ret, specificReturn := fake.readJobSpecsReturnsOnCall[len(fake.readJobSpecsArgsForCall)]
+	fake.readJobSpecsArgsForCall = append(fake.readJobSpecsArgsForCall, struct {
+		arg1 propcheck.Release
+	}{arg1})
+	stub := fake.ReadJobSpecsStub
+	fakeReturns := fake.readJobSpecsReturns
+	fake.recordInvocation("ReadJobSpecs", []interface{}{arg1})
+	fake.readJobSpecsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeSpecReader) ReadJobSpecsCallCount() int {
+	fake.readJobSpecsMutex.RLock()
+	defer fake.readJobSpecsMutex.RUnlock()
+	return len(fake.readJobSpecsArgsForCall)
+}
+
+func (fake *FakeSpecReader) ReadJobSpecsCalls(stub func(propcheck.Release) (map[string]manifest.Manifest, bool, error)) {
+	fake.readJobSpecsMutex.Lock()
+	defer fake.readJobSpecsMutex.Unlock()
+	fake.ReadJobSpecsStub = stub
+}
+
+func (fake *FakeSpecReader) ReadJobSpecsArgsForCall(i int) propcheck.Release {
+	fake.readJobSpecsMutex.RLock()
+	defer fake.readJobSpecsMutex.RUnlock()
+	argsForCall := fake.readJobSpecsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpecReader) ReadJobSpecsReturns(result1 map[string]manifest.Manifest, result2 bool, result3 error) {
+	fake.readJobSpecsMutex.Lock()
+	defer fake.readJobSpecsMutex.Unlock()
+	fake.ReadJobSpecsStub = nil
+	fake.readJobSpecsReturns = struct {
+		result1 map[string]manifest.Manifest
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeSpecReader) ReadJobSpecsReturnsOnCall(i int, result1 map[string]manifest.Manifest, result2 bool, result3 error) {
+	fake.readJobSpecsMutex.Lock()
+	defer fake.readJobSpecsMutex.Unlock()
+	fake.ReadJobSpecsStub = nil
+	if fake.readJobSpecsReturnsOnCall == nil {
+		fake.readJobSpecsReturnsOnCall = make(map[int]struct {
+			result1 map[string]manifest.Manifest
+			result2 bool
+			result3 error
+		})
+	}
+	fake.readJobSpecsReturnsOnCall[i] = struct {
+		result1 map[string]manifest.Manifest
+		result2 bool
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeSpecReader) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.readJobSpecsMutex.RLock()
+	defer fake.readJobSpecsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSpecReader) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ propcheck.SpecReader = new(FakeSpecReader)
@@ -0,0 +1,13 @@
+package propcheck_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPropcheck(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "director/propcheck")
+}
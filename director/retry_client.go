@@ -0,0 +1,195 @@
+package director
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cloudfoundry/bosh-utils/httpclient"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+)
+
+// RetryClient retries idempotent requests that failed because of network
+// errors or transient load balancer errors (502, 503, 504). Non-idempotent
+// requests are only retried if connection to the Director was never made.
+type RetryClient struct {
+	client AdjustedClient
+	policy RetryPolicy
+	sleep  func(time.Duration)
+
+	logTag string
+	logger boshlog.Logger
+}
+
+func NewRetryClient(client AdjustedClient, policy RetryPolicy, sleep func(time.Duration), logger boshlog.Logger) RetryClient {
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	return RetryClient{
+		client: client,
+		policy: policy.WithDefaults(),
+		sleep:  sleep,
+
+		logTag: "director.RetryClient",
+		logger: logger,
+	}
+}
+
+func (c RetryClient) Do(req *http.Request) (*http.Response, error) {
+	originalBody, err := httpclient.MakeReplayable(req)
+	if originalBody != nil {
+		defer originalBody.Close()
+	}
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Making the request retryable")
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, bosherr.WrapError(err, "Updating request body for retry")
+			}
+		}
+
+		resp, err := c.attempt(req)
+
+		if attempt >= c.policy.MaxAttempts || !c.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		if err != nil {
+			c.logger.Debug(c.logTag, "Retrying request '%s %s' after attempt %d failed: %s",
+				req.Method, req.URL, attempt, err)
+		} else {
+			c.logger.Debug(c.logTag, "Retrying request '%s %s' after attempt %d responded with %d",
+				req.Method, req.URL, attempt, resp.StatusCode)
+
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		c.sleep(c.policy.Delay(attempt))
+	}
+}
+
+func (c RetryClient) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return c.policy.IsIdempotent(req) || isDialError(err)
+	}
+
+	return c.policy.IsIdempotent(req) && c.policy.IsRetryableStatus(resp.StatusCode)
+}
+
+func (c RetryClient) attempt(req *http.Request) (*http.Response, error) {
+	if c.policy.Timeout <= 0 {
+		return c.client.Do(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := &responseTimer{timeout: c.policy.Timeout, expire: cancel}
+
+	req = req.WithContext(ctx)
+
+	// Uploads may take a while so only waiting for response is timed
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = timerStartingReadCloser{ReadCloser: req.Body, timer: timer}
+	} else {
+		timer.Start()
+	}
+
+	resp, err := c.client.Do(req)
+
+	if timer.Stop() {
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		cancel()
+
+		return nil, bosherr.Errorf("Timed out after %s waiting for response to '%s %s'",
+			c.policy.Timeout, req.Method, req.URL)
+	}
+
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+
+	// Keep request context alive until body is consumed
+	resp.Body = cancelingReadCloser{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// responseTimer is started once request is sent
+type responseTimer struct {
+	timeout time.Duration
+	expire  func()
+
+	timer   *time.Timer
+	stopped bool
+	lock    sync.Mutex
+}
+
+func (t *responseTimer) Start() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.timer == nil && !t.stopped {
+		t.timer = time.AfterFunc(t.timeout, t.expire)
+	}
+}
+
+// Stop returns true if timer has already expired
+func (t *responseTimer) Stop() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stopped = true
+
+	return t.timer != nil && !t.timer.Stop()
+}
+
+type timerStartingReadCloser struct {
+	io.ReadCloser
+	timer *responseTimer
+}
+
+func (r timerStartingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if err == io.EOF {
+		r.timer.Start()
+	}
+	return n, err
+}
+
+func (r timerStartingReadCloser) Close() error {
+	r.timer.Start()
+	return r.ReadCloser.Close()
+}
+
+type cancelingReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r cancelingReadCloser) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
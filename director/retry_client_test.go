@@ -0,0 +1,240 @@
+package director_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+)
+
+var _ = Describe("RetryClient", func() {
+	var (
+		innerClient *fakedir.FakeAdjustedClient
+		policy      RetryPolicy
+		sleeps      []time.Duration
+		req         *http.Request
+	)
+
+	BeforeEach(func() {
+		innerClient = &fakedir.FakeAdjustedClient{}
+		policy = RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second, MaxDelay: 10 * time.Second}
+		sleeps = nil
+
+		req = &http.Request{
+			Method: "GET",
+			URL:    &url.URL{Scheme: "https", Host: "director", Path: "/info"},
+			Header: http.Header{},
+		}
+	})
+
+	buildClient := func() RetryClient {
+		sleep := func(d time.Duration) { sleeps = append(sleeps, d) }
+		return NewRetryClient(innerClient, policy, sleep, boshlog.NewLogger(boshlog.LevelNone))
+	}
+
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}
+	}
+
+	It("returns successful response without retrying", func() {
+		innerClient.DoReturns(response(200), nil)
+
+		resp, err := buildClient().Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(200))
+		Expect(innerClient.DoCallCount()).To(Equal(1))
+		Expect(sleeps).To(BeEmpty())
+	})
+
+	It("retries idempotent requests on transient statuses with increasing delays", func() {
+		innerClient.DoReturnsOnCall(0, response(502), nil)
+		innerClient.DoReturnsOnCall(1, response(503), nil)
+		innerClient.DoReturnsOnCall(2, response(200), nil)
+
+		resp, err := buildClient().Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(200))
+		Expect(innerClient.DoCallCount()).To(Equal(3))
+
+		Expect(sleeps).To(HaveLen(2))
+		Expect(sleeps[0]).To(BeNumerically("<=", time.Second))
+		Expect(sleeps[1]).To(BeNumerically(">=", time.Second))
+	})
+
+	It("returns last response after max attempts", func() {
+		innerClient.DoReturns(response(503), nil)
+
+		resp, err := buildClient().Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(503))
+		Expect(innerClient.DoCallCount()).To(Equal(3))
+	})
+
+	It("retries idempotent requests on network errors", func() {
+		innerClient.DoReturnsOnCall(0, nil, errors.New("connection reset by peer"))
+		innerClient.DoReturnsOnCall(1, response(200), nil)
+
+		resp, err := buildClient().Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(200))
+		Expect(innerClient.DoCallCount()).To(Equal(2))
+	})
+
+	It("returns last error after max attempts", func() {
+		innerClient.DoReturns(nil, errors.New("fake-err"))
+
+		_, err := buildClient().Do(req)
+		Expect(err).To(MatchError("fake-err"))
+		Expect(innerClient.DoCallCount()).To(Equal(3))
+	})
+
+	It("does not retry on other statuses", func() {
+		innerClient.DoReturns(response(500), nil)
+
+		resp, err := buildClient().Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(500))
+		Expect(innerClient.DoCallCount()).To(Equal(1))
+	})
+
+	Context("when request is not idempotent", func() {
+		BeforeEach(func() {
+			req.Method = "POST"
+			req.Body = io.NopCloser(strings.NewReader("body"))
+		})
+
+		It("does not retry on transient statuses", func() {
+			innerClient.DoReturns(response(503), nil)
+
+			resp, err := buildClient().Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(503))
+			Expect(innerClient.DoCallCount()).To(Equal(1))
+		})
+
+		It("does not retry on errors after connecting", func() {
+			innerClient.DoReturns(nil, errors.New("connection reset by peer"))
+
+			_, err := buildClient().Do(req)
+			Expect(err).To(HaveOccurred())
+			Expect(innerClient.DoCallCount()).To(Equal(1))
+		})
+
+		It("retries with the same body if connection could not be established", func() {
+			var bodies []string
+
+			innerClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				body, err := io.ReadAll(req.Body)
+				Expect(err).ToNot(HaveOccurred())
+				bodies = append(bodies, string(body))
+
+				if len(bodies) == 1 {
+					return nil, &url.Error{Op: "Post", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}
+				}
+				return response(200), nil
+			}
+
+			resp, err := buildClient().Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(200))
+			Expect(bodies).To(Equal([]string{"body", "body"}))
+		})
+	})
+
+	Context("when timeout is configured", func() {
+		BeforeEach(func() {
+			policy.Timeout = 50 * time.Millisecond
+			policy.MaxAttempts = 2
+		})
+
+		It("retries after attempt times out", func() {
+			innerClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				if innerClient.DoCallCount() == 1 {
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				}
+				return response(200), nil
+			}
+
+			resp, err := buildClient().Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(200))
+			Expect(innerClient.DoCallCount()).To(Equal(2))
+		})
+
+		It("returns timeout error after max attempts", func() {
+			innerClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+
+			_, err := buildClient().Do(req)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Timed out after 50ms waiting for response to 'GET https://director/info'"))
+		})
+
+		It("does not apply timeout to sending request body", func() {
+			req.Method = "POST"
+			req.Body = io.NopCloser(strings.NewReader("body"))
+
+			innerClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				time.Sleep(100 * time.Millisecond)
+
+				_, err := io.ReadAll(req.Body)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(req.Context().Err()).ToNot(HaveOccurred())
+
+				return response(200), nil
+			}
+
+			resp, err := buildClient().Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(200))
+		})
+
+		It("times out waiting for response once request body is sent", func() {
+			req.Method = "POST"
+			req.Body = io.NopCloser(strings.NewReader("body"))
+
+			innerClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				_, err := io.ReadAll(req.Body)
+				Expect(err).ToNot(HaveOccurred())
+
+				<-req.Context().Done()
+				return nil, req.Context().Err()
+			}
+
+			_, err := buildClient().Do(req)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Timed out after 50ms waiting for response to 'POST https://director/info'"))
+		})
+
+		It("does not apply timeout to reading response body", func() {
+			var reqCtxErr func() error
+
+			innerClient.DoStub = func(req *http.Request) (*http.Response, error) {
+				reqCtxErr = req.Context().Err
+				return response(200), nil
+			}
+
+			resp, err := buildClient().Do(req)
+			Expect(err).ToNot(HaveOccurred())
+
+			time.Sleep(100 * time.Millisecond)
+			Expect(reqCtxErr()).ToNot(HaveOccurred())
+
+			Expect(resp.Body.Close()).To(Succeed())
+			Expect(reqCtxErr()).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package director
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+type RetryPolicy struct {
+	// Total number of attempts including the first one
+	MaxAttempts int
+
+	// Delay before second attempt; doubled for each following attempt
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+
+	// Per attempt timeout for receiving response headers once request was sent (0 disables it).
+	// Request and response bodies are not subject to it since uploads and downloads may take a while.
+	Timeout time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  5,
+		InitialDelay: 500 * time.Millisecond,
+		MaxDelay:     10 * time.Second,
+	}
+}
+
+// WithDefaults fills in unset values from the default policy
+func (p RetryPolicy) WithDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = defaults.InitialDelay
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaults.MaxDelay
+	}
+
+	if p.MaxDelay < p.InitialDelay {
+		p.MaxDelay = p.InitialDelay
+	}
+
+	return p
+}
+
+// Delay returns exponentially increasing delay to wait after given
+// (1-based) attempt. Half of the delay is randomized so that multiple
+// clients do not hit load balancer at the same time.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.InitialDelay
+
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	half := delay / 2
+
+	if half <= 0 {
+		return delay
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half)+1)) //nolint:gosec
+}
+
+// IsIdempotent returns true for requests that could be safely sent
+// to the Director again even if it might have already received them.
+func (p RetryPolicy) IsIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryableStatus returns true for statuses typically returned by
+// load balancers when Director is temporarily unavailable.
+func (p RetryPolicy) IsRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
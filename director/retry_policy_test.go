@@ -0,0 +1,85 @@
+package director_test
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director"
+)
+
+var _ = Describe("RetryPolicy", func() {
+	Describe("WithDefaults", func() {
+		It("fills in unset values", func() {
+			Expect(RetryPolicy{}.WithDefaults()).To(Equal(DefaultRetryPolicy()))
+		})
+
+		It("keeps set values", func() {
+			policy := RetryPolicy{MaxAttempts: 2, InitialDelay: time.Second, MaxDelay: 5 * time.Second, Timeout: time.Minute}
+			Expect(policy.WithDefaults()).To(Equal(policy))
+		})
+
+		It("raises max delay to initial delay", func() {
+			policy := RetryPolicy{InitialDelay: time.Minute}.WithDefaults()
+			Expect(policy.MaxDelay).To(Equal(time.Minute))
+		})
+	})
+
+	Describe("Delay", func() {
+		var policy RetryPolicy
+
+		BeforeEach(func() {
+			policy = RetryPolicy{MaxAttempts: 10, InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+		})
+
+		It("doubles delay for each attempt with up to half of it randomized", func() {
+			for i := 0; i < 20; i++ {
+				Expect(policy.Delay(1)).To(BeNumerically(">=", 500*time.Millisecond))
+				Expect(policy.Delay(1)).To(BeNumerically("<=", time.Second))
+
+				Expect(policy.Delay(2)).To(BeNumerically(">=", time.Second))
+				Expect(policy.Delay(2)).To(BeNumerically("<=", 2*time.Second))
+
+				Expect(policy.Delay(3)).To(BeNumerically(">=", 2*time.Second))
+				Expect(policy.Delay(3)).To(BeNumerically("<=", 4*time.Second))
+			}
+		})
+
+		It("caps delay at max delay", func() {
+			for i := 0; i < 20; i++ {
+				Expect(policy.Delay(9)).To(BeNumerically(">=", 2500*time.Millisecond))
+				Expect(policy.Delay(9)).To(BeNumerically("<=", 5*time.Second))
+			}
+		})
+	})
+
+	Describe("IsIdempotent", func() {
+		It("returns true for safe methods", func() {
+			for _, method := range []string{"GET", "HEAD", "OPTIONS"} {
+				Expect(RetryPolicy{}.IsIdempotent(&http.Request{Method: method})).To(BeTrue())
+			}
+		})
+
+		It("returns false for methods that change Director state", func() {
+			for _, method := range []string{"POST", "PUT", "DELETE", "PATCH"} {
+				Expect(RetryPolicy{}.IsIdempotent(&http.Request{Method: method})).To(BeFalse())
+			}
+		})
+	})
+
+	Describe("IsRetryableStatus", func() {
+		It("returns true for transient load balancer errors", func() {
+			Expect(RetryPolicy{}.IsRetryableStatus(502)).To(BeTrue())
+			Expect(RetryPolicy{}.IsRetryableStatus(503)).To(BeTrue())
+			Expect(RetryPolicy{}.IsRetryableStatus(504)).To(BeTrue())
+		})
+
+		It("returns false for other statuses", func() {
+			Expect(RetryPolicy{}.IsRetryableStatus(200)).To(BeFalse())
+			Expect(RetryPolicy{}.IsRetryableStatus(401)).To(BeFalse())
+			Expect(RetryPolicy{}.IsRetryableStatus(500)).To(BeFalse())
+		})
+	})
+})
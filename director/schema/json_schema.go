@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"encoding/json"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema returns draft-07 JSON Schema document that
+// could be used by editors to complete and check manifests
+func (s *Schema) JSONSchema(title string) ([]byte, error) {
+	doc := s.jsonSchema()
+	doc["$schema"] = jsonSchemaDraft
+	doc["title"] = title
+
+	bytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshalling JSON schema")
+	}
+
+	return bytes, nil
+}
+
+func (s *Schema) jsonSchema() map[string]interface{} {
+	doc := map[string]interface{}{}
+
+	if len(s.Description) > 0 {
+		doc["description"] = s.Description
+	}
+
+	if len(s.Types) > 0 {
+		var types []string
+
+		for _, t := range s.Types {
+			types = append(types, string(t))
+		}
+
+		if s.Nullable {
+			types = append(types, "null")
+		}
+
+		if len(types) == 1 {
+			doc["type"] = types[0]
+		} else {
+			doc["type"] = types
+		}
+	}
+
+	if len(s.Properties) > 0 {
+		props := map[string]interface{}{}
+
+		for name, prop := range s.Properties {
+			props[name] = prop.jsonSchema()
+		}
+
+		doc["properties"] = props
+	}
+
+	if len(s.Required) > 0 {
+		doc["required"] = s.Required
+	}
+
+	// Unknown keys are only warnings during validation
+	// but editors are expected to highlight them
+	if len(s.Properties) > 0 && !s.FreeForm {
+		doc["additionalProperties"] = false
+	}
+
+	if s.Items != nil {
+		doc["items"] = s.Items.jsonSchema()
+	}
+
+	return doc
+}
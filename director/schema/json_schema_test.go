@@ -0,0 +1,73 @@
+package schema_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/schema"
+)
+
+var _ = Describe("JSONSchema", func() {
+	It("returns draft-07 document describing structure", func() {
+		bytes, err := CPIConfig().JSONSchema("BOSH CPI config")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(bytes).To(MatchJSON(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "BOSH CPI config",
+  "description": "BOSH CPI config",
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["cpis"],
+  "properties": {
+    "cpis": {
+      "description": "CPIs",
+      "type": "array",
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["name", "type"],
+        "properties": {
+          "name": {"description": "Name of the CPI referenced from AZs", "type": "string"},
+          "type": {"description": "Type of the CPI (e.g. aws)", "type": "string"},
+          "exec_path": {"description": "Path to the CPI executable", "type": "string"},
+          "properties": {"description": "CPI settings", "type": ["object", "null"]},
+          "migrated_from": {
+            "description": "CPIs this CPI was renamed from",
+            "type": "array",
+            "items": {
+              "type": "object",
+              "additionalProperties": false,
+              "required": ["name"],
+              "properties": {
+                "name": {"description": "Name of the former CPI", "type": "string"}
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`))
+	})
+
+	It("lists multiple types for values that accept them", func() {
+		bytes, err := DeploymentManifest().JSONSchema("BOSH deployment manifest")
+		Expect(err).ToNot(HaveOccurred())
+
+		var doc struct {
+			Properties map[string]struct {
+				Properties map[string]struct {
+					Type interface{}
+				}
+			}
+		}
+
+		err = json.Unmarshal(bytes, &doc)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(doc.Properties["update"].Properties["max_in_flight"].Type).To(Equal([]interface{}{"integer", "string"}))
+	})
+})
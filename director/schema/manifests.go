@@ -0,0 +1,230 @@
+package schema
+
+// Structures below follow manifest documentation at
+// https://bosh.io/docs/manifest-v2/, https://bosh.io/docs/runtime-config/,
+// https://bosh.io/docs/cloud-config/ and https://bosh.io/docs/cpi-config/
+
+func DeploymentManifest() *Schema {
+	return obj("BOSH deployment manifest", map[string]*Schema{
+		"name":          str("Name of the deployment"),
+		"director_uuid": str("Deprecated: UUID of the director"),
+		"releases":      arr("Releases used by the deployment", release()),
+		"stemcells":     arr("Stemcells used by the deployment", stemcell()),
+		"update":        update("Default update settings for instance groups"),
+		"instance_groups": arr("Instance groups", obj("", map[string]*Schema{
+			"name":                 str("Name of the instance group"),
+			"azs":                  strs("AZs from cloud config to place instances in"),
+			"instances":            integer("Number of instances"),
+			"jobs":                 arr("Jobs to colocate on each instance", job()),
+			"vm_type":              str("VM type from cloud config"),
+			"vm_extensions":        strs("VM extensions from cloud config"),
+			"vm_resources":         vmResources(),
+			"stemcell":             str("Alias of stemcell from stemcells section"),
+			"persistent_disk":      integer("Persistent disk size in MB"),
+			"persistent_disk_type": str("Disk type from cloud config"),
+			"persistent_disks": arr("Multiple persistent disks", obj("", map[string]*Schema{
+				"name": str("Name of the disk"),
+				"type": str("Disk type from cloud config"),
+			}, "name", "type")),
+			"networks": arr("Networks from cloud config", obj("", map[string]*Schema{
+				"name":       str("Name of the network"),
+				"static_ips": strs("Static IP addresses or ranges"),
+				"default":    strs("Types of default network settings (dns, gateway)"),
+			}, "name")),
+			"update": update("Update settings overriding the top level ones"),
+			"migrated_from": arr("Instance groups this instance group was renamed or merged from", obj("", map[string]*Schema{
+				"name": str("Name of the former instance group"),
+				"az":   str("AZ of the former instance group"),
+			}, "name")),
+			"lifecycle":  str("Either 'service' or 'errand'"),
+			"properties": hash("Deprecated: properties for all jobs"),
+			"env":        hash("Agent and VM settings"),
+		}, "name", "instances", "jobs", "networks")),
+		"addons":     arr("Addons colocated with instance groups", addon()),
+		"properties": hash("Deprecated: global properties"),
+		"variables":  arr("Variables generated by the director", variable()),
+		"tags":       hash("Tags added to VMs"),
+		"features": obj("Deployment features", map[string]*Schema{
+			"converge_variables":      boolean("Converge variables to latest versions on deploy"),
+			"randomize_az_placement":  boolean("Randomize placement of instances across AZs"),
+			"use_dns_addresses":       boolean("Use DNS addresses in links"),
+			"use_link_dns_names":      boolean("Use link names in DNS addresses"),
+			"use_short_dns_addresses": boolean("Use short DNS addresses in links"),
+			"use_tmpfs_config":        boolean("Place job configuration on tmpfs"),
+		}),
+	}, "name")
+}
+
+func RuntimeConfig() *Schema {
+	return obj("BOSH runtime config", map[string]*Schema{
+		"releases":  arr("Releases used by addons", release()),
+		"addons":    arr("Addons colocated with instance groups", addon()),
+		"tags":      hash("Tags added to VMs of all deployments"),
+		"variables": arr("Variables generated by the director", variable()),
+	})
+}
+
+func CloudConfig() *Schema {
+	return obj("BOSH cloud config", map[string]*Schema{
+		"azs": arr("Availability zones", obj("", map[string]*Schema{
+			"name":             str("Name of the AZ"),
+			"cpi":              str("Name of the CPI from CPI config"),
+			"cloud_properties": hash("IaaS specific AZ settings"),
+		}, "name")),
+		"networks": arr("Networks", obj("", map[string]*Schema{
+			"name": str("Name of the network"),
+			"type": str("Either 'manual', 'dynamic' or 'vip'"),
+			"subnets": arr("Subnets", obj("", map[string]*Schema{
+				"name":             str("Name of the subnet"),
+				"range":            str("Subnet IP range (e.g. 10.10.0.0/24)"),
+				"gateway":          str("Subnet gateway IP"),
+				"dns":              strs("DNS IP addresses"),
+				"reserved":         strs("IP addresses or ranges that are not used by the director"),
+				"static":           strs("IP addresses or ranges for static IPs"),
+				"az":               str("AZ of the subnet"),
+				"azs":              strs("AZs of the subnet"),
+				"prefix":           intOrStr("Size of prefixes delegated to VMs"),
+				"cloud_properties": hash("IaaS specific subnet settings"),
+			})),
+			"dns":              strs("DNS IP addresses of dynamic network"),
+			"cloud_properties": hash("IaaS specific network settings"),
+		}, "name")),
+		"vm_types": arr("VM types", obj("", map[string]*Schema{
+			"name":             str("Name of the VM type"),
+			"cloud_properties": hash("IaaS specific VM settings"),
+		}, "name")),
+		"vm_extensions": arr("VM extensions", obj("", map[string]*Schema{
+			"name":             str("Name of the VM extension"),
+			"cloud_properties": hash("IaaS specific VM settings"),
+		}, "name")),
+		"disk_types": arr("Disk types", obj("", map[string]*Schema{
+			"name":             str("Name of the disk type"),
+			"disk_size":        integer("Disk size in MB"),
+			"cloud_properties": hash("IaaS specific disk settings"),
+		}, "name", "disk_size")),
+		"compilation": obj("Compilation VM settings", map[string]*Schema{
+			"workers":               integer("Maximum number of compilation VMs"),
+			"network":               str("Network of compilation VMs"),
+			"az":                    str("AZ of compilation VMs"),
+			"vm_type":               str("VM type of compilation VMs"),
+			"vm_resources":          vmResources(),
+			"reuse_compilation_vms": boolean("Reuse compilation VMs between packages"),
+			"orphan_workers":        boolean("Orphan compilation VMs instead of deleting them"),
+			"cloud_properties":      hash("IaaS specific VM settings"),
+			"env":                   hash("Agent and VM settings"),
+		}, "workers", "network"),
+	})
+}
+
+func CPIConfig() *Schema {
+	return obj("BOSH CPI config", map[string]*Schema{
+		"cpis": arr("CPIs", obj("", map[string]*Schema{
+			"name":       str("Name of the CPI referenced from AZs"),
+			"type":       str("Type of the CPI (e.g. aws)"),
+			"exec_path":  str("Path to the CPI executable"),
+			"properties": hash("CPI settings"),
+			"migrated_from": arr("CPIs this CPI was renamed from", obj("", map[string]*Schema{
+				"name": str("Name of the former CPI"),
+			}, "name")),
+		}, "name", "type")),
+	}, "cpis")
+}
+
+func release() *Schema {
+	return obj("", map[string]*Schema{
+		"name":    str("Name of the release"),
+		"version": version("Version of the release or 'latest'"),
+		"url":     str("URL of the release tarball"),
+		"sha1":    str("Digest of the release tarball"),
+		"stemcell": obj("Stemcell of a compiled release", map[string]*Schema{
+			"os":      str("Operating system of the stemcell"),
+			"version": version("Version of the stemcell"),
+		}),
+		"exported_from": arr("Stemcells the compiled release was exported from", obj("", map[string]*Schema{
+			"os":      str("Operating system of the stemcell"),
+			"version": version("Version of the stemcell"),
+		})),
+	}, "name", "version")
+}
+
+func stemcell() *Schema {
+	return obj("", map[string]*Schema{
+		"alias":   str("Name used by instance groups to reference the stemcell"),
+		"os":      str("Operating system of the stemcell"),
+		"name":    str("Full name of the stemcell"),
+		"version": version("Version of the stemcell or 'latest'"),
+	}, "alias", "version")
+}
+
+func update(desc string) *Schema {
+	return obj(desc, map[string]*Schema{
+		"canaries":          intOrStr("Number or percentage of canary instances"),
+		"max_in_flight":     intOrStr("Number or percentage of instances updated in parallel"),
+		"canary_watch_time": intOrStr("Time in milliseconds or range to wait for canaries"),
+		"update_watch_time": intOrStr("Time in milliseconds or range to wait for instances"),
+		"serial":            boolean("Update instance groups one at a time"),
+		"vm_strategy":       str("Either 'delete-create' or 'create-swap-delete'"),
+
+		"initial_deploy_az_update_strategy": str("Either 'serial' or 'parallel'"),
+	})
+}
+
+func job() *Schema {
+	return obj("", map[string]*Schema{
+		"name":       str("Name of the job"),
+		"release":    str("Name of the release the job comes from"),
+		"consumes":   hash("Links consumed by the job"),
+		"provides":   hash("Links provided by the job"),
+		"properties": hash("Job properties"),
+		"custom_provider_definitions": arr("Links provided in addition to the ones in job spec", obj("", map[string]*Schema{
+			"name":       str("Name of the link"),
+			"type":       str("Type of the link"),
+			"properties": strs("Job properties included in the link"),
+		}, "name", "type")),
+	}, "name", "release")
+}
+
+func addon() *Schema {
+	return obj("", map[string]*Schema{
+		"name":    str("Name of the addon"),
+		"jobs":    arr("Jobs to colocate", job()),
+		"include": placementRules("Instances to place the addon on"),
+		"exclude": placementRules("Instances to not place the addon on"),
+	}, "name", "jobs")
+}
+
+func placementRules(desc string) *Schema {
+	return obj(desc, map[string]*Schema{
+		"stemcell": arr("Stemcells", obj("", map[string]*Schema{
+			"os": str("Operating system of the stemcell"),
+		}, "os")),
+		"deployments":     strs("Deployment names"),
+		"instance_groups": strs("Instance group names"),
+		"networks":        strs("Network names"),
+		"teams":           strs("Team names"),
+		"azs":             strs("AZ names"),
+		"lifecycle":       str("Either 'service' or 'errand'"),
+		"jobs": arr("Jobs", obj("", map[string]*Schema{
+			"name":    str("Name of the job"),
+			"release": str("Name of the release the job comes from"),
+		}, "name", "release")),
+	})
+}
+
+func variable() *Schema {
+	return obj("", map[string]*Schema{
+		"name":        str("Name of the variable"),
+		"type":        str("Type of the variable (e.g. password, certificate)"),
+		"options":     hash("Generation options"),
+		"consumes":    hash("Links used to generate the variable"),
+		"update_mode": str("Either 'converge' or 'no-overwrite'"),
+	}, "name", "type")
+}
+
+func vmResources() *Schema {
+	return obj("VM requirements used to pick IaaS specific VM settings", map[string]*Schema{
+		"cpu":                 integer("Number of CPUs"),
+		"ram":                 integer("Amount of RAM in MB"),
+		"ephemeral_disk_size": integer("Ephemeral disk size in MB"),
+	}, "cpu", "ram", "ephemeral_disk_size")
+}
@@ -0,0 +1,113 @@
+package schema
+
+import (
+	"sort"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+type Type string
+
+const (
+	TypeString  Type = "string"
+	TypeInteger Type = "integer"
+	TypeNumber  Type = "number"
+	TypeBoolean Type = "boolean"
+	TypeObject  Type = "object"
+	TypeArray   Type = "array"
+
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+// Schema describes expected structure of a YAML value.
+// Value of any type is accepted when Types is empty.
+type Schema struct {
+	Types       []Type
+	Description string
+
+	// Nullable allows keys to be present without a value (e.g. 'properties:')
+	Nullable bool
+
+	Properties map[string]*Schema
+	Required   []string
+
+	// FreeForm objects accept any keys (e.g. cloud_properties)
+	FreeForm bool
+
+	Items *Schema
+}
+
+type Problem struct {
+	Level   string
+	Path    string
+	Line    int
+	Message string
+}
+
+var schemasByType = map[string]func() *Schema{
+	"deployment":     DeploymentManifest,
+	"runtime-config": RuntimeConfig,
+	"cloud-config":   CloudConfig,
+	"cpi-config":     CPIConfig,
+}
+
+// ManifestTypes returns names accepted by ForType
+func ManifestTypes() []string {
+	var names []string
+
+	for name := range schemasByType {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func ForType(name string) (*Schema, error) {
+	schemaFunc, found := schemasByType[name]
+	if !found {
+		return nil, bosherr.Errorf("Unknown manifest type '%s', expected one of %v", name, ManifestTypes())
+	}
+
+	return schemaFunc(), nil
+}
+
+func str(desc string) *Schema {
+	return &Schema{Types: []Type{TypeString}, Description: desc}
+}
+
+func integer(desc string) *Schema {
+	return &Schema{Types: []Type{TypeInteger}, Description: desc}
+}
+
+func boolean(desc string) *Schema {
+	return &Schema{Types: []Type{TypeBoolean}, Description: desc}
+}
+
+// intOrStr is used for values such as percentages ('30%') and ranges ('1000-5000')
+func intOrStr(desc string) *Schema {
+	return &Schema{Types: []Type{TypeInteger, TypeString}, Description: desc}
+}
+
+// version accepts unquoted numbers since YAML parses '1' and '1.2' as numbers
+func version(desc string) *Schema {
+	return &Schema{Types: []Type{TypeString, TypeNumber}, Description: desc}
+}
+
+func hash(desc string) *Schema {
+	return &Schema{Types: []Type{TypeObject}, Description: desc, FreeForm: true, Nullable: true}
+}
+
+func obj(desc string, props map[string]*Schema, required ...string) *Schema {
+	return &Schema{Types: []Type{TypeObject}, Description: desc, Properties: props, Required: required}
+}
+
+func arr(desc string, items *Schema) *Schema {
+	return &Schema{Types: []Type{TypeArray}, Description: desc, Items: items}
+}
+
+func strs(desc string) *Schema {
+	return arr(desc, &Schema{Types: []Type{TypeString}})
+}
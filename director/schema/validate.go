@@ -0,0 +1,288 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v3"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+const maxSuggestionDistance = 2
+
+type validator struct {
+	problems []Problem
+}
+
+type mappingPair struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// Validate reports unknown keys as warnings and type mismatches and
+// missing keys as errors. Paths are formatted like ops file paths
+// (e.g. '/instance_groups/name=web/azs') so that they could be used for fixing problems.
+func (s *Schema) Validate(bytes []byte) ([]Problem, error) {
+	var doc yaml.Node
+
+	err := yaml.Unmarshal(bytes, &doc)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Parsing manifest")
+	}
+
+	if len(doc.Content) == 0 {
+		return []Problem{{Level: LevelError, Path: "/", Message: "Expected non-empty manifest"}}, nil
+	}
+
+	v := &validator{}
+	v.validate(s, doc.Content[0], []patch.Token{patch.RootToken{}})
+
+	return v.problems, nil
+}
+
+func (v *validator) validate(schema *Schema, node *yaml.Node, path []patch.Token) {
+	node = resolveAlias(node)
+
+	if len(schema.Types) == 0 {
+		return
+	}
+
+	if node.Kind == yaml.ScalarNode && boshtpl.IsVariable(node.Value) {
+		return
+	}
+
+	actual := nodeType(node)
+
+	if actual == "null" && schema.Nullable {
+		return
+	}
+
+	if !typeMatches(schema.Types, actual) {
+		v.addError(path, node.Line, "Expected %s but got %s", typeNames(schema.Types), actual)
+		return
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		if !schema.FreeForm {
+			v.validateMapping(schema, node, path)
+		}
+
+	case yaml.SequenceNode:
+		if schema.Items != nil {
+			for i, item := range node.Content {
+				v.validate(schema.Items, item, appendToken(path, itemToken(item, i)))
+			}
+		}
+	}
+}
+
+func (v *validator) validateMapping(schema *Schema, node *yaml.Node, path []patch.Token) {
+	found := map[string]bool{}
+
+	for _, pair := range mappingPairs(node) {
+		key := pair.key.Value
+		found[key] = true
+
+		prop, known := schema.Properties[key]
+		if !known {
+			msg := fmt.Sprintf("Unknown key '%s'", key)
+
+			if suggestion := closestKey(key, schema.Properties); len(suggestion) > 0 {
+				msg += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+			}
+
+			v.problems = append(v.problems, Problem{
+				Level: LevelWarning, Path: pathString(appendToken(path, patch.KeyToken{Key: key})), Line: pair.key.Line, Message: msg})
+
+			continue
+		}
+
+		v.validate(prop, pair.value, appendToken(path, patch.KeyToken{Key: key}))
+	}
+
+	for _, key := range schema.Required {
+		if !found[key] {
+			v.addError(path, node.Line, "Missing required key '%s'", key)
+		}
+	}
+}
+
+func (v *validator) addError(path []patch.Token, line int, msg string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Level: LevelError, Path: pathString(path), Line: line, Message: fmt.Sprintf(msg, args...)})
+}
+
+// mappingPairs expands merge keys ('<<: *anchor'); explicitly specified keys take precedence
+func mappingPairs(node *yaml.Node) []mappingPair {
+	var pairs, merged []mappingPair
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if key.Tag != "!!merge" {
+			pairs = append(pairs, mappingPair{key, value})
+			continue
+		}
+
+		value = resolveAlias(value)
+
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+
+		for _, source := range sources {
+			source = resolveAlias(source)
+			if source.Kind == yaml.MappingNode {
+				merged = append(merged, mappingPairs(source)...)
+			}
+		}
+	}
+
+	explicit := map[string]bool{}
+	for _, pair := range pairs {
+		explicit[pair.key.Value] = true
+	}
+
+	for _, pair := range merged {
+		if !explicit[pair.key.Value] {
+			explicit[pair.key.Value] = true
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return pairs
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+
+	return node
+}
+
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return string(TypeObject)
+	case yaml.SequenceNode:
+		return string(TypeArray)
+	}
+
+	switch node.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!int":
+		return string(TypeInteger)
+	case "!!float":
+		return string(TypeNumber)
+	case "!!bool":
+		return string(TypeBoolean)
+	default:
+		return string(TypeString)
+	}
+}
+
+func typeMatches(expected []Type, actual string) bool {
+	for _, t := range expected {
+		if string(t) == actual || (t == TypeNumber && actual == string(TypeInteger)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func typeNames(types []Type) string {
+	var names []string
+
+	for _, t := range types {
+		names = append(names, string(t))
+	}
+
+	return strings.Join(names, " or ")
+}
+
+// itemToken prefers name and alias keys over indexes
+// since they stay the same when items are reordered
+func itemToken(item *yaml.Node, index int) patch.Token {
+	item = resolveAlias(item)
+
+	if item.Kind == yaml.MappingNode {
+		for _, key := range []string{"name", "alias"} {
+			for _, pair := range mappingPairs(item) {
+				if pair.key.Value == key && pair.value.Kind == yaml.ScalarNode && len(pair.value.Value) > 0 {
+					return patch.MatchingIndexToken{Key: key, Value: pair.value.Value}
+				}
+			}
+		}
+	}
+
+	return patch.IndexToken{Index: index}
+}
+
+func appendToken(tokens []patch.Token, token patch.Token) []patch.Token {
+	return append(append([]patch.Token{}, tokens...), token)
+}
+
+// pathString formats the root itself as '/' instead of an empty ops file path
+func pathString(tokens []patch.Token) string {
+	if len(tokens) == 1 {
+		return "/"
+	}
+
+	return patch.NewPointer(tokens).String()
+}
+
+func closestKey(key string, props map[string]*Schema) string {
+	var candidates []string
+
+	for name := range props {
+		candidates = append(candidates, name)
+	}
+
+	// Iterate in stable order so that ties are resolved consistently
+	sort.Strings(candidates)
+
+	closest, closestDistance := "", maxSuggestionDistance+1
+
+	for _, name := range candidates {
+		if distance := editDistance(key, name); distance < closestDistance {
+			closest, closestDistance = name, distance
+		}
+	}
+
+	return closest
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,190 @@
+package schema_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/schema"
+)
+
+var _ = Describe("Schema", func() {
+	Describe("Validate", func() {
+		validate := func(schema *Schema, manifest string) []Problem {
+			problems, err := schema.Validate([]byte(manifest))
+			Expect(err).ToNot(HaveOccurred())
+			return problems
+		}
+
+		It("returns no problems for valid deployment manifest", func() {
+			Expect(validate(DeploymentManifest(), `
+name: dep
+releases:
+- name: rel
+  version: 1.2
+- name: other-rel
+  version: latest
+  url: https://example.com/rel.tgz
+  sha1: abc
+stemcells:
+- alias: default
+  os: ubuntu-jammy
+  version: latest
+update:
+  canaries: 1
+  max_in_flight: 30%
+  canary_watch_time: 1000-30000
+  update_watch_time: 5000
+instance_groups:
+- name: web
+  azs: [z1]
+  instances: ((web_instances))
+  vm_type: default
+  stemcell: default
+  networks:
+  - name: default
+  jobs:
+  - name: nginx
+    release: rel
+    properties:
+      anything: {goes: here}
+  properties:
+variables:
+- name: password
+  type: password
+features:
+  use_dns_addresses: true
+`)).To(BeEmpty())
+		})
+
+		It("reports type mismatches and missing keys as errors with paths and lines", func() {
+			Expect(validate(DeploymentManifest(), `
+name: dep
+instance_groups:
+- name: web
+  azs: z1
+  instances: "2"
+  networks:
+  - static_ips: [10.0.0.1]
+  jobs:
+  - name: nginx
+    release: rel
+- instances: 1
+  jobs: []
+  networks: []
+`)).To(Equal([]Problem{
+				{Level: LevelError, Path: "/instance_groups/name=web/azs", Line: 5, Message: "Expected array but got string"},
+				{Level: LevelError, Path: "/instance_groups/name=web/instances", Line: 6, Message: "Expected integer but got string"},
+				{Level: LevelError, Path: "/instance_groups/name=web/networks/0", Line: 8, Message: "Missing required key 'name'"},
+				{Level: LevelError, Path: "/instance_groups/1", Line: 12, Message: "Missing required key 'name'"},
+			}))
+		})
+
+		It("reports unknown keys as warnings and suggests similar known keys", func() {
+			Expect(validate(DeploymentManifest(), `
+name: dep
+instance_group: []
+stemcells:
+- alias: default
+  version: "1"
+  ostype: ubuntu
+custom: value
+`)).To(Equal([]Problem{
+				{Level: LevelWarning, Path: "/instance_group", Line: 3, Message: "Unknown key 'instance_group' (did you mean 'instance_groups'?)"},
+				{Level: LevelWarning, Path: "/stemcells/alias=default/ostype", Line: 7, Message: "Unknown key 'ostype'"},
+				{Level: LevelWarning, Path: "/custom", Line: 8, Message: "Unknown key 'custom'"},
+			}))
+		})
+
+		It("reports error when required top level key is missing", func() {
+			Expect(validate(DeploymentManifest(), "releases: []")).To(Equal([]Problem{
+				{Level: LevelError, Path: "/", Line: 1, Message: "Missing required key 'name'"},
+			}))
+		})
+
+		It("reports error when manifest is empty", func() {
+			Expect(validate(DeploymentManifest(), "")).To(Equal([]Problem{
+				{Level: LevelError, Path: "/", Message: "Expected non-empty manifest"},
+			}))
+		})
+
+		It("reports error when manifest is not a hash", func() {
+			Expect(validate(CloudConfig(), "- a")).To(Equal([]Problem{
+				{Level: LevelError, Path: "/", Line: 1, Message: "Expected object but got array"},
+			}))
+		})
+
+		It("follows anchors and merge keys", func() {
+			Expect(validate(CloudConfig(), `
+vm_types:
+- &small
+  name: small
+  cloud_properties: {cpu: 1}
+- <<: *small
+  name: large
+  cpus: 2
+disk_types:
+- name: default
+  disk_size: &size large
+`)).To(Equal([]Problem{
+				{Level: LevelWarning, Path: "/vm_types/name=large/cpus", Line: 8, Message: "Unknown key 'cpus'"},
+				{Level: LevelError, Path: "/disk_types/name=default/disk_size", Line: 11, Message: "Expected integer but got string"},
+			}))
+		})
+
+		It("escapes keys and names used in paths", func() {
+			Expect(validate(CPIConfig(), `
+cpis:
+- name: aws/us-east-1
+  type: aws
+  prop~erties: {}
+- name: aws:eu-west-1
+  type: aws
+  "zone:a": z1
+`)).To(Equal([]Problem{
+				{Level: LevelWarning, Path: "/cpis/name=aws~1us-east-1/prop~0erties", Line: 5, Message: "Unknown key 'prop~erties' (did you mean 'properties'?)"},
+				{Level: LevelWarning, Path: "/cpis/name=aws~7eu-west-1/zone~7a", Line: 8, Message: "Unknown key 'zone:a'"},
+			}))
+		})
+
+		It("validates runtime configs", func() {
+			Expect(validate(RuntimeConfig(), `
+addons:
+- name: dns
+  jobs:
+  - name: bosh-dns
+    release: bosh-dns
+  include:
+    stemcell:
+    - os: ubuntu-jammy
+    deployments: dep
+`)).To(Equal([]Problem{
+				{Level: LevelError, Path: "/addons/name=dns/include/deployments", Line: 10, Message: "Expected array but got string"},
+			}))
+		})
+
+		It("returns error when manifest is not valid YAML", func() {
+			_, err := DeploymentManifest().Validate([]byte("name: ["))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Parsing manifest"))
+		})
+	})
+
+	Describe("ForType", func() {
+		It("returns schema for each manifest type", func() {
+			for _, name := range ManifestTypes() {
+				schema, err := ForType(name)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(schema).ToNot(BeNil())
+			}
+
+			Expect(ManifestTypes()).To(Equal([]string{"cloud-config", "cpi-config", "deployment", "runtime-config"}))
+		})
+
+		It("returns error for unknown type", func() {
+			_, err := ForType("unknown")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"Unknown manifest type 'unknown', expected one of [cloud-config cpi-config deployment runtime-config]"))
+		})
+	})
+})
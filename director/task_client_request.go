@@ -111,6 +111,10 @@ func (r TaskClientRequest) WaitForCompletion(id int, type_ string, taskReporter
 		}
 
 		if taskResp.IsRunning() {
+			if stoppable, ok := taskReporter.(StoppableTaskReporter); ok && stoppable.Stopped() {
+				return nil
+			}
+
 			time.Sleep(r.taskCheckStepDuration)
 			continue
 		}
@@ -141,6 +145,12 @@ func (r TaskClientRequest) waitForResult(taskResp taskShortResp) ([]byte, error)
 	return respBody, nil
 }
 
+// StoppableTaskReporter allows to stop following output of a running task
+// without waiting for it to finish; the task itself keeps running
+type StoppableTaskReporter interface {
+	Stopped() bool
+}
+
 type taskReporterWriter struct {
 	id           int
 	totalLen     int
@@ -419,6 +419,28 @@ var _ = Describe("TaskClientRequest", func() {
 			}
 		})
 
+		It("stops following running task once stoppable task reporter is stopped", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/tasks/123"),
+					ghttp.RespondWith(http.StatusOK, `{"id":123, "state":"processing"}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/tasks/123/output", "type=event"),
+					ghttp.RespondWith(http.StatusOK, "chunk1"),
+				),
+			)
+
+			reporter := &stoppableTaskReporter{FakeTaskReporter: taskReporter}
+			taskReporter.TaskOutputChunkStub = func(int, []byte) { reporter.stopped = true }
+
+			Expect(req.WaitForCompletion(123, "event", reporter)).ToNot(HaveOccurred())
+
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+			Expect(taskReporter.TaskOutputChunkCallCount()).To(Equal(1))
+			Expect(taskReporter.TaskFinishedCallCount()).To(Equal(1))
+		})
+
 		It("returns an error if getting task state fails", func() {
 			server.AppendHandlers(
 				ghttp.CombineHandlers(
@@ -456,3 +478,10 @@ var _ = Describe("TaskClientRequest", func() {
 		})
 	})
 })
+
+type stoppableTaskReporter struct {
+	*fakedir.FakeTaskReporter
+	stopped bool
+}
+
+func (r *stoppableTaskReporter) Stopped() bool { return r.stopped }
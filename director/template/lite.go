@@ -0,0 +1,477 @@
+package template
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	TemplateEngineLite = "lite"
+
+	liteInclude = "$include"
+	liteFor     = "$for"
+	liteIn      = "$in"
+	liteDo      = "$do"
+	liteIf      = "$if"
+	liteThen    = "$then"
+	liteElse    = "$else"
+)
+
+var liteDirectiveKeys = map[string][]string{
+	liteInclude: {liteInclude},
+	liteFor:     {liteFor, liteIn, liteDo},
+	liteIf:      {liteIf, liteThen, liteElse},
+}
+
+// LiteEngine evaluates directives that let manifests include other files,
+// repeat a node for each item of a list variable and keep a node only
+// when a variable is present:
+//
+//	instance_groups:
+//	- $include: path/relative/to/file.yml
+//	- $for: az
+//	  $in: azs
+//	  $do: {name: web-((az.name))}
+//	- $if: tls
+//	  $then: {...}
+//	  $else: {...}
+//
+// Loop variables are substituted while evaluating; all other variables
+// are left for regular interpolation so that result is a plain manifest.
+type LiteEngine struct {
+	fs boshsys.FileSystem
+}
+
+type liteEvaluator struct {
+	fs       boshsys.FileSystem
+	vars     Variables
+	includes []string
+}
+
+type liteScope map[string]interface{}
+
+func NewLiteEngine(fs boshsys.FileSystem) LiteEngine {
+	return LiteEngine{fs: fs}
+}
+
+// Evaluate resolves includes relative to dir
+func (e LiteEngine) Evaluate(bytes []byte, dir string, vars Variables) ([]byte, error) {
+	obj, err := e.unmarshal(bytes)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshaling template")
+	}
+
+	evaluator := &liteEvaluator{fs: e.fs, vars: vars}
+
+	result, found, _, err := evaluator.node(obj, liteScope{}, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return []byte{}, nil
+	}
+
+	bytes, err = yaml.Marshal(result)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshaling template")
+	}
+
+	return bytes, nil
+}
+
+// unmarshal keeps order of hash keys so that result stays close to the original template
+func (e LiteEngine) unmarshal(bytes []byte) (interface{}, error) {
+	var obj interface{}
+
+	err := yaml.Unmarshal(bytes, &obj)
+	if err != nil {
+		return nil, err
+	}
+
+	switch obj.(type) {
+	case map[interface{}]interface{}:
+		var hash yaml.MapSlice
+
+		err = yaml.Unmarshal(bytes, &hash)
+
+		return hash, err
+
+	case []interface{}:
+		var hashes []yaml.MapSlice
+
+		if yaml.Unmarshal(bytes, &hashes) == nil {
+			list := make([]interface{}, len(hashes))
+			for i, item := range hashes {
+				list[i] = item
+			}
+			return list, nil
+		}
+	}
+
+	return obj, nil
+}
+
+// node returns false if node should be omitted and true
+// for directive results that should be spliced into a list
+func (e *liteEvaluator) node(node interface{}, scope liteScope, dir string) (interface{}, bool, bool, error) {
+	switch typedNode := node.(type) {
+	case yaml.MapSlice:
+		directive, err := e.directive(typedNode)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		if len(directive) > 0 {
+			return e.evalDirective(directive, typedNode, scope, dir)
+		}
+
+		result := yaml.MapSlice{}
+
+		for _, item := range typedNode {
+			key, err := e.substitute(item.Key, scope)
+			if err != nil {
+				return nil, false, false, err
+			}
+
+			val, found, _, err := e.node(item.Value, scope, dir)
+			if err != nil {
+				return nil, false, false, err
+			}
+
+			if found {
+				result = append(result, yaml.MapItem{Key: key, Value: val})
+			}
+		}
+
+		return result, true, false, nil
+
+	case map[interface{}]interface{}:
+		hash := yaml.MapSlice{}
+		for k, v := range typedNode {
+			hash = append(hash, yaml.MapItem{Key: k, Value: v})
+		}
+
+		sort.Slice(hash, func(i, j int) bool {
+			return fmt.Sprintf("%v", hash[i].Key) < fmt.Sprintf("%v", hash[j].Key)
+		})
+
+		return e.node(hash, scope, dir)
+
+	case []interface{}:
+		result, err := e.items(typedNode, scope, dir)
+		return result, true, false, err
+
+	default:
+		val, err := e.substitute(node, scope)
+		return val, true, false, err
+	}
+}
+
+func (e *liteEvaluator) items(list []interface{}, scope liteScope, dir string) ([]interface{}, error) {
+	result := []interface{}{}
+
+	for _, item := range list {
+		val, found, splice, err := e.node(item, scope, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			continue
+		}
+
+		if splice {
+			result = append(result, val.([]interface{})...)
+		} else {
+			result = append(result, val)
+		}
+	}
+
+	return result, nil
+}
+
+func (e *liteEvaluator) directive(hash yaml.MapSlice) (string, error) {
+	var directive string
+
+	for _, item := range hash {
+		key, ok := item.Key.(string)
+		if !ok {
+			continue
+		}
+
+		if _, found := liteDirectiveKeys[key]; found {
+			if len(directive) > 0 {
+				return "", bosherr.Errorf("Expected only one of '%s' or '%s' in the same hash", directive, key)
+			}
+			directive = key
+		}
+	}
+
+	if len(directive) == 0 {
+		return "", nil
+	}
+
+	allowed := liteDirectiveKeys[directive]
+
+	for _, item := range hash {
+		if !e.contains(allowed, item.Key) {
+			return "", bosherr.Errorf("Expected directive '%s' to only be used with keys '%s' but found '%v'",
+				directive, strings.Join(allowed, "', '"), item.Key)
+		}
+	}
+
+	return directive, nil
+}
+
+func (e *liteEvaluator) evalDirective(directive string, hash yaml.MapSlice, scope liteScope, dir string) (interface{}, bool, bool, error) {
+	args := map[string]interface{}{}
+	for _, item := range hash {
+		args[item.Key.(string)] = item.Value
+	}
+
+	switch directive {
+	case liteInclude:
+		return e.include(args[liteInclude], scope, dir)
+
+	case liteFor:
+		return e.loop(args, scope, dir)
+
+	default:
+		name, ok := args[liteIf].(string)
+		if !ok {
+			return nil, false, false, bosherr.Errorf("Expected '%s' to be a variable name", liteIf)
+		}
+
+		_, found, err := e.lookup(name, scope)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		branch := liteElse
+		if found {
+			branch = liteThen
+		}
+
+		val, present := args[branch]
+		if !present {
+			return nil, false, false, nil
+		}
+
+		val, found, _, err = e.node(val, scope, dir)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		_, isList := val.([]interface{})
+
+		return val, found, isList, nil
+	}
+}
+
+func (e *liteEvaluator) include(pathArg interface{}, scope liteScope, dir string) (interface{}, bool, bool, error) {
+	path, ok := pathArg.(string)
+	if !ok || len(path) == 0 {
+		return nil, false, false, bosherr.Errorf("Expected '%s' to be a file path", liteInclude)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	for _, includedPath := range e.includes {
+		if includedPath == path {
+			return nil, false, false, bosherr.Errorf("Expected '%s' to not include itself", path)
+		}
+	}
+
+	bytes, err := e.fs.ReadFile(path)
+	if err != nil {
+		return nil, false, false, bosherr.WrapErrorf(err, "Including '%s'", path)
+	}
+
+	obj, err := LiteEngine{}.unmarshal(bytes)
+	if err != nil {
+		return nil, false, false, bosherr.WrapErrorf(err, "Unmarshaling '%s'", path)
+	}
+
+	e.includes = append(e.includes, path)
+	defer func() { e.includes = e.includes[:len(e.includes)-1] }()
+
+	val, found, _, err := e.node(obj, scope, filepath.Dir(path))
+	if err != nil {
+		return nil, false, false, bosherr.WrapErrorf(err, "Including '%s'", path)
+	}
+
+	_, isList := val.([]interface{})
+
+	return val, found, isList, nil
+}
+
+func (e *liteEvaluator) loop(args map[string]interface{}, scope liteScope, dir string) (interface{}, bool, bool, error) {
+	name, ok := args[liteFor].(string)
+	if !ok || len(name) == 0 {
+		return nil, false, false, bosherr.Errorf("Expected '%s' to be a loop variable name", liteFor)
+	}
+
+	body, found := args[liteDo]
+	if !found {
+		return nil, false, false, bosherr.Errorf("Expected '%s' for loop over '%s'", liteDo, name)
+	}
+
+	var list []interface{}
+
+	switch typedIn := args[liteIn].(type) {
+	case string:
+		val, found, err := e.lookup(typedIn, scope)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		if !found {
+			return nil, false, false, bosherr.Errorf("Expected to find variable '%s' to loop over", typedIn)
+		}
+
+		list, ok = val.([]interface{})
+		if !ok {
+			return nil, false, false, bosherr.Errorf("Expected variable '%s' to be a list", typedIn)
+		}
+
+	case []interface{}:
+		list = typedIn
+
+	default:
+		return nil, false, false, bosherr.Errorf("Expected '%s' to be a variable name or a list", liteIn)
+	}
+
+	result := []interface{}{}
+
+	for _, item := range list {
+		itemScope := liteScope{}
+		for k, v := range scope {
+			itemScope[k] = v
+		}
+		itemScope[name] = item
+
+		items, err := e.items([]interface{}{body}, itemScope, dir)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		result = append(result, items...)
+	}
+
+	return result, true, true, nil
+}
+
+// lookup resolves names such as 'az.name' against loop variables and then template variables
+func (e *liteEvaluator) lookup(name string, scope liteScope) (interface{}, bool, error) {
+	pieces := strings.Split(name, ".")
+
+	val, found := scope[pieces[0]]
+	if !found {
+		var err error
+
+		val, found, err = e.vars.Get(VariableDefinition{Name: pieces[0]})
+		if err != nil || !found {
+			return nil, false, err
+		}
+	}
+
+	for _, key := range pieces[1:] {
+		switch typedVal := val.(type) {
+		case map[interface{}]interface{}:
+			val, found = typedVal[key]
+		case yaml.MapSlice:
+			found = false
+			for _, item := range typedVal {
+				if item.Key == key {
+					val, found = item.Value, true
+				}
+			}
+		default:
+			found = false
+		}
+
+		if !found {
+			return nil, false, nil
+		}
+	}
+
+	return val, true, nil
+}
+
+// substitute replaces references to loop variables in strings; other variables are kept
+func (e *liteEvaluator) substitute(node interface{}, scope liteScope) (interface{}, error) {
+	str, ok := node.(string)
+	if !ok || len(scope) == 0 {
+		return node, nil
+	}
+
+	var errs []error
+
+	if interpolationAnchoredRegex.MatchString(str) {
+		name := interpolationRegex.FindStringSubmatch(str)[1]
+
+		if _, inScope := scope[strings.Split(name, ".")[0]]; inScope {
+			val, found, err := e.lookup(name, scope)
+			if err != nil {
+				return nil, err
+			}
+
+			if !found {
+				return nil, bosherr.Errorf("Expected to find loop variable '%s'", name)
+			}
+
+			return val, nil
+		}
+	}
+
+	result := interpolationRegex.ReplaceAllStringFunc(str, func(match string) string {
+		name := interpolationRegex.FindStringSubmatch(match)[1]
+
+		if _, inScope := scope[strings.Split(name, ".")[0]]; !inScope {
+			return match
+		}
+
+		val, found, err := e.lookup(name, scope)
+		if err != nil {
+			errs = append(errs, err)
+			return match
+		}
+
+		switch val.(type) {
+		case string, int, int64, uint64, float64, bool:
+		default:
+			found = false
+		}
+
+		if !found {
+			errs = append(errs, bosherr.Errorf("Expected to find loop variable '%s' with a value that can be used within a string", name))
+			return match
+		}
+
+		return fmt.Sprintf("%v", val)
+	})
+
+	if len(errs) > 0 {
+		return nil, bosherr.NewMultiError(errs...)
+	}
+
+	return result, nil
+}
+
+func (e *liteEvaluator) contains(keys []string, key interface{}) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
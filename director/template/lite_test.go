@@ -0,0 +1,156 @@
+package template_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cppforlife/go-patch/patch"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+var _ = Describe("LiteEngine", func() {
+	// Each directory in testdata/lite contains template.yml, optional vars.yml
+	// and either expected.yml or expected-error.txt with a part of the error message.
+	// Set UPDATE_GOLDEN=true to regenerate expected.yml files.
+	Describe("golden files", func() {
+		goldenDir := filepath.Join("testdata", "lite")
+
+		entries, err := os.ReadDir(goldenDir)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, entry := range entries {
+			caseDir, err := filepath.Abs(filepath.Join(goldenDir, entry.Name()))
+			if err != nil {
+				panic(err)
+			}
+
+			It("evaluates "+entry.Name(), func() {
+				engine := NewLiteEngine(boshsys.NewOsFileSystem(boshlog.NewLogger(boshlog.LevelNone)))
+
+				tplBytes, err := os.ReadFile(filepath.Join(caseDir, "template.yml"))
+				Expect(err).ToNot(HaveOccurred())
+
+				vars := StaticVariables{}
+
+				varsBytes, err := os.ReadFile(filepath.Join(caseDir, "vars.yml"))
+				if err == nil {
+					Expect(yaml.Unmarshal(varsBytes, &vars)).To(Succeed())
+				}
+
+				result, err := engine.Evaluate(tplBytes, caseDir, vars)
+
+				errBytes, readErr := os.ReadFile(filepath.Join(caseDir, "expected-error.txt"))
+				if readErr == nil {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring(strings.TrimSpace(string(errBytes))))
+					return
+				}
+
+				Expect(err).ToNot(HaveOccurred())
+
+				expectedPath := filepath.Join(caseDir, "expected.yml")
+
+				if os.Getenv("UPDATE_GOLDEN") == "true" {
+					Expect(os.WriteFile(expectedPath, result, 0644)).To(Succeed())
+				}
+
+				expected, err := os.ReadFile(expectedPath)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(result)).To(Equal(string(expected)))
+
+				// Result is a plain manifest that can be interpolated as usual
+				_, err = NewTemplate(result).Evaluate(vars, patch.Ops{}, EvaluateOpts{})
+				Expect(err).ToNot(HaveOccurred())
+			})
+		}
+	})
+
+	Describe("Evaluate", func() {
+		var (
+			fs     *fakesys.FakeFileSystem
+			engine LiteEngine
+		)
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+			engine = NewLiteEngine(fs)
+		})
+
+		It("returns plain template unchanged apart from formatting", func() {
+			result, err := engine.Evaluate([]byte("name: dep\nb: ((b))\na: [1, 2]\n"), "/dir", StaticVariables{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(result)).To(Equal("name: dep\nb: ((b))\na:\n- 1\n- 2\n"))
+		})
+
+		It("replaces whole values of loop variables keeping their type", func() {
+			result, err := engine.Evaluate([]byte(`
+list:
+- $for: item
+  $in: items
+  $do: ((item))
+`), "/dir", StaticVariables{"items": []interface{}{1, map[interface{}]interface{}{"a": "b"}}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(result)).To(Equal("list:\n- 1\n- a: b\n"))
+		})
+
+		It("returns error when loop variable cannot be used within a string", func() {
+			_, err := engine.Evaluate([]byte(`
+list:
+- $for: item
+  $in: items
+  $do: name-((item))
+`), "/dir", StaticVariables{"items": []interface{}{map[interface{}]interface{}{"a": "b"}}})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected to find loop variable 'item' with a value that can be used within a string"))
+		})
+
+		It("returns error when list variable is not found", func() {
+			_, err := engine.Evaluate([]byte("list:\n- {$for: item, $in: items, $do: a}\n"), "/dir", StaticVariables{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected to find variable 'items' to loop over"))
+		})
+
+		It("returns error when variables fail", func() {
+			vars := &FakeVariables{GetErr: os.ErrPermission}
+
+			_, err := engine.Evaluate([]byte("a: {$if: enabled, $then: b}\n"), "/dir", vars)
+			Expect(err).To(Equal(os.ErrPermission))
+		})
+
+		It("returns error when multiple directives are used in the same hash", func() {
+			_, err := engine.Evaluate([]byte("a: {$if: enabled, $include: file.yml}\n"), "/dir", StaticVariables{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp(`Expected only one of '\$(if|include)' or '\$(if|include)' in the same hash`))
+		})
+
+		It("returns error when included file cannot be read", func() {
+			_, err := engine.Evaluate([]byte("a: {$include: file.yml}\n"), "/dir", StaticVariables{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Including '/dir/file.yml'"))
+		})
+
+		It("includes files relative to given directory", func() {
+			Expect(fs.WriteFileString("/dir/file.yml", "b: c\n")).To(Succeed())
+
+			result, err := engine.Evaluate([]byte("a: {$include: file.yml}\n"), "/dir", StaticVariables{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(result)).To(Equal("a:\n  b: c\n"))
+		})
+
+		It("returns error when template cannot be parsed", func() {
+			_, err := engine.Evaluate([]byte("a: ["), "/dir", StaticVariables{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unmarshaling template"))
+		})
+	})
+})
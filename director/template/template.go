@@ -92,6 +92,12 @@ var (
 	interpolationAnchoredRegex = regexp.MustCompile("\\A" + interpolationRegex.String() + "\\z")
 )
 
+// IsVariable returns true if the whole string refers to a variable (e.g. '((password))')
+// which is resolved by the director instead of being a literal value
+func IsVariable(str string) bool {
+	return interpolationAnchoredRegex.MatchString(str)
+}
+
 func (i interpolator) Interpolate(node interface{}, varsLookup varsLookup) (interface{}, error) {
 	switch typedNode := node.(type) {
 	case map[interface{}]interface{}:
@@ -0,0 +1,308 @@
+package template
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/cppforlife/go-patch/patch"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// OpStatusApplied is used for ops that changed the template
+	OpStatusApplied = "applied"
+	// OpStatusUnchanged is used for ops that did not change the template (e.g. test ops or same values)
+	OpStatusUnchanged = "unchanged"
+	// OpStatusSkipped is used for ops with optional paths ('?') that were not found
+	OpStatusSkipped = "skipped"
+)
+
+// TracedOp is an op together with its origin
+type TracedOp struct {
+	Op patch.Op
+
+	File  string
+	Index int
+	Type  string
+	Path  string
+}
+
+type OpTrace struct {
+	TracedOp
+
+	// Matched is true if op path was found before the op was applied
+	Matched bool
+	Status  string
+
+	// Values at op path before variables are interpolated
+	Before      interface{}
+	BeforeFound bool
+	After       interface{}
+	AfterFound  bool
+}
+
+type Blame struct {
+	// Op is nil if value comes from the template itself
+	Op *OpTrace
+
+	// Found is false if path does not exist or is within a variable value
+	Found bool
+	Value interface{}
+
+	// Vars lists variables used within the value or containing the value
+	Vars []string
+}
+
+// TraceOps applies ops one by one and records how each of them changed the template.
+// Traces of ops applied before a failing op are returned together with the error.
+func (t Template) TraceOps(ops []TracedOp) ([]OpTrace, error) {
+	var traces []OpTrace
+
+	_, err := t.applyTraced(ops, func(trace OpTrace, _ interface{}) {
+		traces = append(traces, trace)
+	})
+
+	return traces, err
+}
+
+// Blame finds the last op that changed value at the path and variables used by that value
+func (t Template) Blame(ops []TracedOp, path patch.Pointer) (Blame, error) {
+	var blame Blame
+
+	var lastVal []byte
+
+	obj, err := t.applyTraced(ops, func(trace OpTrace, obj interface{}) {
+		val, found := lookup(obj, path)
+
+		var valBytes []byte
+		if found {
+			valBytes = snapshot(val)
+		}
+
+		if trace.Status == OpStatusApplied && !bytes.Equal(valBytes, lastVal) {
+			blameTrace := trace
+			blame.Op = &blameTrace
+		}
+
+		lastVal = valBytes
+	}, func(obj interface{}) {
+		if val, found := lookup(obj, path); found {
+			lastVal = snapshot(val)
+		}
+	})
+	if err != nil {
+		return Blame{}, err
+	}
+
+	tokens := path.Tokens()
+
+	// Value might be part of a variable value (e.g. '/tls/ca' within '((tls))')
+	for i := len(tokens); i > 0; i-- {
+		val, found := lookup(obj, patch.NewPointer(tokens[:i]))
+		if !found {
+			continue
+		}
+
+		if i == len(tokens) {
+			blame.Found = true
+			blame.Value = val
+			blame.Vars = varNames(val)
+		} else if str, ok := val.(string); ok && interpolationAnchoredRegex.MatchString(str) {
+			blame.Vars = varNames(val)
+		}
+
+		break
+	}
+
+	return blame, nil
+}
+
+func (t Template) applyTraced(ops []TracedOp, fn func(OpTrace, interface{}), initFns ...func(interface{})) (interface{}, error) {
+	var obj interface{}
+
+	err := yaml.Unmarshal(t.bytes, &obj)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, initFn := range initFns {
+		initFn(obj)
+	}
+
+	for _, op := range ops {
+		trace := OpTrace{TracedOp: op}
+
+		pointer, pointerErr := patch.NewPointerFromString(op.Path)
+
+		if pointerErr == nil {
+			trace.Before, trace.BeforeFound = lookup(obj, pointer)
+			trace.Before = copyValue(trace.Before)
+		}
+
+		trace.Matched = trace.BeforeFound
+
+		before := snapshot(obj)
+
+		obj, err = op.Op.Apply(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		if pointerErr == nil {
+			trace.After, trace.AfterFound = lookup(obj, pointer)
+			trace.After = copyValue(trace.After)
+		}
+
+		switch {
+		case !bytes.Equal(before, snapshot(obj)):
+			trace.Status = OpStatusApplied
+		case !trace.Matched && pointerErr == nil && isOptional(pointer):
+			trace.Status = OpStatusSkipped
+		default:
+			trace.Status = OpStatusUnchanged
+		}
+
+		fn(trace, obj)
+	}
+
+	return obj, nil
+}
+
+// lookup resolves path without creating or skipping optional parts;
+// appending ('-') resolves to the last item so that added values could be shown
+func lookup(obj interface{}, pointer patch.Pointer) (interface{}, bool) {
+	for _, token := range pointer.Tokens() {
+		switch typedToken := token.(type) {
+		case patch.RootToken:
+			continue
+
+		case patch.KeyToken:
+			hash, ok := obj.(map[interface{}]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			obj, ok = hash[typedToken.Key]
+			if !ok {
+				return nil, false
+			}
+
+		case patch.IndexToken:
+			array, ok := obj.([]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			idx, err := patch.ArrayIndex{Index: typedToken.Index, Modifiers: typedToken.Modifiers, Array: array}.Concrete()
+			if err != nil {
+				return nil, false
+			}
+
+			obj = array[idx]
+
+		case patch.AfterLastIndexToken:
+			array, ok := obj.([]interface{})
+			if !ok || len(array) == 0 {
+				return nil, false
+			}
+
+			obj = array[len(array)-1]
+
+		case patch.MatchingIndexToken:
+			array, ok := obj.([]interface{})
+			if !ok || len(typedToken.Modifiers) > 0 {
+				return nil, false
+			}
+
+			var matches []interface{}
+
+			for _, item := range array {
+				if hash, ok := item.(map[interface{}]interface{}); ok && hash[typedToken.Key] == typedToken.Value {
+					matches = append(matches, item)
+				}
+			}
+
+			if len(matches) != 1 {
+				return nil, false
+			}
+
+			obj = matches[0]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return obj, true
+}
+
+func isOptional(pointer patch.Pointer) bool {
+	for _, token := range pointer.Tokens() {
+		switch typedToken := token.(type) {
+		case patch.KeyToken:
+			if typedToken.Optional {
+				return true
+			}
+		case patch.MatchingIndexToken:
+			if typedToken.Optional {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func snapshot(obj interface{}) []byte {
+	bytes, _ := yaml.Marshal(obj)
+	return bytes
+}
+
+// copyValue protects recorded values from being changed by subsequent ops
+func copyValue(val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+
+	var copied interface{}
+
+	_ = yaml.Unmarshal(snapshot(val), &copied)
+
+	return copied
+}
+
+func varNames(val interface{}) []string {
+	found := map[string]struct{}{}
+
+	var walk func(interface{})
+
+	walk = func(val interface{}) {
+		switch typedVal := val.(type) {
+		case map[interface{}]interface{}:
+			for k, v := range typedVal {
+				walk(k)
+				walk(v)
+			}
+		case []interface{}:
+			for _, v := range typedVal {
+				walk(v)
+			}
+		case string:
+			for _, match := range interpolationRegex.FindAllStringSubmatch(typedVal, -1) {
+				found[match[1]] = struct{}{}
+			}
+		}
+	}
+
+	walk(val)
+
+	var names []string
+
+	for name := range found {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
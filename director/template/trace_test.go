@@ -0,0 +1,189 @@
+package template_test
+
+import (
+	"github.com/cppforlife/go-patch/patch"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+func tracedOps(file, opsYAML string) []TracedOp {
+	var opDefs []patch.OpDefinition
+
+	err := yaml.Unmarshal([]byte(opsYAML), &opDefs)
+	Expect(err).ToNot(HaveOccurred())
+
+	ops, err := patch.NewOpsFromDefinitions(opDefs)
+	Expect(err).ToNot(HaveOccurred())
+
+	var result []TracedOp
+
+	for i, op := range ops {
+		result = append(result, TracedOp{Op: op, File: file, Index: i, Type: opDefs[i].Type, Path: *opDefs[i].Path})
+	}
+
+	return result
+}
+
+var _ = Describe("Template", func() {
+	tpl := NewTemplate([]byte(`
+name: dep
+instance_groups:
+- name: web
+  instances: 1
+  jobs: []
+`))
+
+	Describe("TraceOps", func() {
+		It("records values before and after each op", func() {
+			ops := tracedOps("ops.yml", `
+- type: replace
+  path: /instance_groups/name=web/instances
+  value: ((web_instances))
+- type: replace
+  path: /instance_groups/name=web/jobs/-
+  value: {name: nginx}
+- type: replace
+  path: /name
+  value: dep
+- type: remove
+  path: /instance_groups/name=worker?
+- type: replace
+  path: /update?/canaries
+  value: 2
+- type: remove
+  path: /name
+`)
+
+			traces, err := tpl.TraceOps(ops)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(traces).To(HaveLen(6))
+
+			Expect(traces[0]).To(Equal(OpTrace{
+				TracedOp:    ops[0],
+				Matched:     true,
+				Status:      OpStatusApplied,
+				Before:      1,
+				BeforeFound: true,
+				After:       "((web_instances))",
+				AfterFound:  true,
+			}))
+
+			Expect(traces[1].Matched).To(BeFalse())
+			Expect(traces[1].Status).To(Equal(OpStatusApplied))
+			Expect(traces[1].BeforeFound).To(BeFalse())
+			Expect(traces[1].After).To(Equal(map[interface{}]interface{}{"name": "nginx"}))
+
+			Expect(traces[2].Matched).To(BeTrue())
+			Expect(traces[2].Status).To(Equal(OpStatusUnchanged))
+
+			Expect(traces[3].Matched).To(BeFalse())
+			Expect(traces[3].Status).To(Equal(OpStatusSkipped))
+
+			Expect(traces[4].Matched).To(BeFalse())
+			Expect(traces[4].Status).To(Equal(OpStatusApplied))
+			Expect(traces[4].After).To(Equal(2))
+
+			Expect(traces[5].Status).To(Equal(OpStatusApplied))
+			Expect(traces[5].Before).To(Equal("dep"))
+			Expect(traces[5].AfterFound).To(BeFalse())
+		})
+
+		It("does not let later ops change recorded values", func() {
+			ops := tracedOps("ops.yml", `
+- type: replace
+  path: /instance_groups/name=web/jobs
+  value: []
+- type: replace
+  path: /instance_groups/name=web/jobs/-
+  value: {name: nginx}
+`)
+
+			traces, err := tpl.TraceOps(ops)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(traces[0].After).To(Equal([]interface{}{}))
+		})
+
+		It("returns traces of ops applied before failing op", func() {
+			ops := tracedOps("ops.yml", `
+- type: replace
+  path: /name
+  value: new
+- type: replace
+  path: /missing/key
+  value: 1
+`)
+
+			traces, err := tpl.TraceOps(ops)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("missing"))
+			Expect(traces).To(HaveLen(1))
+		})
+	})
+
+	Describe("Blame", func() {
+		It("returns last op that changed the value and variables it uses", func() {
+			ops := append(tracedOps("ops1.yml", `
+- type: replace
+  path: /instance_groups/name=web/instances
+  value: 2
+- type: replace
+  path: /name
+  value: other
+`), tracedOps("ops2.yml", `
+- type: replace
+  path: /instance_groups/0/instances
+  value: ((count))-((suffix))
+- type: replace
+  path: /instance_groups/name=web/instances
+  value: ((count))-((suffix))
+`)...)
+
+			blame, err := tpl.Blame(ops, patch.MustNewPointerFromString("/instance_groups/name=web/instances"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blame.Op).ToNot(BeNil())
+			Expect(blame.Op.File).To(Equal("ops2.yml"))
+			Expect(blame.Op.Index).To(Equal(0))
+			Expect(blame.Found).To(BeTrue())
+			Expect(blame.Value).To(Equal("((count))-((suffix))"))
+			Expect(blame.Vars).To(Equal([]string{"count", "suffix"}))
+		})
+
+		It("returns no op if value comes from the template", func() {
+			blame, err := tpl.Blame(nil, patch.MustNewPointerFromString("/name"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blame.Op).To(BeNil())
+			Expect(blame.Found).To(BeTrue())
+			Expect(blame.Value).To(Equal("dep"))
+			Expect(blame.Vars).To(BeEmpty())
+		})
+
+		It("returns variable that contains the path", func() {
+			ops := tracedOps("ops.yml", `
+- type: replace
+  path: /tls?
+  value: ((tls))
+`)
+
+			blame, err := tpl.Blame(ops, patch.MustNewPointerFromString("/tls/ca"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blame.Op).To(BeNil())
+			Expect(blame.Found).To(BeFalse())
+			Expect(blame.Vars).To(Equal([]string{"tls"}))
+		})
+
+		It("returns op that removed the value", func() {
+			ops := tracedOps("ops.yml", `
+- type: remove
+  path: /name
+`)
+
+			blame, err := tpl.Blame(ops, patch.MustNewPointerFromString("/name"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blame.Op.Type).To(Equal("remove"))
+			Expect(blame.Found).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package director
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TracingClient writes one JSON line per request attempt
+// so that timings could be shared without leaking credentials.
+type TracingClient struct {
+	client AdjustedClient
+	writer io.Writer
+	now    func() time.Time
+
+	writerLock *sync.Mutex
+}
+
+type HTTPTraceEntry struct {
+	Time      time.Time `json:"time"`
+	ContextID string    `json:"context_id,omitempty"`
+
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeaders http.Header `json:"request_headers,omitempty"`
+
+	StatusCode      int         `json:"status_code,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+
+	// Time until response headers were received
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+func NewTracingClient(client AdjustedClient, writer io.Writer, now func() time.Time) TracingClient {
+	if now == nil {
+		now = time.Now
+	}
+
+	return TracingClient{
+		client: client,
+		writer: writer,
+		now:    now,
+
+		writerLock: &sync.Mutex{},
+	}
+}
+
+func (c TracingClient) Do(req *http.Request) (*http.Response, error) {
+	startedAt := c.now()
+
+	entry := HTTPTraceEntry{
+		Time:      startedAt.UTC(),
+		ContextID: req.Header.Get("X-Bosh-Context-Id"),
+
+		Method:         req.Method,
+		URL:            c.sanitizedURL(req),
+		RequestHeaders: c.sanitizedRequestHeaders(req),
+	}
+
+	resp, err := c.client.Do(req)
+
+	entry.DurationMS = c.now().Sub(startedAt).Milliseconds()
+
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.StatusCode = resp.StatusCode
+		entry.ResponseHeaders = c.sanitizedResponseHeaders(resp)
+	}
+
+	c.write(entry)
+
+	return resp, err
+}
+
+func (c TracingClient) write(entry HTTPTraceEntry) {
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	c.writerLock.Lock()
+	defer c.writerLock.Unlock()
+
+	// Tracing is best effort and must not affect requests
+	_, _ = c.writer.Write(append(bytes, '\n'))
+}
+
+func (c TracingClient) sanitizedURL(req *http.Request) string {
+	if req.URL == nil {
+		return ""
+	}
+
+	url := *req.URL
+	url.User = nil
+
+	return url.String()
+}
+
+func (c TracingClient) sanitizedRequestHeaders(req *http.Request) http.Header {
+	// Clone headers since sanitizer modifies them in place
+	sanitizedReq, _ := RequestSanitizer{Request: http.Request{Header: req.Header.Clone()}}.SanitizeRequest()
+
+	return sanitizedReq.Header
+}
+
+func (c TracingClient) sanitizedResponseHeaders(resp *http.Response) http.Header {
+	headers := resp.Header.Clone()
+
+	if headers != nil && headers.Get("Set-Cookie") != "" {
+		headers.Set("Set-Cookie", "[removed]")
+	}
+
+	return headers
+}
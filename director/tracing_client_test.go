@@ -0,0 +1,115 @@
+package director_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+)
+
+var _ = Describe("TracingClient", func() {
+	var (
+		innerClient *fakedir.FakeAdjustedClient
+		output      *bytes.Buffer
+		client      TracingClient
+		req         *http.Request
+	)
+
+	BeforeEach(func() {
+		innerClient = &fakedir.FakeAdjustedClient{}
+		output = &bytes.Buffer{}
+
+		times := []time.Time{
+			time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 1, 1, 0, 0, 1, 500000000, time.UTC),
+		}
+		now := func() time.Time {
+			t := times[0]
+			times = times[1:]
+			return t
+		}
+
+		client = NewTracingClient(innerClient, output, now)
+
+		req = &http.Request{
+			Method: "GET",
+			URL:    &url.URL{Scheme: "https", User: url.UserPassword("user", "pass"), Host: "director", Path: "/tasks", RawQuery: "state=processing"},
+			Header: http.Header{
+				"Authorization":     []string{"bearer secret"},
+				"X-Bosh-Context-Id": []string{"ctx-id"},
+			},
+		}
+	})
+
+	readEntries := func() []HTTPTraceEntry {
+		var entries []HTTPTraceEntry
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			var entry HTTPTraceEntry
+			Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	It("writes sanitized request and response timing as a JSON line", func() {
+		innerClient.DoReturns(&http.Response{
+			StatusCode: 200,
+			Header:     http.Header{"Content-Type": []string{"application/json"}, "Set-Cookie": []string{"session=secret"}},
+		}, nil)
+
+		resp, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(200))
+
+		Expect(readEntries()).To(Equal([]HTTPTraceEntry{{
+			Time:      time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			ContextID: "ctx-id",
+
+			Method: "GET",
+			URL:    "https://director/tasks?state=processing",
+			RequestHeaders: http.Header{
+				"Authorization":     []string{"[removed]"},
+				"X-Bosh-Context-Id": []string{"ctx-id"},
+			},
+
+			StatusCode: 200,
+			ResponseHeaders: http.Header{
+				"Content-Type": []string{"application/json"},
+				"Set-Cookie":   []string{"[removed]"},
+			},
+
+			DurationMS: 1500,
+		}}))
+	})
+
+	It("does not modify original request headers", func() {
+		innerClient.DoReturns(&http.Response{StatusCode: 200}, nil)
+
+		_, err := client.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(req.Header.Get("Authorization")).To(Equal("bearer secret"))
+		Expect(innerClient.DoArgsForCall(0).Header.Get("Authorization")).To(Equal("bearer secret"))
+	})
+
+	It("records request errors", func() {
+		innerClient.DoReturns(nil, errors.New("fake-err"))
+
+		_, err := client.Do(req)
+		Expect(err).To(MatchError("fake-err"))
+
+		entries := readEntries()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Error).To(Equal("fake-err"))
+		Expect(entries[0].StatusCode).To(Equal(0))
+	})
+})
@@ -97,6 +97,7 @@ var _ = Describe("bosh", func() {
 			fakeDigestCalculator          *fakebicrypto.FakeDigestCalculator
 			legacyDeploymentStateMigrator biconfig.LegacyDeploymentStateMigrator
 			deploymentStateService        biconfig.DeploymentStateService
+			dryRun                        bool
 			vmRepo                        biconfig.VMRepo
 			diskRepo                      biconfig.DiskRepo
 			stemcellRepo                  biconfig.StemcellRepo
@@ -401,6 +402,9 @@ cloud_provider:
 			doGet := func(deploymentManifestPath string, statePath string, deploymentVars boshtpl.Variables, deploymentOp patch.Op) cmd.DeploymentPreparer {
 				// todo: figure this out?
 				deploymentStateService = biconfig.NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, biconfig.DeploymentStatePath(deploymentManifestPath, statePath))
+				if dryRun {
+					deploymentStateService = biconfig.NewDryRunDeploymentStateService(fs, fakeUUIDGenerator, logger, biconfig.DeploymentStatePath(deploymentManifestPath, statePath))
+				}
 				vmRepo = biconfig.NewVMRepo(deploymentStateService)
 				diskRepo = biconfig.NewDiskRepo(deploymentStateService, fakeRepoUUIDGenerator)
 				stemcellRepo = biconfig.NewStemcellRepo(deploymentStateService, fakeRepoUUIDGenerator)
@@ -766,6 +770,8 @@ cloud_provider:
 		}
 
 		BeforeEach(func() {
+			dryRun = false
+
 			fs = fakesys.NewFakeFileSystem()
 			fs.EnableStrictTempRootBehavior()
 
@@ -933,6 +939,31 @@ cloud_provider:
 					Expect(err).ToNot(HaveOccurred())
 				})
 
+				It("plans the disk migration in a dry run without calling the CPI or changing the deployment state", func() {
+					dryRun = true
+					mockStateBuilderFactory.EXPECT().NewBuilder(gomock.Any(), gomock.Any()).Return(mockStateBuilder).AnyTimes()
+
+					deploymentStateBefore, err := fs.ReadFileString(deploymentStatePath)
+					Expect(err).ToNot(HaveOccurred())
+
+					dryRunOpts := newDeployOpts(deploymentManifestPath, "")
+					dryRunOpts.DryRun = true
+
+					err = newCreateEnvCmd().Run(fakeStage, dryRunOpts)
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(stdOut).To(gbytes.Say(`has_vm\s+\["fake-vm-cid-1"\]\s+true`))
+					Expect(stdOut).To(gbytes.Say(`delete_vm\s+\["fake-vm-cid-1"\]`))
+					Expect(stdOut).To(gbytes.Say(`create_vm\s+.*"fake-disk-cid-1".*dry-run-vm-1`))
+					Expect(stdOut).To(gbytes.Say(`attach_disk\s+\["dry-run-vm-1","fake-disk-cid-1"\]`))
+					Expect(stdOut).To(gbytes.Say(`create_disk\s+\[2048,.*dry-run-disk-2`))
+					Expect(stdOut).To(gbytes.Say(`attach_disk\s+\["dry-run-vm-1","dry-run-disk-2"\]`))
+					Expect(stdOut).To(gbytes.Say(`detach_disk\s+\["dry-run-vm-1","fake-disk-cid-1"\]`))
+					Expect(stdOut).To(gbytes.Say(`delete_disk\s+\["fake-disk-cid-1"\]`))
+
+					Expect(fs.ReadFileString(deploymentStatePath)).To(Equal(deploymentStateBefore))
+				})
+
 				Context("when current VM has been deleted manually (outside of bosh)", func() {
 					It("migrates the disk content, but does not shutdown the old VM", func() {
 						expectDeployWithDiskMigrationMissingVM()
@@ -0,0 +1,222 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	boshjob "github.com/cloudfoundry/bosh-cli/v7/release/job"
+)
+
+const (
+	DiffAdded          = "added"
+	DiffRemoved        = "removed"
+	DiffChanged        = "changed"
+	DiffDefaultChanged = "default changed"
+)
+
+type Diff struct {
+	Jobs     []ResourceDiff
+	Packages []ResourceDiff
+	Specs    []SpecDiff
+}
+
+type ResourceDiff struct {
+	Name            string
+	Change          string
+	FromFingerprint string
+	ToFingerprint   string
+}
+
+type SpecDiff struct {
+	Job    string
+	Kind   string // property, consumes or provides
+	Name   string
+	Change string
+	From   string
+	To     string
+}
+
+// NewDiff compares jobs and packages of two releases. Job spec details
+// (properties and links) are only compared for jobs present in both releases.
+func NewDiff(from, to Release) Diff {
+	var diff Diff
+
+	diff.Jobs = diffFingerprints(jobFingerprints(from), jobFingerprints(to))
+	diff.Packages = diffFingerprints(pkgFingerprints(from), pkgFingerprints(to))
+
+	toJobs := map[string]*boshjob.Job{}
+
+	for _, job := range to.Jobs() {
+		toJobs[job.Name()] = job
+	}
+
+	for _, fromJob := range from.Jobs() {
+		if toJob, found := toJobs[fromJob.Name()]; found {
+			diff.Specs = append(diff.Specs, diffJobSpecs(fromJob, toJob)...)
+		}
+	}
+
+	sort.SliceStable(diff.Specs, func(i, j int) bool {
+		if diff.Specs[i].Job != diff.Specs[j].Job {
+			return diff.Specs[i].Job < diff.Specs[j].Job
+		}
+		if diff.Specs[i].Kind != diff.Specs[j].Kind {
+			return diff.Specs[i].Kind < diff.Specs[j].Kind
+		}
+		return diff.Specs[i].Name < diff.Specs[j].Name
+	})
+
+	return diff
+}
+
+func (d Diff) Empty() bool {
+	return len(d.Jobs) == 0 && len(d.Packages) == 0 && len(d.Specs) == 0
+}
+
+func jobFingerprints(release Release) map[string]string {
+	fps := map[string]string{}
+
+	for _, job := range release.Jobs() {
+		fps[job.Name()] = job.Fingerprint()
+	}
+
+	return fps
+}
+
+func pkgFingerprints(release Release) map[string]string {
+	fps := map[string]string{}
+
+	for _, pkg := range release.Packages() {
+		fps[pkg.Name()] = pkg.Fingerprint()
+	}
+
+	for _, pkg := range release.CompiledPackages() {
+		fps[pkg.Name()] = pkg.Fingerprint()
+	}
+
+	return fps
+}
+
+func diffFingerprints(from, to map[string]string) []ResourceDiff {
+	var diffs []ResourceDiff
+
+	for name, fromFP := range from {
+		toFP, found := to[name]
+
+		switch {
+		case !found:
+			diffs = append(diffs, ResourceDiff{Name: name, Change: DiffRemoved, FromFingerprint: fromFP})
+		case fromFP != toFP:
+			diffs = append(diffs, ResourceDiff{Name: name, Change: DiffChanged, FromFingerprint: fromFP, ToFingerprint: toFP})
+		}
+	}
+
+	for name, toFP := range to {
+		if _, found := from[name]; !found {
+			diffs = append(diffs, ResourceDiff{Name: name, Change: DiffAdded, ToFingerprint: toFP})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+
+	return diffs
+}
+
+func diffJobSpecs(from, to *boshjob.Job) []SpecDiff {
+	var diffs []SpecDiff
+
+	for name, fromDef := range from.Properties {
+		toDef, found := to.Properties[name]
+
+		switch {
+		case !found:
+			diffs = append(diffs, SpecDiff{
+				Job: from.Name(), Kind: "property", Name: name, Change: DiffRemoved,
+				From: formatPropertyDefault(fromDef),
+			})
+		case !reflect.DeepEqual(fromDef.Default, toDef.Default):
+			diffs = append(diffs, SpecDiff{
+				Job: from.Name(), Kind: "property", Name: name, Change: DiffDefaultChanged,
+				From: formatPropertyDefault(fromDef), To: formatPropertyDefault(toDef),
+			})
+		}
+	}
+
+	for name, toDef := range to.Properties {
+		if _, found := from.Properties[name]; !found {
+			diffs = append(diffs, SpecDiff{
+				Job: from.Name(), Kind: "property", Name: name, Change: DiffAdded,
+				To: formatPropertyDefault(toDef),
+			})
+		}
+	}
+
+	diffs = append(diffs, diffLinks(from.Name(), "consumes", from.Consumes, to.Consumes)...)
+	diffs = append(diffs, diffLinks(from.Name(), "provides", from.Provides, to.Provides)...)
+
+	return diffs
+}
+
+func diffLinks(jobName, kind string, from, to []boshjob.LinkDefinition) []SpecDiff {
+	var diffs []SpecDiff
+
+	toLinks := map[string]boshjob.LinkDefinition{}
+
+	for _, link := range to {
+		toLinks[link.Name] = link
+	}
+
+	fromLinks := map[string]boshjob.LinkDefinition{}
+
+	for _, fromLink := range from {
+		fromLinks[fromLink.Name] = fromLink
+
+		toLink, found := toLinks[fromLink.Name]
+
+		switch {
+		case !found:
+			diffs = append(diffs, SpecDiff{
+				Job: jobName, Kind: kind, Name: fromLink.Name, Change: DiffRemoved,
+				From: formatLink(fromLink),
+			})
+		case fromLink != toLink:
+			diffs = append(diffs, SpecDiff{
+				Job: jobName, Kind: kind, Name: fromLink.Name, Change: DiffChanged,
+				From: formatLink(fromLink), To: formatLink(toLink),
+			})
+		}
+	}
+
+	for _, toLink := range to {
+		if _, found := fromLinks[toLink.Name]; !found {
+			diffs = append(diffs, SpecDiff{
+				Job: jobName, Kind: kind, Name: toLink.Name, Change: DiffAdded,
+				To: formatLink(toLink),
+			})
+		}
+	}
+
+	return diffs
+}
+
+func formatPropertyDefault(def boshjob.PropertyDefinition) string {
+	if def.Default == nil {
+		return ""
+	}
+
+	bytes, err := json.Marshal(def.Default)
+	if err != nil {
+		return fmt.Sprintf("%v", def.Default)
+	}
+
+	return string(bytes)
+}
+
+func formatLink(link boshjob.LinkDefinition) string {
+	if link.Optional {
+		return link.Type + " (optional)"
+	}
+	return link.Type
+}
@@ -0,0 +1,101 @@
+package release_test
+
+import (
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshjob "github.com/cloudfoundry/bosh-cli/v7/release/job"
+	boshpkg "github.com/cloudfoundry/bosh-cli/v7/release/pkg"
+	. "github.com/cloudfoundry/bosh-cli/v7/release/resource"
+)
+
+var _ = Describe("NewDiff", func() {
+	var (
+		fs *fakesys.FakeFileSystem
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+	})
+
+	newJob := func(name, fp string) *boshjob.Job {
+		job := boshjob.NewJob(NewResourceWithBuiltArchive(name, fp, "path", "sha1"))
+		job.Properties = map[string]boshjob.PropertyDefinition{}
+		return job
+	}
+
+	newPkg := func(name, fp string) *boshpkg.Package {
+		return boshpkg.NewPackage(NewResourceWithBuiltArchive(name, fp, "path", "sha1"), nil)
+	}
+
+	newRelease := func(jobs []*boshjob.Job, pkgs []*boshpkg.Package) Release {
+		return NewRelease("rel", "1", "", false, jobs, pkgs, nil, nil, "", fs)
+	}
+
+	It("returns an empty diff for identical releases", func() {
+		from := newRelease([]*boshjob.Job{newJob("job", "fp")}, []*boshpkg.Package{newPkg("pkg", "fp")})
+		to := newRelease([]*boshjob.Job{newJob("job", "fp")}, []*boshpkg.Package{newPkg("pkg", "fp")})
+
+		Expect(NewDiff(from, to).Empty()).To(BeTrue())
+	})
+
+	It("reports added, removed and changed jobs and packages", func() {
+		from := newRelease(
+			[]*boshjob.Job{newJob("job1", "fp1"), newJob("job2", "fp2")},
+			[]*boshpkg.Package{newPkg("pkg1", "fp1"), newPkg("pkg2", "fp2")},
+		)
+		to := newRelease(
+			[]*boshjob.Job{newJob("job2", "fp2-new"), newJob("job3", "fp3")},
+			[]*boshpkg.Package{newPkg("pkg1", "fp1"), newPkg("pkg3", "fp3")},
+		)
+
+		diff := NewDiff(from, to)
+
+		Expect(diff.Jobs).To(Equal([]ResourceDiff{
+			{Name: "job1", Change: "removed", FromFingerprint: "fp1"},
+			{Name: "job2", Change: "changed", FromFingerprint: "fp2", ToFingerprint: "fp2-new"},
+			{Name: "job3", Change: "added", ToFingerprint: "fp3"},
+		}))
+
+		Expect(diff.Packages).To(Equal([]ResourceDiff{
+			{Name: "pkg2", Change: "removed", FromFingerprint: "fp2"},
+			{Name: "pkg3", Change: "added", ToFingerprint: "fp3"},
+		}))
+
+		Expect(diff.Specs).To(BeEmpty())
+	})
+
+	It("reports property and link changes of jobs present in both releases", func() {
+		fromJob := newJob("job", "fp1")
+		fromJob.Properties = map[string]boshjob.PropertyDefinition{
+			"port":    {Default: biproperty.Property(80)},
+			"removed": {Default: biproperty.Property("val")},
+			"same":    {Default: biproperty.Property(true)},
+		}
+		fromJob.Consumes = []boshjob.LinkDefinition{{Name: "db", Type: "database"}}
+		fromJob.Provides = []boshjob.LinkDefinition{{Name: "web", Type: "http"}}
+
+		toJob := newJob("job", "fp2")
+		toJob.Properties = map[string]boshjob.PropertyDefinition{
+			"port":  {Default: biproperty.Property(8080)},
+			"same":  {Default: biproperty.Property(true)},
+			"added": {Default: biproperty.Property(map[string]interface{}{"key": "val"})},
+		}
+		toJob.Consumes = []boshjob.LinkDefinition{{Name: "db", Type: "database", Optional: true}}
+		toJob.Provides = []boshjob.LinkDefinition{{Name: "api", Type: "http"}}
+
+		diff := NewDiff(newRelease([]*boshjob.Job{fromJob}, nil), newRelease([]*boshjob.Job{toJob}, nil))
+
+		Expect(diff.Specs).To(Equal([]SpecDiff{
+			{Job: "job", Kind: "consumes", Name: "db", Change: "changed", From: "database", To: "database (optional)"},
+			{Job: "job", Kind: "property", Name: "added", Change: "added", To: `{"key":"val"}`},
+			{Job: "job", Kind: "property", Name: "port", Change: "default changed", From: "80", To: "8080"},
+			{Job: "job", Kind: "property", Name: "removed", Change: "removed", From: `"val"`},
+			{Job: "job", Kind: "provides", Name: "api", Change: "added", To: "http"},
+			{Job: "job", Kind: "provides", Name: "web", Change: "removed", From: "http"},
+		}))
+	})
+})
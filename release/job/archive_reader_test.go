@@ -50,6 +50,10 @@ properties:
   prop:
     description: prop-desc
     default: prop-default
+consumes:
+- {name: db, type: database, optional: true}
+provides:
+- {name: web, type: http}
 `)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -69,6 +73,8 @@ properties:
 					Default:     biproperty.Property("prop-default"),
 				},
 			}))
+			Expect(job.Consumes).To(Equal([]LinkDefinition{{Name: "db", Type: "database", Optional: true}}))
+			Expect(job.Provides).To(Equal([]LinkDefinition{{Name: "web", Type: "http"}}))
 
 			Expect(job.ExtractedPath()).To(Equal("/extracted/job"))
 
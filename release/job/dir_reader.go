@@ -36,8 +36,13 @@ func (r DirReaderImpl) Read(path string) (*Job, error) {
 	}
 
 	job := NewJob(NewResource(manifest.Name, fp, archive))
-	job.PackageNames = manifest.Packages
-	// Does not read all manifest values...
+
+	// Property defaults are only used for diffing releases,
+	// so unusual ones must not prevent creating a release
+	err = job.applyManifest(manifest, true)
+	if err != nil {
+		return nil, err
+	}
 
 	return job, nil
 }
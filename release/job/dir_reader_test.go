@@ -4,6 +4,7 @@ import (
 	"errors"
 	"path/filepath"
 
+	biproperty "github.com/cloudfoundry/bosh-utils/property"
 	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -47,6 +48,8 @@ properties:
   prop:
     description: prop-desc
     default: prop-default
+consumes:
+- {name: db, type: database}
 `)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -58,7 +61,15 @@ properties:
 			archive.FingerprintReturns("fp", nil)
 
 			expectedJob := NewJob(NewResource("my-job", "fp", archive))
-			expectedJob.PackageNames = []string{"pkg"} // only expect pkg names
+			expectedJob.Templates = map[string]string{"src": "dst"}
+			expectedJob.PackageNames = []string{"pkg"}
+			expectedJob.Properties = map[string]PropertyDefinition{
+				"prop": {
+					Description: "prop-desc",
+					Default:     biproperty.Property("prop-default"),
+				},
+			}
+			expectedJob.Consumes = []LinkDefinition{{Name: "db", Type: "database"}}
 
 			job, err := reader.Read(filepath.Join("/", "my-job"))
 			Expect(err).NotTo(HaveOccurred())
@@ -83,7 +94,10 @@ properties:
 
 			job, err := reader.Read(filepath.Join("/", "my-job"))
 			Expect(err).NotTo(HaveOccurred())
-			Expect(job).To(Equal(NewJob(NewResource("my-job", "fp", archive))))
+			expectedJob := NewJob(NewResource("my-job", "fp", archive))
+			expectedJob.Properties = map[string]PropertyDefinition{}
+
+			Expect(job).To(Equal(expectedJob))
 
 			Expect(collectedFiles).To(Equal([]File{
 				{Path: filepath.Join("/", "my-job", "spec"), DirPath: filepath.Join("/", "my-job"), RelativePath: "job.MF"},
@@ -93,6 +107,26 @@ properties:
 			Expect(collectedChunks).To(BeEmpty())
 		})
 
+		It("keeps property defaults that cannot be converted to properties as they are", func() {
+			err := fs.WriteFileString(filepath.Join("/", "my-job", "spec"), `---
+name: my-job
+properties:
+  ports:
+    default: {80: http, 443: https}
+`)
+			Expect(err).ToNot(HaveOccurred())
+
+			archive.FingerprintReturns("fp", nil)
+
+			job, err := reader.Read(filepath.Join("/", "my-job"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.Properties).To(Equal(map[string]PropertyDefinition{
+				"ports": {
+					Default: map[interface{}]interface{}{80: "http", 443: "https"},
+				},
+			}))
+		})
+
 		It("returns error if spec file is not valid", func() {
 			err := fs.WriteFileString(filepath.Join("/", "my-job", "spec"), `-`)
 			Expect(err).ToNot(HaveOccurred())
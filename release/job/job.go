@@ -7,6 +7,7 @@ import (
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 
 	"github.com/cloudfoundry/bosh-cli/v7/crypto"
+	boshjobman "github.com/cloudfoundry/bosh-cli/v7/release/job/manifest"
 	boshpkg "github.com/cloudfoundry/bosh-cli/v7/release/pkg"
 	. "github.com/cloudfoundry/bosh-cli/v7/release/resource"
 )
@@ -24,6 +25,8 @@ type Job struct {
 	PackageNames []string
 	Packages     []boshpkg.Compilable
 	Properties   map[string]PropertyDefinition
+	Consumes     []LinkDefinition
+	Provides     []LinkDefinition
 
 	extractedPath string
 	fs            boshsys.FileSystem
@@ -34,6 +37,12 @@ type PropertyDefinition struct {
 	Default     biproperty.Property
 }
 
+type LinkDefinition struct {
+	Name     string
+	Type     string
+	Optional bool
+}
+
 func NewJob(resource Resource) *Job {
 	return &Job{resource: resource}
 }
@@ -42,6 +51,54 @@ func NewExtractedJob(resource Resource, extractedPath string, fs boshsys.FileSys
 	return &Job{resource: resource, extractedPath: extractedPath, fs: fs}
 }
 
+// applyManifest copies templates, packages, property definitions and
+// link definitions from a job spec. Property defaults that cannot be
+// converted to properties (e.g. maps with non-string keys) are an error
+// unless keepRawDefaults is set, in which case they are kept as parsed.
+func (j *Job) applyManifest(manifest boshjobman.Manifest, keepRawDefaults bool) error {
+	j.Templates = manifest.Templates
+	j.PackageNames = manifest.Packages
+
+	properties := make(map[string]PropertyDefinition, len(manifest.Properties))
+
+	for propertyName, rawPropertyDef := range manifest.Properties {
+		defaultValue, err := biproperty.Build(rawPropertyDef.Default)
+		if err != nil {
+			if !keepRawDefaults {
+				errMsg := "Parsing job '%s' property '%s' default: %#v"
+				return bosherr.WrapErrorf(err, errMsg, j.Name(), propertyName, rawPropertyDef.Default)
+			}
+
+			defaultValue = rawPropertyDef.Default
+		}
+
+		properties[propertyName] = PropertyDefinition{
+			Description: rawPropertyDef.Description,
+			Default:     defaultValue,
+		}
+	}
+
+	j.Properties = properties
+	j.Consumes = newLinkDefinitions(manifest.Consumes)
+	j.Provides = newLinkDefinitions(manifest.Provides)
+
+	return nil
+}
+
+func newLinkDefinitions(rawLinkDefs []boshjobman.LinkDefinition) []LinkDefinition {
+	var linkDefs []LinkDefinition
+
+	for _, rawLinkDef := range rawLinkDefs {
+		linkDefs = append(linkDefs, LinkDefinition{
+			Name:     rawLinkDef.Name,
+			Type:     rawLinkDef.Type,
+			Optional: rawLinkDef.Optional,
+		})
+	}
+
+	return linkDefs
+}
+
 func (j Job) Name() string        { return j.resource.Name() }
 func (j Job) Fingerprint() string { return j.resource.Fingerprint() }
 
@@ -80,6 +137,8 @@ func (j *Job) RehashWithCalculator(calculator crypto.DigestCalculator, archiveFi
 		PackageNames: j.PackageNames,
 		Packages:     j.Packages,
 		Properties:   j.Properties,
+		Consumes:     j.Consumes,
+		Provides:     j.Provides,
 
 		extractedPath: j.extractedPath,
 		fs:            j.fs,
@@ -11,11 +11,20 @@ type Manifest struct {
 	Templates  map[string]string             `yaml:"templates"`
 	Packages   []string                      `yaml:"packages"`
 	Properties map[string]PropertyDefinition `yaml:"properties"`
+	Consumes   []LinkDefinition              `yaml:"consumes"`
+	Provides   []LinkDefinition              `yaml:"provides"`
 }
 
 type PropertyDefinition struct {
 	Description string      `yaml:"description"`
 	Default     interface{} `yaml:"default"`
+	Example     interface{} `yaml:"example"`
+}
+
+type LinkDefinition struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Optional bool   `yaml:"optional"`
 }
 
 func NewManifestFromPath(path string, fs boshsys.FileSystem) (Manifest, error) {
@@ -26,10 +35,21 @@ func NewManifestFromPath(path string, fs boshsys.FileSystem) (Manifest, error) {
 		return manifest, bosherr.WrapErrorf(err, "Reading job spec '%s'", path)
 	}
 
-	err = yaml.Unmarshal(bytes, &manifest)
+	manifest, err = NewManifestFromBytes(bytes)
 	if err != nil {
 		return manifest, bosherr.WrapErrorf(err, "Unmarshalling job spec '%s'", path)
 	}
 
 	return manifest, nil
 }
+
+func NewManifestFromBytes(bytes []byte) (Manifest, error) {
+	var manifest Manifest
+
+	err := yaml.Unmarshal(bytes, &manifest)
+	if err != nil {
+		return manifest, err
+	}
+
+	return manifest, nil
+}
@@ -37,6 +37,15 @@ properties:
   prop1.prop2:
     description: prop2-desc
     default: prop2-default
+
+consumes:
+- name: db
+  type: database
+  optional: true
+
+provides:
+- name: web
+  type: http
 `
 
 		err := fs.WriteFileString("/path", contents)
@@ -61,6 +70,9 @@ properties:
 					Default:     "prop2-default",
 				},
 			},
+
+			Consumes: []LinkDefinition{{Name: "db", Type: "database", Optional: true}},
+			Provides: []LinkDefinition{{Name: "web", Type: "http"}},
 		}))
 	})
 
@@ -83,3 +95,29 @@ properties:
 		Expect(err.Error()).To(ContainSubstring("fake-err"))
 	})
 })
+
+var _ = Describe("NewManifestFromBytes", func() {
+	It("parses property examples", func() {
+		manifest, err := NewManifestFromBytes([]byte(`
+name: name
+properties:
+  listen.port:
+    default: 8080
+  users:
+    example:
+    - name: admin
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.Properties).To(Equal(map[string]PropertyDefinition{
+			"listen.port": {Default: 8080},
+			"users": {Example: []interface{}{
+				map[interface{}]interface{}{"name": "admin"},
+			}},
+		}))
+	})
+
+	It("returns error if manifest is not valid yaml", func() {
+		_, err := NewManifestFromBytes([]byte("-"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,9 @@
 package manifest
 
 import (
+	"path"
+	"strings"
+
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 	boshsys "github.com/cloudfoundry/bosh-utils/system"
 	"gopkg.in/yaml.v2"
@@ -28,3 +31,53 @@ func NewManifestFromPath(path string, fs boshsys.FileSystem) (Manifest, error) {
 
 	return manifest, nil
 }
+
+// IncludesFile checks whether file path relative to src or blobs directory
+// is matched by 'files' and not by 'excluded_files' patterns
+func (m Manifest) IncludesFile(filePath string) bool {
+	for _, pattern := range m.ExcludedFiles {
+		if matchGlob(pattern, filePath) {
+			return false
+		}
+	}
+
+	for _, pattern := range m.Files {
+		if matchGlob(pattern, filePath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGlob supports the same patterns as package specs: '*', '?',
+// character classes and '**' matching any number of directories
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	matched, err := path.Match(pattern[0], name[0])
+	if err != nil || !matched {
+		return false
+	}
+
+	return matchSegments(pattern[1:], name[1:])
+}
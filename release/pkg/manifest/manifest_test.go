@@ -71,3 +71,29 @@ excluded_files:
 		Expect(err.Error()).To(ContainSubstring("fake-err"))
 	})
 })
+
+var _ = Describe("Manifest", func() {
+	Describe("IncludesFile", func() {
+		manifest := Manifest{
+			Files:         []string{"app/**/*", "vendor/*.tgz", "config?.yml"},
+			ExcludedFiles: []string{"vendor/skip.tgz"},
+		}
+
+		It("matches files by patterns including '**'", func() {
+			Expect(manifest.IncludesFile("app/main.go")).To(BeTrue())
+			Expect(manifest.IncludesFile("app/src/lib/lib.go")).To(BeTrue())
+			Expect(manifest.IncludesFile("vendor/dep.tgz")).To(BeTrue())
+			Expect(manifest.IncludesFile("config1.yml")).To(BeTrue())
+		})
+
+		It("does not match files outside of patterns", func() {
+			Expect(manifest.IncludesFile("vendor/nested/dep.tgz")).To(BeFalse())
+			Expect(manifest.IncludesFile("other/file")).To(BeFalse())
+			Expect(manifest.IncludesFile("config10.yml")).To(BeFalse())
+		})
+
+		It("does not match excluded files", func() {
+			Expect(manifest.IncludesFile("vendor/skip.tgz")).To(BeFalse())
+		})
+	})
+})
@@ -2,6 +2,7 @@ package release
 
 import (
 	"path/filepath"
+	"time"
 
 	boshcmd "github.com/cloudfoundry/bosh-utils/fileutil"
 	boshlog "github.com/cloudfoundry/bosh-utils/logger"
@@ -16,6 +17,7 @@ import (
 
 type Provider struct {
 	fingerprinterFactory func(bool) Fingerprinter
+	sourceDate           time.Time
 
 	cmdRunner        boshsys.CmdRunner
 	compressor       boshcmd.Compressor
@@ -35,6 +37,7 @@ func NewProvider(
 		fingerprinterFactory: func(followSymlinks bool) Fingerprinter {
 			return NewFingerprinterImpl(digestCalculator, fs, followSymlinks)
 		},
+		sourceDate:       ResolveSourceDate(SourceDateEpochOr(UnixEpoch), logger),
 		cmdRunner:        cmdRunner,
 		compressor:       compressor,
 		digestCalculator: digestCalculator,
@@ -43,6 +46,13 @@ func NewProvider(
 	}
 }
 
+// WithSourceDate returns provider that uses given source date
+// as modification time of entries in created archives.
+func (p Provider) WithSourceDate(sourceDate time.Time) Provider {
+	p.sourceDate = sourceDate
+	return p
+}
+
 func (p Provider) NewMultiReader(dirPath string) MultiReader {
 	opts := MultiReaderOpts{
 		ArchiveReader:  p.NewArchiveReader(),
@@ -62,19 +72,29 @@ func (p Provider) archiveReader(extracting bool) ArchiveReader {
 }
 
 func (p Provider) NewDirReader(dirPath string) DirReader {
-	archiveFactory := func(args ArchiveFactoryArgs) Archive {
-		return NewArchiveImpl(
-			args, dirPath, p.fingerprinterFactory(args.FollowSymlinks), p.compressor, p.digestCalculator, p.cmdRunner, p.fs)
-	}
+	jobDirReader := p.NewJobDirReader(dirPath)
+	pkgDirReader := p.NewPackageDirReader(dirPath)
+	licDirReader := boshlic.NewDirReaderImpl(p.dirArchiveFactory(dirPath), p.fs)
 
+	return NewDirReader(jobDirReader, pkgDirReader, licDirReader, p.fs, p.logger)
+}
+
+func (p Provider) NewJobDirReader(dirPath string) boshjob.DirReaderImpl {
+	return boshjob.NewDirReaderImpl(p.dirArchiveFactory(dirPath), p.fs)
+}
+
+func (p Provider) NewPackageDirReader(dirPath string) boshpkg.DirReaderImpl {
 	srcDirPath := filepath.Join(dirPath, "src")
 	blobsDirPath := filepath.Join(dirPath, "blobs")
 
-	jobDirReader := boshjob.NewDirReaderImpl(archiveFactory, p.fs)
-	pkgDirReader := boshpkg.NewDirReaderImpl(archiveFactory, srcDirPath, blobsDirPath, p.fs)
-	licDirReader := boshlic.NewDirReaderImpl(archiveFactory, p.fs)
+	return boshpkg.NewDirReaderImpl(p.dirArchiveFactory(dirPath), srcDirPath, blobsDirPath, p.fs)
+}
 
-	return NewDirReader(jobDirReader, pkgDirReader, licDirReader, p.fs, p.logger)
+func (p Provider) dirArchiveFactory(dirPath string) ArchiveFunc {
+	return func(args ArchiveFactoryArgs) Archive {
+		return NewArchiveImpl(
+			args, dirPath, p.fingerprinterFactory(args.FollowSymlinks), p.archiveCompressor(), p.digestCalculator, p.cmdRunner, p.fs)
+	}
 }
 
 func (p Provider) NewManifestReader() ManifestReader {
@@ -82,5 +102,11 @@ func (p Provider) NewManifestReader() ManifestReader {
 }
 
 func (p Provider) NewArchiveWriter() ArchiveWriter {
-	return NewArchiveWriter(p.compressor, p.fs, p.logger)
+	return NewArchiveWriter(p.archiveCompressor(), p.fs, p.logger)
+}
+
+// archiveCompressor makes created job, package, license and release
+// archives byte-for-byte reproducible
+func (p Provider) archiveCompressor() boshcmd.Compressor {
+	return NewReproducibleCompressor(p.compressor, p.sourceDate, p.fs)
 }
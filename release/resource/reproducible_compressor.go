@@ -0,0 +1,227 @@
+package resource
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshcmd "github.com/cloudfoundry/bosh-utils/fileutil"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+// SourceDateFunc returns the time recorded as modification time
+// of all entries in reproducible tarballs.
+type SourceDateFunc func() (time.Time, error)
+
+// UnixEpoch is used when no better source date is known.
+func UnixEpoch() (time.Time, error) { return time.Unix(0, 0).UTC(), nil }
+
+// SourceDateEpochOr returns SOURCE_DATE_EPOCH (https://reproducible-builds.org/specs/source-date-epoch/)
+// if it's set; otherwise it returns time from fallback or Unix epoch if fallback returns zero time.
+func SourceDateEpochOr(fallback SourceDateFunc) SourceDateFunc {
+	return func() (time.Time, error) {
+		epoch := strings.TrimSpace(os.Getenv("SOURCE_DATE_EPOCH"))
+
+		if len(epoch) > 0 {
+			secs, err := strconv.ParseInt(epoch, 10, 64)
+			if err != nil {
+				return time.Time{}, bosherr.WrapErrorf(err, "Parsing SOURCE_DATE_EPOCH '%s'", epoch)
+			}
+			return time.Unix(secs, 0).UTC(), nil
+		}
+
+		sourceDate, err := fallback()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if sourceDate.IsZero() {
+			return UnixEpoch()
+		}
+
+		return sourceDate.UTC(), nil
+	}
+}
+
+// ResolveSourceDate returns time from sourceDate. Archives can be created
+// without a meaningful source date, so errors are logged and Unix epoch is used instead.
+func ResolveSourceDate(sourceDate SourceDateFunc, logger boshlog.Logger) time.Time {
+	date, err := sourceDate()
+	if err != nil {
+		logger.Warn("sourceDate", "Using Unix epoch as source date: %s", err.Error())
+		return time.Unix(0, 0).UTC()
+	}
+
+	return date
+}
+
+// ReproducibleCompressor creates gzipped tarballs whose bytes only depend
+// on names, contents and permissions of included files: entries are in stable order,
+// owned by uid/gid 0, have the same modification time, and gzip header
+// does not include a name or a timestamp. Decompression is delegated.
+type ReproducibleCompressor struct {
+	compressor boshcmd.Compressor
+	sourceDate time.Time
+	fs         boshsys.FileSystem
+}
+
+func NewReproducibleCompressor(
+	compressor boshcmd.Compressor,
+	sourceDate time.Time,
+	fs boshsys.FileSystem,
+) ReproducibleCompressor {
+	return ReproducibleCompressor{compressor: compressor, sourceDate: sourceDate, fs: fs}
+}
+
+func (c ReproducibleCompressor) CompressFilesInDir(dir string) (string, error) {
+	return c.CompressSpecificFilesInDir(dir, []string{"."})
+}
+
+func (c ReproducibleCompressor) CompressSpecificFilesInDir(dir string, files []string) (string, error) {
+	entries, err := c.collectEntries(dir, files)
+	if err != nil {
+		return "", err
+	}
+
+	tarball, err := c.fs.TempFile("bosh-ReproducibleCompressor-CompressSpecificFilesInDir")
+	if err != nil {
+		return "", bosherr.WrapError(err, "Creating temporary file for tarball")
+	}
+
+	defer tarball.Close() //nolint:errcheck
+
+	// Zero value gzip header has no name, no mtime and 'unknown' OS
+	gzipWriter := gzip.NewWriter(tarball)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, entry := range entries {
+		err = c.writeEntry(tarWriter, dir, entry, c.sourceDate)
+		if err != nil {
+			return "", bosherr.WrapErrorf(err, "Adding '%s' to tarball", entry)
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return "", bosherr.WrapError(err, "Closing tarball")
+	}
+
+	err = gzipWriter.Close()
+	if err != nil {
+		return "", bosherr.WrapError(err, "Closing gzip stream")
+	}
+
+	return tarball.Name(), nil
+}
+
+func (c ReproducibleCompressor) DecompressFileToDir(path string, dir string, options boshcmd.CompressorOptions) error {
+	return c.compressor.DecompressFileToDir(path, dir, options)
+}
+
+func (c ReproducibleCompressor) CleanUp(path string) error {
+	return c.fs.RemoveAll(path)
+}
+
+// collectEntries keeps order of given files (e.g. release.MF comes first in release tarballs)
+// and walks directories in lexical order; entries are named the same way as tar names them
+// (e.g. './job.MF' when compressing '.')
+func (c ReproducibleCompressor) collectEntries(dir string, files []string) ([]string, error) {
+	var entries []string
+
+	for _, file := range files {
+		root := filepath.Join(dir, file)
+
+		err := c.fs.Walk(root, func(path string, _ os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relPath, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			relPath = filepath.ToSlash(relPath)
+
+			if file == "." {
+				relPath = strings.TrimSuffix("./"+relPath, "/.")
+				if relPath == "." {
+					relPath = "./"
+				}
+			}
+
+			entries = append(entries, relPath)
+
+			return nil
+		})
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Collecting files in '%s'", root)
+		}
+	}
+
+	return entries, nil
+}
+
+func (c ReproducibleCompressor) writeEntry(tarWriter *tar.Writer, dir, entry string, modTime time.Time) error {
+	path := filepath.Join(dir, filepath.FromSlash(entry))
+
+	info, err := c.fs.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    entry,
+		Mode:    int64(info.Mode().Perm()),
+		ModTime: modTime,
+	}
+
+	switch {
+	case info.IsDir():
+		header.Typeflag = tar.TypeDir
+		if !strings.HasSuffix(header.Name, "/") {
+			header.Name += "/"
+		}
+
+	case info.Mode()&os.ModeSymlink != 0:
+		header.Typeflag = tar.TypeSymlink
+		header.Linkname, err = c.fs.Readlink(path)
+		if err != nil {
+			return err
+		}
+
+	case info.Mode().IsRegular():
+		header.Typeflag = tar.TypeReg
+		header.Size = info.Size()
+
+	default:
+		return bosherr.Errorf("Unsupported file type '%s'", info.Mode().Type())
+	}
+
+	err = tarWriter.WriteHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if header.Typeflag != tar.TypeReg {
+		return nil
+	}
+
+	file, err := c.fs.OpenFile(path, os.O_RDONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close() //nolint:errcheck
+
+	_, err = io.Copy(tarWriter, file)
+
+	return err
+}
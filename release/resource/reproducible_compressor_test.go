@@ -0,0 +1,260 @@
+package resource_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	boshcrypto "github.com/cloudfoundry/bosh-utils/crypto"
+	boshcmd "github.com/cloudfoundry/bosh-utils/fileutil"
+	fakecmd "github.com/cloudfoundry/bosh-utils/fileutil/fakes"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	bicrypto "github.com/cloudfoundry/bosh-cli/v7/crypto"
+	. "github.com/cloudfoundry/bosh-cli/v7/release/resource"
+)
+
+var _ = Describe("ReproducibleCompressor", func() {
+	var (
+		fs         boshsys.FileSystem
+		sourceDate time.Time
+		compressor ReproducibleCompressor
+		dir        string
+	)
+
+	type entry struct {
+		Name     string
+		Mode     int64
+		Uid      int
+		Gid      int
+		ModTime  time.Time
+		Linkname string
+		Content  string
+	}
+
+	readEntries := func(path string) (*gzip.Header, []entry) {
+		file, err := os.Open(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		defer file.Close() //nolint:errcheck
+
+		gzipReader, err := gzip.NewReader(file)
+		Expect(err).ToNot(HaveOccurred())
+
+		var entries []entry
+
+		tarReader := tar.NewReader(gzipReader)
+
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).ToNot(HaveOccurred())
+
+			content, err := io.ReadAll(tarReader)
+			Expect(err).ToNot(HaveOccurred())
+
+			entries = append(entries, entry{
+				Name:     header.Name,
+				Mode:     header.Mode,
+				Uid:      header.Uid,
+				Gid:      header.Gid,
+				ModTime:  header.ModTime.UTC(),
+				Linkname: header.Linkname,
+				Content:  string(content),
+			})
+		}
+
+		return &gzipReader.Header, entries
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		dir, err = os.MkdirTemp("", "reproducible-compressor")
+		Expect(err).ToNot(HaveOccurred())
+
+		DeferCleanup(func() { os.RemoveAll(dir) }) //nolint:errcheck
+
+		fs = boshsys.NewOsFileSystem(boshlog.NewLogger(boshlog.LevelNone))
+
+		Expect(fs.MkdirAll(filepath.Join(dir, "b-dir"), os.FileMode(0755))).To(Succeed())
+		Expect(fs.WriteFileString(filepath.Join(dir, "b-dir", "file2"), "file2")).To(Succeed())
+		Expect(fs.Chmod(filepath.Join(dir, "b-dir", "file2"), os.FileMode(0744))).To(Succeed())
+		Expect(fs.WriteFileString(filepath.Join(dir, "a-file1"), "file1")).To(Succeed())
+		Expect(fs.Chmod(filepath.Join(dir, "a-file1"), os.FileMode(0600))).To(Succeed())
+		Expect(fs.Symlink("a-file1", filepath.Join(dir, "c-link"))).To(Succeed())
+
+		sourceDate = time.Unix(1700000000, 0).UTC()
+
+		compressor = NewReproducibleCompressor(nil, sourceDate, fs)
+	})
+
+	Describe("CompressFilesInDir", func() {
+		It("creates tarball with entries in lexical order owned by root with source date as modification time", func() {
+			path, err := compressor.CompressFilesInDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			defer compressor.CleanUp(path) //nolint:errcheck
+
+			gzipHeader, entries := readEntries(path)
+
+			Expect(gzipHeader.Name).To(BeEmpty())
+			Expect(gzipHeader.ModTime.IsZero()).To(BeTrue())
+
+			Expect(entries).To(Equal([]entry{
+				{Name: "./", Mode: int64(dirMode(dir)), ModTime: sourceDate},
+				{Name: "./a-file1", Mode: 0600, ModTime: sourceDate, Content: "file1"},
+				{Name: "./b-dir/", Mode: 0755, ModTime: sourceDate},
+				{Name: "./b-dir/file2", Mode: 0744, ModTime: sourceDate, Content: "file2"},
+				{Name: "./c-link", Mode: 0777, ModTime: sourceDate, Linkname: "a-file1"},
+			}))
+		})
+
+		It("creates identical tarballs regardless of file modification times", func() {
+			digestCalculator := bicrypto.NewDigestCalculator(fs, []boshcrypto.Algorithm{boshcrypto.DigestAlgorithmSHA256})
+
+			path1, err := compressor.CompressFilesInDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			defer compressor.CleanUp(path1) //nolint:errcheck
+
+			later := time.Now().Add(time.Hour)
+			Expect(os.Chtimes(filepath.Join(dir, "a-file1"), later, later)).To(Succeed())
+			Expect(os.Chtimes(filepath.Join(dir, "b-dir"), later, later)).To(Succeed())
+
+			path2, err := compressor.CompressFilesInDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			defer compressor.CleanUp(path2) //nolint:errcheck
+
+			digest1, err := digestCalculator.Calculate(path1)
+			Expect(err).ToNot(HaveOccurred())
+
+			digest2, err := digestCalculator.Calculate(path2)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(digest1).To(Equal(digest2))
+		})
+	})
+
+	Describe("CompressSpecificFilesInDir", func() {
+		It("only includes given files and directories in given order", func() {
+			path, err := compressor.CompressSpecificFilesInDir(dir, []string{"b-dir", "a-file1"})
+			Expect(err).ToNot(HaveOccurred())
+
+			defer compressor.CleanUp(path) //nolint:errcheck
+
+			_, entries := readEntries(path)
+
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name)
+			}
+
+			Expect(names).To(Equal([]string{"b-dir/", "b-dir/file2", "a-file1"}))
+		})
+	})
+
+	Describe("DecompressFileToDir", func() {
+		It("delegates to wrapped compressor", func() {
+			fakeCompressor := fakecmd.NewFakeCompressor()
+			compressor = NewReproducibleCompressor(fakeCompressor, time.Unix(0, 0).UTC(), fs)
+
+			err := compressor.DecompressFileToDir("/tarball", "/dir", boshcmd.CompressorOptions{StripComponents: 1})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeCompressor.DecompressFileToDirTarballPaths).To(Equal([]string{"/tarball"}))
+			Expect(fakeCompressor.DecompressFileToDirDirs).To(Equal([]string{"/dir"}))
+			Expect(fakeCompressor.DecompressFileToDirOptions).To(Equal([]boshcmd.CompressorOptions{{StripComponents: 1}}))
+		})
+	})
+})
+
+var _ = Describe("SourceDateEpochOr", func() {
+	var (
+		fallbackDate time.Time
+		fallbackErr  error
+		sourceDate   SourceDateFunc
+	)
+
+	BeforeEach(func() {
+		fallbackDate = time.Unix(1600000000, 0)
+		fallbackErr = nil
+
+		sourceDate = SourceDateEpochOr(func() (time.Time, error) { return fallbackDate, fallbackErr })
+	})
+
+	It("returns SOURCE_DATE_EPOCH if it is set", func() {
+		GinkgoT().Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+		date, err := sourceDate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(date).To(Equal(time.Unix(1700000000, 0).UTC()))
+	})
+
+	It("returns error if SOURCE_DATE_EPOCH is not a number", func() {
+		GinkgoT().Setenv("SOURCE_DATE_EPOCH", "yesterday")
+
+		_, err := sourceDate()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Parsing SOURCE_DATE_EPOCH 'yesterday'"))
+	})
+
+	It("returns fallback date if SOURCE_DATE_EPOCH is not set", func() {
+		GinkgoT().Setenv("SOURCE_DATE_EPOCH", "")
+
+		date, err := sourceDate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(date).To(Equal(time.Unix(1600000000, 0).UTC()))
+	})
+
+	It("returns Unix epoch if fallback date is zero", func() {
+		GinkgoT().Setenv("SOURCE_DATE_EPOCH", "")
+		fallbackDate = time.Time{}
+
+		date, err := sourceDate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(date).To(Equal(time.Unix(0, 0).UTC()))
+	})
+
+	It("returns error from fallback", func() {
+		GinkgoT().Setenv("SOURCE_DATE_EPOCH", "")
+		fallbackErr = errors.New("fake-err")
+
+		_, err := sourceDate()
+		Expect(err).To(MatchError("fake-err"))
+	})
+})
+
+var _ = Describe("ResolveSourceDate", func() {
+	var logger boshlog.Logger
+
+	BeforeEach(func() {
+		logger = boshlog.NewLogger(boshlog.LevelNone)
+	})
+
+	It("returns the source date", func() {
+		date := ResolveSourceDate(func() (time.Time, error) { return time.Unix(1700000000, 0).UTC(), nil }, logger)
+		Expect(date).To(Equal(time.Unix(1700000000, 0).UTC()))
+	})
+
+	It("returns Unix epoch if source date cannot be determined", func() {
+		date := ResolveSourceDate(func() (time.Time, error) { return time.Time{}, errors.New("fake-err") }, logger)
+		Expect(date).To(Equal(time.Unix(0, 0).UTC()))
+	})
+})
+
+func dirMode(path string) os.FileMode {
+	info, err := os.Stat(path)
+	Expect(err).ToNot(HaveOccurred())
+	return info.Mode().Perm()
+}
@@ -0,0 +1,158 @@
+package sbom
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// CycloneDX 1.5 JSON (https://cyclonedx.org/docs/1.5/json/)
+
+type cdxDocument struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string         `json:"type"`
+	BOMRef     string         `json:"bom-ref,omitempty"`
+	Name       string         `json:"name"`
+	Version    string         `json:"version,omitempty"`
+	Hashes     []cdxHash      `json:"hashes,omitempty"`
+	Licenses   []cdxLicense   `json:"licenses,omitempty"`
+	Properties []cdxProperty  `json:"properties,omitempty"`
+	Components []cdxComponent `json:"components,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxLicense struct {
+	License cdxLicenseChoice `json:"license"`
+}
+
+type cdxLicenseChoice struct {
+	Name string         `json:"name"`
+	Text cdxLicenseText `json:"text"`
+}
+
+type cdxLicenseText struct {
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+const cdxReleaseRef = "release"
+
+func CycloneDXDocument(bom BOM) ([]byte, error) {
+	release := cdxComponent{
+		Type:    "application",
+		BOMRef:  cdxReleaseRef,
+		Name:    bom.Name,
+		Version: bom.Version,
+		Properties: []cdxProperty{
+			{Name: "bosh:commit_hash", Value: bom.CommitHash},
+		},
+	}
+
+	for _, license := range bom.Licenses {
+		release.Licenses = append(release.Licenses, cdxLicense{
+			License: cdxLicenseChoice{Name: license.Name, Text: cdxLicenseText{Content: license.Text}},
+		})
+	}
+
+	doc := cdxDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Timestamp: bom.Created.Format(time.RFC3339),
+			Tools: cdxTools{
+				Components: []cdxComponent{{Type: "application", Name: "bosh-cli"}},
+			},
+			Component: release,
+		},
+		Components: []cdxComponent{},
+	}
+
+	releaseDep := cdxDependency{Ref: cdxReleaseRef, DependsOn: []string{}}
+
+	for _, component := range bom.Components {
+		cdxComp := cdxComponent{
+			Type:    "library",
+			BOMRef:  component.Ref,
+			Name:    component.Name,
+			Version: component.Version,
+			Properties: []cdxProperty{
+				{Name: "bosh:kind", Value: component.Kind},
+			},
+		}
+
+		if component.Kind == KindBlob {
+			cdxComp.Type = "file"
+			cdxComp.Properties = append(cdxComp.Properties, cdxProperty{
+				Name: "bosh:size", Value: strconv.FormatInt(component.Size, 10),
+			})
+		}
+
+		if len(component.Digest) > 0 {
+			alg, value := splitDigest(component.Digest)
+			cdxComp.Hashes = []cdxHash{{Alg: strings.Replace(alg, "SHA", "SHA-", 1), Content: value}}
+		}
+
+		if component.Vendored {
+			cdxComp.Properties = append(cdxComp.Properties, cdxProperty{Name: "bosh:vendored", Value: "true"})
+		}
+
+		if len(component.Stemcell) > 0 {
+			cdxComp.Properties = append(cdxComp.Properties, cdxProperty{Name: "bosh:stemcell", Value: component.Stemcell})
+		}
+
+		doc.Components = append(doc.Components, cdxComp)
+
+		releaseDep.DependsOn = append(releaseDep.DependsOn, component.Ref)
+
+		if component.Kind != KindBlob {
+			dependsOn := append([]string{}, component.DependsOn...)
+			dependsOn = append(dependsOn, component.Contains...)
+
+			doc.Dependencies = append(doc.Dependencies, cdxDependency{Ref: component.Ref, DependsOn: dependsOn})
+		}
+	}
+
+	doc.Dependencies = append([]cdxDependency{releaseDep}, doc.Dependencies...)
+
+	bytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshaling CycloneDX document")
+	}
+
+	return bytes, nil
+}
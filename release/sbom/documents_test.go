@@ -0,0 +1,149 @@
+package sbom_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/release/sbom"
+)
+
+var _ = Describe("Documents", func() {
+	var bom BOM
+
+	BeforeEach(func() {
+		bom = BOM{
+			Name:       "rel",
+			Version:    "1.0",
+			CommitHash: "abc123",
+			Created:    time.Date(2024, time.March, 1, 12, 30, 15, 0, time.UTC),
+			Components: []Component{
+				{Ref: "package/golang", Kind: KindPackage, Name: "golang", Version: "golang-fp", Digest: "sha256:golang-sha", Vendored: true},
+				{Ref: "package/app", Kind: KindPackage, Name: "app", Version: "app-fp", Digest: "app-sha1;sha256:app-sha",
+					DependsOn: []string{"package/golang"}, Contains: []string{"blob/app/lib.tgz"}},
+				{Ref: "blob/app/lib.tgz", Kind: KindBlob, Name: "app/lib.tgz", Digest: "lib-sha1", Size: 10},
+			},
+			Licenses: []LicenseFile{
+				{Name: "LICENSE", Text: "license-text"},
+				{Name: "NOTICE", Text: "notice-text"},
+			},
+		}
+	})
+
+	unmarshal := func(bytes []byte) map[string]interface{} {
+		var doc map[string]interface{}
+		Expect(json.Unmarshal(bytes, &doc)).To(Succeed())
+		return doc
+	}
+
+	Describe("SPDXDocument", func() {
+		It("describes release with its packages, blobs and licenses", func() {
+			bytes, err := SPDXDocument(bom)
+			Expect(err).ToNot(HaveOccurred())
+
+			doc := unmarshal(bytes)
+			Expect(doc["name"]).To(Equal("rel-1.0"))
+			Expect(doc["documentNamespace"]).To(Equal("https://bosh.io/spdx/rel/1.0/abc123"))
+			Expect(doc["creationInfo"]).To(HaveKeyWithValue("created", "2024-03-01T12:30:15Z"))
+
+			Expect(doc["hasExtractedLicensingInfos"]).To(Equal([]interface{}{
+				map[string]interface{}{"licenseId": "LicenseRef-LICENSE", "name": "LICENSE", "extractedText": "license-text"},
+			}))
+
+			pkgs := doc["packages"].([]interface{})
+			Expect(pkgs).To(HaveLen(4))
+
+			releasePkg := pkgs[0].(map[string]interface{})
+			Expect(releasePkg["SPDXID"]).To(Equal("SPDXRef-Release"))
+			Expect(releasePkg["licenseDeclared"]).To(Equal("LicenseRef-LICENSE"))
+			Expect(releasePkg["attributionTexts"]).To(Equal([]interface{}{"notice-text"}))
+
+			golangPkg := pkgs[1].(map[string]interface{})
+			Expect(golangPkg["SPDXID"]).To(Equal("SPDXRef-package-golang"))
+			Expect(golangPkg["comment"]).To(Equal("BOSH package, vendored"))
+			Expect(golangPkg["checksums"]).To(Equal([]interface{}{
+				map[string]interface{}{"algorithm": "SHA256", "checksumValue": "golang-sha"},
+			}))
+
+			appPkg := pkgs[2].(map[string]interface{})
+			Expect(appPkg["checksums"]).To(Equal([]interface{}{
+				map[string]interface{}{"algorithm": "SHA1", "checksumValue": "app-sha1"},
+			}))
+
+			blobPkg := pkgs[3].(map[string]interface{})
+			Expect(blobPkg["SPDXID"]).To(Equal("SPDXRef-blob-app-lib.tgz"))
+			Expect(blobPkg["comment"]).To(Equal("BOSH blob (10 bytes)"))
+
+			Expect(doc["relationships"]).To(Equal([]interface{}{
+				map[string]interface{}{"spdxElementId": "SPDXRef-DOCUMENT", "relationshipType": "DESCRIBES", "relatedSpdxElement": "SPDXRef-Release"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-Release", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-package-golang"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-Release", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-package-app"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-package-app", "relationshipType": "DEPENDS_ON", "relatedSpdxElement": "SPDXRef-package-golang"},
+				map[string]interface{}{"spdxElementId": "SPDXRef-package-app", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-blob-app-lib.tgz"},
+			}))
+		})
+
+		It("keeps IDs unique when refs only differ in characters not allowed in IDs", func() {
+			bom.Components = []Component{
+				{Ref: "blob/a-b", Kind: KindBlob, Name: "a-b"},
+				{Ref: "blob/a_b", Kind: KindBlob, Name: "a_b"},
+				{Ref: "package/app", Kind: KindPackage, Name: "app", Contains: []string{"blob/a-b", "blob/a_b"}},
+			}
+			bom.Licenses = []LicenseFile{{Name: "LICENSE-a"}, {Name: "LICENSE_a"}}
+
+			bytes, err := SPDXDocument(bom)
+			Expect(err).ToNot(HaveOccurred())
+
+			doc := unmarshal(bytes)
+
+			pkgs := doc["packages"].([]interface{})
+			Expect(pkgs[0].(map[string]interface{})["licenseDeclared"]).To(Equal("LicenseRef-LICENSE-a AND LicenseRef-LICENSE-a-2"))
+			Expect(pkgs[1].(map[string]interface{})["SPDXID"]).To(Equal("SPDXRef-blob-a-b"))
+			Expect(pkgs[2].(map[string]interface{})["SPDXID"]).To(Equal("SPDXRef-blob-a-b-2"))
+
+			Expect(doc["relationships"]).To(ContainElement(map[string]interface{}{
+				"spdxElementId": "SPDXRef-package-app", "relationshipType": "CONTAINS", "relatedSpdxElement": "SPDXRef-blob-a-b-2",
+			}))
+		})
+	})
+
+	Describe("CycloneDXDocument", func() {
+		It("describes release with its packages, blobs and licenses", func() {
+			bytes, err := CycloneDXDocument(bom)
+			Expect(err).ToNot(HaveOccurred())
+
+			doc := unmarshal(bytes)
+			Expect(doc["specVersion"]).To(Equal("1.5"))
+
+			metadata := doc["metadata"].(map[string]interface{})
+			Expect(metadata["timestamp"]).To(Equal("2024-03-01T12:30:15Z"))
+
+			release := metadata["component"].(map[string]interface{})
+			Expect(release["bom-ref"]).To(Equal("release"))
+			Expect(release["licenses"]).To(HaveLen(2))
+
+			components := doc["components"].([]interface{})
+			Expect(components).To(HaveLen(3))
+
+			Expect(components[0]).To(HaveKeyWithValue("hashes", []interface{}{
+				map[string]interface{}{"alg": "SHA-256", "content": "golang-sha"},
+			}))
+			Expect(components[0]).To(HaveKeyWithValue("properties", ContainElement(
+				map[string]interface{}{"name": "bosh:vendored", "value": "true"},
+			)))
+
+			Expect(components[2]).To(HaveKeyWithValue("type", "file"))
+			Expect(components[2]).To(HaveKeyWithValue("properties", ContainElement(
+				map[string]interface{}{"name": "bosh:size", "value": "10"},
+			)))
+
+			Expect(doc["dependencies"]).To(Equal([]interface{}{
+				map[string]interface{}{"ref": "release", "dependsOn": []interface{}{"package/golang", "package/app", "blob/app/lib.tgz"}},
+				map[string]interface{}{"ref": "package/golang", "dependsOn": []interface{}{}},
+				map[string]interface{}{"ref": "package/app", "dependsOn": []interface{}{"package/golang", "blob/app/lib.tgz"}},
+			}))
+		})
+	})
+})
@@ -0,0 +1,245 @@
+package sbom
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshcmd "github.com/cloudfoundry/bosh-utils/fileutil"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshpkgman "github.com/cloudfoundry/bosh-cli/v7/release/pkg/manifest"
+	boshres "github.com/cloudfoundry/bosh-cli/v7/release/resource"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+)
+
+const (
+	KindPackage         = "package"
+	KindCompiledPackage = "compiled-package"
+	KindBlob            = "blob"
+)
+
+// BOM is a format independent description of release contents
+type BOM struct {
+	Name       string
+	Version    string
+	CommitHash string
+	Created    time.Time
+
+	Components []Component
+	Licenses   []LicenseFile
+}
+
+type Component struct {
+	Ref  string // unique within BOM, e.g. 'package/golang'
+	Kind string
+
+	Name    string
+	Version string // fingerprint; empty for blobs
+	Digest  string // as recorded by BOSH: SHA1 hex or 'sha256:<hex>'
+	Size    int64
+
+	Vendored bool
+	Stemcell string // os/version of compiled packages
+
+	DependsOn []string // refs of other packages
+	Contains  []string // refs of blobs included in a package
+}
+
+type LicenseFile struct {
+	Name string
+	Text string
+}
+
+type FSGenerator struct {
+	blobsDirFactory func(dirPath string) boshreldir.BlobsDir
+	compressor      boshcmd.Compressor
+	timeService     clock.Clock
+	fs              boshsys.FileSystem
+}
+
+func NewFSGenerator(
+	blobsDirFactory func(dirPath string) boshreldir.BlobsDir,
+	compressor boshcmd.Compressor,
+	timeService clock.Clock,
+	fs boshsys.FileSystem,
+) FSGenerator {
+	return FSGenerator{
+		blobsDirFactory: blobsDirFactory,
+		compressor:      compressor,
+		timeService:     timeService,
+		fs:              fs,
+	}
+}
+
+func (g FSGenerator) Generate(release boshrel.Release, releaseDirPath string, format string) ([]byte, error) {
+	if format != FormatSPDX && format != FormatCycloneDX {
+		return nil, bosherr.Errorf("Unknown SBOM format '%s', expected '%s' or '%s'", format, FormatSPDX, FormatCycloneDX)
+	}
+
+	bom, err := g.Build(release, releaseDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatCycloneDX {
+		return CycloneDXDocument(bom)
+	}
+
+	return SPDXDocument(bom)
+}
+
+func (g FSGenerator) Build(release boshrel.Release, releaseDirPath string) (BOM, error) {
+	// Honor SOURCE_DATE_EPOCH so that SBOM can be reproduced together with release tarball
+	created, err := boshres.SourceDateEpochOr(func() (time.Time, error) { return g.timeService.Now(), nil })()
+	if err != nil {
+		return BOM{}, err
+	}
+
+	bom := BOM{
+		Name:       release.Name(),
+		Version:    release.Version(),
+		CommitHash: release.CommitHashWithMark("+"),
+		Created:    created.UTC().Truncate(time.Second),
+	}
+
+	pkgSpecs := map[string]boshpkgman.Manifest{}
+
+	for _, pkg := range release.Packages() {
+		component := Component{
+			Ref:     KindPackage + "/" + pkg.Name(),
+			Kind:    KindPackage,
+			Name:    pkg.Name(),
+			Version: pkg.Fingerprint(),
+			Digest:  pkg.ArchiveDigest(),
+		}
+
+		for _, depName := range pkg.DependencyNames() {
+			component.DependsOn = append(component.DependsOn, KindPackage+"/"+depName)
+		}
+
+		if len(releaseDirPath) > 0 {
+			pkgDirPath := filepath.Join(releaseDirPath, "packages", pkg.Name())
+
+			component.Vendored = g.fs.FileExists(filepath.Join(pkgDirPath, "spec.lock"))
+
+			specPath := filepath.Join(pkgDirPath, "spec")
+
+			if !component.Vendored && g.fs.FileExists(specPath) {
+				pkgSpecs[pkg.Name()], err = boshpkgman.NewManifestFromPath(specPath, g.fs)
+				if err != nil {
+					return BOM{}, err
+				}
+			}
+		}
+
+		bom.Components = append(bom.Components, component)
+	}
+
+	for _, compiledPkg := range release.CompiledPackages() {
+		component := Component{
+			Ref:      KindCompiledPackage + "/" + compiledPkg.Name(),
+			Kind:     KindCompiledPackage,
+			Name:     compiledPkg.Name(),
+			Version:  compiledPkg.Fingerprint(),
+			Digest:   compiledPkg.ArchiveDigest(),
+			Stemcell: compiledPkg.OSVersionSlug(),
+		}
+
+		for _, depName := range compiledPkg.DependencyNames() {
+			component.DependsOn = append(component.DependsOn, KindCompiledPackage+"/"+depName)
+		}
+
+		bom.Components = append(bom.Components, component)
+	}
+
+	if len(releaseDirPath) > 0 {
+		err = g.addBlobs(&bom, releaseDirPath, pkgSpecs)
+		if err != nil {
+			return BOM{}, err
+		}
+	}
+
+	bom.Licenses, err = g.licenseFiles(release)
+	if err != nil {
+		return BOM{}, err
+	}
+
+	return bom, nil
+}
+
+func (g FSGenerator) addBlobs(bom *BOM, releaseDirPath string, pkgSpecs map[string]boshpkgman.Manifest) error {
+	if !g.fs.FileExists(filepath.Join(releaseDirPath, "config", "blobs.yml")) {
+		return nil
+	}
+
+	blobs, err := g.blobsDirFactory(releaseDirPath).Blobs()
+	if err != nil {
+		return bosherr.WrapError(err, "Listing blobs")
+	}
+
+	for _, blob := range blobs {
+		blobRef := KindBlob + "/" + blob.Path
+
+		bom.Components = append(bom.Components, Component{
+			Ref:    blobRef,
+			Kind:   KindBlob,
+			Name:   blob.Path,
+			Digest: blob.SHA1,
+			Size:   blob.Size,
+		})
+
+		for i, component := range bom.Components {
+			spec, found := pkgSpecs[component.Name]
+			if component.Kind == KindPackage && found && spec.IncludesFile(blob.Path) {
+				bom.Components[i].Contains = append(bom.Components[i].Contains, blobRef)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g FSGenerator) licenseFiles(release boshrel.Release) ([]LicenseFile, error) {
+	license := release.License()
+	if license == nil {
+		return nil, nil
+	}
+
+	extractPath, err := g.fs.TempDir("bosh-release-sbom-license")
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Creating temp directory to extract license")
+	}
+
+	defer g.fs.RemoveAll(extractPath) //nolint:errcheck
+
+	err = g.compressor.DecompressFileToDir(license.ArchivePath(), extractPath, boshcmd.CompressorOptions{})
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Extracting license archive")
+	}
+
+	var files []LicenseFile
+
+	for _, pattern := range []string{"LICENSE*", "NOTICE*"} {
+		paths, err := g.fs.Glob(filepath.Join(extractPath, pattern))
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Listing '%s' files", pattern)
+		}
+
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			text, err := g.fs.ReadFileString(path)
+			if err != nil {
+				return nil, bosherr.WrapErrorf(err, "Reading license file '%s'", filepath.Base(path))
+			}
+
+			files = append(files, LicenseFile{Name: filepath.Base(path), Text: text})
+		}
+	}
+
+	return files, nil
+}
@@ -0,0 +1,273 @@
+package sbom_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	fakecmd "github.com/cloudfoundry/bosh-utils/fileutil/fakes"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	boshlic "github.com/cloudfoundry/bosh-cli/v7/release/license"
+	boshpkg "github.com/cloudfoundry/bosh-cli/v7/release/pkg"
+	fakerel "github.com/cloudfoundry/bosh-cli/v7/release/releasefakes"
+	. "github.com/cloudfoundry/bosh-cli/v7/release/resource"
+	. "github.com/cloudfoundry/bosh-cli/v7/release/sbom"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+	fakereldir "github.com/cloudfoundry/bosh-cli/v7/releasedir/releasedirfakes"
+)
+
+var _ = Describe("FSGenerator", func() {
+	var (
+		blobsDir    *fakereldir.FakeBlobsDir
+		compressor  *fakecmd.FakeCompressor
+		timeService *fakeclock.FakeClock
+		fs          *fakesys.FakeFileSystem
+		release     *fakerel.FakeRelease
+		generator   FSGenerator
+
+		blobsDirPaths []string
+	)
+
+	BeforeEach(func() {
+		blobsDir = &fakereldir.FakeBlobsDir{}
+		compressor = fakecmd.NewFakeCompressor()
+		timeService = fakeclock.NewFakeClock(time.Date(2024, time.March, 1, 12, 30, 15, 500, time.UTC))
+		fs = fakesys.NewFakeFileSystem()
+
+		blobsDirPaths = nil
+
+		blobsDirFactory := func(dirPath string) boshreldir.BlobsDir {
+			blobsDirPaths = append(blobsDirPaths, dirPath)
+			return blobsDir
+		}
+
+		generator = NewFSGenerator(blobsDirFactory, compressor, timeService, fs)
+
+		release = &fakerel.FakeRelease{}
+		release.NameReturns("rel")
+		release.VersionReturns("1.0")
+		release.CommitHashWithMarkReturns("abc123+")
+
+		release.PackagesReturns([]*boshpkg.Package{
+			boshpkg.NewPackage(NewResourceWithBuiltArchive("golang", "golang-fp", "/golang.tgz", "sha256:golang-sha"), nil),
+			boshpkg.NewPackage(NewResourceWithBuiltArchive("app", "app-fp", "/app.tgz", "app-sha1"), []string{"golang"}),
+		})
+	})
+
+	Describe("Build", func() {
+		It("describes packages of the release", func() {
+			bom, err := generator.Build(release, "")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bom).To(Equal(BOM{
+				Name:       "rel",
+				Version:    "1.0",
+				CommitHash: "abc123+",
+				Created:    time.Date(2024, time.March, 1, 12, 30, 15, 0, time.UTC),
+				Components: []Component{
+					{
+						Ref:     "package/golang",
+						Kind:    KindPackage,
+						Name:    "golang",
+						Version: "golang-fp",
+						Digest:  "sha256:golang-sha",
+					},
+					{
+						Ref:       "package/app",
+						Kind:      KindPackage,
+						Name:      "app",
+						Version:   "app-fp",
+						Digest:    "app-sha1",
+						DependsOn: []string{"package/golang"},
+					},
+				},
+			}))
+
+			Expect(blobsDirPaths).To(BeEmpty())
+		})
+
+		It("uses SOURCE_DATE_EPOCH as creation time if set", func() {
+			GinkgoT().Setenv("SOURCE_DATE_EPOCH", "1000")
+
+			bom, err := generator.Build(release, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bom.Created).To(Equal(time.Unix(1000, 0).UTC()))
+		})
+
+		It("describes compiled packages with their stemcell", func() {
+			release.PackagesReturns(nil)
+			release.CompiledPackagesReturns([]*boshpkg.CompiledPackage{
+				boshpkg.NewCompiledPackageWithArchive("app", "app-fp", "ubuntu-jammy/1.1", "/app.tgz", "app-sha1", []string{"golang"}),
+			})
+
+			bom, err := generator.Build(release, "")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bom.Components).To(Equal([]Component{
+				{
+					Ref:       "compiled-package/app",
+					Kind:      KindCompiledPackage,
+					Name:      "app",
+					Version:   "app-fp",
+					Digest:    "app-sha1",
+					Stemcell:  "ubuntu-jammy/1.1",
+					DependsOn: []string{"compiled-package/golang"},
+				},
+			}))
+		})
+
+		Context("when release directory is given", func() {
+			BeforeEach(func() {
+				err := fs.WriteFileString("/dir/config/blobs.yml", "")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = fs.WriteFileString("/dir/packages/golang/spec.lock", "name: golang\nfingerprint: golang-fp\n")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = fs.WriteFileString("/dir/packages/app/spec", `---
+name: app
+files:
+- app/**/*
+- vendor/*.tgz
+excluded_files:
+- vendor/skip.tgz
+`)
+				Expect(err).ToNot(HaveOccurred())
+
+				blobsDir.BlobsReturns([]boshreldir.Blob{
+					{Path: "app/src/lib.tgz", Size: 10, SHA1: "lib-sha1"},
+					{Path: "vendor/skip.tgz", Size: 20, SHA1: "sha256:skip-sha"},
+				}, nil)
+			})
+
+			It("marks vendored packages and attributes blobs to packages that include them", func() {
+				bom, err := generator.Build(release, "/dir")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(blobsDirPaths).To(Equal([]string{"/dir"}))
+
+				Expect(bom.Components).To(Equal([]Component{
+					{
+						Ref:      "package/golang",
+						Kind:     KindPackage,
+						Name:     "golang",
+						Version:  "golang-fp",
+						Digest:   "sha256:golang-sha",
+						Vendored: true,
+					},
+					{
+						Ref:       "package/app",
+						Kind:      KindPackage,
+						Name:      "app",
+						Version:   "app-fp",
+						Digest:    "app-sha1",
+						DependsOn: []string{"package/golang"},
+						Contains:  []string{"blob/app/src/lib.tgz"},
+					},
+					{
+						Ref:    "blob/app/src/lib.tgz",
+						Kind:   KindBlob,
+						Name:   "app/src/lib.tgz",
+						Digest: "lib-sha1",
+						Size:   10,
+					},
+					{
+						Ref:    "blob/vendor/skip.tgz",
+						Kind:   KindBlob,
+						Name:   "vendor/skip.tgz",
+						Digest: "sha256:skip-sha",
+						Size:   20,
+					},
+				}))
+			})
+
+			It("skips blobs if release directory does not track any", func() {
+				err := fs.RemoveAll("/dir/config/blobs.yml")
+				Expect(err).ToNot(HaveOccurred())
+
+				bom, err := generator.Build(release, "/dir")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(bom.Components).To(HaveLen(2))
+				Expect(blobsDirPaths).To(BeEmpty())
+			})
+
+			It("returns error if listing blobs fails", func() {
+				blobsDir.BlobsReturns(nil, errors.New("fake-err"))
+
+				_, err := generator.Build(release, "/dir")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+			})
+		})
+
+		Context("when release has a license", func() {
+			BeforeEach(func() {
+				release.LicenseReturns(boshlic.NewLicense(NewResourceWithBuiltArchive("license", "lic-fp", "/license.tgz", "lic-sha1")))
+
+				fs.TempDirDir = "/extracted"
+				fs.SetGlob("/extracted/LICENSE*", []string{"/extracted/LICENSE"})
+				fs.SetGlob("/extracted/NOTICE*", []string{"/extracted/NOTICE"})
+
+				compressor.DecompressFileToDirCallBack = func() {
+					err := fs.WriteFileString("/extracted/LICENSE", "license-text")
+					Expect(err).ToNot(HaveOccurred())
+
+					err = fs.WriteFileString("/extracted/NOTICE", "notice-text")
+					Expect(err).ToNot(HaveOccurred())
+				}
+			})
+
+			It("includes license files and cleans up extracted archive", func() {
+				bom, err := generator.Build(release, "")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(compressor.DecompressFileToDirTarballPaths).To(Equal([]string{"/license.tgz"}))
+
+				Expect(bom.Licenses).To(Equal([]LicenseFile{
+					{Name: "LICENSE", Text: "license-text"},
+					{Name: "NOTICE", Text: "notice-text"},
+				}))
+
+				Expect(fs.FileExists("/extracted")).To(BeFalse())
+			})
+
+			It("returns error if extracting license fails", func() {
+				compressor.DecompressFileToDirErr = errors.New("fake-err")
+
+				_, err := generator.Build(release, "")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-err"))
+			})
+		})
+	})
+
+	Describe("Generate", func() {
+		It("returns SPDX document", func() {
+			bytes, err := generator.Generate(release, "", FormatSPDX)
+			Expect(err).ToNot(HaveOccurred())
+
+			var doc map[string]interface{}
+			Expect(json.Unmarshal(bytes, &doc)).To(Succeed())
+			Expect(doc["spdxVersion"]).To(Equal("SPDX-2.3"))
+		})
+
+		It("returns CycloneDX document", func() {
+			bytes, err := generator.Generate(release, "", FormatCycloneDX)
+			Expect(err).ToNot(HaveOccurred())
+
+			var doc map[string]interface{}
+			Expect(json.Unmarshal(bytes, &doc)).To(Succeed())
+			Expect(doc["bomFormat"]).To(Equal("CycloneDX"))
+		})
+
+		It("returns error for unknown format", func() {
+			_, err := generator.Generate(release, "", "swid")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Unknown SBOM format 'swid', expected 'spdx' or 'cyclonedx'"))
+		})
+	})
+})
@@ -0,0 +1,21 @@
+package sbom
+
+import (
+	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+)
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+const (
+	FormatSPDX      = "spdx"
+	FormatCycloneDX = "cyclonedx"
+)
+
+//counterfeiter:generate . Generator
+
+type Generator interface {
+	// Generate returns SBOM document in given format.
+	// Release directory path is optional; when given, blobs and vendored packages are included.
+	Generate(release boshrel.Release, releaseDirPath string, format string) ([]byte, error)
+}
@@ -0,0 +1,245 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// SPDX 2.3 JSON (https://spdx.github.io/spdx-spec/v2.3/)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+
+	ExtractedLicensingInfos []spdxExtractedLicense `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string         `json:"SPDXID"`
+	Name             string         `json:"name"`
+	VersionInfo      string         `json:"versionInfo,omitempty"`
+	DownloadLocation string         `json:"downloadLocation"`
+	FilesAnalyzed    bool           `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum `json:"checksums,omitempty"`
+	LicenseConcluded string         `json:"licenseConcluded"`
+	LicenseDeclared  string         `json:"licenseDeclared"`
+	CopyrightText    string         `json:"copyrightText"`
+	AttributionTexts []string       `json:"attributionTexts,omitempty"`
+	Comment          string         `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+type spdxExtractedLicense struct {
+	LicenseID     string `json:"licenseId"`
+	Name          string `json:"name"`
+	ExtractedText string `json:"extractedText"`
+}
+
+const (
+	spdxNoAssertion = "NOASSERTION"
+	spdxReleaseID   = "SPDXRef-Release"
+)
+
+var spdxInvalidIDChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+func SPDXDocument(bom BOM) ([]byte, error) {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              fmt.Sprintf("%s-%s", bom.Name, bom.Version),
+		DocumentNamespace: fmt.Sprintf("https://bosh.io/spdx/%s/%s/%s", bom.Name, bom.Version, bom.CommitHash),
+		CreationInfo: spdxCreationInfo{
+			Created:  bom.Created.Format(time.RFC3339),
+			Creators: []string{"Tool: bosh-cli"},
+		},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: spdxReleaseID},
+		},
+	}
+
+	releasePkg := spdxPackage{
+		SPDXID:           spdxReleaseID,
+		Name:             bom.Name,
+		VersionInfo:      bom.Version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxNoAssertion,
+		CopyrightText:    spdxNoAssertion,
+		Comment:          fmt.Sprintf("BOSH release built from commit %s", bom.CommitHash),
+	}
+
+	var licenseIDs []string
+
+	licenseIDGen := newSPDXIDGenerator("LicenseRef-")
+
+	for _, license := range bom.Licenses {
+		if strings.HasPrefix(license.Name, "NOTICE") {
+			releasePkg.AttributionTexts = append(releasePkg.AttributionTexts, license.Text)
+			continue
+		}
+
+		licenseID := licenseIDGen.ID(license.Name)
+		licenseIDs = append(licenseIDs, licenseID)
+
+		doc.ExtractedLicensingInfos = append(doc.ExtractedLicensingInfos, spdxExtractedLicense{
+			LicenseID:     licenseID,
+			Name:          license.Name,
+			ExtractedText: license.Text,
+		})
+	}
+
+	if len(licenseIDs) > 0 {
+		releasePkg.LicenseDeclared = strings.Join(licenseIDs, " AND ")
+	}
+
+	doc.Packages = append(doc.Packages, releasePkg)
+
+	contained := map[string]bool{}
+
+	componentIDGen := newSPDXIDGenerator("SPDXRef-", doc.SPDXID, spdxReleaseID)
+
+	for _, component := range bom.Components {
+		for _, ref := range component.Contains {
+			contained[ref] = true
+		}
+	}
+
+	for _, component := range bom.Components {
+		pkg := spdxPackage{
+			SPDXID:           componentIDGen.ID(component.Ref),
+			Name:             component.Name,
+			VersionInfo:      component.Version,
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			Comment:          componentComment(component),
+		}
+
+		if len(component.Digest) > 0 {
+			alg, value := splitDigest(component.Digest)
+			pkg.Checksums = []spdxChecksum{{Algorithm: alg, ChecksumValue: value}}
+		}
+
+		doc.Packages = append(doc.Packages, pkg)
+
+		if !contained[component.Ref] {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID: spdxReleaseID, RelationshipType: "CONTAINS", RelatedSPDXElement: pkg.SPDXID,
+			})
+		}
+
+		for _, ref := range component.DependsOn {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID: pkg.SPDXID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: componentIDGen.ID(ref),
+			})
+		}
+
+		for _, ref := range component.Contains {
+			doc.Relationships = append(doc.Relationships, spdxRelationship{
+				SPDXElementID: pkg.SPDXID, RelationshipType: "CONTAINS", RelatedSPDXElement: componentIDGen.ID(ref),
+			})
+		}
+	}
+
+	bytes, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshaling SPDX document")
+	}
+
+	return bytes, nil
+}
+
+// spdxIDGenerator keeps IDs unique even if refs only differ in characters that are not allowed in IDs
+type spdxIDGenerator struct {
+	prefix string
+	ids    map[string]string
+	taken  map[string]bool
+}
+
+func newSPDXIDGenerator(prefix string, reservedIDs ...string) *spdxIDGenerator {
+	gen := &spdxIDGenerator{prefix: prefix, ids: map[string]string{}, taken: map[string]bool{}}
+
+	for _, id := range reservedIDs {
+		gen.taken[id] = true
+	}
+
+	return gen
+}
+
+func (g *spdxIDGenerator) ID(ref string) string {
+	if id, found := g.ids[ref]; found {
+		return id
+	}
+
+	base := g.prefix + spdxInvalidIDChars.ReplaceAllString(ref, "-")
+	id := base
+
+	for i := 2; g.taken[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	g.ids[ref] = id
+	g.taken[id] = true
+
+	return id
+}
+
+func componentComment(component Component) string {
+	var parts []string
+
+	switch component.Kind {
+	case KindPackage:
+		parts = append(parts, "BOSH package")
+	case KindCompiledPackage:
+		parts = append(parts, fmt.Sprintf("BOSH package compiled against stemcell %s", component.Stemcell))
+	case KindBlob:
+		parts = append(parts, fmt.Sprintf("BOSH blob (%d bytes)", component.Size))
+	}
+
+	if component.Vendored {
+		parts = append(parts, "vendored")
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// splitDigest converts BOSH digest (SHA1 hex or '<alg>:<hex>') into algorithm name and hex value
+func splitDigest(digest string) (string, string) {
+	// Multiple ';' separated digests may be recorded; first one is used
+	digest = strings.Fields(strings.Replace(digest, ";", " ", -1))[0]
+
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) == 1 {
+		return "SHA1", digest
+	}
+
+	return strings.ToUpper(parts[0]), parts[1]
+}
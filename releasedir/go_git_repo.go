@@ -0,0 +1,377 @@
+package releasedir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+const gitIgnoreTpl = `config/private.yml
+blobs
+dev_releases
+releases/*.tgz
+releases/**/*.tgz
+.dev_builds
+.final_builds/jobs/**/*.tgz
+.final_builds/packages/**/*.tgz
+.DS_Store
+.idea
+*.swp
+*~
+*#
+#*
+`
+
+// GoGitRepo implements GitRepo without requiring git executable.
+// Release directory may be nested inside a repository, a linked worktree or a submodule.
+//
+// Dirty state is determined similarly to 'git status' and honors core.excludesFile,
+// core.fileMode and core.autocrlf from repository, global and system git config.
+// Unlike git, CRLF conversion is not affected by .gitattributes and
+// file contents are rehashed instead of trusting cached index stat info.
+// Last commit SHA is abbreviated according to core.abbrev.
+type GoGitRepo struct {
+	dirPath string
+	fs      boshsys.FileSystem
+}
+
+func NewGoGitRepo(dirPath string, fs boshsys.FileSystem) GoGitRepo {
+	return GoGitRepo{dirPath: dirPath, fs: fs}
+}
+
+func (r GoGitRepo) Init() error {
+	_, err := git.PlainInit(r.dirPath, false)
+	if err != nil && !errors.Is(err, git.ErrRepositoryAlreadyExists) {
+		return bosherr.WrapErrorf(err, "Initing git repo")
+	}
+
+	err = r.fs.WriteFileString(filepath.Join(r.dirPath, ".gitignore"), gitIgnoreTpl)
+	if err != nil {
+		return bosherr.WrapError(err, "Creating .gitignore file")
+	}
+
+	return nil
+}
+
+func (r GoGitRepo) LastCommitSHA() (string, error) {
+	repo, err := r.open()
+	if err != nil {
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return "non-git", nil
+		}
+
+		return "", bosherr.WrapErrorf(err, "Checking last commit SHA")
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "empty", nil
+		}
+
+		return "", bosherr.WrapErrorf(err, "Checking last commit SHA")
+	}
+
+	configs, err := r.configs(repo)
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Checking last commit SHA")
+	}
+
+	sha := head.Hash().String()
+
+	return sha[:abbrevLength(coreOption(configs, "abbrev", "auto"), len(sha))], nil
+}
+
+// LastCommitTime returns zero time if directory is not a git repo or has no commits
+func (r GoGitRepo) LastCommitTime() (time.Time, error) {
+	repo, err := r.open()
+	if err != nil {
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, bosherr.WrapErrorf(err, "Checking last commit time")
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, bosherr.WrapErrorf(err, "Checking last commit time")
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return time.Time{}, bosherr.WrapErrorf(err, "Reading commit '%s'", head.Hash())
+	}
+
+	return commit.Committer.When.UTC().Truncate(time.Second), nil
+}
+
+func (r GoGitRepo) MustNotBeDirty(force bool) (bool, error) {
+	repo, err := r.open()
+	if err != nil {
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return false, nil
+		}
+
+		return false, bosherr.WrapErrorf(err, "Checking status")
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false, bosherr.WrapErrorf(err, "Checking status")
+	}
+
+	configs, err := r.configs(repo)
+	if err != nil {
+		return false, bosherr.WrapErrorf(err, "Checking status")
+	}
+
+	worktree.Excludes, err = r.excludePatterns(configs)
+	if err != nil {
+		return false, bosherr.WrapErrorf(err, "Checking status")
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return false, bosherr.WrapErrorf(err, "Checking status")
+	}
+
+	fileMode := gitBool(coreOption(configs, "filemode", "true"))
+	autoCRLF := strings.ToLower(coreOption(configs, "autocrlf", "false"))
+	convertCRLF := autoCRLF == "input" || gitBool(autoCRLF)
+
+	if !fileMode || convertCRLF {
+		err = r.recheckModified(repo, worktree.Filesystem, status, fileMode, convertCRLF)
+		if err != nil {
+			return false, bosherr.WrapErrorf(err, "Checking status")
+		}
+	}
+
+	var changes []string
+
+	for path, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%c%c %s", fileStatus.Staging, fileStatus.Worktree, path))
+	}
+
+	if len(changes) > 0 {
+		if force {
+			return true, nil
+		}
+
+		sort.Slice(changes, func(i, j int) bool { return changes[i][3:] < changes[j][3:] })
+
+		return true, bosherr.Errorf("Git repository has local modifications:\n\n%s", strings.Join(changes, "\n"))
+	}
+
+	return false, nil
+}
+
+func (r GoGitRepo) open() (*git.Repository, error) {
+	return git.PlainOpenWithOptions(r.dirPath, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+}
+
+// configs returns raw repository, global and system git config in order of precedence
+func (r GoGitRepo) configs(repo *git.Repository) ([]*config.Config, error) {
+	local, err := repo.Config()
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading repository git config")
+	}
+
+	configs := []*config.Config{local.Raw}
+
+	for _, scope := range []gitconfig.Scope{gitconfig.GlobalScope, gitconfig.SystemScope} {
+		cfg, err := gitconfig.LoadConfig(scope)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Reading git config")
+		}
+
+		configs = append(configs, cfg.Raw)
+	}
+
+	return configs, nil
+}
+
+// excludePatterns reads patterns from core.excludesFile, which defaults to XDG git/ignore.
+// Patterns from .gitignore files and .git/info/exclude are read by go-git itself.
+func (r GoGitRepo) excludePatterns(configs []*config.Config) ([]gitignore.Pattern, error) {
+	path := coreOption(configs, "excludesfile", "")
+
+	if len(path) == 0 {
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if len(configHome) == 0 {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, nil
+			}
+
+			configHome = filepath.Join(home, ".config")
+		}
+
+		path = filepath.Join(configHome, "git", "ignore")
+	}
+
+	path, err := r.fs.ExpandPath(path)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Expanding excludes file path '%s'", path)
+	}
+
+	if !r.fs.FileExists(path) {
+		return nil, nil
+	}
+
+	contents, err := r.fs.ReadFileString(path)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading excludes file '%s'", path)
+	}
+
+	var patterns []gitignore.Pattern
+
+	for _, line := range strings.Split(contents, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+
+		if strings.HasPrefix(line, "#") || len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+
+	return patterns, nil
+}
+
+// recheckModified marks files as unmodified if they only differ from the index
+// by executable bit while core.fileMode is off, or by line endings while core.autocrlf is on
+func (r GoGitRepo) recheckModified(repo *git.Repository, fs billy.Filesystem, status git.Status, fileMode, autoCRLF bool) error {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return bosherr.WrapError(err, "Reading index")
+	}
+
+	for path, fileStatus := range status {
+		if fileStatus.Worktree != git.Modified {
+			continue
+		}
+
+		same, err := sameAsIndexEntry(fs, idx, path, fileMode, autoCRLF)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Comparing '%s' with index", path)
+		}
+
+		if same {
+			fileStatus.Worktree = git.Unmodified
+		}
+	}
+
+	return nil
+}
+
+func sameAsIndexEntry(fs billy.Filesystem, idx *index.Index, path string, fileMode, autoCRLF bool) (bool, error) {
+	entry, err := idx.Entry(path)
+	if err != nil {
+		if errors.Is(err, index.ErrEntryNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	info, err := fs.Lstat(path)
+	if err != nil {
+		return false, err
+	}
+
+	mode, err := filemode.NewFromOSFileMode(info.Mode())
+	if err != nil {
+		return false, err
+	}
+
+	if !isRegularFile(mode) || !isRegularFile(entry.Mode) {
+		return false, nil
+	}
+
+	if fileMode && mode != entry.Mode {
+		return false, nil
+	}
+
+	contents, err := util.ReadFile(fs, path)
+	if err != nil {
+		return false, err
+	}
+
+	// Binary files are never converted
+	if autoCRLF && !bytes.Contains(contents, []byte{0}) {
+		contents = bytes.ReplaceAll(contents, []byte("\r\n"), []byte("\n"))
+	}
+
+	return plumbing.ComputeHash(plumbing.BlobObject, contents) == entry.Hash, nil
+}
+
+func isRegularFile(mode filemode.FileMode) bool {
+	return mode.IsRegular() || mode == filemode.Executable
+}
+
+// coreOption returns value from the first config that sets the option in core section
+func coreOption(configs []*config.Config, name, defaultValue string) string {
+	for _, cfg := range configs {
+		if cfg != nil && cfg.Section("core").Options.Has(name) {
+			return cfg.Section("core").Option(name)
+		}
+	}
+
+	return defaultValue
+}
+
+// abbrevLength interprets core.abbrev like 'git rev-parse --short'.
+// Unlike git, 'auto' always uses the minimum of 7 characters
+// instead of growing with the number of objects in the repository.
+func abbrevLength(value string, fullLength int) int {
+	switch strings.ToLower(value) {
+	case "no", "false", "off":
+		return fullLength
+	}
+
+	length, err := strconv.Atoi(value)
+	if err != nil {
+		return 7
+	}
+
+	return max(4, min(length, fullLength))
+}
+
+func gitBool(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	default:
+		return false
+	}
+}
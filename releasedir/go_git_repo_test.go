@@ -0,0 +1,339 @@
+package releasedir_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+)
+
+var _ = Describe("GoGitRepo", func() {
+	var (
+		dirPath string
+		fs      boshsys.FileSystem
+		gitRepo GoGitRepo
+	)
+
+	BeforeEach(func() {
+		dirPath = GinkgoT().TempDir()
+		fs = boshsys.NewOsFileSystem(boshlog.NewLogger(boshlog.LevelNone))
+
+		// Keep user's git config out of the way
+		GinkgoT().Setenv("HOME", GinkgoT().TempDir())
+		GinkgoT().Setenv("XDG_CONFIG_HOME", "")
+		gitRepo = NewGoGitRepo(dirPath, fs)
+	})
+
+	writeFile := func(path, content string) {
+		err := fs.WriteFileString(filepath.Join(dirPath, path), content)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	commitAll := func(when time.Time) string {
+		repo, err := git.PlainOpen(dirPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		worktree, err := repo.Worktree()
+		Expect(err).ToNot(HaveOccurred())
+
+		err = worktree.AddWithOptions(&git.AddOptions{All: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		sig := &object.Signature{Name: "name", Email: "email@example.com", When: when}
+
+		hash, err := worktree.Commit("msg", &git.CommitOptions{Author: sig, Committer: sig})
+		Expect(err).ToNot(HaveOccurred())
+
+		return hash.String()
+	}
+
+	setCoreOption := func(name, value string) {
+		repo, err := git.PlainOpen(dirPath)
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg, err := repo.Config()
+		Expect(err).ToNot(HaveOccurred())
+
+		cfg.Raw.Section("core").SetOption(name, value)
+
+		Expect(repo.SetConfig(cfg)).To(Succeed())
+	}
+
+	// addLinkedWorktree lays out worktree the same way as 'git worktree add --detach'
+	addLinkedWorktree := func(sha string) string {
+		worktreePath := filepath.Join(GinkgoT().TempDir(), "worktree")
+		adminPath := filepath.Join(dirPath, ".git", "worktrees", "worktree")
+
+		Expect(fs.WriteFileString(filepath.Join(adminPath, "HEAD"), sha+"\n")).To(Succeed())
+		Expect(fs.WriteFileString(filepath.Join(adminPath, "commondir"), "../..\n")).To(Succeed())
+		Expect(fs.WriteFileString(filepath.Join(adminPath, "gitdir"), filepath.Join(worktreePath, ".git")+"\n")).To(Succeed())
+		Expect(fs.WriteFileString(filepath.Join(worktreePath, ".git"), "gitdir: "+adminPath+"\n")).To(Succeed())
+
+		repo, err := git.PlainOpenWithOptions(worktreePath, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		worktree, err := repo.Worktree()
+		Expect(err).ToNot(HaveOccurred())
+
+		err = worktree.Reset(&git.ResetOptions{Commit: plumbing.NewHash(sha), Mode: git.HardReset})
+		Expect(err).ToNot(HaveOccurred())
+
+		return worktreePath
+	}
+
+	Describe("Init", func() {
+		It("inits directory as git repo with gitignore", func() {
+			err := gitRepo.Init()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.FileExists(filepath.Join(dirPath, ".git", "HEAD"))).To(BeTrue())
+			Expect(fs.ReadFileString(filepath.Join(dirPath, ".gitignore"))).To(ContainSubstring("config/private.yml\n"))
+		})
+
+		It("does not fail if directory is already a git repo", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			Expect(gitRepo.Init()).To(Succeed())
+		})
+	})
+
+	Describe("LastCommitSHA", func() {
+		It("returns 'non-git' if directory is not a git repo", func() {
+			Expect(gitRepo.LastCommitSHA()).To(Equal("non-git"))
+		})
+
+		It("returns 'empty' if repo has no commits", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			Expect(gitRepo.LastCommitSHA()).To(Equal("empty"))
+		})
+
+		It("returns abbreviated SHA of the last commit", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			sha := commitAll(time.Now())
+
+			Expect(gitRepo.LastCommitSHA()).To(Equal(sha[:7]))
+		})
+
+		It("respects abbreviation length configured via core.abbrev", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			sha := commitAll(time.Now())
+
+			setCoreOption("abbrev", "12")
+			Expect(gitRepo.LastCommitSHA()).To(Equal(sha[:12]))
+
+			setCoreOption("abbrev", "2")
+			Expect(gitRepo.LastCommitSHA()).To(Equal(sha[:4]))
+
+			setCoreOption("abbrev", "no")
+			Expect(gitRepo.LastCommitSHA()).To(Equal(sha))
+		})
+
+		It("finds repo when release directory is nested inside it", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			writeFile("release/config/final.yml", "name: rel\n")
+			sha := commitAll(time.Now())
+
+			nestedRepo := NewGoGitRepo(filepath.Join(dirPath, "release"), fs)
+			Expect(nestedRepo.LastCommitSHA()).To(Equal(sha[:7]))
+		})
+
+		It("follows .git file pointing to separate git directory as used by submodules", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			sha := commitAll(time.Now())
+
+			gitDir := filepath.Join(GinkgoT().TempDir(), "modules", "release")
+			Expect(os.MkdirAll(filepath.Dir(gitDir), 0755)).To(Succeed())
+			Expect(os.Rename(filepath.Join(dirPath, ".git"), gitDir)).To(Succeed())
+			writeFile(".git", "gitdir: "+gitDir+"\n")
+
+			Expect(gitRepo.LastCommitSHA()).To(Equal(sha[:7]))
+		})
+
+		It("finds repo when release directory is a linked worktree", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			commitAll(time.Now())
+			writeFile("tracked", "content")
+			sha := commitAll(time.Now())
+
+			worktreeRepo := NewGoGitRepo(addLinkedWorktree(sha), fs)
+			Expect(worktreeRepo.LastCommitSHA()).To(Equal(sha[:7]))
+		})
+	})
+
+	Describe("LastCommitTime", func() {
+		It("returns zero time if directory is not a git repo", func() {
+			Expect(gitRepo.LastCommitTime()).To(Equal(time.Time{}))
+		})
+
+		It("returns zero time if repo has no commits", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			Expect(gitRepo.LastCommitTime()).To(Equal(time.Time{}))
+		})
+
+		It("returns committer time of the last commit in UTC", func() {
+			Expect(gitRepo.Init()).To(Succeed())
+			commitAll(time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("x", 3600)))
+
+			Expect(gitRepo.LastCommitTime()).To(Equal(time.Date(2020, 1, 2, 2, 4, 5, 0, time.UTC)))
+		})
+	})
+
+	Describe("MustNotBeDirty", func() {
+		It("returns false if directory is not a git repo", func() {
+			dirty, err := gitRepo.MustNotBeDirty(false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dirty).To(BeFalse())
+		})
+
+		Context("when repo has commits", func() {
+			BeforeEach(func() {
+				Expect(gitRepo.Init()).To(Succeed())
+				writeFile("tracked", "content")
+				writeFile("other", "content")
+				commitAll(time.Now())
+			})
+
+			It("returns false if there are no changes", func() {
+				dirty, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+			})
+
+			It("ignores files matched by gitignore", func() {
+				writeFile("blobs/blob.tgz", "content")
+				writeFile("dev_releases/rel/rel-1.yml", "content")
+
+				dirty, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+			})
+
+			It("returns error listing each changed file", func() {
+				writeFile("tracked", "changed")
+				writeFile("untracked", "content")
+				Expect(os.Remove(filepath.Join(dirPath, "other"))).To(Succeed())
+
+				dirty, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Git repository has local modifications:\n\n" +
+					" D other\n" +
+					" M tracked\n" +
+					"?? untracked"))
+				Expect(dirty).To(BeTrue())
+			})
+
+			It("ignores files matched by core.excludesFile", func() {
+				excludesPath := filepath.Join(GinkgoT().TempDir(), "excludes")
+				Expect(fs.WriteFileString(excludesPath, "# comment\n*.log\n")).To(Succeed())
+				setCoreOption("excludesFile", excludesPath)
+
+				writeFile("debug.log", "content")
+
+				dirty, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+			})
+
+			It("ignores files matched by default global excludes file", func() {
+				configHome := GinkgoT().TempDir()
+				GinkgoT().Setenv("XDG_CONFIG_HOME", configHome)
+				Expect(fs.WriteFileString(filepath.Join(configHome, "git", "ignore"), "*.log\n")).To(Succeed())
+
+				writeFile("debug.log", "content")
+
+				dirty, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+			})
+
+			It("reports executable bit changes", func() {
+				Expect(os.Chmod(filepath.Join(dirPath, "tracked"), 0755)).To(Succeed())
+
+				_, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Git repository has local modifications:\n\n M tracked"))
+			})
+
+			It("ignores executable bit changes if core.fileMode is false", func() {
+				setCoreOption("fileMode", "false")
+				Expect(os.Chmod(filepath.Join(dirPath, "tracked"), 0755)).To(Succeed())
+
+				dirty, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+
+				writeFile("tracked", "changed")
+
+				_, err = gitRepo.MustNotBeDirty(false)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Git repository has local modifications:\n\n M tracked"))
+			})
+
+			It("ignores CRLF line endings if core.autocrlf is enabled", func() {
+				writeFile("multiline", "line1\nline2\n")
+				commitAll(time.Now())
+
+				setCoreOption("autocrlf", "true")
+				writeFile("multiline", "line1\r\nline2\r\n")
+
+				dirty, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+
+				writeFile("multiline", "line1\r\nchanged\r\n")
+
+				_, err = gitRepo.MustNotBeDirty(false)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Git repository has local modifications:\n\n M multiline"))
+			})
+
+			It("reports CRLF line endings if core.autocrlf is disabled", func() {
+				writeFile("multiline", "line1\nline2\n")
+				commitAll(time.Now())
+
+				writeFile("multiline", "line1\r\nline2\r\n")
+
+				_, err := gitRepo.MustNotBeDirty(false)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Git repository has local modifications:\n\n M multiline"))
+			})
+
+			It("checks status of linked worktree", func() {
+				writeFile("worktree-file", "content")
+				sha := commitAll(time.Now())
+				worktreePath := addLinkedWorktree(sha)
+				worktreeRepo := NewGoGitRepo(worktreePath, fs)
+
+				dirty, err := worktreeRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+
+				Expect(fs.WriteFileString(filepath.Join(worktreePath, "tracked"), "changed")).To(Succeed())
+
+				_, err = worktreeRepo.MustNotBeDirty(false)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Git repository has local modifications:\n\n M tracked"))
+
+				dirty, err = gitRepo.MustNotBeDirty(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeFalse())
+			})
+
+			It("returns true without error if forced", func() {
+				writeFile("tracked", "changed")
+
+				dirty, err := gitRepo.MustNotBeDirty(true)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(dirty).To(BeTrue())
+			})
+		})
+	})
+})
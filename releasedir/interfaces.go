@@ -2,6 +2,7 @@ package releasedir
 
 import (
 	"io"
+	"time"
 
 	boshcrypto "github.com/cloudfoundry/bosh-utils/crypto"
 	semver "github.com/cppforlife/go-semi-semantic/version"
@@ -65,6 +66,7 @@ type Generator interface {
 type GitRepo interface {
 	Init() error
 	LastCommitSHA() (string, error)
+	LastCommitTime() (time.Time, error)
 	MustNotBeDirty(force bool) (dirty bool, err error)
 }
 
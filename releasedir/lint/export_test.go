@@ -0,0 +1,5 @@
+package lint
+
+var ScanPropertyRefs = scanPropertyRefs
+
+type PropertyRef = propertyRef
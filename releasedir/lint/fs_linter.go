@@ -0,0 +1,430 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshjob "github.com/cloudfoundry/bosh-cli/v7/release/job"
+	boshpkg "github.com/cloudfoundry/bosh-cli/v7/release/pkg"
+	boshpkgman "github.com/cloudfoundry/bosh-cli/v7/release/pkg/manifest"
+	boshres "github.com/cloudfoundry/bosh-cli/v7/release/resource"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+)
+
+type FSLinter struct {
+	dirPath string
+
+	jobDirReader boshjob.DirReader
+	blobsDir     boshreldir.BlobsDir
+
+	fs boshsys.FileSystem
+}
+
+type lintPackage struct {
+	name     string
+	specPath string
+	spec     yamlDoc
+	deps     []string
+	manifest *boshpkgman.Manifest // nil for vendored packages
+}
+
+func NewFSLinter(
+	dirPath string,
+	jobDirReader boshjob.DirReader,
+	blobsDir boshreldir.BlobsDir,
+	fs boshsys.FileSystem,
+) FSLinter {
+	return FSLinter{
+		dirPath:      dirPath,
+		jobDirReader: jobDirReader,
+		blobsDir:     blobsDir,
+		fs:           fs,
+	}
+}
+
+func (l FSLinter) Lint() ([]Finding, error) {
+	var findings []Finding
+
+	pkgs, pkgFindings, err := l.lintPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	findings = append(findings, pkgFindings...)
+
+	jobFindings, err := l.lintJobs(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	findings = append(findings, jobFindings...)
+
+	blobFindings, err := l.lintBlobs(pkgs)
+	if err != nil {
+		return nil, err
+	}
+
+	findings = append(findings, blobFindings...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Location, findings[j].Location
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return findings, nil
+}
+
+func (l FSLinter) lintPackages() (map[string]lintPackage, []Finding, error) {
+	var findings []Finding
+
+	pkgDirs, err := l.fs.Glob(filepath.Join(l.dirPath, "packages", "*"))
+	if err != nil {
+		return nil, nil, bosherr.WrapError(err, "Listing packages in directory")
+	}
+
+	sort.Strings(pkgDirs)
+
+	pkgs := map[string]lintPackage{}
+
+	for _, pkgDir := range pkgDirs {
+		pkg := lintPackage{name: filepath.Base(pkgDir)}
+
+		lockPath := filepath.Join(pkgDir, "spec.lock")
+		specPath := filepath.Join(pkgDir, "spec")
+
+		// Packages are not read fully since fingerprinting requires synced blobs
+		if l.fs.FileExists(lockPath) {
+			pkg.specPath = l.relPath(lockPath)
+			pkg.spec = readYAMLDoc(lockPath, l.fs)
+
+			lock, err := boshpkgman.NewManifestLockFromPath(lockPath, l.fs)
+			if err != nil {
+				findings = append(findings, l.finding(RuleInvalidPackage, pkg.specPath, 1, 1, err.Error()))
+				continue
+			}
+
+			pkg.name = lock.Name
+			pkg.deps = lock.Dependencies
+		} else {
+			pkg.specPath = l.relPath(specPath)
+			pkg.spec = readYAMLDoc(specPath, l.fs)
+
+			manifest, err := boshpkgman.NewManifestFromPath(specPath, l.fs)
+			if err != nil {
+				findings = append(findings, l.finding(RuleInvalidPackage, pkg.specPath, 1, 1, err.Error()))
+				continue
+			}
+
+			pkg.manifest = &manifest
+			pkg.name = manifest.Name
+			pkg.deps = manifest.Dependencies
+		}
+
+		pkgs[pkg.name] = pkg
+	}
+
+	for _, name := range sortedPackageNames(pkgs) {
+		pkg := pkgs[name]
+
+		for _, dep := range pkg.deps {
+			if _, found := pkgs[dep]; !found {
+				line, col := pkg.spec.ItemPosition("dependencies", dep)
+				findings = append(findings, l.finding(RuleMissingPackage, pkg.specPath, line, col,
+					fmt.Sprintf("Package '%s' depends on package '%s' which does not exist", pkg.name, dep)))
+			}
+		}
+	}
+
+	findings = append(findings, l.lintCircularDependencies(pkgs)...)
+
+	return pkgs, findings, nil
+}
+
+func (l FSLinter) lintCircularDependencies(pkgs map[string]lintPackage) []Finding {
+	var compilables []boshpkg.Compilable
+	var packages []*boshpkg.Package
+
+	for _, name := range sortedPackageNames(pkgs) {
+		var deps []string
+
+		for _, dep := range pkgs[name].deps {
+			if _, found := pkgs[dep]; found {
+				deps = append(deps, dep)
+			}
+		}
+
+		packages = append(packages, boshpkg.NewPackage(boshres.NewExistingResource(name, "", ""), deps))
+	}
+
+	for _, pkg := range packages {
+		_ = pkg.AttachDependencies(packages) // missing dependencies are already excluded
+		compilables = append(compilables, pkg)
+	}
+
+	_, err := boshpkg.Sort(compilables)
+	if err == nil {
+		return nil
+	}
+
+	var findings []Finding
+
+	for _, cycle := range findCycles(pkgs) {
+		pkg := pkgs[cycle[0]]
+		line, col := pkg.spec.ItemPosition("dependencies", cycle[1])
+
+		findings = append(findings, l.finding(RuleCircularDependency, pkg.specPath, line, col,
+			fmt.Sprintf("Package '%s' depends on itself via '%s'", pkg.name, strings.Join(cycle, " -> "))))
+	}
+
+	return findings
+}
+
+func (l FSLinter) lintJobs(pkgs map[string]lintPackage) ([]Finding, error) {
+	var findings []Finding
+
+	jobDirs, err := l.fs.Glob(filepath.Join(l.dirPath, "jobs", "*"))
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Listing jobs in directory")
+	}
+
+	sort.Strings(jobDirs)
+
+	for _, jobDir := range jobDirs {
+		specPath := filepath.Join(jobDir, "spec")
+		relSpecPath := l.relPath(specPath)
+
+		job, err := l.jobDirReader.Read(jobDir)
+		if err != nil {
+			findings = append(findings, l.finding(RuleInvalidJob, relSpecPath, 1, 1, err.Error()))
+			continue
+		}
+
+		spec := readYAMLDoc(specPath, l.fs)
+
+		for _, pkgName := range job.PackageNames {
+			if _, found := pkgs[pkgName]; !found {
+				line, col := spec.ItemPosition("packages", pkgName)
+				findings = append(findings, l.finding(RuleMissingPackage, relSpecPath, line, col,
+					fmt.Sprintf("Job '%s' references package '%s' which does not exist", job.Name(), pkgName)))
+			}
+		}
+
+		propFindings, err := l.lintJobProperties(job, jobDir, relSpecPath, spec)
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, propFindings...)
+	}
+
+	return findings, nil
+}
+
+func (l FSLinter) lintJobProperties(job *boshjob.Job, jobDir, relSpecPath string, spec yamlDoc) ([]Finding, error) {
+	var findings []Finding
+	var templatePaths []string
+
+	for src := range job.Templates {
+		templatePaths = append(templatePaths, filepath.Join(jobDir, "templates", src))
+	}
+
+	sort.Strings(templatePaths)
+
+	monitPath := filepath.Join(jobDir, "monit")
+	if l.fs.FileExists(monitPath) {
+		templatePaths = append(templatePaths, monitPath)
+	}
+
+	// Properties exposed via links are used even if templates do not reference them
+	usedProps := spec.Strings("provides", "*", "properties")
+
+	for _, templatePath := range templatePaths {
+		content, err := l.fs.ReadFileString(templatePath)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Reading job template '%s'", templatePath)
+		}
+
+		for _, ref := range scanPropertyRefs(content) {
+			usedProps = append(usedProps, ref.Name)
+
+			if !propertyMatchesAny(ref.Name, propertyNames(job.Properties)) {
+				findings = append(findings, l.finding(RuleUndeclaredProperty, l.relPath(templatePath), ref.Line, ref.Column,
+					fmt.Sprintf("Property '%s' is not declared in spec of job '%s'", ref.Name, job.Name())))
+			}
+		}
+	}
+
+	for _, propName := range propertyNames(job.Properties) {
+		if !propertyMatchesAny(propName, usedProps) {
+			line, col := spec.KeyPosition("properties", propName)
+			findings = append(findings, l.finding(RuleUnusedProperty, relSpecPath, line, col,
+				fmt.Sprintf("Property '%s' of job '%s' is not used by any template", propName, job.Name())))
+		}
+	}
+
+	return findings, nil
+}
+
+func (l FSLinter) lintBlobs(pkgs map[string]lintPackage) ([]Finding, error) {
+	blobsPath := filepath.Join(l.dirPath, "config", "blobs.yml")
+
+	if !l.fs.FileExists(blobsPath) {
+		return nil, nil
+	}
+
+	blobs, err := l.blobsDir.Blobs()
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Listing blobs")
+	}
+
+	var findings []Finding
+
+	doc := readYAMLDoc(blobsPath, l.fs)
+
+	for _, blob := range blobs {
+		var included bool
+
+		for _, pkg := range pkgs {
+			if pkg.manifest != nil && pkg.manifest.IncludesFile(blob.Path) {
+				included = true
+				break
+			}
+		}
+
+		if !included {
+			line, col := doc.KeyPosition(blob.Path)
+			findings = append(findings, l.finding(RuleUnusedBlob, l.relPath(blobsPath), line, col,
+				fmt.Sprintf("Blob '%s' is not included by files of any package", blob.Path)))
+		}
+	}
+
+	return findings, nil
+}
+
+func (l FSLinter) finding(rule Rule, path string, line, col int, msg string) Finding {
+	return Finding{Rule: rule, Message: msg, Location: Location{Path: path, Line: line, Column: col}}
+}
+
+func (l FSLinter) relPath(path string) string {
+	relPath, err := filepath.Rel(l.dirPath, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+
+	return filepath.ToSlash(relPath)
+}
+
+// propertyMatchesAny checks whether property is the same as, nested under,
+// or parent of any of the given names (p("a") makes 'a.b' used and vice versa)
+func propertyMatchesAny(name string, names []string) bool {
+	for _, other := range names {
+		if name == other || strings.HasPrefix(name, other+".") || strings.HasPrefix(other, name+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func propertyNames(props map[string]boshjob.PropertyDefinition) []string {
+	var names []string
+
+	for name := range props {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func sortedPackageNames(pkgs map[string]lintPackage) []string {
+	var names []string
+
+	for name := range pkgs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// findCycles returns each dependency cycle once, starting and
+// ending with its lexically smallest package name
+func findCycles(pkgs map[string]lintPackage) [][]string {
+	var cycles [][]string
+
+	seen := map[string]bool{}
+	visited := map[string]bool{}
+
+	var stack []string
+	onStack := map[string]bool{}
+
+	var visit func(name string)
+
+	visit = func(name string) {
+		visited[name] = true
+		onStack[name] = true
+		stack = append(stack, name)
+
+		for _, dep := range pkgs[name].deps {
+			if _, found := pkgs[dep]; !found {
+				continue
+			}
+
+			if onStack[dep] {
+				var start int
+				for i, n := range stack {
+					if n == dep {
+						start = i
+					}
+				}
+
+				cycle := rotateCycle(stack[start:])
+				key := strings.Join(cycle, " ")
+
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, append(cycle, cycle[0]))
+				}
+			} else if !visited[dep] {
+				visit(dep)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		onStack[name] = false
+	}
+
+	for _, name := range sortedPackageNames(pkgs) {
+		if !visited[name] {
+			visit(name)
+		}
+	}
+
+	return cycles
+}
+
+func rotateCycle(names []string) []string {
+	minIdx := 0
+
+	for i, name := range names {
+		if name < names[minIdx] {
+			minIdx = i
+		}
+	}
+
+	return append(append([]string{}, names[minIdx:]...), names[:minIdx]...)
+}
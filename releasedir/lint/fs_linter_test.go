@@ -0,0 +1,237 @@
+package lint_test
+
+import (
+	"errors"
+	"path/filepath"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	boshjob "github.com/cloudfoundry/bosh-cli/v7/release/job"
+	fakejob "github.com/cloudfoundry/bosh-cli/v7/release/job/jobfakes"
+	boshres "github.com/cloudfoundry/bosh-cli/v7/release/resource"
+	boshreldir "github.com/cloudfoundry/bosh-cli/v7/releasedir"
+	. "github.com/cloudfoundry/bosh-cli/v7/releasedir/lint"
+	fakereldir "github.com/cloudfoundry/bosh-cli/v7/releasedir/releasedirfakes"
+)
+
+var _ = Describe("FSLinter", func() {
+	var (
+		jobDirReader *fakejob.FakeDirReader
+		blobsDir     *fakereldir.FakeBlobsDir
+		fs           *fakesys.FakeFileSystem
+		linter       FSLinter
+
+		jobs map[string]*boshjob.Job
+	)
+
+	BeforeEach(func() {
+		jobDirReader = &fakejob.FakeDirReader{}
+		blobsDir = &fakereldir.FakeBlobsDir{}
+		fs = fakesys.NewFakeFileSystem()
+		linter = NewFSLinter(filepath.Join("/", "dir"), jobDirReader, blobsDir, fs)
+
+		jobs = map[string]*boshjob.Job{}
+
+		jobDirReader.ReadStub = func(path string) (*boshjob.Job, error) {
+			job, found := jobs[filepath.Base(path)]
+			if !found {
+				return nil, errors.New("fake-job-err")
+			}
+			return job, nil
+		}
+
+		fs.SetGlob(filepath.Join("/", "dir", "packages", "*"), []string{})
+		fs.SetGlob(filepath.Join("/", "dir", "jobs", "*"), []string{})
+	})
+
+	addPackage := func(name, spec string) string {
+		path := filepath.Join("/", "dir", "packages", name)
+		err := fs.WriteFileString(filepath.Join(path, "spec"), spec)
+		Expect(err).ToNot(HaveOccurred())
+		return path
+	}
+
+	addJob := func(name, spec string, templates map[string]string, props []string, pkgs []string) string {
+		path := filepath.Join("/", "dir", "jobs", name)
+		err := fs.WriteFileString(filepath.Join(path, "spec"), spec)
+		Expect(err).ToNot(HaveOccurred())
+
+		job := boshjob.NewJob(boshres.NewExistingResource(name, "", ""))
+		job.Templates = map[string]string{}
+		job.Properties = map[string]boshjob.PropertyDefinition{}
+		job.PackageNames = pkgs
+
+		for src, content := range templates {
+			job.Templates[src] = src
+			err := fs.WriteFileString(filepath.Join(path, "templates", src), content)
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		for _, prop := range props {
+			job.Properties[prop] = boshjob.PropertyDefinition{}
+		}
+
+		jobs[name] = job
+
+		return path
+	}
+
+	It("returns no findings for a consistent release", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "packages", "*"), []string{
+			addPackage("pkg", "---\nname: pkg\nfiles: [src/**/*]\n"),
+		})
+		fs.SetGlob(filepath.Join("/", "dir", "jobs", "*"), []string{
+			addJob("job", "---\nname: job\npackages: [pkg]\nproperties:\n  port: {}\n",
+				map[string]string{"ctl.erb": "<%= p('port') %>"}, []string{"port"}, []string{"pkg"}),
+		})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("reports missing packages referenced by jobs and packages", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "packages", "*"), []string{
+			addPackage("pkg", "---\nname: pkg\ndependencies:\n- other\n"),
+		})
+		fs.SetGlob(filepath.Join("/", "dir", "jobs", "*"), []string{
+			addJob("job", "---\nname: job\npackages:\n- pkg\n- absent\n", nil, nil, []string{"pkg", "absent"}),
+		})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{
+			{
+				Rule:     RuleMissingPackage,
+				Message:  "Job 'job' references package 'absent' which does not exist",
+				Location: Location{Path: "jobs/job/spec", Line: 5, Column: 3},
+			},
+			{
+				Rule:     RuleMissingPackage,
+				Message:  "Package 'pkg' depends on package 'other' which does not exist",
+				Location: Location{Path: "packages/pkg/spec", Line: 4, Column: 3},
+			},
+		}))
+	})
+
+	It("takes vendored package dependencies from spec lock", func() {
+		path := filepath.Join("/", "dir", "packages", "vendored")
+		err := fs.WriteFileString(filepath.Join(path, "spec.lock"), "---\nname: vendored\nfingerprint: fp\ndependencies:\n- other\n")
+		Expect(err).ToNot(HaveOccurred())
+
+		fs.SetGlob(filepath.Join("/", "dir", "packages", "*"), []string{path})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{{
+			Rule:     RuleMissingPackage,
+			Message:  "Package 'vendored' depends on package 'other' which does not exist",
+			Location: Location{Path: "packages/vendored/spec.lock", Line: 5, Column: 3},
+		}}))
+	})
+
+	It("reports packages with invalid spec", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "packages", "*"), []string{
+			addPackage("broken", "-"),
+		})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Rule).To(Equal(RuleInvalidPackage))
+		Expect(findings[0].Location).To(Equal(Location{Path: "packages/broken/spec", Line: 1, Column: 1}))
+	})
+
+	It("reports circular package dependencies once per cycle", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "packages", "*"), []string{
+			addPackage("a", "---\nname: a\ndependencies: [b]\n"),
+			addPackage("b", "---\nname: b\ndependencies: [a]\n"),
+		})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{{
+			Rule:     RuleCircularDependency,
+			Message:  "Package 'a' depends on itself via 'a -> b -> a'",
+			Location: Location{Path: "packages/a/spec", Line: 3, Column: 16},
+		}}))
+	})
+
+	It("reports undeclared and unused properties", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "jobs", "*"), []string{
+			addJob("job", "---\nname: job\nproperties:\n  used.nested: {}\n  unused: {}\n",
+				map[string]string{"config.erb": "<%= p('used') %>\n<%= p('undeclared') %>"},
+				[]string{"used.nested", "unused"}, nil),
+		})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{
+			{
+				Rule:     RuleUnusedProperty,
+				Message:  "Property 'unused' of job 'job' is not used by any template",
+				Location: Location{Path: "jobs/job/spec", Line: 5, Column: 3},
+			},
+			{
+				Rule:     RuleUndeclaredProperty,
+				Message:  "Property 'undeclared' is not declared in spec of job 'job'",
+				Location: Location{Path: "jobs/job/templates/config.erb", Line: 2, Column: 7},
+			},
+		}))
+	})
+
+	It("considers properties provided via links as used", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "jobs", "*"), []string{
+			addJob("job", "---\nname: job\nprovides:\n- name: conn\n  type: conn\n  properties: [port]\nproperties:\n  port: {}\n",
+				nil, []string{"port"}, nil),
+		})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(BeEmpty())
+	})
+
+	It("reports jobs that cannot be read", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "jobs", "*"), []string{filepath.Join("/", "dir", "jobs", "broken")})
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{{
+			Rule:     RuleInvalidJob,
+			Message:  "fake-job-err",
+			Location: Location{Path: "jobs/broken/spec", Line: 1, Column: 1},
+		}}))
+	})
+
+	It("reports blobs not included by any package", func() {
+		fs.SetGlob(filepath.Join("/", "dir", "packages", "*"), []string{
+			addPackage("pkg", "---\nname: pkg\nfiles: [used/*.tgz]\n"),
+		})
+
+		err := fs.WriteFileString(filepath.Join("/", "dir", "config", "blobs.yml"), "used/a.tgz:\n  size: 1\nunused.tgz:\n  size: 1\n")
+		Expect(err).ToNot(HaveOccurred())
+
+		blobsDir.BlobsReturns([]boshreldir.Blob{{Path: "used/a.tgz"}, {Path: "unused.tgz"}}, nil)
+
+		findings, err := linter.Lint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(findings).To(Equal([]Finding{{
+			Rule:     RuleUnusedBlob,
+			Message:  "Blob 'unused.tgz' is not included by files of any package",
+			Location: Location{Path: "config/blobs.yml", Line: 3, Column: 1},
+		}}))
+	})
+
+	It("returns error if blobs cannot be listed", func() {
+		err := fs.WriteFileString(filepath.Join("/", "dir", "config", "blobs.yml"), "")
+		Expect(err).ToNot(HaveOccurred())
+
+		blobsDir.BlobsReturns(nil, errors.New("fake-err"))
+
+		_, err = linter.Lint()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("fake-err"))
+	})
+})
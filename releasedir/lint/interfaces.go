@@ -0,0 +1,56 @@
+package lint
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . Linter
+
+type Linter interface {
+	// Lint returns problems found in the release directory sorted by location
+	Lint() ([]Finding, error)
+}
+
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+type Rule struct {
+	ID          string
+	Description string
+	Level       string
+}
+
+var (
+	RuleInvalidJob         = Rule{"invalid-job", "Job cannot be read", LevelError}
+	RuleInvalidPackage     = Rule{"invalid-package", "Package cannot be read", LevelError}
+	RuleMissingPackage     = Rule{"missing-package", "Job or package spec references package that does not exist", LevelError}
+	RuleCircularDependency = Rule{"circular-dependency", "Packages depend on each other in a cycle", LevelError}
+	RuleUndeclaredProperty = Rule{"undeclared-property", "Template references property that is not declared in job spec", LevelError}
+	RuleUnusedProperty     = Rule{"unused-property", "Property declared in job spec is not used by any template", LevelWarning}
+	RuleUnusedBlob         = Rule{"unused-blob", "Blob is not included by any package", LevelWarning}
+)
+
+// Rules lists all checks performed by the linter
+var Rules = []Rule{
+	RuleInvalidJob,
+	RuleInvalidPackage,
+	RuleMissingPackage,
+	RuleCircularDependency,
+	RuleUndeclaredProperty,
+	RuleUnusedProperty,
+	RuleUnusedBlob,
+}
+
+type Finding struct {
+	Rule     Rule
+	Message  string
+	Location Location
+}
+
+// Location points to a file relative to the release directory; line and column start at 1
+type Location struct {
+	Path   string
+	Line   int
+	Column int
+}
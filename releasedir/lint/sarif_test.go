@@ -0,0 +1,53 @@
+package lint_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/releasedir/lint"
+)
+
+var _ = Describe("SARIFDocument", func() {
+	It("returns SARIF log with all rules and given findings", func() {
+		bytes, err := SARIFDocument([]Finding{{
+			Rule:     RuleUnusedBlob,
+			Message:  "msg",
+			Location: Location{Path: "config/blobs.yml", Line: 3, Column: 1},
+		}})
+		Expect(err).ToNot(HaveOccurred())
+
+		var doc map[string]interface{}
+		Expect(json.Unmarshal(bytes, &doc)).To(Succeed())
+
+		Expect(doc["version"]).To(Equal("2.1.0"))
+
+		run := doc["runs"].([]interface{})[0].(map[string]interface{})
+		rules := run["tool"].(map[string]interface{})["driver"].(map[string]interface{})["rules"].([]interface{})
+		Expect(rules).To(HaveLen(len(Rules)))
+
+		Expect(run["results"]).To(Equal([]interface{}{
+			map[string]interface{}{
+				"ruleId":    "unused-blob",
+				"ruleIndex": float64(6),
+				"level":     "warning",
+				"message":   map[string]interface{}{"text": "msg"},
+				"locations": []interface{}{
+					map[string]interface{}{
+						"physicalLocation": map[string]interface{}{
+							"artifactLocation": map[string]interface{}{"uri": "config/blobs.yml", "uriBaseId": "%SRCROOT%"},
+							"region":           map[string]interface{}{"startLine": float64(3), "startColumn": float64(1)},
+						},
+					},
+				},
+			},
+		}))
+	})
+
+	It("returns empty results when there are no findings", func() {
+		bytes, err := SARIFDocument(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(bytes)).To(ContainSubstring(`"results": []`))
+	})
+})
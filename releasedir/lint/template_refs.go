@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// propertyRef is a property name referenced by a job template via p() or if_p()
+type propertyRef struct {
+	Name   string
+	Line   int
+	Column int
+}
+
+// Calls on other objects (e.g. link("db").p("port")) are not job properties
+var propertyCallRegex = regexp.MustCompile(`(^|[^\w.])(if_p|p)\s*\(`)
+
+func scanPropertyRefs(content string) []propertyRef {
+	var refs []propertyRef
+
+	for _, match := range propertyCallRegex.FindAllStringSubmatchIndex(content, -1) {
+		funcName := content[match[4]:match[5]]
+
+		args := scanCallArgs(content, match[1])
+
+		for i, arg := range args {
+			// p() takes property name (or array of alternative names) and default;
+			// if_p() takes any number of property names
+			if funcName == "p" && i > 0 {
+				break
+			}
+
+			for _, literal := range arg {
+				line, col := lineAndColumn(content, literal.offset)
+				refs = append(refs, propertyRef{Name: literal.value, Line: line, Column: col})
+			}
+		}
+	}
+
+	return refs
+}
+
+type stringLiteral struct {
+	value  string
+	offset int
+}
+
+// scanCallArgs returns string literals of each argument that is
+// either a string or an array of strings, stopping at closing parenthesis
+func scanCallArgs(content string, start int) [][]stringLiteral {
+	var args [][]stringLiteral
+	var current []stringLiteral
+
+	depth := 0
+	literalArg := true
+	argStarted := false
+
+	for i := start; i < len(content); i++ {
+		c := content[i]
+
+		switch {
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(content[i+1:], c)
+			if end < 0 {
+				return args
+			}
+
+			if literalArg && depth <= 1 {
+				current = append(current, stringLiteral{value: content[i+1 : i+1+end], offset: i})
+			}
+
+			i += end + 1
+			argStarted = true
+
+		case c == '(' || c == '{':
+			depth++
+			literalArg = false
+			argStarted = true
+
+		case c == '[':
+			if argStarted || depth > 0 {
+				literalArg = false
+			}
+			depth++
+			argStarted = true
+
+		case c == ')' || c == ']' || c == '}':
+			if depth == 0 {
+				if literalArg {
+					args = append(args, current)
+				}
+				return args
+			}
+			depth--
+
+		case c == ',' && depth == 0:
+			if literalArg {
+				args = append(args, current)
+			} else {
+				args = append(args, nil)
+			}
+
+			current = nil
+			literalArg = true
+			argStarted = false
+
+		case c == ',' || c == ' ' || c == '\t' || c == '\n' || c == '\r':
+
+		default:
+			literalArg = false
+			argStarted = true
+		}
+	}
+
+	return args
+}
+
+func lineAndColumn(content string, offset int) (int, int) {
+	line := strings.Count(content[:offset], "\n") + 1
+	col := offset - strings.LastIndexByte(content[:offset], '\n')
+	return line, col
+}
@@ -0,0 +1,42 @@
+package lint_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/releasedir/lint"
+)
+
+var _ = Describe("ScanPropertyRefs", func() {
+	It("returns names referenced via p() with their positions", func() {
+		refs := ScanPropertyRefs("port: <%= p('port') %>\nhost: <%= p(\"host\", 'localhost') %>")
+		Expect(refs).To(Equal([]PropertyRef{
+			{Name: "port", Line: 1, Column: 13},
+			{Name: "host", Line: 2, Column: 13},
+		}))
+	})
+
+	It("returns every alternative name of an array passed to p()", func() {
+		refs := ScanPropertyRefs("<%= p(['alt.one', 'alt.two'], 'def') %>")
+		Expect(refs).To(Equal([]PropertyRef{
+			{Name: "alt.one", Line: 1, Column: 8},
+			{Name: "alt.two", Line: 1, Column: 19},
+		}))
+	})
+
+	It("returns all names passed to if_p()", func() {
+		refs := ScanPropertyRefs("<% if_p('a', 'b') do |a, b| %><% end %>")
+		Expect(refs).To(Equal([]PropertyRef{
+			{Name: "a", Line: 1, Column: 9},
+			{Name: "b", Line: 1, Column: 14},
+		}))
+	})
+
+	It("ignores p() called on other objects such as links", func() {
+		Expect(ScanPropertyRefs("<%= link('db').p('port') %>")).To(BeEmpty())
+	})
+
+	It("ignores names that are not string literals", func() {
+		Expect(ScanPropertyRefs("<%= p(name) %><%= p(\"a.#{x}\" + suffix) %>")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,119 @@
+package lint
+
+import (
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlDoc keeps parsed YAML nodes to find positions of keys and items
+type yamlDoc struct {
+	root *yaml.Node
+}
+
+func readYAMLDoc(path string, fs boshsys.FileSystem) yamlDoc {
+	bytes, err := fs.ReadFile(path)
+	if err != nil {
+		return yamlDoc{}
+	}
+
+	var node yaml.Node
+
+	// Positions are best effort; readers report invalid YAML
+	err = yaml.Unmarshal(bytes, &node)
+	if err != nil || len(node.Content) == 0 {
+		return yamlDoc{}
+	}
+
+	return yamlDoc{root: node.Content[0]}
+}
+
+// KeyPosition returns position of the last key found along the given path
+func (d yamlDoc) KeyPosition(keys ...string) (int, int) {
+	line, col := 1, 1
+	node := d.root
+
+	for _, key := range keys {
+		keyNode, valueNode := mappingEntry(node, key)
+		if keyNode == nil {
+			break
+		}
+
+		line, col = keyNode.Line, keyNode.Column
+		node = valueNode
+	}
+
+	return line, col
+}
+
+// ItemPosition returns position of a scalar item in a top level sequence,
+// falling back to position of the sequence key
+func (d yamlDoc) ItemPosition(key, value string) (int, int) {
+	_, seqNode := mappingEntry(d.root, key)
+
+	if seqNode != nil && seqNode.Kind == yaml.SequenceNode {
+		for _, item := range seqNode.Content {
+			if item.Kind == yaml.ScalarNode && item.Value == value {
+				return item.Line, item.Column
+			}
+		}
+	}
+
+	return d.KeyPosition(key)
+}
+
+// Strings returns scalar values found at the path; '*' descends into every sequence item
+func (d yamlDoc) Strings(path ...string) []string {
+	return collectStrings(d.root, path)
+}
+
+func collectStrings(node *yaml.Node, path []string) []string {
+	if node == nil {
+		return nil
+	}
+
+	if len(path) == 0 {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			return []string{node.Value}
+		case yaml.SequenceNode:
+			var values []string
+			for _, item := range node.Content {
+				if item.Kind == yaml.ScalarNode {
+					values = append(values, item.Value)
+				}
+			}
+			return values
+		}
+		return nil
+	}
+
+	if path[0] == "*" {
+		if node.Kind != yaml.SequenceNode {
+			return nil
+		}
+
+		var values []string
+		for _, item := range node.Content {
+			values = append(values, collectStrings(item, path[1:])...)
+		}
+		return values
+	}
+
+	_, valueNode := mappingEntry(node, path[0])
+
+	return collectStrings(valueNode, path[1:])
+}
+
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
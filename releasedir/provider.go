@@ -13,6 +13,7 @@ import (
 
 	bicrypto "github.com/cloudfoundry/bosh-cli/v7/crypto"
 	boshrel "github.com/cloudfoundry/bosh-cli/v7/release"
+	boshres "github.com/cloudfoundry/bosh-cli/v7/release/resource"
 	boshidx "github.com/cloudfoundry/bosh-cli/v7/releasedir/index"
 )
 
@@ -23,7 +24,6 @@ type Provider struct {
 	releaseProvider      boshrel.Provider
 	digestCalculator     bicrypto.DigestCalculator
 
-	cmdRunner              boshsys.CmdRunner
 	uuidGen                boshuuid.Generator
 	timeService            clock.Clock
 	fs                     boshsys.FileSystem
@@ -37,7 +37,6 @@ func NewProvider(
 	blobsReporter BlobsDirReporter,
 	releaseProvider boshrel.Provider,
 	digestCalculator bicrypto.DigestCalculator,
-	cmdRunner boshsys.CmdRunner,
 	uuidGen boshuuid.Generator,
 	timeService clock.Clock,
 	fs boshsys.FileSystem,
@@ -50,7 +49,6 @@ func NewProvider(
 		blobsReporter:          blobsReporter,
 		releaseProvider:        releaseProvider,
 		digestCalculator:       digestCalculator,
-		cmdRunner:              cmdRunner,
 		uuidGen:                uuidGen,
 		timeService:            timeService,
 		fs:                     fs,
@@ -60,7 +58,7 @@ func NewProvider(
 }
 
 func (p Provider) NewFSReleaseDir(dirPath string, parallel int) FSReleaseDir {
-	gitRepo := NewFSGitRepo(dirPath, p.cmdRunner, p.fs)
+	gitRepo := NewGoGitRepo(dirPath, p.fs)
 	blobsDir := p.NewFSBlobsDir(dirPath)
 	generator := NewFSGenerator(dirPath, p.fs)
 
@@ -96,12 +94,26 @@ func (p Provider) NewFSBlobsDir(dirPath string) FSBlobsDir {
 }
 
 func (p Provider) NewReleaseReader(dirPath string, parallel int) boshrel.BuiltReader {
-	multiReader := p.releaseProvider.NewMultiReader(dirPath)
+	multiReader := p.releaseProviderForDir(dirPath).NewMultiReader(dirPath)
 	indiciesProvider := boshidx.NewProvider(p.indexReporter, p.newBlobstore(dirPath), p.fs)
 	devIndex, finalIndex := indiciesProvider.DevAndFinalIndicies(dirPath)
 	return boshrel.NewBuiltReader(multiReader, devIndex, finalIndex, parallel)
 }
 
+// NewArchiveWriter returns writer for release tarballs built from given release directory
+func (p Provider) NewArchiveWriter(dirPath string) boshrel.ArchiveWriter {
+	return p.releaseProviderForDir(dirPath).NewArchiveWriter()
+}
+
+// releaseProviderForDir uses time of the last commit as modification
+// time in created archives unless SOURCE_DATE_EPOCH is set. It is looked up
+// once so that all archives of a release share it.
+func (p Provider) releaseProviderForDir(dirPath string) boshrel.Provider {
+	gitRepo := NewGoGitRepo(dirPath, p.fs)
+	sourceDate := boshres.ResolveSourceDate(boshres.SourceDateEpochOr(gitRepo.LastCommitTime), p.logger)
+	return p.releaseProvider.WithSourceDate(sourceDate)
+}
+
 func (p Provider) newBlobstore(dirPath string) boshblob.DigestBlobstore {
 	provider, options, err := p.newConfig(dirPath).Blobstore()
 	if err != nil {
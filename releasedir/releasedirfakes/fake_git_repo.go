@@ -3,6 +3,7 @@ package releasedirfakes
 
 import (
 	"sync"
+	"time"
 
 	"github.com/cloudfoundry/bosh-cli/v7/releasedir"
 )
@@ -30,6 +31,18 @@ type FakeGitRepo struct {
 		result1 string
 		result2 error
 	}
+	LastCommitTimeStub        func() (time.Time, error)
+	lastCommitTimeMutex       sync.RWMutex
+	lastCommitTimeArgsForCall []struct {
+	}
+	lastCommitTimeReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	lastCommitTimeReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
 	MustNotBeDirtyStub        func(bool) (bool, error)
 	mustNotBeDirtyMutex       sync.RWMutex
 	mustNotBeDirtyArgsForCall []struct {
@@ -156,6 +169,62 @@ func (fake *FakeGitRepo) LastCommitSHAReturnsOnCall(i int, result1 string, resul
 	}{result1, result2}
 }
 
+func (fake *FakeGitRepo) LastCommitTime() (time.Time, error) {
+	fake.lastCommitTimeMutex.Lock()
+	ret, specificReturn := fake.lastCommitTimeReturnsOnCall[len(fake.lastCommitTimeArgsForCall)]
+	fake.lastCommitTimeArgsForCall = append(fake.lastCommitTimeArgsForCall, struct {
+	}{})
+	stub := fake.LastCommitTimeStub
+	fakeReturns := fake.lastCommitTimeReturns
+	fake.recordInvocation("LastCommitTime", []interface{}{})
+	fake.lastCommitTimeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeGitRepo) LastCommitTimeCallCount() int {
+	fake.lastCommitTimeMutex.RLock()
+	defer fake.lastCommitTimeMutex.RUnlock()
+	return len(fake.lastCommitTimeArgsForCall)
+}
+
+func (fake *FakeGitRepo) LastCommitTimeCalls(stub func() (time.Time, error)) {
+	fake.lastCommitTimeMutex.Lock()
+	defer fake.lastCommitTimeMutex.Unlock()
+	fake.LastCommitTimeStub = stub
+}
+
+func (fake *FakeGitRepo) LastCommitTimeReturns(result1 time.Time, result2 error) {
+	fake.lastCommitTimeMutex.Lock()
+	defer fake.lastCommitTimeMutex.Unlock()
+	fake.LastCommitTimeStub = nil
+	fake.lastCommitTimeReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeGitRepo) LastCommitTimeReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.lastCommitTimeMutex.Lock()
+	defer fake.lastCommitTimeMutex.Unlock()
+	fake.LastCommitTimeStub = nil
+	if fake.lastCommitTimeReturnsOnCall == nil {
+		fake.lastCommitTimeReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.lastCommitTimeReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeGitRepo) MustNotBeDirty(arg1 bool) (bool, error) {
 	fake.mustNotBeDirtyMutex.Lock()
 	ret, specificReturn := fake.mustNotBeDirtyReturnsOnCall[len(fake.mustNotBeDirtyArgsForCall)]
@@ -227,6 +296,8 @@ func (fake *FakeGitRepo) Invocations() map[string][][]interface{} {
 	defer fake.initMutex.RUnlock()
 	fake.lastCommitSHAMutex.RLock()
 	defer fake.lastCommitSHAMutex.RUnlock()
+	fake.lastCommitTimeMutex.RLock()
+	defer fake.lastCommitTimeMutex.RUnlock()
 	fake.mustNotBeDirtyMutex.RLock()
 	defer fake.mustNotBeDirtyMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,316 @@
+package inspect
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"strconv"
+
+	boshcrypto "github.com/cloudfoundry/bosh-utils/crypto"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	manifestEntry      = "stemcell.MF"
+	imageEntry         = "image"
+	packagesEntry      = "packages.txt"
+	devToolsEntry      = "dev_tools_file_list"
+	imageRootFileEntry = "root.img"
+
+	sparseChunkSize = 64 * 1024
+)
+
+var errStopEntries = bosherr.Error("Stop reading entries")
+
+type FSArchive struct {
+	path string
+	fs   boshsys.FileSystem
+}
+
+func NewFSArchive(path string, fs boshsys.FileSystem) FSArchive {
+	return FSArchive{path: path, fs: fs}
+}
+
+func (a FSArchive) Packages() ([]Package, error) {
+	content, err := a.readEntry(packagesEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePackages(string(content)), nil
+}
+
+func (a FSArchive) DevToolsFiles() ([]string, error) {
+	content, err := a.readEntry(devToolsEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFileList(string(content)), nil
+}
+
+func (a FSArchive) Validate() ([]Problem, error) {
+	manifestBytes, err := a.readEntry(manifestEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := ValidateManifest(manifestBytes)
+
+	var manifest struct {
+		SHA1 string `yaml:"sha1"`
+	}
+
+	// Problems with manifest are already reported above
+	_ = yaml.Unmarshal(manifestBytes, &manifest)
+
+	digest, digestErr := boshcrypto.ParseMultipleDigest(strconv.Quote(manifest.SHA1))
+
+	var foundImage bool
+
+	err = a.eachEntry(func(name string, r io.Reader) error {
+		if name != imageEntry {
+			return nil
+		}
+
+		foundImage = true
+
+		if digestErr == nil {
+			err := digest.Verify(r)
+			if err != nil {
+				problems = append(problems, Problem{LevelError, "sha1", err.Error()})
+			}
+		}
+
+		return errStopEntries
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !foundImage {
+		problems = append(problems, Problem{LevelError, imageEntry, "Expected stemcell tarball to include image"})
+	}
+
+	return problems, nil
+}
+
+func (a FSArchive) Files() ([]File, error) {
+	var files []File
+	var foundImage bool
+
+	err := a.eachEntry(func(name string, r io.Reader) error {
+		if name != imageEntry {
+			return nil
+		}
+
+		foundImage = true
+
+		var err error
+
+		files, err = a.imageFiles(r)
+		if err != nil {
+			return bosherr.WrapError(err, "Listing files in stemcell image")
+		}
+
+		return errStopEntries
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !foundImage {
+		return nil, bosherr.Errorf("Missing '%s' in stemcell tarball", imageEntry)
+	}
+
+	return files, nil
+}
+
+// imageFiles accepts either disk image itself or a tarball wrapping it
+// (e.g. root.img in OpenStack stemcells)
+func (a FSArchive) imageFiles(image io.Reader) ([]File, error) {
+	buffered := bufio.NewReader(image)
+
+	magic, err := buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, bosherr.WrapError(err, "Reading image")
+	}
+
+	if !bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		return a.diskImageFiles(buffered)
+	}
+
+	gr, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading image tarball")
+	}
+
+	defer gr.Close()
+
+	var regularFiles []string
+	var diskImage io.Reader
+
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Reading image tarball entry")
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		regularFiles = append(regularFiles, path.Clean(hdr.Name))
+
+		if path.Clean(hdr.Name) == imageRootFileEntry {
+			diskImage = tr
+			break
+		}
+	}
+
+	if diskImage == nil {
+		return nil, bosherr.Errorf(
+			"Expected image tarball to contain raw or qcow2 disk image '%s' but found '%v'",
+			imageRootFileEntry, regularFiles)
+	}
+
+	return a.diskImageFiles(diskImage)
+}
+
+func (a FSArchive) diskImageFiles(diskImage io.Reader) ([]File, error) {
+	file, err := a.fs.TempFile("bosh-stemcell-image")
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Creating temporary file for image")
+	}
+
+	defer a.fs.RemoveAll(file.Name()) //nolint:errcheck
+	defer file.Close()
+
+	size, err := copySparse(file, diskImage)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Copying image to temporary file")
+	}
+
+	return imageFiles(file, size)
+}
+
+// copySparse skips writing zero chunks so that mostly empty disk images do not take up space
+func copySparse(dst io.WriterAt, src io.Reader) (int64, error) {
+	buf := make([]byte, sparseChunkSize)
+	zeros := make([]byte, sparseChunkSize)
+
+	var size int64
+	var endsWithZeros bool
+
+	for {
+		n, err := io.ReadFull(src, buf)
+		if n > 0 {
+			endsWithZeros = bytes.Equal(buf[:n], zeros[:n])
+
+			if !endsWithZeros {
+				_, writeErr := dst.WriteAt(buf[:n], size)
+				if writeErr != nil {
+					return 0, writeErr
+				}
+			}
+
+			size += int64(n)
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Extend file to full size since trailing zeros were skipped
+	if endsWithZeros {
+		_, err := dst.WriteAt(zeros[:1], size-1)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return size, nil
+}
+
+func (a FSArchive) readEntry(entryName string) ([]byte, error) {
+	var content []byte
+
+	err := a.eachEntry(func(name string, r io.Reader) error {
+		if name != entryName {
+			return nil
+		}
+
+		var err error
+
+		content, err = io.ReadAll(r)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading '%s' entry", entryName)
+		}
+
+		return errStopEntries
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if content == nil {
+		return nil, bosherr.Errorf("Missing '%s' in stemcell tarball", entryName)
+	}
+
+	return content, nil
+}
+
+// eachEntry calls fn for regular files until it returns errStopEntries
+func (a FSArchive) eachEntry(fn func(name string, r io.Reader) error) error {
+	file, err := a.fs.OpenFile(a.path, os.O_RDONLY, 0)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Opening stemcell tarball '%s'", a.path)
+	}
+
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading stemcell tarball '%s'", a.path)
+	}
+
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return bosherr.WrapError(err, "Reading next tar entry")
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		err = fn(path.Clean(hdr.Name), tr)
+		if err == errStopEntries {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
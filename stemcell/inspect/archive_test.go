@@ -0,0 +1,473 @@
+package inspect_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+)
+
+var _ = Describe("FSArchive", func() {
+	var (
+		fs          boshsys.FileSystem
+		tarballPath string
+		archive     FSArchive
+		packagesTxt = "Desired=Unknown/Install/Remove/Purge/Hold\n" +
+			"| Status=Not/Inst/Conf-files/Unpacked/halF-conf/Half-inst/trig-aWait/Trig-pend\n" +
+			"|/ Err?=(none)/Reinst-required (Status,Err: uppercase=bad)\n" +
+			"||/ Name           Version        Architecture Description\n" +
+			"+++-==============-==============-============-=================================\n" +
+			"ii  adduser        3.118ubuntu5   all          add and remove users and groups\n" +
+			"ii  libc6:amd64    2.35-0ubuntu3  amd64        GNU C Library: Shared libraries\n"
+	)
+
+	BeforeEach(func() {
+		fs = boshsys.NewOsFileSystem(boshlog.NewLogger(boshlog.LevelNone))
+		tarballPath = filepath.Join(GinkgoT().TempDir(), "stemcell.tgz")
+		archive = NewFSArchive(tarballPath, fs)
+	})
+
+	Describe("Packages", func() {
+		It("returns packages listed in packages.txt", func() {
+			writeTarball(tarballPath, tarEntry{"./packages.txt", []byte(packagesTxt)})
+
+			Expect(archive.Packages()).To(Equal([]Package{
+				{Name: "adduser", Version: "3.118ubuntu5", Architecture: "all"},
+				{Name: "libc6:amd64", Version: "2.35-0ubuntu3", Architecture: "amd64"},
+			}))
+		})
+
+		It("reads lists that are not in dpkg format as name and version", func() {
+			writeTarball(tarballPath, tarEntry{"packages.txt", []byte("openssl 1.0.2k\nbash\n")})
+
+			Expect(archive.Packages()).To(Equal([]Package{
+				{Name: "openssl", Version: "1.0.2k"},
+				{Name: "bash"},
+			}))
+		})
+
+		It("returns error if packages.txt is missing", func() {
+			writeTarball(tarballPath, tarEntry{"stemcell.MF", []byte("name: s")})
+
+			_, err := archive.Packages()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Missing 'packages.txt' in stemcell tarball"))
+		})
+
+		It("returns error if tarball cannot be read", func() {
+			Expect(fs.WriteFileString(tarballPath, "not-gzip")).To(Succeed())
+
+			_, err := archive.Packages()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading stemcell tarball"))
+		})
+	})
+
+	Describe("DevToolsFiles", func() {
+		It("returns paths listed in dev_tools_file_list", func() {
+			writeTarball(tarballPath, tarEntry{"dev_tools_file_list", []byte("/usr/bin/gcc\n\n/usr/bin/make\n")})
+
+			Expect(archive.DevToolsFiles()).To(Equal([]string{"/usr/bin/gcc", "/usr/bin/make"}))
+		})
+	})
+
+	Describe("Validate", func() {
+		image := []byte("image-content")
+		imageSHA1 := fmt.Sprintf("%x", sha1.Sum(image))
+
+		manifest := func(sha1 string) []byte {
+			return []byte(fmt.Sprintf(`---
+name: bosh-openstack-kvm-ubuntu-jammy-go_agent
+version: "1.100"
+operating_system: ubuntu-jammy
+sha1: %s
+bosh_protocol: 1
+api_version: 3
+stemcell_formats: [openstack-qcow2]
+cloud_properties:
+  disk: 5120
+`, sha1))
+		}
+
+		It("returns no problems for valid stemcell", func() {
+			writeTarball(tarballPath, tarEntry{"stemcell.MF", manifest(imageSHA1)}, tarEntry{"image", image})
+
+			Expect(archive.Validate()).To(BeEmpty())
+		})
+
+		It("reports image that does not match digest", func() {
+			writeTarball(tarballPath, tarEntry{"stemcell.MF", manifest(imageSHA1)}, tarEntry{"image", []byte("other")})
+
+			problems, err := archive.Validate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(problems).To(HaveLen(1))
+			Expect(problems[0].Level).To(Equal(LevelError))
+			Expect(problems[0].Field).To(Equal("sha1"))
+			Expect(problems[0].Message).To(ContainSubstring(imageSHA1))
+		})
+
+		It("reports missing image", func() {
+			writeTarball(tarballPath, tarEntry{"stemcell.MF", manifest(imageSHA1)})
+
+			Expect(archive.Validate()).To(Equal([]Problem{
+				{LevelError, "image", "Expected stemcell tarball to include image"},
+			}))
+		})
+
+		It("returns error if stemcell.MF is missing", func() {
+			writeTarball(tarballPath, tarEntry{"image", image})
+
+			_, err := archive.Validate()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Missing 'stemcell.MF' in stemcell tarball"))
+		})
+	})
+
+	Describe("Files", func() {
+		var ext4, ext2 []byte
+
+		BeforeEach(func() {
+			ext4 = readFixture("ext4.img.gz")
+			ext2 = readFixture("ext2.img.gz")
+		})
+
+		expectFixtureFiles := func(files []File) {
+			Expect(files).To(ContainElement(File{Path: "/etc", Mode: os.ModeDir | 0755, Size: 4096}))
+			Expect(files).To(ContainElement(File{Path: "/etc/passwd", Mode: 0644, Size: 32}))
+			Expect(files).To(ContainElement(File{Path: "/usr/bin/hello", Mode: os.ModeSetuid | 0755, Size: 18}))
+			Expect(files).To(ContainElement(File{Path: "/var/lib/dpkg/status", Mode: 0644, Size: 20000}))
+			Expect(files).To(ContainElement(File{
+				Path: "/etc/short-link", Mode: os.ModeSymlink | 0777, Size: 16, LinkTarget: "../usr/bin/hello",
+			}))
+			Expect(files).To(ContainElement(File{
+				Path: "/etc/long-link", Mode: os.ModeSymlink | 0777, Size: 72,
+				LinkTarget: "/usr/share/some/very/long/target/path/that/exceeds/sixty/characters/file",
+			}))
+
+			var many []string
+			for _, file := range files {
+				if filepath.Dir(file.Path) == "/many" {
+					many = append(many, file.Path)
+				}
+			}
+			Expect(many).To(HaveLen(400))
+			Expect(many[0]).To(Equal("/many/file-with-a-rather-long-name-number-1"))
+
+			Expect(files[0].Path).To(Equal("/etc"))
+		}
+
+		It("lists files of unpartitioned ext4 image wrapped in image tarball", func() {
+			writeTarball(tarballPath, tarEntry{"image", imageTarball(ext4)})
+
+			files, err := archive.Files()
+			Expect(err).ToNot(HaveOccurred())
+			expectFixtureFiles(files)
+		})
+
+		It("lists files of ext2 image using indirect blocks", func() {
+			writeTarball(tarballPath, tarEntry{"image", imageTarball(ext2)})
+
+			files, err := archive.Files()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(files).To(ContainElement(File{Path: "/etc", Mode: os.ModeDir | 0755, Size: 1024}))
+			Expect(files).To(ContainElement(File{
+				Path: "/etc/long-link", Mode: os.ModeSymlink | 0777, Size: 72,
+				LinkTarget: "/usr/share/some/very/long/target/path/that/exceeds/sixty/characters/file",
+			}))
+			Expect(len(files)).To(BeNumerically(">", 400))
+		})
+
+		It("lists files of raw disk with MBR partition table", func() {
+			writeTarball(tarballPath, tarEntry{"image", imageTarball(withMBR(ext4))})
+
+			files, err := archive.Files()
+			Expect(err).ToNot(HaveOccurred())
+			expectFixtureFiles(files)
+		})
+
+		It("lists files of raw disk with GPT partition table", func() {
+			writeTarball(tarballPath, tarEntry{"image", imageTarball(withGPT(ext4))})
+
+			files, err := archive.Files()
+			Expect(err).ToNot(HaveOccurred())
+			expectFixtureFiles(files)
+		})
+
+		It("lists files of qcow2 disk image", func() {
+			writeTarball(tarballPath, tarEntry{"image", imageTarball(toQCOW2(withMBR(ext4), false))})
+
+			files, err := archive.Files()
+			Expect(err).ToNot(HaveOccurred())
+			expectFixtureFiles(files)
+		})
+
+		It("lists files of qcow2 disk image with compressed clusters", func() {
+			writeTarball(tarballPath, tarEntry{"image", imageTarball(toQCOW2(withMBR(ext4), true))})
+
+			files, err := archive.Files()
+			Expect(err).ToNot(HaveOccurred())
+			expectFixtureFiles(files)
+		})
+
+		It("lists files of disk image that is not wrapped in tarball", func() {
+			writeTarball(tarballPath, tarEntry{"image", ext4})
+
+			files, err := archive.Files()
+			Expect(err).ToNot(HaveOccurred())
+			expectFixtureFiles(files)
+		})
+
+		It("returns error if image tarball does not contain root image", func() {
+			writeTarball(tarballPath, tarEntry{"image", gzipTarball(tarEntry{"image.ovf", []byte("ovf")})})
+
+			_, err := archive.Files()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected image tarball to contain raw or qcow2 disk image 'root.img' but found '[image.ovf]'"))
+		})
+
+		It("returns error if disk image does not contain ext filesystem", func() {
+			writeTarball(tarballPath, tarEntry{"image", imageTarball(make([]byte, 4096))})
+
+			_, err := archive.Files()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected disk image to contain ext filesystem"))
+		})
+
+		Context("when image is malformed", func() {
+			superblock := func() []byte { return ext4[1024:] }
+
+			expectFilesErr := func(disk []byte, msg string) {
+				writeTarball(tarballPath, tarEntry{"image", imageTarball(disk)})
+
+				_, err := archive.Files()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(msg))
+			}
+
+			It("returns error if ext block size is too large", func() {
+				binary.LittleEndian.PutUint32(superblock()[0x18:], 32)
+				expectFilesErr(ext4, "Invalid ext log block size '32'")
+			})
+
+			It("returns error if ext inodes per group is zero", func() {
+				binary.LittleEndian.PutUint32(superblock()[0x28:], 0)
+				expectFilesErr(ext4, "Invalid ext inodes per group '0'")
+			})
+
+			It("returns error if ext inode size is too small", func() {
+				binary.LittleEndian.PutUint16(superblock()[0x58:], 0)
+				expectFilesErr(ext4, "Invalid ext inode size '0'")
+			})
+
+			It("returns error if 64-bit ext group descriptor size is too small", func() {
+				incompat := binary.LittleEndian.Uint32(superblock()[0x60:])
+				binary.LittleEndian.PutUint32(superblock()[0x60:], incompat|0x80)
+				binary.LittleEndian.PutUint16(superblock()[0xFE:], 0)
+				expectFilesErr(ext4, "Invalid ext group descriptor size '0'")
+			})
+
+			It("returns error if ext directory is unreasonably large instead of reading it", func() {
+				root := extRootInode(ext4)
+				binary.LittleEndian.PutUint32(root[0x4:], 0)
+				binary.LittleEndian.PutUint32(root[0x6C:], 0x10000)
+				expectFilesErr(ext4, "Expected inode 2 size '281474976710656' to not exceed '67108864'")
+			})
+
+			It("returns error if inode number exceeds ext inode count", func() {
+				binary.LittleEndian.PutUint32(superblock()[0x0:], 1)
+				expectFilesErr(ext4, "Invalid inode number 2")
+			})
+
+			It("returns error if qcow2 cluster bits are out of range", func() {
+				img := toQCOW2(withMBR(ext4), false)
+				binary.BigEndian.PutUint32(img[20:], 63)
+				expectFilesErr(img, "Invalid qcow2 cluster bits '63'")
+			})
+
+			It("returns error if qcow2 L1 table is unreasonably large instead of reading it", func() {
+				img := toQCOW2(withMBR(ext4), false)
+				binary.BigEndian.PutUint32(img[36:], 0xFFFFFFFF)
+				expectFilesErr(img, "Invalid qcow2 L1 table size '4294967295'")
+			})
+		})
+
+		It("returns error if image is missing", func() {
+			writeTarball(tarballPath, tarEntry{"stemcell.MF", []byte("name: s")})
+
+			_, err := archive.Files()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Missing 'image' in stemcell tarball"))
+		})
+	})
+})
+
+type tarEntry struct {
+	name    string
+	content []byte
+}
+
+func gzipTarball(entries ...tarEntry) []byte {
+	var buf bytes.Buffer
+
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for _, entry := range entries {
+		err := tw.WriteHeader(&tar.Header{Name: entry.name, Mode: 0644, Size: int64(len(entry.content)), Typeflag: tar.TypeReg})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = tw.Write(entry.content)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Expect(tw.Close()).To(Succeed())
+	Expect(gw.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
+
+func writeTarball(path string, entries ...tarEntry) {
+	Expect(os.WriteFile(path, gzipTarball(entries...), 0644)).To(Succeed())
+}
+
+func imageTarball(disk []byte) []byte {
+	return gzipTarball(tarEntry{"./root.img", disk})
+}
+
+func readFixture(name string) []byte {
+	file, err := os.Open(filepath.Join("assets", name))
+	Expect(err).ToNot(HaveOccurred())
+
+	defer file.Close()
+
+	gr, err := gzip.NewReader(file)
+	Expect(err).ToNot(HaveOccurred())
+
+	content, err := io.ReadAll(gr)
+	Expect(err).ToNot(HaveOccurred())
+
+	return content
+}
+
+// extRootInode returns on-disk inode of the root directory located through the first group descriptor
+func extRootInode(fs []byte) []byte {
+	sb := fs[1024:]
+	blockSize := 1024 << binary.LittleEndian.Uint32(sb[0x18:])
+	inodeSize := int(binary.LittleEndian.Uint16(sb[0x58:]))
+	descTable := (int(binary.LittleEndian.Uint32(sb[0x14:])) + 1) * blockSize
+	inodeTable := int(binary.LittleEndian.Uint32(fs[descTable+0x8:])) * blockSize
+
+	// Root directory is always inode 2
+	return fs[inodeTable+inodeSize:]
+}
+
+const partitionLBA = 2048
+
+func withMBR(fs []byte) []byte {
+	disk := make([]byte, partitionLBA*512+len(fs))
+	copy(disk[partitionLBA*512:], fs)
+
+	entry := disk[446:]
+	entry[4] = 0x83
+	binary.LittleEndian.PutUint32(entry[8:], partitionLBA)
+	binary.LittleEndian.PutUint32(entry[12:], uint32(len(fs)/512))
+
+	disk[510], disk[511] = 0x55, 0xAA
+
+	return disk
+}
+
+func withGPT(fs []byte) []byte {
+	disk := make([]byte, partitionLBA*512+len(fs))
+	copy(disk[partitionLBA*512:], fs)
+
+	// Protective MBR
+	disk[446+4] = 0xEE
+	disk[510], disk[511] = 0x55, 0xAA
+
+	header := disk[512:]
+	copy(header, "EFI PART")
+	binary.LittleEndian.PutUint64(header[0x48:], 2)
+	binary.LittleEndian.PutUint32(header[0x50:], 128)
+	binary.LittleEndian.PutUint32(header[0x54:], 128)
+
+	// EFI system partition without ext filesystem, followed by root partition
+	esp := disk[2*512:]
+	copy(esp, bytes.Repeat([]byte{0xEF}, 16))
+	binary.LittleEndian.PutUint64(esp[0x20:], 40)
+	binary.LittleEndian.PutUint64(esp[0x28:], partitionLBA-1)
+
+	root := disk[2*512+128:]
+	copy(root, bytes.Repeat([]byte{0x83}, 16))
+	binary.LittleEndian.PutUint64(root[0x20:], partitionLBA)
+	binary.LittleEndian.PutUint64(root[0x28:], uint64(partitionLBA+len(fs)/512-1))
+
+	return disk
+}
+
+// toQCOW2 lays out header, L1 table, single L2 table and then data clusters
+func toQCOW2(raw []byte, compress bool) []byte {
+	const clusterBits = 16
+	const clusterSize = 1 << clusterBits
+	const l2Offset = 2 * clusterSize
+
+	Expect(len(raw)).To(BeNumerically("<=", clusterSize/8*clusterSize))
+
+	img := make([]byte, 3*clusterSize)
+
+	copy(img, []byte{'Q', 'F', 'I', 0xfb})
+	binary.BigEndian.PutUint32(img[4:], 2)
+	binary.BigEndian.PutUint32(img[20:], clusterBits)
+	binary.BigEndian.PutUint64(img[24:], uint64(len(raw)))
+	binary.BigEndian.PutUint32(img[36:], 1)
+	binary.BigEndian.PutUint64(img[40:], clusterSize)
+
+	binary.BigEndian.PutUint64(img[clusterSize:], l2Offset|1<<63)
+
+	for i := 0; i*clusterSize < len(raw); i++ {
+		cluster := make([]byte, clusterSize)
+		copy(cluster, raw[i*clusterSize:])
+
+		if bytes.Equal(cluster, make([]byte, clusterSize)) {
+			continue
+		}
+
+		offset := uint64(len(img))
+
+		if compress {
+			var buf bytes.Buffer
+
+			fw, err := flate.NewWriter(&buf, flate.BestCompression)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = fw.Write(cluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fw.Close()).To(Succeed())
+
+			sectors := uint64((buf.Len() + 511) / 512)
+			img = append(img, buf.Bytes()...)
+			img = append(img, make([]byte, int(sectors*512)-buf.Len())...)
+
+			binary.BigEndian.PutUint64(img[l2Offset+i*8:], 1<<62|(sectors-1)<<(62-(clusterBits-8))|offset)
+		} else {
+			img = append(img, cluster...)
+			binary.BigEndian.PutUint64(img[l2Offset+i*8:], offset|1<<63)
+		}
+	}
+
+	return img
+}
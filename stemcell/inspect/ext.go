@@ -0,0 +1,477 @@
+package inspect
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// Minimal read-only support for ext2/3/4 filesystems as found in stemcell root images
+// (https://www.kernel.org/doc/html/latest/filesystems/ext4/index.html)
+
+const (
+	extSuperblockOffset = 1024
+	extMagic            = 0xEF53
+	extRootInode        = 2
+
+	extIncompatFiletype   = 0x2
+	extIncompatMetaBG     = 0x10
+	extIncompat64Bit      = 0x80
+	extIncompatInlineData = 0x8000
+
+	extFlagExtents    = 0x80000
+	extFlagInlineData = 0x10000000
+
+	extExtentMagic = 0xF30A
+
+	// Limits protect against crafted images claiming huge block sizes or files
+	extMaxLogBlockSize = 6 // 64 KiB
+	extMinInodeSize    = 128
+	extMinDescSize     = 32
+	extMaxDirSize      = 64 << 20
+	extMaxLinkSize     = 4096
+)
+
+type extFS struct {
+	r io.ReaderAt
+
+	blockSize      int64
+	inodeSize      int64
+	inodesCount    uint32
+	inodesPerGroup uint32
+	descSize       int64
+	descTableBlock int64
+	incompat       uint32
+}
+
+type extInode struct {
+	num   uint32
+	mode  uint16
+	uid   uint32
+	gid   uint32
+	size  int64
+	flags uint32
+	block [60]byte
+}
+
+func isExtFS(r io.ReaderAt) bool {
+	buf := make([]byte, 2)
+
+	_, err := r.ReadAt(buf, extSuperblockOffset+0x38)
+	if err != nil {
+		return false
+	}
+
+	return binary.LittleEndian.Uint16(buf) == extMagic
+}
+
+func newExtFS(r io.ReaderAt) (*extFS, error) {
+	sb := make([]byte, 1024)
+
+	_, err := r.ReadAt(sb, extSuperblockOffset)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading ext superblock")
+	}
+
+	if binary.LittleEndian.Uint16(sb[0x38:]) != extMagic {
+		return nil, bosherr.Error("Expected ext filesystem superblock")
+	}
+
+	logBlockSize := binary.LittleEndian.Uint32(sb[0x18:])
+	if logBlockSize > extMaxLogBlockSize {
+		return nil, bosherr.Errorf("Invalid ext log block size '%d'", logBlockSize)
+	}
+
+	fs := &extFS{
+		r:              r,
+		blockSize:      1024 << logBlockSize,
+		inodeSize:      extMinInodeSize,
+		inodesCount:    binary.LittleEndian.Uint32(sb[0x0:]),
+		inodesPerGroup: binary.LittleEndian.Uint32(sb[0x28:]),
+		descSize:       extMinDescSize,
+		incompat:       binary.LittleEndian.Uint32(sb[0x60:]),
+	}
+
+	if fs.inodesPerGroup == 0 {
+		return nil, bosherr.Error("Invalid ext inodes per group '0'")
+	}
+
+	if binary.LittleEndian.Uint32(sb[0x4C:]) >= 1 {
+		fs.inodeSize = int64(binary.LittleEndian.Uint16(sb[0x58:]))
+	}
+
+	if fs.inodeSize < extMinInodeSize || fs.inodeSize > fs.blockSize {
+		return nil, bosherr.Errorf("Invalid ext inode size '%d'", fs.inodeSize)
+	}
+
+	if fs.incompat&extIncompat64Bit != 0 {
+		fs.descSize = int64(binary.LittleEndian.Uint16(sb[0xFE:]))
+	}
+
+	if fs.descSize < extMinDescSize || fs.descSize > fs.blockSize {
+		return nil, bosherr.Errorf("Invalid ext group descriptor size '%d'", fs.descSize)
+	}
+
+	if fs.incompat&extIncompatMetaBG != 0 {
+		return nil, bosherr.Error("Reading ext filesystems with meta_bg feature is not supported")
+	}
+
+	// Group descriptors follow the block containing the superblock
+	fs.descTableBlock = int64(binary.LittleEndian.Uint32(sb[0x14:])) + 1
+
+	return fs, nil
+}
+
+// Walk visits every file reachable from the root directory in lexical order
+func (fs *extFS) Walk(fn func(File) error) error {
+	root, err := fs.inode(extRootInode)
+	if err != nil {
+		return err
+	}
+
+	return fs.walkDir("/", root, map[uint32]bool{}, fn)
+}
+
+func (fs *extFS) walkDir(dirPath string, dir extInode, visited map[uint32]bool, fn func(File) error) error {
+	if visited[dir.num] {
+		return nil
+	}
+
+	visited[dir.num] = true
+
+	entries, err := fs.dirEntries(dir)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading directory '%s'", dirPath)
+	}
+
+	for _, entry := range entries {
+		inode, err := fs.inode(entry.inode)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reading inode of '%s'", path.Join(dirPath, entry.name))
+		}
+
+		file := File{
+			Path: path.Join(dirPath, entry.name),
+			Mode: inode.fileMode(),
+			UID:  inode.uid,
+			GID:  inode.gid,
+			Size: inode.size,
+		}
+
+		if file.Mode&os.ModeSymlink != 0 {
+			target, err := fs.readLink(inode)
+			if err != nil {
+				return bosherr.WrapErrorf(err, "Reading symlink '%s'", file.Path)
+			}
+
+			file.LinkTarget = target
+		}
+
+		err = fn(file)
+		if err != nil {
+			return err
+		}
+
+		if file.Mode.IsDir() {
+			err = fs.walkDir(file.Path, inode, visited, fn)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (fs *extFS) inode(num uint32) (extInode, error) {
+	if num == 0 || num > fs.inodesCount {
+		return extInode{}, bosherr.Errorf("Invalid inode number %d", num)
+	}
+
+	group := int64(num-1) / int64(fs.inodesPerGroup)
+	index := int64(num-1) % int64(fs.inodesPerGroup)
+
+	desc := make([]byte, fs.descSize)
+
+	_, err := fs.r.ReadAt(desc, fs.descTableBlock*fs.blockSize+group*fs.descSize)
+	if err != nil {
+		return extInode{}, bosherr.WrapErrorf(err, "Reading group descriptor %d", group)
+	}
+
+	tableBlock := int64(binary.LittleEndian.Uint32(desc[0x8:]))
+	if fs.descSize >= 64 {
+		tableBlock |= int64(binary.LittleEndian.Uint32(desc[0x28:])) << 32
+	}
+
+	buf := make([]byte, 128)
+
+	_, err = fs.r.ReadAt(buf, tableBlock*fs.blockSize+index*fs.inodeSize)
+	if err != nil {
+		return extInode{}, bosherr.WrapErrorf(err, "Reading inode %d", num)
+	}
+
+	inode := extInode{
+		num:   num,
+		mode:  binary.LittleEndian.Uint16(buf[0x0:]),
+		uid:   uint32(binary.LittleEndian.Uint16(buf[0x2:])) | uint32(binary.LittleEndian.Uint16(buf[0x78:]))<<16,
+		gid:   uint32(binary.LittleEndian.Uint16(buf[0x18:])) | uint32(binary.LittleEndian.Uint16(buf[0x7A:]))<<16,
+		size:  int64(binary.LittleEndian.Uint32(buf[0x4:])) | int64(binary.LittleEndian.Uint32(buf[0x6C:]))<<32,
+		flags: binary.LittleEndian.Uint32(buf[0x20:]),
+	}
+
+	copy(inode.block[:], buf[0x28:0x28+60])
+
+	return inode, nil
+}
+
+func (i extInode) fileMode() os.FileMode {
+	mode := os.FileMode(i.mode & 0777)
+
+	switch i.mode & 0xF000 {
+	case 0x1000:
+		mode |= os.ModeNamedPipe
+	case 0x2000:
+		mode |= os.ModeDevice | os.ModeCharDevice
+	case 0x4000:
+		mode |= os.ModeDir
+	case 0x6000:
+		mode |= os.ModeDevice
+	case 0xA000:
+		mode |= os.ModeSymlink
+	case 0xC000:
+		mode |= os.ModeSocket
+	}
+
+	if i.mode&0x800 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if i.mode&0x400 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if i.mode&0x200 != 0 {
+		mode |= os.ModeSticky
+	}
+
+	return mode
+}
+
+type extDirEntry struct {
+	inode uint32
+	name  string
+}
+
+// dirEntries reads directory blocks linearly which also works for hashed
+// directories since their index blocks look like empty entries
+func (fs *extFS) dirEntries(dir extInode) ([]extDirEntry, error) {
+	data, err := fs.readData(dir, extMaxDirSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []extDirEntry
+
+	for off := 0; off+8 <= len(data); {
+		inode := binary.LittleEndian.Uint32(data[off:])
+		recLen := int(binary.LittleEndian.Uint16(data[off+4:]))
+
+		nameLen := int(data[off+6])
+		if fs.incompat&extIncompatFiletype == 0 {
+			nameLen = int(binary.LittleEndian.Uint16(data[off+6:]))
+		}
+
+		if recLen < 8 || off+recLen > len(data) || 8+nameLen > recLen {
+			return nil, bosherr.Errorf("Invalid directory entry at offset %d", off)
+		}
+
+		name := string(data[off+8 : off+8+nameLen])
+
+		if inode != 0 && name != "." && name != ".." {
+			entries = append(entries, extDirEntry{inode: inode, name: name})
+		}
+
+		off += recLen
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	return entries, nil
+}
+
+func (fs *extFS) readLink(inode extInode) (string, error) {
+	// Short targets are stored in place of block pointers
+	if inode.flags&(extFlagExtents|extFlagInlineData) == 0 && inode.size < int64(len(inode.block)) {
+		return string(inode.block[:inode.size]), nil
+	}
+
+	data, err := fs.readData(inode, extMaxLinkSize)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// readData reads whole contents of an inode which must not be larger than maxSize
+func (fs *extFS) readData(inode extInode, maxSize int64) ([]byte, error) {
+	if inode.flags&extFlagInlineData != 0 {
+		return nil, bosherr.Error("Reading ext inline data is not supported")
+	}
+
+	if inode.size < 0 || inode.size > maxSize {
+		return nil, bosherr.Errorf("Expected inode %d size '%d' to not exceed '%d'", inode.num, uint64(inode.size), maxSize)
+	}
+
+	numBlocks := (inode.size + fs.blockSize - 1) / fs.blockSize
+	data := make([]byte, numBlocks*fs.blockSize)
+
+	var err error
+
+	if inode.flags&extFlagExtents != 0 {
+		err = fs.readExtents(inode.block[:], data, 0, map[int64]bool{})
+	} else {
+		err = fs.readBlockMap(inode.block[:], data, numBlocks)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return data[:inode.size], nil
+}
+
+// readExtents reads each extent tree block at most once and expects no more tree blocks
+// than a file of this size needs, so that crafted trees cannot cause excessive reads
+func (fs *extFS) readExtents(node []byte, data []byte, depth int, visited map[int64]bool) error {
+	if depth > 5 || binary.LittleEndian.Uint16(node[0:]) != extExtentMagic {
+		return bosherr.Error("Invalid extent tree")
+	}
+
+	dataBlocks := int64(len(data)) / fs.blockSize
+
+	entries := int(binary.LittleEndian.Uint16(node[2:]))
+	nodeDepth := binary.LittleEndian.Uint16(node[6:])
+
+	for i := 0; i < entries && 12+i*12+12 <= len(node); i++ {
+		entry := node[12+i*12:]
+
+		if nodeDepth > 0 {
+			leaf := int64(binary.LittleEndian.Uint32(entry[4:])) | int64(binary.LittleEndian.Uint16(entry[8:]))<<32
+
+			if visited[leaf] {
+				continue
+			}
+
+			if int64(len(visited)) > 2*dataBlocks+1 {
+				return bosherr.Error("Invalid extent tree")
+			}
+
+			visited[leaf] = true
+
+			child := make([]byte, fs.blockSize)
+
+			_, err := fs.r.ReadAt(child, leaf*fs.blockSize)
+			if err != nil {
+				return bosherr.WrapErrorf(err, "Reading extent tree block %d", leaf)
+			}
+
+			err = fs.readExtents(child, data, depth+1, visited)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		logical := int64(binary.LittleEndian.Uint32(entry[0:]))
+		length := int64(binary.LittleEndian.Uint16(entry[4:]))
+		start := int64(binary.LittleEndian.Uint32(entry[8:])) | int64(binary.LittleEndian.Uint16(entry[6:]))<<32
+
+		// Uninitialized extents read as zeros
+		if length > 32768 {
+			continue
+		}
+
+		for b := int64(0); b < length && logical+b < dataBlocks; b++ {
+			err := fs.readBlock(start+b, data, logical+b)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (fs *extFS) readBlockMap(block []byte, data []byte, numBlocks int64) error {
+	var logical int64
+
+	for i := 0; i < 15 && logical < numBlocks; i++ {
+		ptr := int64(binary.LittleEndian.Uint32(block[i*4:]))
+
+		// 12 direct pointers followed by single, double and triple indirect ones
+		level := 0
+		if i >= 12 {
+			level = i - 11
+		}
+
+		var err error
+
+		logical, err = fs.readIndirect(ptr, level, data, logical, numBlocks)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fs *extFS) readIndirect(ptr int64, level int, data []byte, logical, numBlocks int64) (int64, error) {
+	perBlock := fs.blockSize / 4
+
+	covered := int64(1)
+	for i := 0; i < level; i++ {
+		covered *= perBlock
+	}
+
+	if ptr == 0 {
+		return logical + covered, nil
+	}
+
+	if level == 0 {
+		return logical + 1, fs.readBlock(ptr, data, logical)
+	}
+
+	ptrs := make([]byte, fs.blockSize)
+
+	_, err := fs.r.ReadAt(ptrs, ptr*fs.blockSize)
+	if err != nil {
+		return 0, bosherr.WrapErrorf(err, "Reading indirect block %d", ptr)
+	}
+
+	for i := int64(0); i < perBlock && logical < numBlocks; i++ {
+		logical, err = fs.readIndirect(int64(binary.LittleEndian.Uint32(ptrs[i*4:])), level-1, data, logical, numBlocks)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return logical, nil
+}
+
+func (fs *extFS) readBlock(physical int64, data []byte, logical int64) error {
+	off := logical * fs.blockSize
+	if off+fs.blockSize > int64(len(data)) {
+		return nil
+	}
+
+	_, err := fs.r.ReadAt(data[off:off+fs.blockSize], physical*fs.blockSize)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading block %d", physical)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+const sectorSize = 512
+
+type partition struct {
+	offset int64
+	size   int64
+}
+
+// imageFiles lists files of the root filesystem found in a raw or qcow2 disk image
+func imageFiles(r io.ReaderAt, size int64) ([]File, error) {
+	if isQCOW2(r) {
+		img, err := newQCOW2Image(r)
+		if err != nil {
+			return nil, err
+		}
+
+		r, size = img, img.Size()
+	}
+
+	root, err := rootPartition(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	fs, err := newExtFS(io.NewSectionReader(r, root.offset, root.size))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+
+	err = fs.Walk(func(file File) error {
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// rootPartition returns the largest ext partition, or whole disk if it is not partitioned
+func rootPartition(r io.ReaderAt, size int64) (partition, error) {
+	if isExtFS(r) {
+		return partition{offset: 0, size: size}, nil
+	}
+
+	candidates, err := partitions(r)
+	if err != nil {
+		return partition{}, err
+	}
+
+	var root partition
+
+	for _, p := range candidates {
+		if p.size > root.size && isExtFS(io.NewSectionReader(r, p.offset, p.size)) {
+			root = p
+		}
+	}
+
+	if root.size == 0 {
+		return partition{}, bosherr.Error("Expected disk image to contain ext filesystem")
+	}
+
+	return root, nil
+}
+
+func partitions(r io.ReaderAt) ([]partition, error) {
+	mbr := make([]byte, sectorSize)
+
+	_, err := r.ReadAt(mbr, 0)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading master boot record")
+	}
+
+	if mbr[510] != 0x55 || mbr[511] != 0xAA {
+		return nil, nil
+	}
+
+	var parts []partition
+
+	for i := 0; i < 4; i++ {
+		entry := mbr[446+i*16:]
+
+		partType := entry[4]
+		if partType == 0 {
+			continue
+		}
+
+		// Protective MBR entry
+		if partType == 0xEE {
+			return gptPartitions(r)
+		}
+
+		parts = append(parts, partition{
+			offset: int64(binary.LittleEndian.Uint32(entry[8:])) * sectorSize,
+			size:   int64(binary.LittleEndian.Uint32(entry[12:])) * sectorSize,
+		})
+	}
+
+	return parts, nil
+}
+
+func gptPartitions(r io.ReaderAt) ([]partition, error) {
+	header := make([]byte, 92)
+
+	_, err := r.ReadAt(header, sectorSize)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading GPT header")
+	}
+
+	if !bytes.Equal(header[:8], []byte("EFI PART")) {
+		return nil, bosherr.Error("Expected GPT header after protective MBR")
+	}
+
+	entriesLBA := int64(binary.LittleEndian.Uint64(header[0x48:]))
+	numEntries := int64(binary.LittleEndian.Uint32(header[0x50:]))
+	entrySize := int64(binary.LittleEndian.Uint32(header[0x54:]))
+
+	if entrySize < 128 || numEntries > 1024 {
+		return nil, bosherr.Error("Invalid GPT header")
+	}
+
+	entries := make([]byte, numEntries*entrySize)
+
+	_, err = r.ReadAt(entries, entriesLBA*sectorSize)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading GPT partition entries")
+	}
+
+	var parts []partition
+
+	for i := int64(0); i < numEntries; i++ {
+		entry := entries[i*entrySize:]
+
+		if bytes.Equal(entry[:16], make([]byte, 16)) {
+			continue
+		}
+
+		first := int64(binary.LittleEndian.Uint64(entry[0x20:]))
+		last := int64(binary.LittleEndian.Uint64(entry[0x28:]))
+
+		parts = append(parts, partition{offset: first * sectorSize, size: (last - first + 1) * sectorSize})
+	}
+
+	return parts, nil
+}
@@ -0,0 +1,317 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package inspectfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+)
+
+type FakeArchive struct {
+	DevToolsFilesStub        func() ([]string, error)
+	devToolsFilesMutex       sync.RWMutex
+	devToolsFilesArgsForCall []struct {
+	}
+	devToolsFilesReturns struct {
+		result1 []string
+		result2 error
+	}
+	devToolsFilesReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	FilesStub        func() ([]inspect.File, error)
+	filesMutex       sync.RWMutex
+	filesArgsForCall []struct {
+	}
+	filesReturns struct {
+		result1 []inspect.File
+		result2 error
+	}
+	filesReturnsOnCall map[int]struct {
+		result1 []inspect.File
+		result2 error
+	}
+	PackagesStub        func() ([]inspect.Package, error)
+	packagesMutex       sync.RWMutex
+	packagesArgsForCall []struct {
+	}
+	packagesReturns struct {
+		result1 []inspect.Package
+		result2 error
+	}
+	packagesReturnsOnCall map[int]struct {
+		result1 []inspect.Package
+		result2 error
+	}
+	ValidateStub        func() ([]inspect.Problem, error)
+	validateMutex       sync.RWMutex
+	validateArgsForCall []struct {
+	}
+	validateReturns struct {
+		result1 []inspect.Problem
+		result2 error
+	}
+	validateReturnsOnCall map[int]struct {
+		result1 []inspect.Problem
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeArchive) DevToolsFiles() ([]string, error) {
+	fake.devToolsFilesMutex.Lock()
+	ret, specificReturn := fake.devToolsFilesReturnsOnCall[len(fake.devToolsFilesArgsForCall)]
+	fake.devToolsFilesArgsForCall = append(fake.devToolsFilesArgsForCall, struct {
+	}{})
+	stub := fake.DevToolsFilesStub
+	fakeReturns := fake.devToolsFilesReturns
+	fake.recordInvocation("DevToolsFiles", []interface{}{})
+	fake.devToolsFilesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeArchive) DevToolsFilesCallCount() int {
+	fake.devToolsFilesMutex.RLock()
+	defer fake.devToolsFilesMutex.RUnlock()
+	return len(fake.devToolsFilesArgsForCall)
+}
+
+func (fake *FakeArchive) DevToolsFilesCalls(stub func() ([]string, error)) {
+	fake.devToolsFilesMutex.Lock()
+	defer fake.devToolsFilesMutex.Unlock()
+	fake.DevToolsFilesStub = stub
+}
+
+func (fake *FakeArchive) DevToolsFilesReturns(result1 []string, result2 error) {
+	fake.devToolsFilesMutex.Lock()
+	defer fake.devToolsFilesMutex.Unlock()
+	fake.DevToolsFilesStub = nil
+	fake.devToolsFilesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) DevToolsFilesReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.devToolsFilesMutex.Lock()
+	defer fake.devToolsFilesMutex.Unlock()
+	fake.DevToolsFilesStub = nil
+	if fake.devToolsFilesReturnsOnCall == nil {
+		fake.devToolsFilesReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.devToolsFilesReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) Files() ([]inspect.File, error) {
+	fake.filesMutex.Lock()
+	ret, specificReturn := fake.filesReturnsOnCall[len(fake.filesArgsForCall)]
+	fake.filesArgsForCall = append(fake.filesArgsForCall, struct {
+	}{})
+	stub := fake.FilesStub
+	fakeReturns := fake.filesReturns
+	fake.recordInvocation("Files", []interface{}{})
+	fake.filesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeArchive) FilesCallCount() int {
+	fake.filesMutex.RLock()
+	defer fake.filesMutex.RUnlock()
+	return len(fake.filesArgsForCall)
+}
+
+func (fake *FakeArchive) FilesCalls(stub func() ([]inspect.File, error)) {
+	fake.filesMutex.Lock()
+	defer fake.filesMutex.Unlock()
+	fake.FilesStub = stub
+}
+
+func (fake *FakeArchive) FilesReturns(result1 []inspect.File, result2 error) {
+	fake.filesMutex.Lock()
+	defer fake.filesMutex.Unlock()
+	fake.FilesStub = nil
+	fake.filesReturns = struct {
+		result1 []inspect.File
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) FilesReturnsOnCall(i int, result1 []inspect.File, result2 error) {
+	fake.filesMutex.Lock()
+	defer fake.filesMutex.Unlock()
+	fake.FilesStub = nil
+	if fake.filesReturnsOnCall == nil {
+		fake.filesReturnsOnCall = make(map[int]struct {
+			result1 []inspect.File
+			result2 error
+		})
+	}
+	fake.filesReturnsOnCall[i] = struct {
+		result1 []inspect.File
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) Packages() ([]inspect.Package, error) {
+	fake.packagesMutex.Lock()
+	ret, specificReturn := fake.packagesReturnsOnCall[len(fake.packagesArgsForCall)]
+	fake.packagesArgsForCall = append(fake.packagesArgsForCall, struct {
+	}{})
+	stub := fake.PackagesStub
+	fakeReturns := fake.packagesReturns
+	fake.recordInvocation("Packages", []interface{}{})
+	fake.packagesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeArchive) PackagesCallCount() int {
+	fake.packagesMutex.RLock()
+	defer fake.packagesMutex.RUnlock()
+	return len(fake.packagesArgsForCall)
+}
+
+func (fake *FakeArchive) PackagesCalls(stub func() ([]inspect.Package, error)) {
+	fake.packagesMutex.Lock()
+	defer fake.packagesMutex.Unlock()
+	fake.PackagesStub = stub
+}
+
+func (fake *FakeArchive) PackagesReturns(result1 []inspect.Package, result2 error) {
+	fake.packagesMutex.Lock()
+	defer fake.packagesMutex.Unlock()
+	fake.PackagesStub = nil
+	fake.packagesReturns = struct {
+		result1 []inspect.Package
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) PackagesReturnsOnCall(i int, result1 []inspect.Package, result2 error) {
+	fake.packagesMutex.Lock()
+	defer fake.packagesMutex.Unlock()
+	fake.PackagesStub = nil
+	if fake.packagesReturnsOnCall == nil {
+		fake.packagesReturnsOnCall = make(map[int]struct {
+			result1 []inspect.Package
+			result2 error
+		})
+	}
+	fake.packagesReturnsOnCall[i] = struct {
+		result1 []inspect.Package
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) Validate() ([]inspect.Problem, error) {
+	fake.validateMutex.Lock()
+	ret, specificReturn := fake.validateReturnsOnCall[len(fake.validateArgsForCall)]
+	fake.validateArgsForCall = append(fake.validateArgsForCall, struct {
+	}{})
+	stub := fake.ValidateStub
+	fakeReturns := fake.validateReturns
+	fake.recordInvocation("Validate", []interface{}{})
+	fake.validateMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeArchive) ValidateCallCount() int {
+	fake.validateMutex.RLock()
+	defer fake.validateMutex.RUnlock()
+	return len(fake.validateArgsForCall)
+}
+
+func (fake *FakeArchive) ValidateCalls(stub func() ([]inspect.Problem, error)) {
+	fake.validateMutex.Lock()
+	defer fake.validateMutex.Unlock()
+	fake.ValidateStub = stub
+}
+
+func (fake *FakeArchive) ValidateReturns(result1 []inspect.Problem, result2 error) {
+	fake.validateMutex.Lock()
+	defer fake.validateMutex.Unlock()
+	fake.ValidateStub = nil
+	fake.validateReturns = struct {
+		result1 []inspect.Problem
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) ValidateReturnsOnCall(i int, result1 []inspect.Problem, result2 error) {
+	fake.validateMutex.Lock()
+	defer fake.validateMutex.Unlock()
+	fake.ValidateStub = nil
+	if fake.validateReturnsOnCall == nil {
+		fake.validateReturnsOnCall = make(map[int]struct {
+			result1 []inspect.Problem
+			result2 error
+		})
+	}
+	fake.validateReturnsOnCall[i] = struct {
+		result1 []inspect.Problem
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeArchive) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.devToolsFilesMutex.RLock()
+	defer fake.devToolsFilesMutex.RUnlock()
+	fake.filesMutex.RLock()
+	defer fake.filesMutex.RUnlock()
+	fake.packagesMutex.RLock()
+	defer fake.packagesMutex.RUnlock()
+	fake.validateMutex.RLock()
+	defer fake.validateMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeArchive) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ inspect.Archive = new(FakeArchive)
@@ -0,0 +1,64 @@
+package inspect
+
+import (
+	"os"
+)
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . Archive
+
+// Archive provides contents of a stemcell tarball without unpacking it to disk
+type Archive interface {
+	// Files lists files of the root filesystem inside raw or qcow2 stemcell image
+	Files() ([]File, error)
+
+	// Packages returns OS packages listed in packages.txt
+	Packages() ([]Package, error)
+
+	// DevToolsFiles returns paths listed in dev_tools_file_list
+	DevToolsFiles() ([]string, error)
+
+	// Validate checks stemcell.MF and image digest
+	Validate() ([]Problem, error)
+}
+
+type File struct {
+	Path       string
+	Mode       os.FileMode
+	UID        uint32
+	GID        uint32
+	Size       int64
+	LinkTarget string
+}
+
+type Package struct {
+	Name         string
+	Version      string
+	Architecture string
+}
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeUpdated = "updated"
+)
+
+type PackageChange struct {
+	Name   string
+	Change string
+	Before string
+	After  string
+}
+
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+)
+
+type Problem struct {
+	Level   string
+	Field   string
+	Message string
+}
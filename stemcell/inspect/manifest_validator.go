@@ -0,0 +1,149 @@
+package inspect
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	digestRegexes = map[string]*regexp.Regexp{
+		"sha1":   regexp.MustCompile(`^[0-9a-f]{40}$`),
+		"sha256": regexp.MustCompile(`^[0-9a-f]{64}$`),
+		"sha512": regexp.MustCompile(`^[0-9a-f]{128}$`),
+	}
+
+	knownManifestFields = []string{
+		"name", "version", "operating_system", "sha1", "bosh_protocol",
+		"stemcell_formats", "api_version", "cloud_properties",
+	}
+)
+
+// ValidateManifest checks stemcell.MF contents against fields
+// that the director and CPIs rely on when uploading stemcells
+func ValidateManifest(bytes []byte) []Problem {
+	var manifest map[interface{}]interface{}
+
+	err := yaml.Unmarshal(bytes, &manifest)
+	if err != nil {
+		return []Problem{{LevelError, "stemcell.MF", fmt.Sprintf("Expected valid YAML: %s", err)}}
+	}
+
+	if manifest == nil {
+		return []Problem{{LevelError, "stemcell.MF", "Expected YAML hash"}}
+	}
+
+	var problems []Problem
+
+	addErr := func(field, msg string, args ...interface{}) {
+		problems = append(problems, Problem{LevelError, field, fmt.Sprintf(msg, args...)})
+	}
+
+	addWarn := func(field, msg string, args ...interface{}) {
+		problems = append(problems, Problem{LevelWarning, field, fmt.Sprintf(msg, args...)})
+	}
+
+	for _, field := range []string{"name", "operating_system"} {
+		if value, ok := manifest[field].(string); !ok || len(value) == 0 {
+			addErr(field, "Expected non-empty string")
+		}
+	}
+
+	switch version := manifest["version"].(type) {
+	case string:
+		if len(version) == 0 {
+			addErr("version", "Expected non-empty string")
+		}
+	case int, float64:
+		addWarn("version", "Expected quoted string; numeric value '%v' may not match intended version (e.g. '1.10' becomes '1.1')", version)
+	default:
+		addErr("version", "Expected non-empty string")
+	}
+
+	if sha1, ok := manifest["sha1"].(string); ok && len(sha1) > 0 {
+		for _, digest := range strings.Split(sha1, ";") {
+			algo, value, found := strings.Cut(digest, ":")
+			if !found {
+				algo, value = "sha1", digest
+			}
+
+			if regex, known := digestRegexes[algo]; !known || !regex.MatchString(value) {
+				addErr("sha1", "Expected image digest(s) like '<sha1>' or 'sha256:<sha256>' but got '%s'", digest)
+			}
+		}
+	} else {
+		addErr("sha1", "Expected digest of the image")
+	}
+
+	if value, found := manifest["bosh_protocol"]; found {
+		if _, ok := value.(int); !ok {
+			if str, ok := value.(string); !ok || !isDigits(str) {
+				addErr("bosh_protocol", "Expected integer")
+			}
+		}
+	}
+
+	if value, found := manifest["api_version"]; found {
+		if apiVersion, ok := value.(int); !ok || apiVersion < 1 {
+			addErr("api_version", "Expected positive integer")
+		}
+	}
+
+	if value, found := manifest["stemcell_formats"]; found {
+		formats, ok := value.([]interface{})
+		if !ok || len(formats) == 0 {
+			addErr("stemcell_formats", "Expected non-empty array of strings")
+		}
+
+		for i, format := range formats {
+			if str, ok := format.(string); !ok || len(str) == 0 {
+				addErr(fmt.Sprintf("stemcell_formats[%d]", i), "Expected non-empty string")
+			}
+		}
+	} else {
+		addWarn("stemcell_formats", "Expected stemcell formats so that director can match stemcell with CPIs")
+	}
+
+	if value, found := manifest["cloud_properties"]; found {
+		if _, ok := value.(map[interface{}]interface{}); !ok && value != nil {
+			addErr("cloud_properties", "Expected hash")
+		}
+	} else {
+		addErr("cloud_properties", "Expected hash (may be empty)")
+	}
+
+	var unknownFields []string
+
+	for key := range manifest {
+		if name := fmt.Sprintf("%v", key); !containsString(knownManifestFields, name) {
+			unknownFields = append(unknownFields, name)
+		}
+	}
+
+	sort.Strings(unknownFields)
+
+	for _, name := range unknownFields {
+		addWarn(name, "Unknown field")
+	}
+
+	return problems
+}
+
+func isDigits(str string) bool {
+	_, err := strconv.ParseUint(str, 10, 64)
+	return err == nil
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+
+	return false
+}
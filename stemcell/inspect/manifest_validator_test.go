@@ -0,0 +1,78 @@
+package inspect_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+)
+
+var _ = Describe("ValidateManifest", func() {
+	It("returns no problems for complete manifest", func() {
+		Expect(ValidateManifest([]byte(`---
+name: bosh-aws-xen-hvm-ubuntu-jammy-go_agent
+version: "1.100"
+operating_system: ubuntu-jammy
+sha1: 2ef7bde608ce5404e97d5f042f95f89f1c232871;sha256:a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447
+bosh_protocol: "1"
+api_version: 3
+stemcell_formats: [aws-light]
+cloud_properties:
+  ami: {us-east-1: ami-123}
+`))).To(BeEmpty())
+	})
+
+	It("accepts empty cloud properties", func() {
+		Expect(ValidateManifest([]byte(`---
+name: s
+version: "1"
+operating_system: os
+sha1: 2ef7bde608ce5404e97d5f042f95f89f1c232871
+stemcell_formats: [warden-tgz]
+cloud_properties:
+`))).To(BeEmpty())
+	})
+
+	It("reports missing and invalid fields", func() {
+		Expect(ValidateManifest([]byte(`---
+name: ""
+version: [1]
+sha1: md5:abc
+bosh_protocol: one
+api_version: 0
+stemcell_formats: [""]
+cloud_properties: []
+extra: true
+`))).To(Equal([]Problem{
+			{LevelError, "name", "Expected non-empty string"},
+			{LevelError, "operating_system", "Expected non-empty string"},
+			{LevelError, "version", "Expected non-empty string"},
+			{LevelError, "sha1", "Expected image digest(s) like '<sha1>' or 'sha256:<sha256>' but got 'md5:abc'"},
+			{LevelError, "bosh_protocol", "Expected integer"},
+			{LevelError, "api_version", "Expected positive integer"},
+			{LevelError, "stemcell_formats[0]", "Expected non-empty string"},
+			{LevelError, "cloud_properties", "Expected hash"},
+			{LevelWarning, "extra", "Unknown field"},
+		}))
+	})
+
+	It("warns about numeric versions and missing stemcell formats", func() {
+		Expect(ValidateManifest([]byte(`---
+name: s
+version: 1.10
+operating_system: os
+sha1: 2ef7bde608ce5404e97d5f042f95f89f1c232871
+`))).To(Equal([]Problem{
+			{LevelWarning, "version", "Expected quoted string; numeric value '1.1' may not match intended version (e.g. '1.10' becomes '1.1')"},
+			{LevelWarning, "stemcell_formats", "Expected stemcell formats so that director can match stemcell with CPIs"},
+			{LevelError, "cloud_properties", "Expected hash (may be empty)"},
+		}))
+	})
+
+	It("reports manifest that is not a YAML hash", func() {
+		problems := ValidateManifest([]byte("- item"))
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Field).To(Equal("stemcell.MF"))
+		Expect(problems[0].Message).To(ContainSubstring("Expected valid YAML"))
+	})
+})
@@ -0,0 +1,101 @@
+package inspect
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches desired/status/error columns of 'dpkg -l' output (e.g. 'ii ')
+var dpkgStatusRegex = regexp.MustCompile(`^[uirph][ncuihfwt][ R]?\s`)
+
+// parsePackages understands 'dpkg -l' output; other formats are read as
+// whitespace separated name and version per line
+func parsePackages(content string) []Package {
+	var pkgs []Package
+
+	hasArch := false
+
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "||/") {
+			hasArch = strings.Contains(line, "Architecture")
+			continue
+		}
+
+		if strings.HasPrefix(line, "Desired=") || strings.HasPrefix(line, "|") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if dpkgStatusRegex.MatchString(line) && len(fields) >= 3 {
+			pkg := Package{Name: fields[1], Version: fields[2]}
+			if hasArch && len(fields) >= 4 {
+				pkg.Architecture = fields[3]
+			}
+
+			pkgs = append(pkgs, pkg)
+			continue
+		}
+
+		pkg := Package{Name: fields[0]}
+		if len(fields) > 1 {
+			pkg.Version = fields[1]
+		}
+
+		pkgs = append(pkgs, pkg)
+	}
+
+	return pkgs
+}
+
+func parseFileList(content string) []string {
+	var paths []string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) > 0 {
+			paths = append(paths, line)
+		}
+	}
+
+	return paths
+}
+
+// DiffPackages returns added, removed and changed packages sorted by name
+func DiffPackages(before, after []Package) []PackageChange {
+	beforeVersions := map[string]string{}
+	afterVersions := map[string]string{}
+
+	for _, pkg := range before {
+		beforeVersions[pkg.Name] = pkg.Version
+	}
+
+	for _, pkg := range after {
+		afterVersions[pkg.Name] = pkg.Version
+	}
+
+	var changes []PackageChange
+
+	for name, beforeVersion := range beforeVersions {
+		afterVersion, found := afterVersions[name]
+		if !found {
+			changes = append(changes, PackageChange{Name: name, Change: ChangeRemoved, Before: beforeVersion})
+		} else if afterVersion != beforeVersion {
+			changes = append(changes, PackageChange{Name: name, Change: ChangeUpdated, Before: beforeVersion, After: afterVersion})
+		}
+	}
+
+	for name, afterVersion := range afterVersions {
+		if _, found := beforeVersions[name]; !found {
+			changes = append(changes, PackageChange{Name: name, Change: ChangeAdded, After: afterVersion})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+
+	return changes
+}
@@ -0,0 +1,35 @@
+package inspect_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/stemcell/inspect"
+)
+
+var _ = Describe("DiffPackages", func() {
+	It("returns added, removed and updated packages sorted by name", func() {
+		before := []Package{
+			{Name: "openssl", Version: "3.0.2-0ubuntu1.10"},
+			{Name: "bash", Version: "5.1-6ubuntu1"},
+			{Name: "telnet", Version: "0.17"},
+		}
+
+		after := []Package{
+			{Name: "bash", Version: "5.1-6ubuntu1"},
+			{Name: "openssl", Version: "3.0.2-0ubuntu1.12"},
+			{Name: "curl", Version: "7.81.0"},
+		}
+
+		Expect(DiffPackages(before, after)).To(Equal([]PackageChange{
+			{Name: "curl", Change: ChangeAdded, After: "7.81.0"},
+			{Name: "openssl", Change: ChangeUpdated, Before: "3.0.2-0ubuntu1.10", After: "3.0.2-0ubuntu1.12"},
+			{Name: "telnet", Change: ChangeRemoved, Before: "0.17"},
+		}))
+	})
+
+	It("returns no changes for identical lists", func() {
+		pkgs := []Package{{Name: "bash", Version: "5.1"}}
+		Expect(DiffPackages(pkgs, pkgs)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,257 @@
+package inspect
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// Read-only access to guest data of qcow2 images without backing files
+// (https://gitlab.com/qemu-project/qemu/-/blob/master/docs/interop/qcow2.txt)
+
+var qcow2Magic = []byte{'Q', 'F', 'I', 0xfb}
+
+const (
+	qcow2MinClusterBits = 9
+	qcow2MaxClusterBits = 21
+	qcow2MaxL1Entries   = 32 << 20 / 8 // Same limit as QEMU
+
+	qcow2OffsetMask     = 0x00fffffffffffe00
+	qcow2CompressedFlag = 1 << 62
+	qcow2ZeroFlag       = 1
+
+	// Incompatible features that change how guest data is found
+	qcow2IncompatExternalData = 1 << 2
+	qcow2IncompatCompression  = 1 << 3
+	qcow2IncompatExtendedL2   = 1 << 4
+)
+
+type qcow2Image struct {
+	r io.ReaderAt
+
+	size        int64
+	clusterBits uint32
+	l1          []uint64
+
+	l2Cache map[uint64][]uint64
+
+	lastClusterOffset uint64
+	lastCluster       []byte
+}
+
+func isQCOW2(r io.ReaderAt) bool {
+	buf := make([]byte, len(qcow2Magic))
+
+	_, err := r.ReadAt(buf, 0)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(buf, qcow2Magic)
+}
+
+func newQCOW2Image(r io.ReaderAt) (*qcow2Image, error) {
+	header := make([]byte, 104)
+
+	_, err := r.ReadAt(header, 0)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading qcow2 header")
+	}
+
+	if !bytes.Equal(header[:4], qcow2Magic) {
+		return nil, bosherr.Error("Expected qcow2 image header")
+	}
+
+	version := binary.BigEndian.Uint32(header[4:])
+	if version != 2 && version != 3 {
+		return nil, bosherr.Errorf("Unsupported qcow2 version '%d'", version)
+	}
+
+	if binary.BigEndian.Uint64(header[8:]) != 0 {
+		return nil, bosherr.Error("Reading qcow2 images with backing files is not supported")
+	}
+
+	if binary.BigEndian.Uint32(header[32:]) != 0 {
+		return nil, bosherr.Error("Reading encrypted qcow2 images is not supported")
+	}
+
+	if version == 3 {
+		incompat := binary.BigEndian.Uint64(header[72:])
+		if incompat&(qcow2IncompatExternalData|qcow2IncompatCompression|qcow2IncompatExtendedL2) != 0 {
+			return nil, bosherr.Errorf("Reading qcow2 images with incompatible features '%#x' is not supported", incompat)
+		}
+	}
+
+	img := &qcow2Image{
+		r:           r,
+		clusterBits: binary.BigEndian.Uint32(header[20:]),
+		size:        int64(binary.BigEndian.Uint64(header[24:])),
+		l2Cache:     map[uint64][]uint64{},
+	}
+
+	if img.clusterBits < qcow2MinClusterBits || img.clusterBits > qcow2MaxClusterBits {
+		return nil, bosherr.Errorf("Invalid qcow2 cluster bits '%d'", img.clusterBits)
+	}
+
+	if img.size < 0 {
+		return nil, bosherr.Errorf("Invalid qcow2 image size '%d'", uint64(img.size))
+	}
+
+	l1Size := binary.BigEndian.Uint32(header[36:])
+	if l1Size > qcow2MaxL1Entries {
+		return nil, bosherr.Errorf("Invalid qcow2 L1 table size '%d'", l1Size)
+	}
+	l1Offset := int64(binary.BigEndian.Uint64(header[40:]))
+
+	l1Bytes := make([]byte, int64(l1Size)*8)
+
+	_, err = r.ReadAt(l1Bytes, l1Offset)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Reading qcow2 L1 table")
+	}
+
+	img.l1 = make([]uint64, l1Size)
+	for i := range img.l1 {
+		img.l1[i] = binary.BigEndian.Uint64(l1Bytes[i*8:])
+	}
+
+	return img, nil
+}
+
+func (img *qcow2Image) Size() int64 { return img.size }
+
+func (img *qcow2Image) ReadAt(p []byte, off int64) (int, error) {
+	clusterSize := int64(1) << img.clusterBits
+
+	var n int
+
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= img.size {
+			return n, io.EOF
+		}
+
+		inCluster := pos & (clusterSize - 1)
+
+		chunk := int64(len(p) - n)
+		if chunk > clusterSize-inCluster {
+			chunk = clusterSize - inCluster
+		}
+		if chunk > img.size-pos {
+			chunk = img.size - pos
+		}
+
+		err := img.readCluster(p[n:n+int(chunk)], pos-inCluster, inCluster)
+		if err != nil {
+			return n, err
+		}
+
+		n += int(chunk)
+	}
+
+	return n, nil
+}
+
+func (img *qcow2Image) readCluster(p []byte, clusterOffset, inCluster int64) error {
+	l2Bits := img.clusterBits - 3
+
+	l1Index := uint64(clusterOffset) >> (img.clusterBits + l2Bits)
+	l2Index := (uint64(clusterOffset) >> img.clusterBits) & (1<<l2Bits - 1)
+
+	if l1Index >= uint64(len(img.l1)) {
+		clear(p)
+		return nil
+	}
+
+	l2Offset := img.l1[l1Index] & qcow2OffsetMask
+	if l2Offset == 0 {
+		clear(p)
+		return nil
+	}
+
+	l2, err := img.l2Table(l2Offset)
+	if err != nil {
+		return err
+	}
+
+	entry := l2[l2Index]
+
+	if entry&qcow2CompressedFlag != 0 {
+		cluster, err := img.compressedCluster(entry)
+		if err != nil {
+			return err
+		}
+
+		copy(p, cluster[inCluster:])
+		return nil
+	}
+
+	dataOffset := entry & qcow2OffsetMask
+	if dataOffset == 0 || entry&qcow2ZeroFlag != 0 {
+		clear(p)
+		return nil
+	}
+
+	_, err = img.r.ReadAt(p, int64(dataOffset)+inCluster)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Reading qcow2 cluster at '%d'", dataOffset)
+	}
+
+	return nil
+}
+
+func (img *qcow2Image) l2Table(offset uint64) ([]uint64, error) {
+	if l2, found := img.l2Cache[offset]; found {
+		return l2, nil
+	}
+
+	buf := make([]byte, int64(1)<<img.clusterBits)
+
+	_, err := img.r.ReadAt(buf, int64(offset))
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading qcow2 L2 table at '%d'", offset)
+	}
+
+	l2 := make([]uint64, len(buf)/8)
+	for i := range l2 {
+		l2[i] = binary.BigEndian.Uint64(buf[i*8:])
+	}
+
+	img.l2Cache[offset] = l2
+
+	return l2, nil
+}
+
+func (img *qcow2Image) compressedCluster(entry uint64) ([]byte, error) {
+	x := 62 - (img.clusterBits - 8)
+
+	hostOffset := entry & (1<<x - 1)
+	sectors := (entry>>x)&(1<<(img.clusterBits-8)-1) + 1
+
+	if img.lastCluster != nil && img.lastClusterOffset == hostOffset {
+		return img.lastCluster, nil
+	}
+
+	compressed := make([]byte, sectors*512-(hostOffset&511))
+
+	// Compressed data may end before the last sector it claims
+	n, err := img.r.ReadAt(compressed, int64(hostOffset))
+	if err != nil && err != io.EOF {
+		return nil, bosherr.WrapErrorf(err, "Reading compressed qcow2 cluster at '%d'", hostOffset)
+	}
+
+	cluster := make([]byte, int64(1)<<img.clusterBits)
+
+	_, err = io.ReadFull(flate.NewReader(bytes.NewReader(compressed[:n])), cluster)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, bosherr.WrapErrorf(err, "Decompressing qcow2 cluster at '%d'", hostOffset)
+	}
+
+	img.lastClusterOffset = hostOffset
+	img.lastCluster = cluster
+
+	return cluster, nil
+}
@@ -0,0 +1,234 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+)
+
+const (
+	serviceName = "bosh-cli"
+	scopeName   = "github.com/cloudfoundry/bosh-cli/v7"
+)
+
+//counterfeiter:generate . Exporter
+
+type Exporter interface {
+	Export([]SpanData) error
+}
+
+// NewExporter returns an exporter that sends spans to an OTLP/HTTP
+// collector when destination is a URL, or appends them to a file otherwise.
+func NewExporter(destination string, fs boshsys.FileSystem, httpClient *http.Client) (Exporter, error) {
+	if strings.HasPrefix(destination, "http://") || strings.HasPrefix(destination, "https://") {
+		endpoint, err := url.Parse(destination)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Parsing OTLP collector URL '%s'", destination)
+		}
+
+		if endpoint.Path == "" || endpoint.Path == "/" {
+			endpoint.Path = "/v1/traces"
+		}
+
+		return HTTPExporter{endpoint: endpoint.String(), client: httpClient}, nil
+	}
+
+	path, err := fs.ExpandPath(destination)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Expanding OTLP traces path '%s'", destination)
+	}
+
+	return FileExporter{path: path, fs: fs}, nil
+}
+
+// FileExporter appends one OTLP/JSON ExportTraceServiceRequest per line
+type FileExporter struct {
+	path string
+	fs   boshsys.FileSystem
+}
+
+func (e FileExporter) Export(spans []SpanData) error {
+	bytes, err := MarshalOTLP(spans)
+	if err != nil {
+		return err
+	}
+
+	file, err := e.fs.OpenFile(e.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Opening OTLP traces file '%s'", e.path)
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(append(bytes, '\n'))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Writing OTLP traces file '%s'", e.path)
+	}
+
+	return nil
+}
+
+// HTTPExporter sends spans using OTLP/HTTP JSON encoding
+type HTTPExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (e HTTPExporter) Export(spans []SpanData) error {
+	payload, err := MarshalOTLP(spans)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Sending traces to OTLP collector '%s'", e.endpoint)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return bosherr.Errorf("Sending traces to OTLP collector '%s': unexpected status %d: %s",
+			e.endpoint, resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// MarshalOTLP encodes spans according to the OTLP JSON protobuf mapping
+// so that output could be consumed by any OTLP compatible collector.
+func MarshalOTLP(spans []SpanData) ([]byte, error) {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+
+	for _, s := range spans {
+		otlpSpan := otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentSpanID,
+			Name:              s.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes),
+			Status:            otlpStatus{Code: otlpStatusCodeOk},
+		}
+
+		if len(s.Error) > 0 {
+			otlpSpan.Status = otlpStatus{Code: otlpStatusCodeError, Message: s.Error}
+		}
+
+		otlpSpans = append(otlpSpans, otlpSpan)
+	}
+
+	req := otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: otlpAttributes([]Attribute{String("service.name", serviceName)}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: scopeName},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+
+	bytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshaling OTLP traces")
+	}
+
+	return bytes, nil
+}
+
+const (
+	otlpSpanKindInternal = 1
+
+	otlpStatusCodeOk    = 1
+	otlpStatusCodeError = 2
+)
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+	BoolValue   *bool   `json:"boolValue,omitempty"`
+}
+
+func otlpAttributes(attrs []Attribute) []otlpKeyValue {
+	var result []otlpKeyValue
+
+	for _, attr := range attrs {
+		var value otlpAnyValue
+
+		switch typedValue := attr.Value.(type) {
+		case string:
+			value.StringValue = &typedValue
+		case int64:
+			// 64-bit integers are encoded as strings in OTLP JSON
+			str := strconv.FormatInt(typedValue, 10)
+			value.IntValue = &str
+		case bool:
+			value.BoolValue = &typedValue
+		default:
+			str := fmt.Sprintf("%v", typedValue)
+			value.StringValue = &str
+		}
+
+		result = append(result, otlpKeyValue{Key: attr.Key, Value: value})
+	}
+
+	return result
+}
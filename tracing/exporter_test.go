@@ -0,0 +1,156 @@
+package tracing_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/tracing"
+)
+
+var _ = Describe("Exporter", func() {
+	var (
+		fs    *fakesys.FakeFileSystem
+		spans []SpanData
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+
+		spans = []SpanData{
+			{
+				TraceID:      "0102030405060708090a0b0c0d0e0f10",
+				SpanID:       "0102030405060708",
+				ParentSpanID: "1112131415161718",
+				Name:         "cpi create_vm",
+				StartTime:    time.Unix(1, 0),
+				EndTime:      time.Unix(2, 500),
+				Attributes:   []Attribute{String("cpi.method", "create_vm"), Int("cpi.api_version", 2), Bool("cpi.error.ok_to_retry", false)},
+				Error:        "Bosh::Clouds::VMCreationFailed: fake-err",
+			},
+		}
+	})
+
+	Describe("MarshalOTLP", func() {
+		It("encodes spans according to OTLP JSON mapping", func() {
+			bytes, err := MarshalOTLP(spans)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(bytes).To(MatchJSON(`{
+				"resourceSpans": [{
+					"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "bosh-cli"}}]},
+					"scopeSpans": [{
+						"scope": {"name": "github.com/cloudfoundry/bosh-cli/v7"},
+						"spans": [{
+							"traceId": "0102030405060708090a0b0c0d0e0f10",
+							"spanId": "0102030405060708",
+							"parentSpanId": "1112131415161718",
+							"name": "cpi create_vm",
+							"kind": 1,
+							"startTimeUnixNano": "1000000000",
+							"endTimeUnixNano": "2000000500",
+							"attributes": [
+								{"key": "cpi.method", "value": {"stringValue": "create_vm"}},
+								{"key": "cpi.api_version", "value": {"intValue": "2"}},
+								{"key": "cpi.error.ok_to_retry", "value": {"boolValue": false}}
+							],
+							"status": {"code": 2, "message": "Bosh::Clouds::VMCreationFailed: fake-err"}
+						}]
+					}]
+				}]
+			}`))
+		})
+
+		It("marks spans without errors as ok", func() {
+			spans[0].Error = ""
+
+			bytes, err := MarshalOTLP(spans)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(bytes)).To(ContainSubstring(`"status":{"code":1}`))
+		})
+	})
+
+	Describe("NewExporter", func() {
+		Context("when destination is a file path", func() {
+			It("writes a JSON line with spans", func() {
+				fs.ExpandPathExpanded = "/expanded/traces.json"
+
+				exporter, err := NewExporter("~/traces.json", fs, http.DefaultClient)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(exporter.Export(spans)).To(Succeed())
+
+				contents, err := fs.ReadFileString("/expanded/traces.json")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(contents).To(HaveSuffix("\n"))
+
+				lines := strings.Split(strings.TrimSpace(contents), "\n")
+				Expect(lines).To(HaveLen(1))
+
+				var req map[string]interface{}
+				Expect(json.Unmarshal([]byte(lines[0]), &req)).To(Succeed())
+				Expect(req).To(HaveKey("resourceSpans"))
+			})
+		})
+
+		Context("when destination is a URL", func() {
+			var server *ghttp.Server
+
+			BeforeEach(func() {
+				server = ghttp.NewServer()
+			})
+
+			AfterEach(func() {
+				server.Close()
+			})
+
+			It("posts spans to collector's traces endpoint", func() {
+				expectedBody, err := MarshalOTLP(spans)
+				Expect(err).ToNot(HaveOccurred())
+
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/v1/traces"),
+					ghttp.VerifyContentType("application/json"),
+					ghttp.VerifyJSON(string(expectedBody)),
+					ghttp.RespondWith(http.StatusOK, "{}"),
+				))
+
+				exporter, err := NewExporter(server.URL(), fs, http.DefaultClient)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(exporter.Export(spans)).To(Succeed())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+
+			It("keeps explicitly specified path", func() {
+				server.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/custom/traces"),
+					ghttp.RespondWith(http.StatusOK, "{}"),
+				))
+
+				exporter, err := NewExporter(server.URL()+"/custom/traces", fs, http.DefaultClient)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(exporter.Export(spans)).To(Succeed())
+			})
+
+			It("returns error if collector rejects spans", func() {
+				server.AppendHandlers(ghttp.RespondWith(http.StatusBadRequest, "bad-spans"))
+
+				exporter, err := NewExporter(server.URL(), fs, http.DefaultClient)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = exporter.Export(spans)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("unexpected status 400: bad-spans"))
+			})
+		})
+	})
+})
@@ -0,0 +1,177 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+)
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . Tracer
+
+type Tracer interface {
+	// Start begins a span which becomes a child of the most recently
+	// started span that has not ended yet.
+	Start(name string, attrs ...Attribute) Span
+
+	// Shutdown exports all ended spans
+	Shutdown() error
+}
+
+//counterfeiter:generate . Span
+
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+
+	// End marks span as failed if err is not nil
+	End(err error)
+}
+
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute    { return Attribute{Key: key, Value: value} }
+func Int(key string, value int) Attribute   { return Attribute{Key: key, Value: int64(value)} }
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+type SpanData struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+
+	Name       string
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes []Attribute
+
+	Error string
+}
+
+type tracer struct {
+	exporter    Exporter
+	timeService clock.Clock
+
+	traceID string
+	active  []*span
+	ended   []SpanData
+
+	lock *sync.Mutex
+}
+
+func NewTracer(exporter Exporter, timeService clock.Clock) Tracer {
+	return &tracer{
+		exporter:    exporter,
+		timeService: timeService,
+
+		traceID: randomHex(16),
+
+		lock: &sync.Mutex{},
+	}
+}
+
+func (t *tracer) Start(name string, attrs ...Attribute) Span {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	s := &span{
+		tracer: t,
+		data: SpanData{
+			TraceID:    t.traceID,
+			SpanID:     randomHex(8),
+			Name:       name,
+			StartTime:  t.timeService.Now(),
+			Attributes: attrs,
+		},
+	}
+
+	if len(t.active) > 0 {
+		s.data.ParentSpanID = t.active[len(t.active)-1].data.SpanID
+	}
+
+	t.active = append(t.active, s)
+
+	return s
+}
+
+func (t *tracer) Shutdown() error {
+	t.lock.Lock()
+	ended := t.ended
+	t.ended = nil
+	t.lock.Unlock()
+
+	if len(ended) == 0 {
+		return nil
+	}
+
+	return t.exporter.Export(ended)
+}
+
+func (t *tracer) end(s *span, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if s.ended {
+		return
+	}
+
+	s.ended = true
+	s.data.EndTime = t.timeService.Now()
+
+	if err != nil {
+		s.data.Error = err.Error()
+	}
+
+	for i := len(t.active) - 1; i >= 0; i-- {
+		if t.active[i] == s {
+			t.active = append(t.active[:i], t.active[i+1:]...)
+			break
+		}
+	}
+
+	t.ended = append(t.ended, s.data)
+}
+
+type span struct {
+	tracer *tracer
+	data   SpanData
+	ended  bool
+}
+
+func (s *span) SetAttributes(attrs ...Attribute) {
+	s.tracer.lock.Lock()
+	defer s.tracer.lock.Unlock()
+
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+func (s *span) End(err error) { s.tracer.end(s, err) }
+
+type noopTracer struct{}
+
+func NewNoopTracer() Tracer { return noopTracer{} }
+
+func (noopTracer) Start(string, ...Attribute) Span { return noopSpan{} }
+func (noopTracer) Shutdown() error                 { return nil }
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) End(error)                  {}
+
+func randomHex(size int) string {
+	bytes := make([]byte, size)
+
+	_, err := rand.Read(bytes)
+	if err != nil {
+		panic("Generating random trace ID: " + err.Error())
+	}
+
+	return hex.EncodeToString(bytes)
+}
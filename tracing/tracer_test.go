@@ -0,0 +1,123 @@
+package tracing_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/tracing"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing/tracingfakes"
+)
+
+var _ = Describe("Tracer", func() {
+	var (
+		exporter    *tracingfakes.FakeExporter
+		timeService *fakeclock.FakeClock
+		startTime   time.Time
+		tracer      Tracer
+	)
+
+	BeforeEach(func() {
+		exporter = &tracingfakes.FakeExporter{}
+		startTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		timeService = fakeclock.NewFakeClock(startTime)
+		tracer = NewTracer(exporter, timeService)
+	})
+
+	exportedSpans := func() []SpanData {
+		Expect(tracer.Shutdown()).To(Succeed())
+		Expect(exporter.ExportCallCount()).To(Equal(1))
+		return exporter.ExportArgsForCall(0)
+	}
+
+	It("exports ended spans with timings and attributes", func() {
+		span := tracer.Start("span-name", String("key1", "val1"))
+		timeService.Increment(time.Second)
+		span.SetAttributes(Int("key2", 2), Bool("key3", true))
+		span.End(nil)
+
+		spans := exportedSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Name).To(Equal("span-name"))
+		Expect(spans[0].TraceID).To(HaveLen(32))
+		Expect(spans[0].SpanID).To(HaveLen(16))
+		Expect(spans[0].ParentSpanID).To(BeEmpty())
+		Expect(spans[0].StartTime).To(Equal(startTime))
+		Expect(spans[0].EndTime).To(Equal(startTime.Add(time.Second)))
+		Expect(spans[0].Attributes).To(Equal([]Attribute{
+			String("key1", "val1"), Int("key2", 2), Bool("key3", true),
+		}))
+		Expect(spans[0].Error).To(BeEmpty())
+	})
+
+	It("records errors", func() {
+		tracer.Start("span-name").End(errors.New("fake-err"))
+
+		Expect(exportedSpans()[0].Error).To(Equal("fake-err"))
+	})
+
+	It("nests spans started while another span is active", func() {
+		parent := tracer.Start("parent")
+		child1 := tracer.Start("child1")
+		grandchild := tracer.Start("grandchild")
+		grandchild.End(nil)
+		child1.End(nil)
+		child2 := tracer.Start("child2")
+		child2.End(nil)
+		parent.End(nil)
+
+		spans := exportedSpans()
+		Expect(spans).To(HaveLen(4))
+
+		byName := map[string]SpanData{}
+		for _, s := range spans {
+			byName[s.Name] = s
+			Expect(s.TraceID).To(Equal(spans[0].TraceID))
+		}
+
+		Expect(byName["parent"].ParentSpanID).To(BeEmpty())
+		Expect(byName["child1"].ParentSpanID).To(Equal(byName["parent"].SpanID))
+		Expect(byName["grandchild"].ParentSpanID).To(Equal(byName["child1"].SpanID))
+		Expect(byName["child2"].ParentSpanID).To(Equal(byName["parent"].SpanID))
+	})
+
+	It("ignores ending span more than once", func() {
+		span := tracer.Start("span-name")
+		span.End(nil)
+		span.End(errors.New("fake-err"))
+
+		spans := exportedSpans()
+		Expect(spans).To(HaveLen(1))
+		Expect(spans[0].Error).To(BeEmpty())
+	})
+
+	It("does not export spans that have not ended", func() {
+		tracer.Start("span-name")
+
+		Expect(tracer.Shutdown()).To(Succeed())
+		Expect(exporter.ExportCallCount()).To(Equal(0))
+	})
+
+	It("returns export error", func() {
+		exporter.ExportReturns(errors.New("fake-err"))
+
+		tracer.Start("span-name").End(nil)
+
+		Expect(tracer.Shutdown()).To(MatchError("fake-err"))
+	})
+})
+
+var _ = Describe("NoopTracer", func() {
+	It("does nothing", func() {
+		tracer := NewNoopTracer()
+
+		span := tracer.Start("span-name", String("key", "val"))
+		span.SetAttributes(Int("key", 1))
+		span.End(errors.New("fake-err"))
+
+		Expect(tracer.Shutdown()).To(Succeed())
+	})
+})
@@ -0,0 +1,115 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package tracingfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
+)
+
+type FakeSpan struct {
+	EndStub        func(error)
+	endMutex       sync.RWMutex
+	endArgsForCall []struct {
+		arg1 error
+	}
+	SetAttributesStub        func(...tracing.Attribute)
+	setAttributesMutex       sync.RWMutex
+	setAttributesArgsForCall []struct {
+		arg1 []tracing.Attribute
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSpan) End(arg1 error) {
+	fake.endMutex.Lock()
+	fake.endArgsForCall = append(fake.endArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	stub := fake.EndStub
+	fake.recordInvocation("End", []interface{}{arg1})
+	fake.endMutex.Unlock()
+	if stub != nil {
+		fake.EndStub(arg1)
+	}
+}
+
+func (fake *FakeSpan) EndCallCount() int {
+	fake.endMutex.RLock()
+	defer fake.endMutex.RUnlock()
+	return len(fake.endArgsForCall)
+}
+
+func (fake *FakeSpan) EndCalls(stub func(error)) {
+	fake.endMutex.Lock()
+	defer fake.endMutex.Unlock()
+	fake.EndStub = stub
+}
+
+func (fake *FakeSpan) EndArgsForCall(i int) error {
+	fake.endMutex.RLock()
+	defer fake.endMutex.RUnlock()
+	argsForCall := fake.endArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpan) SetAttributes(arg1 ...tracing.Attribute) {
+	fake.setAttributesMutex.Lock()
+	fake.setAttributesArgsForCall = append(fake.setAttributesArgsForCall, struct {
+		arg1 []tracing.Attribute
+	}{arg1})
+	stub := fake.SetAttributesStub
+	fake.recordInvocation("SetAttributes", []interface{}{arg1})
+	fake.setAttributesMutex.Unlock()
+	if stub != nil {
+		fake.SetAttributesStub(arg1...)
+	}
+}
+
+func (fake *FakeSpan) SetAttributesCallCount() int {
+	fake.setAttributesMutex.RLock()
+	defer fake.setAttributesMutex.RUnlock()
+	return len(fake.setAttributesArgsForCall)
+}
+
+func (fake *FakeSpan) SetAttributesCalls(stub func(...tracing.Attribute)) {
+	fake.setAttributesMutex.Lock()
+	defer fake.setAttributesMutex.Unlock()
+	fake.SetAttributesStub = stub
+}
+
+func (fake *FakeSpan) SetAttributesArgsForCall(i int) []tracing.Attribute {
+	fake.setAttributesMutex.RLock()
+	defer fake.setAttributesMutex.RUnlock()
+	argsForCall := fake.setAttributesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSpan) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.endMutex.RLock()
+	defer fake.endMutex.RUnlock()
+	fake.setAttributesMutex.RLock()
+	defer fake.setAttributesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSpan) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ tracing.Span = new(FakeSpan)
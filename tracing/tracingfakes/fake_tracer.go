@@ -0,0 +1,178 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package tracingfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
+)
+
+type FakeTracer struct {
+	ShutdownStub        func() error
+	shutdownMutex       sync.RWMutex
+	shutdownArgsForCall []struct {
+	}
+	shutdownReturns struct {
+		result1 error
+	}
+	shutdownReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StartStub        func(string, ...tracing.Attribute) tracing.Span
+	startMutex       sync.RWMutex
+	startArgsForCall []struct {
+		arg1 string
+		arg2 []tracing.Attribute
+	}
+	startReturns struct {
+		result1 tracing.Span
+	}
+	startReturnsOnCall map[int]struct {
+		result1 tracing.Span
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTracer) Shutdown() error {
+	fake.shutdownMutex.Lock()
+	ret, specificReturn := fake.shutdownReturnsOnCall[len(fake.shutdownArgsForCall)]
+	fake.shutdownArgsForCall = append(fake.shutdownArgsForCall, struct {
+	}{})
+	stub := fake.ShutdownStub
+	fakeReturns := fake.shutdownReturns
+	fake.recordInvocation("Shutdown", []interface{}{})
+	fake.shutdownMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTracer) ShutdownCallCount() int {
+	fake.shutdownMutex.RLock()
+	defer fake.shutdownMutex.RUnlock()
+	return len(fake.shutdownArgsForCall)
+}
+
+func (fake *FakeTracer) ShutdownCalls(stub func() error) {
+	fake.shutdownMutex.Lock()
+	defer fake.shutdownMutex.Unlock()
+	fake.ShutdownStub = stub
+}
+
+func (fake *FakeTracer) ShutdownReturns(result1 error) {
+	fake.shutdownMutex.Lock()
+	defer fake.shutdownMutex.Unlock()
+	fake.ShutdownStub = nil
+	fake.shutdownReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracer) ShutdownReturnsOnCall(i int, result1 error) {
+	fake.shutdownMutex.Lock()
+	defer fake.shutdownMutex.Unlock()
+	fake.ShutdownStub = nil
+	if fake.shutdownReturnsOnCall == nil {
+		fake.shutdownReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.shutdownReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracer) Start(arg1 string, arg2 ...tracing.Attribute) tracing.Span {
+	fake.startMutex.Lock()
+	ret, specificReturn := fake.startReturnsOnCall[len(fake.startArgsForCall)]
+	fake.startArgsForCall = append(fake.startArgsForCall, struct {
+		arg1 string
+		arg2 []tracing.Attribute
+	}{arg1, arg2})
+	stub := fake.StartStub
+	fakeReturns := fake.startReturns
+	fake.recordInvocation("Start", []interface{}{arg1, arg2})
+	fake.startMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeTracer) StartCallCount() int {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	return len(fake.startArgsForCall)
+}
+
+func (fake *FakeTracer) StartCalls(stub func(string, ...tracing.Attribute) tracing.Span) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = stub
+}
+
+func (fake *FakeTracer) StartArgsForCall(i int) (string, []tracing.Attribute) {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	argsForCall := fake.startArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTracer) StartReturns(result1 tracing.Span) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = nil
+	fake.startReturns = struct {
+		result1 tracing.Span
+	}{result1}
+}
+
+func (fake *FakeTracer) StartReturnsOnCall(i int, result1 tracing.Span) {
+	fake.startMutex.Lock()
+	defer fake.startMutex.Unlock()
+	fake.StartStub = nil
+	if fake.startReturnsOnCall == nil {
+		fake.startReturnsOnCall = make(map[int]struct {
+			result1 tracing.Span
+		})
+	}
+	fake.startReturnsOnCall[i] = struct {
+		result1 tracing.Span
+	}{result1}
+}
+
+func (fake *FakeTracer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.shutdownMutex.RLock()
+	defer fake.shutdownMutex.RUnlock()
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTracer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ tracing.Tracer = new(FakeTracer)
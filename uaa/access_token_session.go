@@ -25,9 +25,11 @@ func NewAccessTokenSession(uaa UAA, token AccessToken, config ConfigUpdater, env
 // TokenFunc retrieves new access token on first time use
 // instead of using existing access token optimizing for token
 // being valid for a longer period of time. Subsequent calls
-// will reuse access token until it's time for it to be refreshed.
+// will reuse access token until it's time for it to be refreshed,
+// which happens shortly before token's expiration so that long running
+// commands (e.g. task tracking) do not hit 401s mid-way.
 func (s *AccessTokenSession) TokenFunc(retried bool) (string, error) {
-	if !s.token.IsValid() || retried {
+	if !s.token.IsValid() || retried || TokenExpiresWithin(s.token.Value(), TokenExpiryLeeway) {
 		refreshToken, refreshable := s.token.(RefreshableAccessToken)
 		if !refreshable {
 			return "", errors.New("not a refresh token")
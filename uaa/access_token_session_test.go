@@ -2,6 +2,7 @@ package uaa_test
 
 import (
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -123,6 +124,39 @@ var _ = Describe("AccessTokenSession", func() {
 			})
 		})
 
+		Context("when initial token is valid", func() {
+			BeforeEach(func() {
+				initToken.IsValidReturns(true)
+				initToken.TypeReturns("type0")
+			})
+
+			It("reuses token that does not expire soon", func() {
+				initToken.ValueReturns(tokenValueExpiringAt(time.Now().Add(time.Hour)))
+
+				header, err := sess.TokenFunc(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(header).To(HavePrefix("type0 "))
+				Expect(uaa.RefreshTokenGrantCallCount()).To(Equal(0))
+			})
+
+			It("proactively refreshes token that is about to expire", func() {
+				initToken.ValueReturns(tokenValueExpiringAt(time.Now().Add(10 * time.Second)))
+				initToken.RefreshValueReturns("refresh-value0")
+
+				token := &fakeuaa.FakeRefreshableAccessToken{
+					TypeStub:  func() string { return "type1" },
+					ValueStub: func() string { return "value1" },
+				}
+				uaa.RefreshTokenGrantReturns(token, nil)
+
+				header, err := sess.TokenFunc(false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(header).To(Equal("type1 value1"))
+				Expect(uaa.RefreshTokenGrantArgsForCall(0)).To(Equal("refresh-value0"))
+				Expect(config.UpdateConfigWithTokenCallCount()).To(Equal(1))
+			})
+		})
+
 		Context("when not refreshable", func() {
 			It("returns an error", func() {
 				token := &fakeuaa.FakeAccessToken{}
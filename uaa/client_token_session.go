@@ -9,8 +9,10 @@ func NewClientTokenSession(uaa UAA) *ClientTokenSession {
 	return &ClientTokenSession{uaa: uaa}
 }
 
+// TokenFunc retrieves new access token on first time use, when retried
+// (e.g. after a 401) and shortly before current token expires.
 func (c *ClientTokenSession) TokenFunc(retried bool) (string, error) {
-	if c.token == nil || retried {
+	if c.token == nil || retried || TokenExpiresWithin(c.token.Value(), TokenExpiryLeeway) {
 		token, err := c.uaa.ClientCredentialsGrant()
 		if err != nil {
 			return "", err
@@ -2,6 +2,7 @@ package uaa_test
 
 import (
 	"errors"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -100,6 +101,25 @@ var _ = Describe("ClientTokenSession", func() {
 				})
 			})
 
+			Context("when first token is about to expire", func() {
+				BeforeEach(func() {
+					firstToken.ValueReturns(tokenValueExpiringAt(time.Now().Add(10 * time.Second)))
+				})
+
+				It("proactively retrieves new token", func() {
+					secondToken := &fakeuaa.FakeAccessToken{
+						TypeStub:  func() string { return "type2" },
+						ValueStub: func() string { return "value2" },
+					}
+					uaa.ClientCredentialsGrantReturns(secondToken, nil)
+
+					header, err := sess.TokenFunc(false)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(header).To(Equal("type2 value2"))
+					Expect(uaa.ClientCredentialsGrantCallCount()).To(Equal(2))
+				})
+			})
+
 			Context("when retrying is set", func() {
 				It("returns an auth header with a new token", func() {
 					secondToken := &fakeuaa.FakeAccessToken{
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"strings"
+	"time"
 
 	bosherr "github.com/cloudfoundry/bosh-utils/errors"
 )
@@ -41,6 +42,47 @@ type TokenInfo struct {
 	// ...snip...
 }
 
+// TokenExpiryLeeway is how long before its expiration an access token
+// is considered stale and gets proactively refreshed.
+const TokenExpiryLeeway = 60 * time.Second
+
+// ExpiresAt returns zero time if token did not specify its expiration.
+func (i TokenInfo) ExpiresAt() time.Time {
+	if i.ExpiredAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(i.ExpiredAt), 0).UTC()
+}
+
+// ExpiresWithin returns true if token expires before given duration elapses.
+func (i TokenInfo) ExpiresWithin(d time.Duration) bool {
+	expiresAt := i.ExpiresAt()
+	if expiresAt.IsZero() {
+		return false
+	}
+	return time.Now().Add(d).After(expiresAt)
+}
+
+// NewTokenExpiryFromValue returns zero time if value is opaque
+// (not a JWT) or does not include an expiration.
+func NewTokenExpiryFromValue(value string) time.Time {
+	info, err := NewTokenInfoFromValue(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ExpiresAt()
+}
+
+// TokenExpiresWithin is false for opaque tokens since their
+// expiration cannot be determined by the client.
+func TokenExpiresWithin(value string, d time.Duration) bool {
+	info, err := NewTokenInfoFromValue(value)
+	if err != nil {
+		return false
+	}
+	return info.ExpiresWithin(d)
+}
+
 func NewTokenInfoFromValue(value string) (TokenInfo, error) {
 	var info TokenInfo
 
@@ -1,7 +1,10 @@
 package uaa_test
 
 import (
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -127,3 +130,68 @@ var _ = Describe("NewTokenInfoFromValue", func() {
 		Expect(err.Error()).To(ContainSubstring("Unmarshaling token info"))
 	})
 })
+
+func tokenValueExpiringAt(t time.Time) string {
+	claims := fmt.Sprintf(`{"user_name":"admin","exp":%d}`, t.Unix())
+	return "seg." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".seg"
+}
+
+var _ = Describe("TokenInfo", func() {
+	Describe("ExpiresAt", func() {
+		It("returns expiration time", func() {
+			Expect(TokenInfo{ExpiredAt: 123}.ExpiresAt()).To(Equal(time.Unix(123, 0).UTC()))
+		})
+
+		It("returns zero time if expiration is not set", func() {
+			Expect(TokenInfo{}.ExpiresAt().IsZero()).To(BeTrue())
+		})
+	})
+
+	Describe("ExpiresWithin", func() {
+		It("returns true if token expires within given duration", func() {
+			info := TokenInfo{ExpiredAt: int(time.Now().Add(30 * time.Second).Unix())}
+			Expect(info.ExpiresWithin(time.Minute)).To(BeTrue())
+		})
+
+		It("returns true if token already expired", func() {
+			info := TokenInfo{ExpiredAt: int(time.Now().Add(-time.Minute).Unix())}
+			Expect(info.ExpiresWithin(0)).To(BeTrue())
+		})
+
+		It("returns false if token expires later", func() {
+			info := TokenInfo{ExpiredAt: int(time.Now().Add(time.Hour).Unix())}
+			Expect(info.ExpiresWithin(time.Minute)).To(BeFalse())
+		})
+
+		It("returns false if expiration is not set", func() {
+			Expect(TokenInfo{}.ExpiresWithin(time.Minute)).To(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("TokenExpiresWithin", func() {
+	It("returns true if token value expires within given duration", func() {
+		value := tokenValueExpiringAt(time.Now().Add(30 * time.Second))
+		Expect(TokenExpiresWithin(value, time.Minute)).To(BeTrue())
+	})
+
+	It("returns false if token value expires later", func() {
+		value := tokenValueExpiringAt(time.Now().Add(time.Hour))
+		Expect(TokenExpiresWithin(value, time.Minute)).To(BeFalse())
+	})
+
+	It("returns false for opaque token values", func() {
+		Expect(TokenExpiresWithin("opaque-value", time.Minute)).To(BeFalse())
+	})
+})
+
+var _ = Describe("NewTokenExpiryFromValue", func() {
+	It("returns expiration of token value", func() {
+		expiresAt := time.Unix(time.Now().Add(time.Hour).Unix(), 0).UTC()
+		Expect(NewTokenExpiryFromValue(tokenValueExpiringAt(expiresAt))).To(Equal(expiresAt))
+	})
+
+	It("returns zero time for opaque token values", func() {
+		Expect(NewTokenExpiryFromValue("opaque-value").IsZero()).To(BeTrue())
+	})
+})
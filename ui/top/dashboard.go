@@ -0,0 +1,426 @@
+package top
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshui "github.com/cloudfoundry/bosh-cli/v7/ui"
+	boshuit "github.com/cloudfoundry/bosh-cli/v7/ui/task"
+)
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . Actions
+
+// Actions are run while the screen is suspended so that they can use the terminal
+type Actions interface {
+	Restart(deployment string, slug boshdir.InstanceSlug) error
+	SSH(deployment string, slug boshdir.InstanceSlug) error
+}
+
+type Dashboard struct {
+	director    boshdir.Director
+	collector   Collector
+	screen      Screen
+	actions     Actions
+	timeService clock.Clock
+	interval    time.Duration
+	logger      boshlog.Logger
+
+	snapshot Snapshot
+	state    State
+	history  []State
+
+	// Collecting creates director tasks and may take a while so it happens
+	// in the background; at most one collection is in flight at a time
+	collected      chan collectResult
+	collecting     bool
+	refreshPending bool
+
+	pendingDesc   string
+	pendingAction func() error
+
+	output        *taskOutput
+	outputUpdated chan struct{}
+}
+
+func NewDashboard(
+	director boshdir.Director,
+	screen Screen,
+	actions Actions,
+	timeService clock.Clock,
+	interval time.Duration,
+	logger boshlog.Logger,
+) *Dashboard {
+	return &Dashboard{
+		director:      director,
+		collector:     NewCollector(director, timeService),
+		screen:        screen,
+		actions:       actions,
+		timeService:   timeService,
+		interval:      interval,
+		logger:        logger,
+		collected:     make(chan collectResult, 1),
+		outputUpdated: make(chan struct{}, 1),
+	}
+}
+
+type collectResult struct {
+	snapshot Snapshot
+	err      error
+}
+
+// Run starts with instances of the given deployment when it's non-empty
+func (d *Dashboard) Run(deployment string) error {
+	if len(deployment) > 0 {
+		d.push(State{View: InstancesView, Deployment: deployment})
+	}
+
+	d.refresh()
+
+	ticker := d.timeService.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		err := d.draw()
+		if err != nil {
+			return err
+		}
+
+		select {
+		case key, ok := <-d.screen.Keys():
+			if !ok {
+				return nil
+			}
+
+			quit, err := d.handleKey(key)
+			if err != nil || quit {
+				return err
+			}
+
+		case <-ticker.C():
+			d.refresh()
+
+		case result := <-d.collected:
+			d.applyCollected(result)
+
+		case <-d.outputUpdated:
+		}
+	}
+}
+
+func (d *Dashboard) draw() error {
+	var output string
+
+	if d.state.View == TaskView && d.output != nil {
+		output = d.output.String()
+	}
+
+	width, height := d.screen.Size()
+
+	return d.screen.Draw(Render(d.snapshot, d.state, output, width, height))
+}
+
+func (d *Dashboard) refresh() {
+	if d.collecting {
+		d.refreshPending = true
+		return
+	}
+
+	d.collecting = true
+
+	deployment := d.state.Deployment
+
+	go func() {
+		snapshot, err := d.collector.Collect(deployment)
+		d.collected <- collectResult{snapshot: snapshot, err: err}
+	}()
+}
+
+func (d *Dashboard) applyCollected(result collectResult) {
+	d.collecting = false
+
+	if result.err != nil {
+		d.state.Message = fmt.Sprintf("Refreshing failed: %s", result.err)
+	} else {
+		d.snapshot = result.snapshot
+		d.state.Cursor = d.clampCursor(d.state.Cursor)
+	}
+
+	// Viewed deployment may have changed while collecting
+	if d.refreshPending {
+		d.refreshPending = false
+		d.refresh()
+	}
+}
+
+func (d *Dashboard) handleKey(key Key) (bool, error) {
+	if len(d.state.Prompt) > 0 {
+		d.state.Prompt = ""
+
+		if key.Code == KeyRune && (key.Rune == 'y' || key.Rune == 'Y') {
+			return false, d.runAction(d.pendingDesc, d.pendingAction)
+		}
+
+		d.state.Message = "Cancelled"
+
+		return false, nil
+	}
+
+	d.state.Message = ""
+
+	switch {
+	case key.Code == KeyCtrlC || isRune(key, 'q'):
+		return true, nil
+
+	case key.Code == KeyDown || isRune(key, 'j'):
+		d.state.Cursor = d.clampCursor(d.state.Cursor + 1)
+
+	case key.Code == KeyUp || isRune(key, 'k'):
+		d.state.Cursor = d.clampCursor(d.state.Cursor - 1)
+
+	case key.Code == KeyEnter:
+		d.drillDown()
+
+	case key.Code == KeyEsc || key.Code == KeyBackspace:
+		d.back()
+
+	case isRune(key, 't'):
+		if d.state.View != TasksView {
+			d.push(State{View: TasksView, Deployment: d.state.Deployment})
+		}
+
+	case isRune(key, 'r'):
+		slug, ok := d.selectedInstance()
+		if ok {
+			deployment := d.state.Deployment
+
+			d.state.Prompt = fmt.Sprintf("Restart instance '%s' in deployment '%s'? (y/N)", slug, deployment)
+			d.pendingDesc = fmt.Sprintf("Restart of instance '%s'", slug)
+			d.pendingAction = func() error { return d.actions.Restart(deployment, slug) }
+		}
+
+	case isRune(key, 's'):
+		slug, ok := d.selectedInstance()
+		if ok {
+			deployment := d.state.Deployment
+
+			return false, d.runAction(
+				fmt.Sprintf("SSH session to instance '%s'", slug),
+				func() error { return d.actions.SSH(deployment, slug) },
+			)
+		}
+	}
+
+	return false, nil
+}
+
+func (d *Dashboard) drillDown() {
+	switch d.state.View {
+	case DeploymentsView:
+		if d.state.Cursor < len(d.snapshot.Deployments) {
+			d.push(State{View: InstancesView, Deployment: d.snapshot.Deployments[d.state.Cursor]})
+			d.refresh()
+		}
+
+	case InstancesView:
+		slug, ok := d.selectedInstance()
+		if ok {
+			d.push(State{View: InstanceView, Deployment: d.state.Deployment, Instance: slug})
+		}
+
+	case TasksView:
+		if d.state.Cursor < len(d.snapshot.Tasks) {
+			id := d.snapshot.Tasks[d.state.Cursor].ID()
+			d.push(State{View: TaskView, Deployment: d.state.Deployment, TaskID: id})
+			d.follow(id)
+		}
+	}
+}
+
+func (d *Dashboard) push(state State) {
+	d.unfollow()
+
+	current := d.state
+	current.Message = ""
+
+	d.history = append(d.history, current)
+	d.state = state
+}
+
+func (d *Dashboard) back() {
+	if len(d.history) == 0 {
+		return
+	}
+
+	d.unfollow()
+
+	d.state = d.history[len(d.history)-1]
+	d.history = d.history[:len(d.history)-1]
+
+	if d.state.Deployment != d.snapshot.Deployment {
+		d.refresh()
+	}
+}
+
+func (d *Dashboard) selectedInstance() (boshdir.InstanceSlug, bool) {
+	switch d.state.View {
+	case InstancesView:
+		if d.snapshot.Deployment == d.state.Deployment && d.state.Cursor < len(d.snapshot.Instances) {
+			info := d.snapshot.Instances[d.state.Cursor]
+			if len(info.JobName) > 0 && len(info.ID) > 0 {
+				return boshdir.NewInstanceSlug(info.JobName, info.ID), true
+			}
+		}
+
+	case InstanceView:
+		return d.state.Instance, true
+	}
+
+	return boshdir.InstanceSlug{}, false
+}
+
+func (d *Dashboard) clampCursor(cursor int) int {
+	var rows int
+
+	switch d.state.View {
+	case DeploymentsView:
+		rows = len(d.snapshot.Deployments)
+	case InstancesView:
+		if d.snapshot.Deployment == d.state.Deployment {
+			rows = len(d.snapshot.Instances)
+		}
+	case TasksView:
+		rows = len(d.snapshot.Tasks)
+	}
+
+	if cursor >= rows {
+		cursor = rows - 1
+	}
+
+	if cursor < 0 {
+		cursor = 0
+	}
+
+	return cursor
+}
+
+func (d *Dashboard) runAction(desc string, action func() error) error {
+	err := d.screen.Suspend()
+	if err != nil {
+		return err
+	}
+
+	actionErr := action()
+
+	err = d.screen.Resume()
+	if err != nil {
+		return err
+	}
+
+	d.refresh()
+
+	if actionErr != nil {
+		d.state.Message = fmt.Sprintf("%s failed: %s", desc, actionErr)
+	} else {
+		d.state.Message = fmt.Sprintf("%s finished", desc)
+	}
+
+	return nil
+}
+
+// follow streams task events into a buffer using the same reporter as 'bosh task'
+func (d *Dashboard) follow(id int) {
+	output := &taskOutput{updated: d.outputUpdated}
+	d.output = output
+
+	reporter := stoppableReporter{
+		TaskReporter: boshuit.NewReporter(boshui.NewWriterUI(output, output, d.logger), true),
+		output:       output,
+	}
+
+	go func() {
+		task, err := d.director.FindTask(id)
+		if err == nil {
+			err = task.EventOutput(reporter)
+		}
+
+		if err != nil {
+			_, _ = fmt.Fprintf(output, "\n%s\n", err)
+		}
+	}()
+}
+
+// unfollow stops polling output of the followed task; the task itself keeps running
+func (d *Dashboard) unfollow() {
+	if d.output != nil {
+		d.output.Stop()
+		d.output = nil
+	}
+}
+
+func isRune(key Key, r rune) bool {
+	return key.Code == KeyRune && key.Rune == r
+}
+
+type stoppableReporter struct {
+	boshdir.TaskReporter
+	output *taskOutput
+}
+
+var _ boshdir.StoppableTaskReporter = stoppableReporter{}
+
+func (r stoppableReporter) Stopped() bool { return r.output.Stopped() }
+
+type taskOutput struct {
+	buf     bytes.Buffer
+	updated chan struct{}
+	stopped bool
+	sync.Mutex
+}
+
+func (o *taskOutput) Write(p []byte) (int, error) {
+	o.Lock()
+	if o.stopped {
+		o.Unlock()
+		return len(p), nil
+	}
+	n, err := o.buf.Write(p)
+	o.Unlock()
+
+	// Redraw without blocking the task reporter
+	select {
+	case o.updated <- struct{}{}:
+	default:
+	}
+
+	return n, err
+}
+
+func (o *taskOutput) String() string {
+	o.Lock()
+	defer o.Unlock()
+
+	return o.buf.String()
+}
+
+func (o *taskOutput) Stop() {
+	o.Lock()
+	defer o.Unlock()
+
+	o.stopped = true
+}
+
+func (o *taskOutput) Stopped() bool {
+	o.Lock()
+	defer o.Unlock()
+
+	return o.stopped
+}
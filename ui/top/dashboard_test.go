@@ -0,0 +1,343 @@
+package top_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	. "github.com/cloudfoundry/bosh-cli/v7/ui/top"
+	"github.com/cloudfoundry/bosh-cli/v7/ui/top/topfakes"
+)
+
+var _ = Describe("Dashboard", func() {
+	var (
+		director    *fakedir.FakeDirector
+		deployment  *fakedir.FakeDeployment
+		screen      *topfakes.FakeScreen
+		actions     *topfakes.FakeActions
+		timeService *fakeclock.FakeClock
+		keys        chan Key
+		dashboard   *Dashboard
+		errCh       chan error
+	)
+
+	BeforeEach(func() {
+		director = &fakedir.FakeDirector{}
+		deployment = &fakedir.FakeDeployment{}
+		screen = &topfakes.FakeScreen{}
+		actions = &topfakes.FakeActions{}
+		timeService = fakeclock.NewFakeClock(time.Date(2020, time.May, 1, 10, 0, 0, 0, time.UTC))
+		keys = make(chan Key)
+		errCh = make(chan error, 1)
+
+		director.DeploymentsReturns([]boshdir.Deployment{
+			&fakedir.FakeDeployment{NameStub: func() string { return "redis" }},
+			&fakedir.FakeDeployment{NameStub: func() string { return "cf" }},
+		}, nil)
+
+		director.CurrentTasksReturns([]boshdir.Task{
+			&fakedir.FakeTask{
+				IDStub:          func() int { return 42 },
+				StateStub:       func() string { return "processing" },
+				DescriptionStub: func() string { return "create deployment" },
+			},
+		}, nil)
+
+		director.FindDeploymentReturns(deployment, nil)
+
+		deployment.InstanceInfosReturns([]boshdir.VMInfo{
+			{JobName: "redis", ID: "def", ProcessState: "running"},
+			{JobName: "redis", ID: "abc", ProcessState: "failing"},
+		}, nil)
+
+		screen.SizeReturns(120, 30)
+		screen.KeysReturns(keys)
+
+		dashboard = NewDashboard(director, screen, actions, timeService, 5*time.Second, boshlog.NewLogger(boshlog.LevelNone))
+	})
+
+	run := func(deploymentName string) {
+		go func() {
+			defer GinkgoRecover()
+			errCh <- dashboard.Run(deploymentName)
+		}()
+	}
+
+	lastFrame := func() string {
+		count := screen.DrawCallCount()
+		if count == 0 {
+			return ""
+		}
+		return screen.DrawArgsForCall(count - 1)
+	}
+
+	press := func(bs string) {
+		for _, key := range ParseKeys([]byte(bs)) {
+			keys <- key
+		}
+	}
+
+	// Keys are handled while snapshot is still being collected
+	waitForSnapshot := func() {
+		Eventually(lastFrame).Should(ContainSubstring(" updated "))
+	}
+
+	quit := func() {
+		press("q")
+		Eventually(errCh).Should(Receive(BeNil()))
+	}
+
+	It("draws sorted deployments and quits", func() {
+		run("")
+
+		Eventually(lastFrame).Should(MatchRegexp(`(?s)>\s+cf\s+0\s+0\n\s+redis\s+`))
+
+		quit()
+
+		Expect(director.CurrentTasksArgsForCall(0)).To(Equal(boshdir.TasksFilter{All: true}))
+		Expect(director.FindDeploymentCallCount()).To(Equal(0))
+	})
+
+	It("refreshes on every tick", func() {
+		run("")
+
+		Eventually(director.DeploymentsCallCount).Should(Equal(1))
+
+		timeService.WaitForWatcherAndIncrement(5 * time.Second)
+		Eventually(director.DeploymentsCallCount).Should(Equal(2))
+
+		quit()
+	})
+
+	It("keeps handling keys while snapshot is being collected and collects again afterwards", func() {
+		collected := make(chan struct{})
+		deployment.InstanceInfosStub = func() ([]boshdir.VMInfo, error) {
+			<-collected
+			return nil, nil
+		}
+
+		run("redis")
+
+		Eventually(lastFrame).Should(ContainSubstring("Loading..."))
+
+		press("t")
+		Eventually(lastFrame).Should(HavePrefix("bosh top: Tasks "))
+
+		timeService.WaitForWatcherAndIncrement(5 * time.Second)
+		Consistently(director.DeploymentsCallCount).Should(Equal(1))
+
+		close(collected)
+
+		Eventually(lastFrame).Should(MatchRegexp(`>\s+42\s+processing`))
+		Eventually(director.DeploymentsCallCount).Should(Equal(2))
+
+		quit()
+	})
+
+	It("stops when keys are no longer available", func() {
+		run("")
+
+		close(keys)
+
+		Eventually(errCh).Should(Receive(BeNil()))
+	})
+
+	It("returns error if drawing fails", func() {
+		screen.DrawReturns(errors.New("fake-err"))
+
+		run("")
+
+		Eventually(errCh).Should(Receive(MatchError("fake-err")))
+	})
+
+	It("shows refresh errors without stopping", func() {
+		director.LocksReturns(nil, errors.New("fake-err"))
+
+		run("")
+
+		Eventually(lastFrame).Should(ContainSubstring("Refreshing failed: Listing locks: fake-err"))
+
+		quit()
+	})
+
+	It("drills into instances of the selected deployment and back", func() {
+		run("")
+		waitForSnapshot()
+
+		press("j\r")
+
+		Eventually(lastFrame).Should(ContainSubstring("bosh top: Deployments > redis "))
+		Eventually(lastFrame).Should(MatchRegexp(`(?s)>\s+redis/abc\s+failing.*\n\s+redis/def\s`))
+		Expect(director.FindDeploymentArgsForCall(0)).To(Equal("redis"))
+
+		press("j\r")
+		Eventually(lastFrame).Should(ContainSubstring("bosh top: Deployments > redis > redis/def "))
+
+		press("\x1b\x1b")
+		Eventually(lastFrame).Should(MatchRegexp(`(?s)\s+cf\s+0\s+0\n>\s+redis\s+`))
+
+		quit()
+	})
+
+	It("starts with instances of the given deployment", func() {
+		run("redis")
+
+		Eventually(lastFrame).Should(ContainSubstring("bosh top: Deployments > redis "))
+		Eventually(lastFrame).Should(ContainSubstring("redis/abc"))
+		Expect(director.FindDeploymentArgsForCall(0)).To(Equal("redis"))
+
+		quit()
+	})
+
+	Describe("restart", func() {
+		It("restarts the selected instance after confirmation with the screen suspended", func() {
+			actions.RestartStub = func(string, boshdir.InstanceSlug) error {
+				Expect(screen.SuspendCallCount()).To(Equal(1))
+				Expect(screen.ResumeCallCount()).To(Equal(0))
+				return nil
+			}
+
+			run("redis")
+			waitForSnapshot()
+
+			press("r")
+			Eventually(lastFrame).Should(ContainSubstring("Restart instance 'redis/abc' in deployment 'redis'? (y/N)"))
+			Expect(actions.RestartCallCount()).To(Equal(0))
+
+			press("y")
+			Eventually(lastFrame).Should(ContainSubstring("Restart of instance 'redis/abc' finished"))
+
+			deploymentName, slug := actions.RestartArgsForCall(0)
+			Expect(deploymentName).To(Equal("redis"))
+			Expect(slug).To(Equal(boshdir.NewInstanceSlug("redis", "abc")))
+			Expect(screen.ResumeCallCount()).To(Equal(1))
+
+			quit()
+		})
+
+		It("does not restart unless confirmed", func() {
+			run("redis")
+			waitForSnapshot()
+
+			press("rn")
+			Eventually(lastFrame).Should(ContainSubstring("Cancelled"))
+
+			quit()
+
+			Expect(actions.RestartCallCount()).To(Equal(0))
+			Expect(screen.SuspendCallCount()).To(Equal(0))
+		})
+
+		It("shows restart errors", func() {
+			actions.RestartReturns(errors.New("fake-err"))
+
+			run("redis")
+			waitForSnapshot()
+
+			press("ry")
+			Eventually(lastFrame).Should(ContainSubstring("Restart of instance 'redis/abc' failed: fake-err"))
+
+			quit()
+		})
+
+		It("is not offered for deployments", func() {
+			run("")
+
+			press("r")
+			press("y")
+
+			quit()
+
+			Expect(actions.RestartCallCount()).To(Equal(0))
+		})
+	})
+
+	It("opens SSH session to the instance being viewed", func() {
+		run("redis")
+		waitForSnapshot()
+
+		press("\rs")
+		Eventually(actions.SSHCallCount).Should(Equal(1))
+
+		deploymentName, slug := actions.SSHArgsForCall(0)
+		Expect(deploymentName).To(Equal("redis"))
+		Expect(slug).To(Equal(boshdir.NewInstanceSlug("redis", "abc")))
+
+		Eventually(lastFrame).Should(ContainSubstring("SSH session to instance 'redis/abc' finished"))
+		Expect(screen.SuspendCallCount()).To(Equal(1))
+		Expect(screen.ResumeCallCount()).To(Equal(1))
+
+		quit()
+	})
+
+	It("returns error if screen cannot be resumed", func() {
+		screen.ResumeReturns(errors.New("fake-err"))
+
+		run("redis")
+		waitForSnapshot()
+
+		press("s")
+
+		Eventually(errCh).Should(Receive(MatchError("fake-err")))
+	})
+
+	It("follows output of the selected task", func() {
+		task := &fakedir.FakeTask{}
+		task.EventOutputStub = func(reporter boshdir.TaskReporter) error {
+			reporter.TaskStarted(42)
+			reporter.TaskOutputChunk(42, []byte(`{"time":1588327200,"stage":"Updating instance","tags":[],"total":1,"task":"redis/abc","index":1,"state":"started","progress":0}`+"\n"))
+			return nil
+		}
+		director.FindTaskReturns(task, nil)
+
+		run("")
+
+		press("t")
+		Eventually(lastFrame).Should(MatchRegexp(`>\s+42\s+processing`))
+
+		press("\r")
+		Eventually(lastFrame).Should(ContainSubstring("bosh top: Tasks > 42 "))
+		Eventually(lastFrame).Should(ContainSubstring("Updating instance: redis/abc"))
+		Expect(director.FindTaskArgsForCall(0)).To(Equal(42))
+
+		quit()
+	})
+
+	It("stops following task output when leaving the task", func() {
+		stopped := make(chan struct{})
+
+		task := &fakedir.FakeTask{}
+		task.EventOutputStub = func(reporter boshdir.TaskReporter) error {
+			reporter.TaskOutputChunk(42, []byte(`{"time":1588327200,"stage":"Updating instance","tags":[],"total":1,"task":"redis/abc","index":1,"state":"started","progress":0}`+"\n"))
+
+			for !reporter.(boshdir.StoppableTaskReporter).Stopped() {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			close(stopped)
+			return nil
+		}
+		director.FindTaskReturns(task, nil)
+
+		run("")
+
+		press("t")
+		Eventually(lastFrame).Should(MatchRegexp(`>\s+42\s+processing`))
+
+		press("\r")
+		Eventually(lastFrame).Should(ContainSubstring("Updating instance: redis/abc"))
+		Expect(stopped).ToNot(BeClosed())
+
+		press("\x1b")
+		Eventually(stopped).Should(BeClosed())
+		Eventually(lastFrame).Should(HavePrefix("bosh top: Tasks "))
+
+		quit()
+	})
+})
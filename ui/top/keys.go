@@ -0,0 +1,75 @@
+package top
+
+import (
+	"unicode/utf8"
+)
+
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyCtrlC
+)
+
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// ParseKeys decodes raw terminal input; unknown escape sequences are dropped
+func ParseKeys(bs []byte) []Key {
+	var keys []Key
+
+	for i := 0; i < len(bs); {
+		switch b := bs[i]; {
+		case b == 0x1b:
+			if i+1 < len(bs) && (bs[i+1] == '[' || bs[i+1] == 'O') {
+				end := i + 2
+				for end < len(bs) && (bs[end] < 0x40 || bs[end] > 0x7e) {
+					end++
+				}
+
+				if end < len(bs) {
+					switch bs[end] {
+					case 'A':
+						keys = append(keys, Key{Code: KeyUp})
+					case 'B':
+						keys = append(keys, Key{Code: KeyDown})
+					}
+				}
+
+				i = end + 1
+				continue
+			}
+
+			keys = append(keys, Key{Code: KeyEsc})
+			i++
+
+		case b == '\r' || b == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+			i++
+
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+			i++
+
+		case b == 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+			i++
+
+		default:
+			r, size := utf8.DecodeRune(bs[i:])
+			if r != utf8.RuneError {
+				keys = append(keys, Key{Code: KeyRune, Rune: r})
+			}
+			i += size
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,42 @@
+package top_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/ui/top"
+)
+
+var _ = Describe("ParseKeys", func() {
+	It("decodes printable characters", func() {
+		Expect(ParseKeys([]byte("jq"))).To(Equal([]Key{
+			{Code: KeyRune, Rune: 'j'},
+			{Code: KeyRune, Rune: 'q'},
+		}))
+	})
+
+	It("decodes control keys", func() {
+		Expect(ParseKeys([]byte{'\r', '\n', 0x7f, 0x03})).To(Equal([]Key{
+			{Code: KeyEnter},
+			{Code: KeyEnter},
+			{Code: KeyBackspace},
+			{Code: KeyCtrlC},
+		}))
+	})
+
+	It("decodes arrow keys in both cursor modes", func() {
+		Expect(ParseKeys([]byte("\x1b[A\x1b[B\x1bOA"))).To(Equal([]Key{
+			{Code: KeyUp},
+			{Code: KeyDown},
+			{Code: KeyUp},
+		}))
+	})
+
+	It("decodes a lone escape", func() {
+		Expect(ParseKeys([]byte{0x1b})).To(Equal([]Key{{Code: KeyEsc}}))
+	})
+
+	It("drops unknown escape sequences", func() {
+		Expect(ParseKeys([]byte("\x1b[5~\x1b[1;5Cq"))).To(Equal([]Key{{Code: KeyRune, Rune: 'q'}}))
+	})
+})
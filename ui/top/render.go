@@ -0,0 +1,389 @@
+package top
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	boshtbl "github.com/cloudfoundry/bosh-cli/v7/ui/table"
+)
+
+type View int
+
+const (
+	DeploymentsView View = iota
+	InstancesView
+	InstanceView
+	TasksView
+	TaskView
+)
+
+type State struct {
+	View   View
+	Cursor int
+
+	Deployment string
+	Instance   boshdir.InstanceSlug
+	TaskID     int
+
+	// Prompt takes precedence over Message in the status line
+	Prompt  string
+	Message string
+}
+
+const (
+	cursorMarker = ">"
+	missingValue = "-"
+)
+
+// Render produces a full frame; it has no side effects so that views can be tested without a terminal
+func Render(snapshot Snapshot, state State, taskOutput string, width, height int) string {
+	updated := "loading"
+	if !snapshot.TakenAt.IsZero() {
+		updated = "updated " + snapshot.TakenAt.UTC().Format("15:04:05 UTC")
+	}
+
+	header := []string{
+		padBetween("bosh top: "+breadcrumb(state), updated, width),
+		fmt.Sprintf("%d deployments, %d running tasks, %d locks",
+			len(snapshot.Deployments), len(snapshot.Tasks), len(snapshot.Locks)),
+		"",
+	}
+
+	status := state.Message
+	if len(state.Prompt) > 0 {
+		status = state.Prompt
+	}
+
+	status = strings.ReplaceAll(status, "\n", " ")
+
+	footer := []string{status, helpLine(state.View)}
+
+	bodyHeight := height - len(header) - len(footer)
+	if bodyHeight < 1 {
+		bodyHeight = 1
+	}
+
+	var body []string
+	var focus int
+
+	switch {
+	case snapshot.TakenAt.IsZero():
+		body = []string{"Loading..."}
+	case (state.View == InstancesView || state.View == InstanceView) && snapshot.Deployment != state.Deployment:
+		// Snapshots are collected in the background so the previous deployment may still be shown
+		body = []string{"Loading..."}
+	case state.View == DeploymentsView:
+		body, focus = renderDeployments(snapshot, state.Cursor)
+	case state.View == InstancesView:
+		body, focus = renderInstances(snapshot, state.Cursor)
+	case state.View == InstanceView:
+		body, focus = renderInstance(snapshot, state.Instance)
+	case state.View == TasksView:
+		body, focus = renderTasks(snapshot, state.Cursor)
+	case state.View == TaskView:
+		body, focus = renderTaskOutput(taskOutput)
+	}
+
+	body = scroll(body, focus, bodyHeight)
+	for len(body) < bodyHeight {
+		body = append(body, "")
+	}
+
+	var lines []string
+	for _, line := range append(append(header, body...), footer...) {
+		lines = append(lines, truncate(line, width))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func breadcrumb(state State) string {
+	switch state.View {
+	case InstancesView:
+		return "Deployments > " + state.Deployment
+	case InstanceView:
+		return "Deployments > " + state.Deployment + " > " + state.Instance.String()
+	case TasksView:
+		return "Tasks"
+	case TaskView:
+		return "Tasks > " + strconv.Itoa(state.TaskID)
+	default:
+		return "Deployments"
+	}
+}
+
+func helpLine(view View) string {
+	switch view {
+	case InstancesView:
+		return "j/k move  enter details  r restart  s ssh  t tasks  esc back  q quit"
+	case InstanceView:
+		return "r restart  s ssh  t tasks  esc back  q quit"
+	case TasksView:
+		return "j/k move  enter follow  esc back  q quit"
+	case TaskView:
+		return "esc back  q quit"
+	default:
+		return "j/k move  enter instances  t tasks  q quit"
+	}
+}
+
+func renderDeployments(snapshot Snapshot, cursor int) ([]string, int) {
+	table := boshtbl.Table{
+		Header: boshtbl.NewHeadersFromStrings([]string{" ", "Deployment", "Running Tasks", "Locks"}),
+	}
+
+	for i, name := range snapshot.Deployments {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			marker(i == cursor),
+			boshtbl.NewValueString(name),
+			boshtbl.NewValueInt(len(snapshot.TasksForDeployment(name))),
+			boshtbl.NewValueInt(len(snapshot.LocksForDeployment(name))),
+		})
+	}
+
+	if len(table.Rows) == 0 {
+		return []string{"No deployments"}, 0
+	}
+
+	return tableLines(table), cursor + 1
+}
+
+func renderInstances(snapshot Snapshot, cursor int) ([]string, int) {
+	table := boshtbl.Table{
+		Header: boshtbl.NewHeadersFromStrings([]string{
+			" ", "Instance", "Process State", "AZ", "IPs", "Load (1m, 5m, 15m)",
+			"CPU User", "CPU Sys", "CPU Wait", "Memory", "Swap",
+			"System Disk", "Ephemeral Disk", "Persistent Disk",
+		}),
+	}
+
+	for i, info := range snapshot.Instances {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			marker(i == cursor),
+			boshtbl.NewValueString(instanceName(info)),
+			boshtbl.NewValueString(info.InstanceState()),
+			boshtbl.NewValueString(info.AZ),
+			boshtbl.NewValueString(strings.Join(info.IPs, ", ")),
+			boshtbl.NewValueString(strings.Join(info.Vitals.Load, ", ")),
+			boshtbl.NewValueString(percent(info.Vitals.CPU.User)),
+			boshtbl.NewValueString(percent(info.Vitals.CPU.Sys)),
+			boshtbl.NewValueString(percent(info.Vitals.CPU.Wait)),
+			boshtbl.NewValueString(memSize(info.Vitals.Mem)),
+			boshtbl.NewValueString(memSize(info.Vitals.Swap)),
+			boshtbl.NewValueString(percent(info.Vitals.SystemDisk().Percent)),
+			boshtbl.NewValueString(percent(info.Vitals.EphemeralDisk().Percent)),
+			boshtbl.NewValueString(percent(info.Vitals.PersistentDisk().Percent)),
+		})
+	}
+
+	if len(table.Rows) == 0 {
+		return []string{"No instances"}, 0
+	}
+
+	return tableLines(table), cursor + 1
+}
+
+func renderInstance(snapshot Snapshot, slug boshdir.InstanceSlug) ([]string, int) {
+	info, found := snapshot.FindInstance(slug)
+	if !found {
+		return []string{fmt.Sprintf("Instance '%s' not found", slug)}, 0
+	}
+
+	lines := []string{
+		"Instance       " + instanceName(info),
+		"Process State  " + info.InstanceState(),
+		"AZ             " + info.AZ,
+		"IPs            " + strings.Join(info.IPs, ", "),
+		"VM CID         " + info.VMID,
+		"Load           " + strings.Join(info.Vitals.Load, ", "),
+		fmt.Sprintf("CPU            %s user, %s sys, %s wait",
+			percent(info.Vitals.CPU.User), percent(info.Vitals.CPU.Sys), percent(info.Vitals.CPU.Wait)),
+		"Memory         " + memSize(info.Vitals.Mem),
+		"Swap           " + memSize(info.Vitals.Swap),
+		fmt.Sprintf("Disks          %s system, %s ephemeral, %s persistent",
+			percent(info.Vitals.SystemDisk().Percent),
+			percent(info.Vitals.EphemeralDisk().Percent),
+			percent(info.Vitals.PersistentDisk().Percent)),
+		"",
+	}
+
+	table := boshtbl.Table{
+		Header: boshtbl.NewHeadersFromStrings([]string{"Process", "State", "Uptime", "CPU Total", "Memory"}),
+	}
+
+	for _, p := range info.Processes {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(p.Name),
+			boshtbl.NewValueString(p.State),
+			boshtbl.NewValueString(uptime(p.Uptime.Seconds)),
+			boshtbl.NewValueString(cpuTotal(p.CPU.Total)),
+			boshtbl.NewValueString(memIntSize(p.Mem)),
+		})
+	}
+
+	if len(table.Rows) == 0 {
+		return append(lines, "No processes"), 0
+	}
+
+	return append(lines, tableLines(table)...), 0
+}
+
+func renderTasks(snapshot Snapshot, cursor int) ([]string, int) {
+	var lines []string
+
+	table := boshtbl.Table{
+		Header: boshtbl.NewHeadersFromStrings([]string{" ", "ID", "State", "Started", "User", "Deployment", "Description"}),
+	}
+
+	for i, t := range snapshot.Tasks {
+		table.Rows = append(table.Rows, []boshtbl.Value{
+			marker(i == cursor),
+			boshtbl.NewValueInt(t.ID()),
+			boshtbl.NewValueString(t.State()),
+			boshtbl.NewValueTime(t.StartedAt()),
+			boshtbl.NewValueString(t.User()),
+			boshtbl.NewValueString(t.DeploymentName()),
+			boshtbl.NewValueString(t.Description()),
+		})
+	}
+
+	focus := 0
+
+	if len(table.Rows) == 0 {
+		lines = append(lines, "No running tasks")
+	} else {
+		lines = tableLines(table)
+		focus = cursor + 1
+	}
+
+	lines = append(lines, "")
+
+	locksTable := boshtbl.Table{
+		Header: boshtbl.NewHeadersFromStrings([]string{"Lock", "Resource", "Task ID", "Expires at"}),
+	}
+
+	for _, l := range snapshot.Locks {
+		locksTable.Rows = append(locksTable.Rows, []boshtbl.Value{
+			boshtbl.NewValueString(l.Type),
+			boshtbl.NewValueString(strings.Join(l.Resource, ":")),
+			boshtbl.NewValueString(l.TaskID),
+			boshtbl.NewValueTime(l.ExpiresAt),
+		})
+	}
+
+	if len(locksTable.Rows) == 0 {
+		return append(lines, "No locks"), focus
+	}
+
+	return append(lines, tableLines(locksTable)...), focus
+}
+
+func renderTaskOutput(output string) ([]string, int) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	// Follow the tail of the output like 'bosh task' does
+	return lines, len(lines) - 1
+}
+
+func tableLines(table boshtbl.Table) []string {
+	buf := bytes.NewBufferString("")
+
+	_ = table.Print(buf)
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+
+	return lines
+}
+
+func marker(selected bool) boshtbl.Value {
+	if selected {
+		return boshtbl.NewValueString(cursorMarker)
+	}
+	return boshtbl.NewValueString(" ")
+}
+
+// scroll keeps the focused line visible within the given height
+func scroll(lines []string, focus, height int) []string {
+	if len(lines) <= height {
+		return lines
+	}
+
+	start := 0
+	if focus >= height {
+		start = focus - height + 1
+	}
+
+	return lines[start : start+height]
+}
+
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return line
+	}
+
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+
+	return string(runes[:width])
+}
+
+func padBetween(left, right string, width int) string {
+	gap := width - len([]rune(left)) - len([]rune(right))
+	if gap < 2 {
+		gap = 2
+	}
+
+	return left + strings.Repeat(" ", gap) + right
+}
+
+func percent(str string) string {
+	if len(str) == 0 {
+		return missingValue
+	}
+	return str + "%"
+}
+
+func memSize(size boshdir.VMInfoVitalsMemSize) string {
+	if len(size.Percent) == 0 || len(size.KB) == 0 {
+		return missingValue
+	}
+
+	kb, err := strconv.ParseUint(size.KB, 10, 64)
+	if err != nil {
+		return missingValue
+	}
+
+	return fmt.Sprintf("%s%% (%s)", size.Percent, humanize.Bytes(kb*1000))
+}
+
+func memIntSize(size boshdir.VMInfoVitalsMemIntSize) string {
+	if size.Percent == nil || size.KB == nil {
+		return missingValue
+	}
+	return fmt.Sprintf("%.1f%% (%s)", *size.Percent, humanize.Bytes((*size.KB)*1000))
+}
+
+func cpuTotal(total *float64) string {
+	if total == nil {
+		return missingValue
+	}
+	return fmt.Sprintf("%.1f%%", *total)
+}
+
+func uptime(secs *uint64) string {
+	if secs == nil {
+		return missingValue
+	}
+
+	return fmt.Sprintf("%dd %dh %dm %ds", *secs/60/60/24, *secs/60/60%24, *secs/60%60, *secs%60)
+}
@@ -0,0 +1,178 @@
+package top_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+	fakedir "github.com/cloudfoundry/bosh-cli/v7/director/directorfakes"
+	. "github.com/cloudfoundry/bosh-cli/v7/ui/top"
+)
+
+var _ = Describe("Render", func() {
+	var (
+		snapshot Snapshot
+	)
+
+	BeforeEach(func() {
+		secs := uint64(90061)
+		total := 1.5
+		kb := uint64(2000)
+		memPercent := 0.5
+
+		snapshot = Snapshot{
+			TakenAt:     time.Date(2020, time.May, 1, 10, 0, 5, 0, time.UTC),
+			Deployments: []string{"cf", "redis"},
+			Tasks: []boshdir.Task{
+				&fakedir.FakeTask{
+					IDStub:             func() int { return 42 },
+					StateStub:          func() string { return "processing" },
+					StartedAtStub:      func() time.Time { return time.Date(2020, time.May, 1, 9, 59, 0, 0, time.UTC) },
+					UserStub:           func() string { return "admin" },
+					DeploymentNameStub: func() string { return "redis" },
+					DescriptionStub:    func() string { return "create deployment" },
+				},
+			},
+			Locks: []boshdir.Lock{
+				{Type: "deployment", Resource: []string{"redis"}, TaskID: "42", ExpiresAt: time.Date(2020, time.May, 1, 10, 5, 0, 0, time.UTC)},
+			},
+			Deployment: "redis",
+			Instances: []boshdir.VMInfo{
+				{
+					JobName:      "redis",
+					ID:           "abc",
+					ProcessState: "running",
+					AZ:           "z1",
+					IPs:          []string{"10.0.0.2"},
+					VMID:         "vm-1",
+					Vitals: boshdir.VMInfoVitals{
+						CPU:  boshdir.VMInfoVitalsCPU{User: "1.2", Sys: "0.4", Wait: "0.1"},
+						Mem:  boshdir.VMInfoVitalsMemSize{KB: "1000", Percent: "10"},
+						Load: []string{"0.01", "0.02", "0.03"},
+						Disk: map[string]boshdir.VMInfoVitalsDiskSize{
+							"system":     {Percent: "40", InodePercent: "10"},
+							"persistent": {Percent: "5", InodePercent: "1"},
+						},
+					},
+					Processes: []boshdir.VMInfoProcess{
+						{
+							Name:   "redis-server",
+							State:  "running",
+							CPU:    boshdir.VMInfoVitalsCPU{Total: &total},
+							Mem:    boshdir.VMInfoVitalsMemIntSize{KB: &kb, Percent: &memPercent},
+							Uptime: boshdir.VMInfoVitalsUptime{Seconds: &secs},
+						},
+					},
+				},
+				{JobName: "redis", ID: "def", ProcessState: "failing"},
+			},
+		}
+	})
+
+	lines := func(frame string) []string { return strings.Split(frame, "\n") }
+
+	It("fills exactly the given height and shows summary and help", func() {
+		frame := lines(Render(snapshot, State{}, "", 100, 20))
+
+		Expect(frame).To(HaveLen(20))
+		Expect(frame[0]).To(HavePrefix("bosh top: Deployments"))
+		Expect(frame[0]).To(HaveSuffix("updated 10:00:05 UTC"))
+		Expect(frame[1]).To(Equal("2 deployments, 1 running tasks, 1 locks"))
+		Expect(frame[19]).To(Equal("j/k move  enter instances  t tasks  q quit"))
+	})
+
+	It("marks the selected deployment with its tasks and locks", func() {
+		frame := lines(Render(snapshot, State{Cursor: 1}, "", 100, 20))
+
+		Expect(frame[3]).To(MatchRegexp(`^\s+Deployment\s+Running Tasks\s+Locks$`))
+		Expect(frame[4]).To(MatchRegexp(`^\s+cf\s+0\s+0$`))
+		Expect(frame[5]).To(MatchRegexp(`^>\s+redis\s+1\s+1$`))
+	})
+
+	It("shows instance process states and vitals", func() {
+		frame := Render(snapshot, State{View: InstancesView, Deployment: "redis"}, "", 200, 20)
+
+		Expect(lines(frame)[0]).To(HavePrefix("bosh top: Deployments > redis "))
+		Expect(frame).To(MatchRegexp(`(?m)^>\s+redis/abc\s+running\s+z1\s+10.0.0.2\s+0.01, 0.02, 0.03\s+1.2%\s+0.4%\s+0.1%\s+10% \(1.0 MB\)\s+-\s+40%\s+-\s+5%$`))
+		Expect(frame).To(MatchRegexp(`\n\s+redis/def\s+failing\s+-\s+-\s+-`))
+	})
+
+	It("shows details and processes of a single instance", func() {
+		state := State{View: InstanceView, Deployment: "redis", Instance: boshdir.NewInstanceSlug("redis", "abc")}
+
+		frame := Render(snapshot, state, "", 100, 30)
+
+		Expect(frame).To(ContainSubstring("Instance       redis/abc\n"))
+		Expect(frame).To(ContainSubstring("CPU            1.2% user, 0.4% sys, 0.1% wait\n"))
+		Expect(frame).To(ContainSubstring("Disks          40% system, - ephemeral, 5% persistent\n"))
+		Expect(frame).To(MatchRegexp(`redis-server\s+running\s+1d 1h 1m 1s\s+1.5%\s+0.5% \(2.0 MB\)`))
+	})
+
+	It("reports an instance that went away", func() {
+		state := State{View: InstanceView, Deployment: "redis", Instance: boshdir.NewInstanceSlug("redis", "gone")}
+
+		Expect(Render(snapshot, state, "", 100, 30)).To(ContainSubstring("Instance 'redis/gone' not found"))
+	})
+
+	It("shows loading until the first snapshot is collected", func() {
+		frame := lines(Render(Snapshot{}, State{}, "", 100, 20))
+
+		Expect(frame[0]).To(HaveSuffix("loading"))
+		Expect(frame[3]).To(Equal("Loading..."))
+	})
+
+	It("shows loading until instances of the viewed deployment are collected", func() {
+		frame := Render(snapshot, State{View: InstancesView, Deployment: "cf"}, "", 200, 20)
+
+		Expect(lines(frame)[3]).To(Equal("Loading..."))
+		Expect(frame).ToNot(ContainSubstring("redis/abc"))
+	})
+
+	It("shows running tasks and locks", func() {
+		frame := Render(snapshot, State{View: TasksView}, "", 150, 20)
+
+		Expect(frame).To(MatchRegexp(`>\s+42\s+processing\s+Fri May  1 09:59:00 UTC 2020\s+admin\s+redis\s+create deployment`))
+		Expect(frame).To(MatchRegexp(`deployment\s+redis\s+42\s+Fri May  1 10:05:00 UTC 2020`))
+	})
+
+	It("follows the tail of task output", func() {
+		output := ""
+		for i := 0; i < 30; i++ {
+			output += "line " + strings.Repeat("x", i) + "\n"
+		}
+
+		frame := lines(Render(snapshot, State{View: TaskView, TaskID: 42}, output, 100, 10))
+
+		Expect(frame[0]).To(HavePrefix("bosh top: Tasks > 42 "))
+		Expect(frame[3:8]).To(Equal([]string{
+			"line " + strings.Repeat("x", 25),
+			"line " + strings.Repeat("x", 26),
+			"line " + strings.Repeat("x", 27),
+			"line " + strings.Repeat("x", 28),
+			"line " + strings.Repeat("x", 29),
+		}))
+	})
+
+	It("scrolls to keep the cursor visible", func() {
+		snapshot.Deployments = nil
+		for i := 0; i < 20; i++ {
+			snapshot.Deployments = append(snapshot.Deployments, "dep-"+strings.Repeat("a", i))
+		}
+
+		frame := lines(Render(snapshot, State{Cursor: 19}, "", 100, 10))
+
+		Expect(frame[7]).To(MatchRegexp(`^>\s+dep-a{19}\s+0\s+0$`))
+	})
+
+	It("prefers prompts over messages and truncates lines to the width", func() {
+		frame := lines(Render(snapshot, State{Prompt: "Restart?", Message: "ignored"}, "", 30, 10))
+
+		Expect(frame[8]).To(Equal("Restart?"))
+		for _, line := range frame {
+			Expect(len(line)).To(BeNumerically("<=", 30))
+		}
+	})
+})
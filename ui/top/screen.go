@@ -0,0 +1,244 @@
+package top
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"golang.org/x/term"
+)
+
+//counterfeiter:generate . Screen
+
+type Screen interface {
+	Size() (width, height int)
+	Draw(frame string) error
+
+	// Keys is closed once the screen stops reading input
+	Keys() <-chan Key
+
+	// Suspend hands the terminal back (e.g. for SSH) until Resume is called
+	Suspend() error
+	Resume() error
+
+	Close() error
+}
+
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l"
+	moveHome       = "\x1b[H"
+	clearLine      = "\x1b[K"
+	clearBelow     = "\x1b[J"
+
+	keyPollInterval = 100 * time.Millisecond
+)
+
+type TerminalScreen struct {
+	in  *os.File
+	out *os.File
+
+	state *term.State
+	keys  chan Key
+
+	// Reads are cut short with deadlines so that input
+	// is not stolen from SSH sessions while suspended
+	deadlines bool
+	reading   sync.Mutex
+
+	mu      sync.Mutex
+	paused  bool
+	stopped bool
+}
+
+func NewTerminalScreen(out *os.File) (*TerminalScreen, error) {
+	in, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		in = os.Stdin
+	}
+
+	// Fd() is avoided since it puts files into blocking mode which disables deadlines
+	var isTerminal bool
+	for _, f := range []*os.File{in, out} {
+		err = withFd(f, func(fd int) error {
+			isTerminal = term.IsTerminal(fd)
+			return nil
+		})
+		if err != nil || !isTerminal {
+			if in != os.Stdin {
+				_ = in.Close()
+			}
+			return nil, bosherr.Error("Expected to run in an interactive terminal")
+		}
+	}
+
+	screen := &TerminalScreen{
+		in:        in,
+		out:       out,
+		keys:      make(chan Key, 16),
+		deadlines: in.SetReadDeadline(time.Time{}) == nil,
+	}
+
+	err = screen.Resume()
+	if err != nil {
+		return nil, err
+	}
+
+	go screen.read()
+
+	return screen, nil
+}
+
+func (s *TerminalScreen) Size() (int, int) {
+	width, height := 80, 24
+
+	_ = withFd(s.out, func(fd int) error {
+		w, h, err := term.GetSize(fd)
+		if err == nil {
+			width, height = w, h
+		}
+		return nil
+	})
+
+	return width, height
+}
+
+func (s *TerminalScreen) Draw(frame string) error {
+	lines := strings.Split(frame, "\n")
+	_, err := io.WriteString(s.out, moveHome+strings.Join(lines, clearLine+"\r\n")+clearLine+clearBelow)
+	return err
+}
+
+func (s *TerminalScreen) Keys() <-chan Key { return s.keys }
+
+func (s *TerminalScreen) Suspend() error {
+	s.mu.Lock()
+	s.paused = true
+	s.mu.Unlock()
+
+	if s.deadlines {
+		// Wait for an in-flight read to time out
+		s.reading.Lock()
+		s.reading.Unlock() //nolint:staticcheck
+	}
+
+	return s.restore()
+}
+
+func (s *TerminalScreen) Resume() error {
+	err := withFd(s.in, func(fd int) error {
+		var err error
+		s.state, err = term.MakeRaw(fd)
+		return err
+	})
+	if err != nil {
+		return bosherr.WrapError(err, "Switching terminal to raw mode")
+	}
+
+	_, err = io.WriteString(s.out, enterAltScreen)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.paused = false
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *TerminalScreen) Close() error {
+	s.mu.Lock()
+	s.stopped = true
+	paused := s.paused
+	s.mu.Unlock()
+
+	if !paused {
+		err := s.restore()
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.in != os.Stdin {
+		return s.in.Close()
+	}
+
+	return nil
+}
+
+func (s *TerminalScreen) restore() error {
+	_, err := io.WriteString(s.out, leaveAltScreen)
+	if err != nil {
+		return err
+	}
+
+	if s.state == nil {
+		return nil
+	}
+
+	return withFd(s.in, func(fd int) error {
+		return term.Restore(fd, s.state)
+	})
+}
+
+func (s *TerminalScreen) read() {
+	defer close(s.keys)
+
+	buf := make([]byte, 64)
+
+	for {
+		s.mu.Lock()
+		paused, stopped := s.paused, s.stopped
+		s.mu.Unlock()
+
+		if stopped {
+			return
+		}
+
+		if paused {
+			time.Sleep(keyPollInterval)
+			continue
+		}
+
+		s.reading.Lock()
+		if s.deadlines {
+			_ = s.in.SetReadDeadline(time.Now().Add(keyPollInterval))
+		}
+		n, err := s.in.Read(buf)
+		s.reading.Unlock()
+
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				continue
+			}
+			return
+		}
+
+		for _, key := range ParseKeys(buf[:n]) {
+			s.keys <- key
+		}
+	}
+}
+
+func withFd(f *os.File, fn func(int) error) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var fnErr error
+
+	err = conn.Control(func(fd uintptr) {
+		fnErr = fn(int(fd))
+	})
+	if err != nil {
+		return err
+	}
+
+	return fnErr
+}
@@ -0,0 +1,125 @@
+package top
+
+import (
+	"sort"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+
+	boshdir "github.com/cloudfoundry/bosh-cli/v7/director"
+)
+
+// Snapshot is a point-in-time view of a director
+type Snapshot struct {
+	TakenAt time.Time
+
+	Deployments []string
+	Tasks       []boshdir.Task
+	Locks       []boshdir.Lock
+
+	// Instances are only collected for the deployment in focus
+	// since fetching vitals creates a director task per deployment
+	Deployment string
+	Instances  []boshdir.VMInfo
+}
+
+type Collector struct {
+	director    boshdir.Director
+	timeService clock.Clock
+}
+
+func NewCollector(director boshdir.Director, timeService clock.Clock) Collector {
+	return Collector{director: director, timeService: timeService}
+}
+
+func (c Collector) Collect(deploymentName string) (Snapshot, error) {
+	snapshot := Snapshot{TakenAt: c.timeService.Now(), Deployment: deploymentName}
+
+	deployments, err := c.director.Deployments()
+	if err != nil {
+		return Snapshot{}, bosherr.WrapErrorf(err, "Listing deployments")
+	}
+
+	for _, dep := range deployments {
+		snapshot.Deployments = append(snapshot.Deployments, dep.Name())
+	}
+
+	sort.Strings(snapshot.Deployments)
+
+	snapshot.Tasks, err = c.director.CurrentTasks(boshdir.TasksFilter{All: true})
+	if err != nil {
+		return Snapshot{}, bosherr.WrapErrorf(err, "Listing current tasks")
+	}
+
+	snapshot.Locks, err = c.director.Locks()
+	if err != nil {
+		return Snapshot{}, bosherr.WrapErrorf(err, "Listing locks")
+	}
+
+	if len(deploymentName) > 0 {
+		deployment, err := c.director.FindDeployment(deploymentName)
+		if err != nil {
+			return Snapshot{}, err
+		}
+
+		snapshot.Instances, err = deployment.InstanceInfos()
+		if err != nil {
+			return Snapshot{}, bosherr.WrapErrorf(err, "Fetching instances of deployment '%s'", deploymentName)
+		}
+
+		sort.SliceStable(snapshot.Instances, func(i, j int) bool {
+			return instanceName(snapshot.Instances[i]) < instanceName(snapshot.Instances[j])
+		})
+	}
+
+	return snapshot, nil
+}
+
+func (s Snapshot) TasksForDeployment(name string) []boshdir.Task {
+	var tasks []boshdir.Task
+
+	for _, t := range s.Tasks {
+		if t.DeploymentName() == name {
+			tasks = append(tasks, t)
+		}
+	}
+
+	return tasks
+}
+
+func (s Snapshot) LocksForDeployment(name string) []boshdir.Lock {
+	var locks []boshdir.Lock
+
+	for _, l := range s.Locks {
+		if l.Type == "deployment" && len(l.Resource) > 0 && l.Resource[0] == name {
+			locks = append(locks, l)
+		}
+	}
+
+	return locks
+}
+
+func (s Snapshot) FindInstance(slug boshdir.InstanceSlug) (boshdir.VMInfo, bool) {
+	for _, i := range s.Instances {
+		if i.JobName == slug.Name() && i.ID == slug.IndexOrID() {
+			return i, true
+		}
+	}
+
+	return boshdir.VMInfo{}, false
+}
+
+func instanceName(i boshdir.VMInfo) string {
+	name := "?"
+
+	if len(i.JobName) > 0 {
+		name = i.JobName
+	}
+
+	if len(i.ID) > 0 {
+		name += "/" + i.ID
+	}
+
+	return name
+}
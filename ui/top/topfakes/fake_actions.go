@@ -0,0 +1,190 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package topfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/v7/director"
+	"github.com/cloudfoundry/bosh-cli/v7/ui/top"
+)
+
+type FakeActions struct {
+	RestartStub        func(string, director.InstanceSlug) error
+	restartMutex       sync.RWMutex
+	restartArgsForCall []struct {
+		arg1 string
+		arg2 director.InstanceSlug
+	}
+	restartReturns struct {
+		result1 error
+	}
+	restartReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SSHStub        func(string, director.InstanceSlug) error
+	sSHMutex       sync.RWMutex
+	sSHArgsForCall []struct {
+		arg1 string
+		arg2 director.InstanceSlug
+	}
+	sSHReturns struct {
+		result1 error
+	}
+	sSHReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeActions) Restart(arg1 string, arg2 director.InstanceSlug) error {
+	fake.restartMutex.Lock()
+	ret, specificReturn := fake.restartReturnsOnCall[len(fake.restartArgsForCall)]
+	fake.restartArgsForCall = append(fake.restartArgsForCall, struct {
+		arg1 string
+		arg2 director.InstanceSlug
+	}{arg1, arg2})
+	stub := fake.RestartStub
+	fakeReturns := fake.restartReturns
+	fake.recordInvocation("Restart", []interface{}{arg1, arg2})
+	fake.restartMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeActions) RestartCallCount() int {
+	fake.restartMutex.RLock()
+	defer fake.restartMutex.RUnlock()
+	return len(fake.restartArgsForCall)
+}
+
+func (fake *FakeActions) RestartCalls(stub func(string, director.InstanceSlug) error) {
+	fake.restartMutex.Lock()
+	defer fake.restartMutex.Unlock()
+	fake.RestartStub = stub
+}
+
+func (fake *FakeActions) RestartArgsForCall(i int) (string, director.InstanceSlug) {
+	fake.restartMutex.RLock()
+	defer fake.restartMutex.RUnlock()
+	argsForCall := fake.restartArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeActions) RestartReturns(result1 error) {
+	fake.restartMutex.Lock()
+	defer fake.restartMutex.Unlock()
+	fake.RestartStub = nil
+	fake.restartReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeActions) RestartReturnsOnCall(i int, result1 error) {
+	fake.restartMutex.Lock()
+	defer fake.restartMutex.Unlock()
+	fake.RestartStub = nil
+	if fake.restartReturnsOnCall == nil {
+		fake.restartReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.restartReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeActions) SSH(arg1 string, arg2 director.InstanceSlug) error {
+	fake.sSHMutex.Lock()
+	ret, specificReturn := fake.sSHReturnsOnCall[len(fake.sSHArgsForCall)]
+	fake.sSHArgsForCall = append(fake.sSHArgsForCall, struct {
+		arg1 string
+		arg2 director.InstanceSlug
+	}{arg1, arg2})
+	stub := fake.SSHStub
+	fakeReturns := fake.sSHReturns
+	fake.recordInvocation("SSH", []interface{}{arg1, arg2})
+	fake.sSHMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeActions) SSHCallCount() int {
+	fake.sSHMutex.RLock()
+	defer fake.sSHMutex.RUnlock()
+	return len(fake.sSHArgsForCall)
+}
+
+func (fake *FakeActions) SSHCalls(stub func(string, director.InstanceSlug) error) {
+	fake.sSHMutex.Lock()
+	defer fake.sSHMutex.Unlock()
+	fake.SSHStub = stub
+}
+
+func (fake *FakeActions) SSHArgsForCall(i int) (string, director.InstanceSlug) {
+	fake.sSHMutex.RLock()
+	defer fake.sSHMutex.RUnlock()
+	argsForCall := fake.sSHArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeActions) SSHReturns(result1 error) {
+	fake.sSHMutex.Lock()
+	defer fake.sSHMutex.Unlock()
+	fake.SSHStub = nil
+	fake.sSHReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeActions) SSHReturnsOnCall(i int, result1 error) {
+	fake.sSHMutex.Lock()
+	defer fake.sSHMutex.Unlock()
+	fake.SSHStub = nil
+	if fake.sSHReturnsOnCall == nil {
+		fake.sSHReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.sSHReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeActions) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.restartMutex.RLock()
+	defer fake.restartMutex.RUnlock()
+	fake.sSHMutex.RLock()
+	defer fake.sSHMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeActions) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ top.Actions = new(FakeActions)
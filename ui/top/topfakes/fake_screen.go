@@ -0,0 +1,441 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package topfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry/bosh-cli/v7/ui/top"
+)
+
+type FakeScreen struct {
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
+	closeReturns struct {
+		result1 error
+	}
+	closeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DrawStub        func(string) error
+	drawMutex       sync.RWMutex
+	drawArgsForCall []struct {
+		arg1 string
+	}
+	drawReturns struct {
+		result1 error
+	}
+	drawReturnsOnCall map[int]struct {
+		result1 error
+	}
+	KeysStub        func() <-chan top.Key
+	keysMutex       sync.RWMutex
+	keysArgsForCall []struct {
+	}
+	keysReturns struct {
+		result1 <-chan top.Key
+	}
+	keysReturnsOnCall map[int]struct {
+		result1 <-chan top.Key
+	}
+	ResumeStub        func() error
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+	}
+	resumeReturns struct {
+		result1 error
+	}
+	resumeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	SizeStub        func() (int, int)
+	sizeMutex       sync.RWMutex
+	sizeArgsForCall []struct {
+	}
+	sizeReturns struct {
+		result1 int
+		result2 int
+	}
+	sizeReturnsOnCall map[int]struct {
+		result1 int
+		result2 int
+	}
+	SuspendStub        func() error
+	suspendMutex       sync.RWMutex
+	suspendArgsForCall []struct {
+	}
+	suspendReturns struct {
+		result1 error
+	}
+	suspendReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeScreen) Close() error {
+	fake.closeMutex.Lock()
+	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fakeReturns := fake.closeReturns
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScreen) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeScreen) CloseCalls(stub func() error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
+func (fake *FakeScreen) CloseReturns(result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) CloseReturnsOnCall(i int, result1 error) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = nil
+	if fake.closeReturnsOnCall == nil {
+		fake.closeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.closeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) Draw(arg1 string) error {
+	fake.drawMutex.Lock()
+	ret, specificReturn := fake.drawReturnsOnCall[len(fake.drawArgsForCall)]
+	fake.drawArgsForCall = append(fake.drawArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.DrawStub
+	fakeReturns := fake.drawReturns
+	fake.recordInvocation("Draw", []interface{}{arg1})
+	fake.drawMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScreen) DrawCallCount() int {
+	fake.drawMutex.RLock()
+	defer fake.drawMutex.RUnlock()
+	return len(fake.drawArgsForCall)
+}
+
+func (fake *FakeScreen) DrawCalls(stub func(string) error) {
+	fake.drawMutex.Lock()
+	defer fake.drawMutex.Unlock()
+	fake.DrawStub = stub
+}
+
+func (fake *FakeScreen) DrawArgsForCall(i int) string {
+	fake.drawMutex.RLock()
+	defer fake.drawMutex.RUnlock()
+	argsForCall := fake.drawArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeScreen) DrawReturns(result1 error) {
+	fake.drawMutex.Lock()
+	defer fake.drawMutex.Unlock()
+	fake.DrawStub = nil
+	fake.drawReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) DrawReturnsOnCall(i int, result1 error) {
+	fake.drawMutex.Lock()
+	defer fake.drawMutex.Unlock()
+	fake.DrawStub = nil
+	if fake.drawReturnsOnCall == nil {
+		fake.drawReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.drawReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) Keys() <-chan top.Key {
+	fake.keysMutex.Lock()
+	ret, specificReturn := fake.keysReturnsOnCall[len(fake.keysArgsForCall)]
+	fake.keysArgsForCall = append(fake.keysArgsForCall, struct {
+	}{})
+	stub := fake.KeysStub
+	fakeReturns := fake.keysReturns
+	fake.recordInvocation("Keys", []interface{}{})
+	fake.keysMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScreen) KeysCallCount() int {
+	fake.keysMutex.RLock()
+	defer fake.keysMutex.RUnlock()
+	return len(fake.keysArgsForCall)
+}
+
+func (fake *FakeScreen) KeysCalls(stub func() <-chan top.Key) {
+	fake.keysMutex.Lock()
+	defer fake.keysMutex.Unlock()
+	fake.KeysStub = stub
+}
+
+func (fake *FakeScreen) KeysReturns(result1 <-chan top.Key) {
+	fake.keysMutex.Lock()
+	defer fake.keysMutex.Unlock()
+	fake.KeysStub = nil
+	fake.keysReturns = struct {
+		result1 <-chan top.Key
+	}{result1}
+}
+
+func (fake *FakeScreen) KeysReturnsOnCall(i int, result1 <-chan top.Key) {
+	fake.keysMutex.Lock()
+	defer fake.keysMutex.Unlock()
+	fake.KeysStub = nil
+	if fake.keysReturnsOnCall == nil {
+		fake.keysReturnsOnCall = make(map[int]struct {
+			result1 <-chan top.Key
+		})
+	}
+	fake.keysReturnsOnCall[i] = struct {
+		result1 <-chan top.Key
+	}{result1}
+}
+
+func (fake *FakeScreen) Resume() error {
+	fake.resumeMutex.Lock()
+	ret, specificReturn := fake.resumeReturnsOnCall[len(fake.resumeArgsForCall)]
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+	}{})
+	stub := fake.ResumeStub
+	fakeReturns := fake.resumeReturns
+	fake.recordInvocation("Resume", []interface{}{})
+	fake.resumeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScreen) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *FakeScreen) ResumeCalls(stub func() error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = stub
+}
+
+func (fake *FakeScreen) ResumeReturns(result1 error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) ResumeReturnsOnCall(i int, result1 error) {
+	fake.resumeMutex.Lock()
+	defer fake.resumeMutex.Unlock()
+	fake.ResumeStub = nil
+	if fake.resumeReturnsOnCall == nil {
+		fake.resumeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.resumeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) Size() (int, int) {
+	fake.sizeMutex.Lock()
+	ret, specificReturn := fake.sizeReturnsOnCall[len(fake.sizeArgsForCall)]
+	fake.sizeArgsForCall = append(fake.sizeArgsForCall, struct {
+	}{})
+	stub := fake.SizeStub
+	fakeReturns := fake.sizeReturns
+	fake.recordInvocation("Size", []interface{}{})
+	fake.sizeMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeScreen) SizeCallCount() int {
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	return len(fake.sizeArgsForCall)
+}
+
+func (fake *FakeScreen) SizeCalls(stub func() (int, int)) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = stub
+}
+
+func (fake *FakeScreen) SizeReturns(result1 int, result2 int) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	fake.sizeReturns = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
+func (fake *FakeScreen) SizeReturnsOnCall(i int, result1 int, result2 int) {
+	fake.sizeMutex.Lock()
+	defer fake.sizeMutex.Unlock()
+	fake.SizeStub = nil
+	if fake.sizeReturnsOnCall == nil {
+		fake.sizeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 int
+		})
+	}
+	fake.sizeReturnsOnCall[i] = struct {
+		result1 int
+		result2 int
+	}{result1, result2}
+}
+
+func (fake *FakeScreen) Suspend() error {
+	fake.suspendMutex.Lock()
+	ret, specificReturn := fake.suspendReturnsOnCall[len(fake.suspendArgsForCall)]
+	fake.suspendArgsForCall = append(fake.suspendArgsForCall, struct {
+	}{})
+	stub := fake.SuspendStub
+	fakeReturns := fake.suspendReturns
+	fake.recordInvocation("Suspend", []interface{}{})
+	fake.suspendMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeScreen) SuspendCallCount() int {
+	fake.suspendMutex.RLock()
+	defer fake.suspendMutex.RUnlock()
+	return len(fake.suspendArgsForCall)
+}
+
+func (fake *FakeScreen) SuspendCalls(stub func() error) {
+	fake.suspendMutex.Lock()
+	defer fake.suspendMutex.Unlock()
+	fake.SuspendStub = stub
+}
+
+func (fake *FakeScreen) SuspendReturns(result1 error) {
+	fake.suspendMutex.Lock()
+	defer fake.suspendMutex.Unlock()
+	fake.SuspendStub = nil
+	fake.suspendReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) SuspendReturnsOnCall(i int, result1 error) {
+	fake.suspendMutex.Lock()
+	defer fake.suspendMutex.Unlock()
+	fake.SuspendStub = nil
+	if fake.suspendReturnsOnCall == nil {
+		fake.suspendReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.suspendReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeScreen) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	fake.drawMutex.RLock()
+	defer fake.drawMutex.RUnlock()
+	fake.keysMutex.RLock()
+	defer fake.keysMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.sizeMutex.RLock()
+	defer fake.sizeMutex.RUnlock()
+	fake.suspendMutex.RLock()
+	defer fake.suspendMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeScreen) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ top.Screen = new(FakeScreen)
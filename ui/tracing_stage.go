@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
+)
+
+type tracingStage struct {
+	stage  Stage
+	tracer tracing.Tracer
+}
+
+// NewTracingStage records a span for each (nested) step performed by stage
+func NewTracingStage(stage Stage, tracer tracing.Tracer) Stage {
+	return tracingStage{stage: stage, tracer: tracer}
+}
+
+func (s tracingStage) Perform(name string, closure func() error) error {
+	span := s.tracer.Start(name)
+
+	var closureErr error
+
+	err := s.stage.Perform(name, func() error {
+		closureErr = closure()
+		return closureErr
+	})
+
+	if skipErr, ok := closureErr.(SkipStageError); ok {
+		span.SetAttributes(tracing.String("bosh.stage.skipped", skipErr.SkipMessage()))
+	}
+
+	span.End(err)
+
+	return err
+}
+
+func (s tracingStage) PerformComplex(name string, closure func(Stage) error) error {
+	span := s.tracer.Start(name)
+
+	err := s.stage.PerformComplex(name, func(subStage Stage) error {
+		return closure(NewTracingStage(subStage, s.tracer))
+	})
+
+	span.End(err)
+
+	return err
+}
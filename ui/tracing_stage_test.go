@@ -0,0 +1,98 @@
+package ui_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-cli/v7/tracing"
+	"github.com/cloudfoundry/bosh-cli/v7/tracing/tracingfakes"
+	. "github.com/cloudfoundry/bosh-cli/v7/ui"
+	fakeui "github.com/cloudfoundry/bosh-cli/v7/ui/fakes"
+)
+
+var _ = Describe("TracingStage", func() {
+	var (
+		fakeStage *fakeui.FakeStage
+		tracer    *tracingfakes.FakeTracer
+		spans     []*tracingfakes.FakeSpan
+		stage     Stage
+	)
+
+	BeforeEach(func() {
+		fakeStage = fakeui.NewFakeStage()
+		tracer = &tracingfakes.FakeTracer{}
+		spans = nil
+		tracer.StartStub = func(string, ...tracing.Attribute) tracing.Span {
+			span := &tracingfakes.FakeSpan{}
+			spans = append(spans, span)
+			return span
+		}
+		stage = NewTracingStage(fakeStage, tracer)
+	})
+
+	Describe("Perform", func() {
+		It("records a span for the step", func() {
+			called := false
+
+			err := stage.Perform("fake-step", func() error {
+				called = true
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(called).To(BeTrue())
+
+			Expect(tracer.StartCallCount()).To(Equal(1))
+			name, _ := tracer.StartArgsForCall(0)
+			Expect(name).To(Equal("fake-step"))
+
+			Expect(spans[0].EndCallCount()).To(Equal(1))
+			Expect(spans[0].EndArgsForCall(0)).ToNot(HaveOccurred())
+		})
+
+		It("records step errors", func() {
+			err := stage.Perform("fake-step", func() error { return errors.New("fake-err") })
+			Expect(err).To(MatchError("fake-err"))
+
+			Expect(spans[0].EndArgsForCall(0)).To(MatchError("fake-err"))
+		})
+
+		It("marks skipped steps", func() {
+			skipErr := NewSkipStageError(errors.New("fake-reason"), "fake-skip-msg")
+
+			err := stage.Perform("fake-step", func() error { return skipErr })
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(spans[0].SetAttributesCallCount()).To(Equal(1))
+			Expect(spans[0].SetAttributesArgsForCall(0)).To(Equal(
+				[]tracing.Attribute{tracing.String("bosh.stage.skipped", "fake-skip-msg")}))
+			Expect(spans[0].EndArgsForCall(0)).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("PerformComplex", func() {
+		It("records spans for stage and its nested steps", func() {
+			err := stage.PerformComplex("fake-stage", func(subStage Stage) error {
+				return subStage.Perform("fake-step", func() error { return nil })
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(tracer.StartCallCount()).To(Equal(2))
+			name, _ := tracer.StartArgsForCall(0)
+			Expect(name).To(Equal("fake-stage"))
+			name, _ = tracer.StartArgsForCall(1)
+			Expect(name).To(Equal("fake-step"))
+
+			Expect(spans[0].EndCallCount()).To(Equal(1))
+			Expect(spans[1].EndCallCount()).To(Equal(1))
+		})
+
+		It("records stage errors", func() {
+			err := stage.PerformComplex("fake-stage", func(Stage) error { return errors.New("fake-err") })
+			Expect(err).To(MatchError("fake-err"))
+
+			Expect(spans[0].EndArgsForCall(0)).To(MatchError("fake-err"))
+		})
+	})
+})
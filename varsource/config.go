@@ -0,0 +1,140 @@
+package varsource
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	TypeCredHub = "credhub"
+	TypeVault   = "vault"
+	TypeExec    = "exec"
+
+	defaultVaultMount = "secret"
+)
+
+type Config struct {
+	Type string `yaml:"type"`
+
+	// CredHub and Vault
+	URL    string `yaml:"url"`
+	CACert string `yaml:"ca_cert"` // PEM contents or path to a file
+	Prefix string `yaml:"prefix"`
+
+	// CredHub (authenticates with UAA client credentials)
+	Client       string `yaml:"client"`
+	ClientSecret string `yaml:"client_secret"`
+
+	// Vault (KV v2 secrets engine)
+	Token string `yaml:"token"`
+	Mount string `yaml:"mount"`
+
+	// Exec plugin
+	Path string   `yaml:"path"`
+	Args []string `yaml:"args"`
+}
+
+type configsFile struct {
+	Sources []Config `yaml:"sources"`
+}
+
+// NewConfigFromSpec parses short form of a source: 'credhub[:PREFIX]',
+// 'vault[:MOUNT[/PREFIX]]' or 'exec:PATH'
+func NewConfigFromSpec(spec string) (Config, error) {
+	typ, arg, _ := strings.Cut(spec, ":")
+
+	switch typ {
+	case TypeCredHub:
+		return Config{Type: typ, Prefix: arg}, nil
+
+	case TypeVault:
+		mount, prefix, _ := strings.Cut(strings.Trim(arg, "/"), "/")
+		return Config{Type: typ, Mount: mount, Prefix: prefix}, nil
+
+	case TypeExec:
+		if len(arg) == 0 {
+			return Config{}, bosherr.Errorf("Expected exec variables source '%s' to specify plugin path", spec)
+		}
+
+		return Config{Type: typ, Path: arg}, nil
+
+	default:
+		return Config{}, bosherr.Errorf(
+			"Expected variables source '%s' to be one of 'credhub[:PREFIX]', 'vault[:MOUNT[/PREFIX]]' or 'exec:PATH'", spec)
+	}
+}
+
+// NewConfigsFromBytes parses YAML file with a 'sources' list
+func NewConfigsFromBytes(bytes []byte) ([]Config, error) {
+	var file configsFile
+
+	err := yaml.UnmarshalStrict(bytes, &file)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Deserializing variables sources")
+	}
+
+	return file.Sources, nil
+}
+
+// WithEnv fills in settings that are not set explicitly from environment
+// variables used by CredHub and Vault CLIs (e.g. CREDHUB_SERVER, VAULT_ADDR)
+func (c Config) WithEnv(getenv func(string) string) Config {
+	setDefault := func(val *string, envName string) {
+		if len(*val) == 0 {
+			*val = getenv(envName)
+		}
+	}
+
+	switch c.Type {
+	case TypeCredHub:
+		setDefault(&c.URL, "CREDHUB_SERVER")
+		setDefault(&c.Client, "CREDHUB_CLIENT")
+		setDefault(&c.ClientSecret, "CREDHUB_SECRET")
+		setDefault(&c.CACert, "CREDHUB_CA_CERT")
+
+	case TypeVault:
+		setDefault(&c.URL, "VAULT_ADDR")
+		setDefault(&c.Token, "VAULT_TOKEN")
+		setDefault(&c.CACert, "VAULT_CACERT")
+	}
+
+	if c.Type == TypeVault && len(c.Mount) == 0 {
+		c.Mount = defaultVaultMount
+	}
+
+	return c
+}
+
+func (c Config) Validate() error {
+	switch c.Type {
+	case TypeCredHub:
+		if len(c.URL) == 0 {
+			return bosherr.Error("Expected CredHub variables source to specify URL (or CREDHUB_SERVER)")
+		}
+
+		if len(c.Client) == 0 {
+			return bosherr.Error("Expected CredHub variables source to specify client (or CREDHUB_CLIENT)")
+		}
+
+	case TypeVault:
+		if len(c.URL) == 0 {
+			return bosherr.Error("Expected Vault variables source to specify URL (or VAULT_ADDR)")
+		}
+
+		if len(c.Token) == 0 {
+			return bosherr.Error("Expected Vault variables source to specify token (or VAULT_TOKEN)")
+		}
+
+	case TypeExec:
+		if len(c.Path) == 0 {
+			return bosherr.Error("Expected exec variables source to specify path")
+		}
+
+	default:
+		return bosherr.Errorf("Unknown variables source type '%s', expected one of credhub, vault or exec", c.Type)
+	}
+
+	return nil
+}
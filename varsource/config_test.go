@@ -0,0 +1,138 @@
+package varsource_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/cloudfoundry/bosh-cli/v7/varsource"
+)
+
+var _ = Describe("Config", func() {
+	Describe("NewConfigFromSpec", func() {
+		It("parses credhub source with optional prefix", func() {
+			Expect(NewConfigFromSpec("credhub")).To(Equal(Config{Type: "credhub"}))
+			Expect(NewConfigFromSpec("credhub:/bosh/env")).To(Equal(Config{Type: "credhub", Prefix: "/bosh/env"}))
+		})
+
+		It("parses vault source with optional mount and prefix", func() {
+			Expect(NewConfigFromSpec("vault")).To(Equal(Config{Type: "vault"}))
+			Expect(NewConfigFromSpec("vault:kv")).To(Equal(Config{Type: "vault", Mount: "kv"}))
+			Expect(NewConfigFromSpec("vault:/kv/bosh/env/")).To(Equal(Config{Type: "vault", Mount: "kv", Prefix: "bosh/env"}))
+		})
+
+		It("parses exec source", func() {
+			Expect(NewConfigFromSpec("exec:/bin/plugin")).To(Equal(Config{Type: "exec", Path: "/bin/plugin"}))
+		})
+
+		It("returns error if exec source does not specify path", func() {
+			_, err := NewConfigFromSpec("exec")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected exec variables source 'exec' to specify plugin path"))
+		})
+
+		It("returns error for unknown source", func() {
+			_, err := NewConfigFromSpec("lastpass:x")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected variables source 'lastpass:x' to be one of"))
+		})
+	})
+
+	Describe("NewConfigsFromBytes", func() {
+		It("parses sources", func() {
+			configs, err := NewConfigsFromBytes([]byte(`
+sources:
+- type: credhub
+  url: https://credhub:8844
+  client: admin
+  client_secret: secret
+  prefix: /bosh/env
+- type: exec
+  path: /bin/plugin
+  args: [--env, prod]
+`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configs).To(Equal([]Config{
+				{Type: "credhub", URL: "https://credhub:8844", Client: "admin", ClientSecret: "secret", Prefix: "/bosh/env"},
+				{Type: "exec", Path: "/bin/plugin", Args: []string{"--env", "prod"}},
+			}))
+		})
+
+		It("returns error for unknown keys", func() {
+			_, err := NewConfigsFromBytes([]byte("sources:\n- type: vault\n  adress: http://vault\n"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Deserializing variables sources"))
+		})
+	})
+
+	Describe("WithEnv", func() {
+		env := map[string]string{
+			"CREDHUB_SERVER":  "https://credhub",
+			"CREDHUB_CLIENT":  "env-client",
+			"CREDHUB_SECRET":  "env-secret",
+			"CREDHUB_CA_CERT": "/ca.pem",
+			"VAULT_ADDR":      "http://vault:8200",
+			"VAULT_TOKEN":     "env-token",
+			"VAULT_CACERT":    "/vault-ca.pem",
+		}
+
+		getenv := func(name string) string { return env[name] }
+
+		It("fills in CredHub settings that are not explicitly set", func() {
+			config := Config{Type: "credhub", Client: "client"}.WithEnv(getenv)
+			Expect(config).To(Equal(Config{
+				Type:         "credhub",
+				URL:          "https://credhub",
+				Client:       "client",
+				ClientSecret: "env-secret",
+				CACert:       "/ca.pem",
+			}))
+		})
+
+		It("fills in Vault settings and defaults mount", func() {
+			config := Config{Type: "vault", Token: "token"}.WithEnv(getenv)
+			Expect(config).To(Equal(Config{
+				Type:   "vault",
+				URL:    "http://vault:8200",
+				Token:  "token",
+				CACert: "/vault-ca.pem",
+				Mount:  "secret",
+			}))
+		})
+
+		It("does not change exec settings", func() {
+			Expect(Config{Type: "exec", Path: "/bin/plugin"}.WithEnv(getenv)).To(Equal(Config{Type: "exec", Path: "/bin/plugin"}))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("requires URL and client for CredHub", func() {
+			Expect(Config{Type: "credhub", URL: "https://credhub", Client: "c"}.Validate()).To(Succeed())
+
+			err := Config{Type: "credhub", Client: "c"}.Validate()
+			Expect(err).To(MatchError("Expected CredHub variables source to specify URL (or CREDHUB_SERVER)"))
+
+			err = Config{Type: "credhub", URL: "https://credhub"}.Validate()
+			Expect(err).To(MatchError("Expected CredHub variables source to specify client (or CREDHUB_CLIENT)"))
+		})
+
+		It("requires URL and token for Vault", func() {
+			Expect(Config{Type: "vault", URL: "http://vault", Token: "t"}.Validate()).To(Succeed())
+
+			err := Config{Type: "vault", Token: "t"}.Validate()
+			Expect(err).To(MatchError("Expected Vault variables source to specify URL (or VAULT_ADDR)"))
+
+			err = Config{Type: "vault", URL: "http://vault"}.Validate()
+			Expect(err).To(MatchError("Expected Vault variables source to specify token (or VAULT_TOKEN)"))
+		})
+
+		It("requires path for exec", func() {
+			err := Config{Type: "exec"}.Validate()
+			Expect(err).To(MatchError("Expected exec variables source to specify path"))
+		})
+
+		It("returns error for unknown type", func() {
+			err := Config{Type: "unknown"}.Validate()
+			Expect(err).To(MatchError("Unknown variables source type 'unknown', expected one of credhub, vault or exec"))
+		})
+	})
+})
@@ -0,0 +1,254 @@
+package varsource
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	gourl "net/url"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	boshuaa "github.com/cloudfoundry/bosh-cli/v7/uaa"
+)
+
+// CredHub reads and writes credentials via CredHub API.
+// UAA location is discovered from CredHub's info endpoint on first use.
+type CredHub struct {
+	url          string
+	prefix       string
+	client       string
+	clientSecret string
+	caCert       string
+
+	httpClient *http.Client
+	tokenFunc  func(bool) (string, error)
+
+	logTag string
+	logger boshlog.Logger
+}
+
+var _ WritableSource = &CredHub{}
+
+type credHubInfoResp struct {
+	AuthServer struct {
+		URL string `json:"url"`
+	} `json:"auth-server"`
+}
+
+type credHubDataResp struct {
+	Data []struct {
+		Value interface{} `json:"value"`
+	} `json:"data"`
+}
+
+type credHubSetReq struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+func NewCredHub(config Config, caCert string, httpClient *http.Client, logger boshlog.Logger) *CredHub {
+	return &CredHub{
+		url:          strings.TrimSuffix(config.URL, "/"),
+		prefix:       config.Prefix,
+		client:       config.Client,
+		clientSecret: config.ClientSecret,
+		caCert:       caCert,
+
+		httpClient: httpClient,
+
+		logTag: "varsource.CredHub",
+		logger: logger,
+	}
+}
+
+func (c *CredHub) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	name := c.credentialName(varDef.Name)
+	query := gourl.Values{"name": []string{name}, "current": []string{"true"}}
+
+	respBody, status, err := c.request("GET", "/api/v1/data?"+query.Encode(), nil)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Getting credential '%s' from CredHub", name)
+	}
+
+	if status == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	var resp credHubDataResp
+
+	err = json.Unmarshal(respBody, &resp)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Deserializing credential '%s' from CredHub", name)
+	}
+
+	if len(resp.Data) == 0 {
+		return nil, false, nil
+	}
+
+	return fromJSON(resp.Data[0].Value), true, nil
+}
+
+// List does not return any definitions since CredHub is expected
+// to contain credentials that are not used by the manifest
+func (c *CredHub) List() ([]boshtpl.VariableDefinition, error) {
+	return nil, nil
+}
+
+func (c *CredHub) Set(varDef boshtpl.VariableDefinition, val interface{}) error {
+	name := c.credentialName(varDef.Name)
+
+	jsonVal, err := toJSON(val)
+	if err != nil {
+		return err
+	}
+
+	credType := credHubType(varDef.Type, jsonVal)
+
+	// CredHub calculates fingerprints itself
+	if hash, ok := jsonVal.(map[string]interface{}); ok && credType == "ssh" {
+		delete(hash, "public_key_fingerprint")
+	}
+
+	reqBody, err := json.Marshal(credHubSetReq{Name: name, Type: credType, Value: jsonVal})
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Serializing credential '%s'", name)
+	}
+
+	_, _, err = c.request("PUT", "/api/v1/data", reqBody)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Setting credential '%s' in CredHub", name)
+	}
+
+	return nil
+}
+
+// credentialName makes relative names absolute by prepending the prefix
+func (c *CredHub) credentialName(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+
+	return strings.TrimSuffix("/"+strings.Trim(c.prefix, "/"), "/") + "/" + name
+}
+
+// request returns response body and status; responses other than 2xx and 404 are errors
+func (c *CredHub) request(method, path string, body []byte) ([]byte, int, error) {
+	if c.tokenFunc == nil {
+		err := c.setUpAuth()
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	retried := false
+
+	for {
+		token, err := c.tokenFunc(retried)
+		if err != nil {
+			return nil, 0, bosherr.WrapError(err, "Getting UAA access token")
+		}
+
+		req, err := http.NewRequest(method, c.url+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, 0, bosherr.WrapError(err, "Building request")
+		}
+
+		req.Header.Set("Authorization", token)
+		req.Header.Set("Content-Type", "application/json")
+
+		respBody, status, err := c.do(req)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if status == http.StatusUnauthorized && !retried {
+			retried = true
+			continue
+		}
+
+		if status == http.StatusNotFound || (status >= 200 && status < 300) {
+			return respBody, status, nil
+		}
+
+		return nil, 0, bosherr.Errorf("CredHub responded with non-successful status code '%d' response '%s'", status, respBody)
+	}
+}
+
+func (c *CredHub) setUpAuth() error {
+	req, err := http.NewRequest("GET", c.url+"/info", nil)
+	if err != nil {
+		return bosherr.WrapError(err, "Building request")
+	}
+
+	respBody, status, err := c.do(req)
+	if err != nil {
+		return bosherr.WrapError(err, "Getting CredHub info")
+	}
+
+	if status != http.StatusOK {
+		return bosherr.Errorf("Getting CredHub info: responded with non-successful status code '%d'", status)
+	}
+
+	var info credHubInfoResp
+
+	err = json.Unmarshal(respBody, &info)
+	if err != nil {
+		return bosherr.WrapError(err, "Deserializing CredHub info")
+	}
+
+	uaaConfig, err := boshuaa.NewConfigFromURL(info.AuthServer.URL)
+	if err != nil {
+		return err
+	}
+
+	uaaConfig.Client = c.client
+	uaaConfig.ClientSecret = c.clientSecret
+	uaaConfig.CACert = c.caCert
+
+	uaa, err := boshuaa.NewFactory(c.logger).New(uaaConfig)
+	if err != nil {
+		return err
+	}
+
+	c.tokenFunc = boshuaa.NewClientTokenSession(uaa).TokenFunc
+
+	return nil
+}
+
+func (c *CredHub) do(req *http.Request) ([]byte, int, error) {
+	c.logger.Debug(c.logTag, "Requesting '%s %s'", req.Method, req.URL.Path)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, bosherr.WrapErrorf(err, "Performing request '%s %s'", req.Method, req.URL.Path)
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, bosherr.WrapError(err, "Reading response")
+	}
+
+	return respBody, resp.StatusCode, nil
+}
+
+// credHubType maps variable types to CredHub credential types;
+// values of unknown types are stored as generic values
+func credHubType(varType string, val interface{}) string {
+	switch varType {
+	case "password", "certificate", "rsa", "ssh":
+		return varType
+	}
+
+	if _, ok := val.(map[string]interface{}); ok {
+		return "json"
+	}
+
+	return "value"
+}
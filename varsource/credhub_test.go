@@ -0,0 +1,175 @@
+package varsource_test
+
+import (
+	"encoding/pem"
+	"net/http"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	. "github.com/cloudfoundry/bosh-cli/v7/varsource"
+)
+
+var _ = Describe("CredHub", func() {
+	var (
+		server  *ghttp.Server
+		source  boshtpl.Variables
+		caCert  string
+		factory Factory
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewTLSServer()
+
+		caCert = string(pem.EncodeToMemory(&pem.Block{
+			Type: "CERTIFICATE", Bytes: server.HTTPTestServer.Certificate().Raw}))
+
+		fs := fakesys.NewFakeFileSystem()
+		factory = NewFactory(fs, fakesys.NewFakeCmdRunner(), boshlog.NewLogger(boshlog.LevelNone))
+
+		var err error
+
+		source, err = factory.New(Config{
+			Type:         "credhub",
+			URL:          server.URL(),
+			Client:       "client",
+			ClientSecret: "client-secret",
+			CACert:       caCert,
+			Prefix:       "/bosh/env",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/info"),
+				ghttp.RespondWith(http.StatusOK, `{"auth-server":{"url":"`+server.URL()+`"}}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/oauth/token"),
+				ghttp.VerifyBasicAuth("client", "client-secret"),
+				ghttp.VerifyBody([]byte("grant_type=client_credentials")),
+				ghttp.RespondWith(http.StatusOK, `{"token_type":"bearer","access_token":"access-token"}`),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Get", func() {
+		It("returns current value of credential under the prefix", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "current=true&name=%2Fbosh%2Fenv%2Fcert"),
+					ghttp.VerifyHeader(http.Header{"Authorization": []string{"bearer access-token"}}),
+					ghttp.RespondWith(http.StatusOK, `{"data":[{"type":"certificate","value":{"ca":"ca","certificate":"cert"}}]}`),
+				),
+			)
+
+			val, found, err := source.Get(boshtpl.VariableDefinition{Name: "cert"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal(map[interface{}]interface{}{"ca": "ca", "certificate": "cert"}))
+		})
+
+		It("uses absolute names as is", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data", "current=true&name=%2Fshared%2Fpass"),
+					ghttp.RespondWith(http.StatusOK, `{"data":[{"type":"password","value":"secret"}]}`),
+				),
+			)
+
+			val, found, err := source.Get(boshtpl.VariableDefinition{Name: "/shared/pass"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("secret"))
+		})
+
+		It("returns not found if credential does not exist", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data"),
+					ghttp.RespondWith(http.StatusNotFound, `{"error":"not found"}`),
+				),
+			)
+
+			_, found, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("gets new token and retries once if token is rejected", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data"),
+					ghttp.RespondWith(http.StatusUnauthorized, ``),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.RespondWith(http.StatusOK, `{"token_type":"bearer","access_token":"new-token"}`),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data"),
+					ghttp.VerifyHeader(http.Header{"Authorization": []string{"bearer new-token"}}),
+					ghttp.RespondWith(http.StatusOK, `{"data":[{"type":"value","value":8080}]}`),
+				),
+			)
+
+			val, found, err := source.Get(boshtpl.VariableDefinition{Name: "port"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal(8080))
+		})
+
+		It("returns error if CredHub responds with error", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v1/data"),
+					ghttp.RespondWith(http.StatusForbidden, `forbidden`),
+				),
+			)
+
+			_, _, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Getting credential '/bosh/env/pass' from CredHub"))
+			Expect(err.Error()).To(ContainSubstring("non-successful status code '403' response 'forbidden'"))
+		})
+	})
+
+	Describe("Set", func() {
+		It("saves value with CredHub type based on variable type", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/v1/data"),
+					ghttp.VerifyJSON(`{"name":"/bosh/env/key","type":"ssh","value":{"private_key":"priv","public_key":"pub"}}`),
+					ghttp.RespondWith(http.StatusOK, `{}`),
+				),
+			)
+
+			err := source.(WritableSource).Set(
+				boshtpl.VariableDefinition{Name: "key", Type: "ssh"},
+				map[interface{}]interface{}{"private_key": "priv", "public_key": "pub", "public_key_fingerprint": "fp"},
+			)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("saves values of unknown types as generic values", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("PUT", "/api/v1/data"),
+					ghttp.VerifyJSON(`{"name":"/bosh/env/opts","type":"json","value":{"a":1}}`),
+					ghttp.RespondWith(http.StatusOK, `{}`),
+				),
+			)
+
+			err := source.(WritableSource).Set(boshtpl.VariableDefinition{Name: "opts"}, map[interface{}]interface{}{"a": 1})
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,81 @@
+package varsource
+
+import (
+	"bytes"
+	"encoding/json"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+// Exec runs a plugin for each variable. Plugin receives variable
+// definition as JSON on stdin (e.g. '{"name":"pass","type":"password","options":null}')
+// and is expected to print JSON on stdout (e.g. '{"found":true,"value":"secret"}').
+// Plugins may use definition's type and options to generate missing values.
+type Exec struct {
+	path string
+	args []string
+
+	cmdRunner boshsys.CmdRunner
+}
+
+var _ boshtpl.Variables = Exec{}
+
+type execReq struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Options interface{} `json:"options"`
+}
+
+type execResp struct {
+	Found bool        `json:"found"`
+	Value interface{} `json:"value"`
+}
+
+func NewExec(config Config, cmdRunner boshsys.CmdRunner) Exec {
+	return Exec{path: config.Path, args: config.Args, cmdRunner: cmdRunner}
+}
+
+func (e Exec) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	options, err := toJSON(varDef.Options)
+	if err != nil {
+		return nil, false, err
+	}
+
+	reqBytes, err := json.Marshal(execReq{Name: varDef.Name, Type: varDef.Type, Options: options})
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Serializing variable definition '%s'", varDef.Name)
+	}
+
+	cmd := boshsys.Command{
+		Name:  e.path,
+		Args:  e.args,
+		Stdin: bytes.NewReader(reqBytes),
+		Quiet: true,
+	}
+
+	stdout, stderr, _, err := e.cmdRunner.RunComplexCommand(cmd)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Running variables plugin '%s' for variable '%s': %s", e.path, varDef.Name, stderr)
+	}
+
+	var resp execResp
+
+	err = json.Unmarshal([]byte(stdout), &resp)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Deserializing output of variables plugin '%s' for variable '%s'", e.path, varDef.Name)
+	}
+
+	if !resp.Found {
+		return nil, false, nil
+	}
+
+	return fromJSON(resp.Value), true, nil
+}
+
+// List does not return any definitions since plugins only look up requested variables
+func (e Exec) List() ([]boshtpl.VariableDefinition, error) {
+	return nil, nil
+}
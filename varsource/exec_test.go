@@ -0,0 +1,78 @@
+package varsource_test
+
+import (
+	"errors"
+	"io"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	. "github.com/cloudfoundry/bosh-cli/v7/varsource"
+)
+
+var _ = Describe("Exec", func() {
+	var (
+		cmdRunner *fakesys.FakeCmdRunner
+		source    boshtpl.Variables
+	)
+
+	BeforeEach(func() {
+		cmdRunner = fakesys.NewFakeCmdRunner()
+
+		factory := NewFactory(fakesys.NewFakeFileSystem(), cmdRunner, boshlog.NewLogger(boshlog.LevelNone))
+
+		var err error
+
+		source, err = factory.New(Config{Type: "exec", Path: "/bin/plugin", Args: []string{"--env", "prod"}})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("passes variable definition on stdin and returns found value", func() {
+		cmdRunner.AddCmdResult("/bin/plugin --env prod", fakesys.FakeCmdResult{
+			Stdout: `{"found":true,"value":{"certificate":"cert","private_key":"key"}}`,
+		})
+
+		val, found, err := source.Get(boshtpl.VariableDefinition{
+			Name:    "cert",
+			Type:    "certificate",
+			Options: map[interface{}]interface{}{"common_name": "bosh", "alternative_names": []interface{}{"10.0.0.6"}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(val).To(Equal(map[interface{}]interface{}{"certificate": "cert", "private_key": "key"}))
+
+		Expect(cmdRunner.RunComplexCommands).To(HaveLen(1))
+
+		stdin, err := io.ReadAll(cmdRunner.RunComplexCommands[0].Stdin)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stdin).To(MatchJSON(`{"name":"cert","type":"certificate","options":{"alternative_names":["10.0.0.6"],"common_name":"bosh"}}`))
+	})
+
+	It("returns not found if plugin does not find the variable", func() {
+		cmdRunner.AddCmdResult("/bin/plugin --env prod", fakesys.FakeCmdResult{Stdout: `{"found":false}`})
+
+		_, found, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+	})
+
+	It("returns error including stderr if plugin fails", func() {
+		cmdRunner.AddCmdResult("/bin/plugin --env prod", fakesys.FakeCmdResult{
+			Stderr: "not logged in", ExitStatus: 1, Error: errors.New("fake-err")})
+
+		_, _, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Running variables plugin '/bin/plugin' for variable 'pass': not logged in"))
+	})
+
+	It("returns error if plugin output is not JSON", func() {
+		cmdRunner.AddCmdResult("/bin/plugin --env prod", fakesys.FakeCmdResult{Stdout: "secret"})
+
+		_, _, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Deserializing output of variables plugin '/bin/plugin' for variable 'pass'"))
+	})
+})
@@ -0,0 +1,81 @@
+package varsource
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry/bosh-utils/crypto"
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cloudfoundry/bosh-utils/httpclient"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-utils/system"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+type Factory struct {
+	fs        boshsys.FileSystem
+	cmdRunner boshsys.CmdRunner
+	logger    boshlog.Logger
+}
+
+func NewFactory(fs boshsys.FileSystem, cmdRunner boshsys.CmdRunner, logger boshlog.Logger) Factory {
+	return Factory{fs: fs, cmdRunner: cmdRunner, logger: logger}
+}
+
+// New validates configuration and builds a source; connections are made on first use
+func (f Factory) New(config Config) (boshtpl.Variables, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.Type {
+	case TypeCredHub:
+		caCert, httpClient, err := f.httpClient(config)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewCredHub(config, caCert, httpClient, f.logger), nil
+
+	case TypeVault:
+		_, httpClient, err := f.httpClient(config)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewVault(config, httpClient, f.logger), nil
+
+	default:
+		return NewExec(config, f.cmdRunner), nil
+	}
+}
+
+// httpClient trusts CA certificate given as PEM contents or a path to a file
+func (f Factory) httpClient(config Config) (string, *http.Client, error) {
+	caCert := config.CACert
+
+	if len(caCert) > 0 && !strings.HasPrefix(strings.TrimSpace(caCert), "-----BEGIN") {
+		path, err := f.fs.ExpandPath(caCert)
+		if err != nil {
+			return "", nil, bosherr.WrapErrorf(err, "Expanding CA certificate path '%s'", caCert)
+		}
+
+		caCert, err = f.fs.ReadFileString(path)
+		if err != nil {
+			return "", nil, bosherr.WrapErrorf(err, "Reading CA certificate '%s'", path)
+		}
+	}
+
+	if len(caCert) == 0 {
+		return "", httpclient.CreateDefaultClient(nil), nil
+	}
+
+	certPool, err := crypto.CertPoolFromPEM([]byte(caCert))
+	if err != nil {
+		return "", nil, bosherr.WrapError(err, "Parsing CA certificate")
+	}
+
+	return caCert, httpclient.CreateDefaultClient(certPool), nil
+}
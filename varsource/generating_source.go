@@ -0,0 +1,58 @@
+package varsource
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	cfgtypes "github.com/cloudfoundry/config-server/types"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+// GeneratingSource generates values of variables that have a type
+// but are not found in the source, and saves them to the source.
+type GeneratingSource struct {
+	Source WritableSource
+
+	ValueGeneratorFactory cfgtypes.ValueGeneratorFactory
+}
+
+var _ boshtpl.Variables = &GeneratingSource{}
+
+func NewGeneratingSource(source WritableSource) *GeneratingSource {
+	return &GeneratingSource{
+		Source:                source,
+		ValueGeneratorFactory: cfgtypes.NewValueGeneratorConcrete(nil),
+	}
+}
+
+func (s *GeneratingSource) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	val, found, err := s.Source.Get(varDef)
+	if found || err != nil || len(varDef.Type) == 0 {
+		return val, found, err
+	}
+
+	generator, err := s.ValueGeneratorFactory.GetGenerator(varDef.Type)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Generating variable '%s'", varDef.Name)
+	}
+
+	val, err = generator.Generate(varDef.Options)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Generating variable '%s'", varDef.Name)
+	}
+
+	jsonVal, err := toJSON(val)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.Source.Set(varDef, jsonVal)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return fromJSON(jsonVal), true, nil
+}
+
+func (s *GeneratingSource) List() ([]boshtpl.VariableDefinition, error) {
+	return s.Source.List()
+}
@@ -0,0 +1,82 @@
+package varsource_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	. "github.com/cloudfoundry/bosh-cli/v7/varsource"
+	"github.com/cloudfoundry/bosh-cli/v7/varsource/varsourcefakes"
+)
+
+var _ = Describe("GeneratingSource", func() {
+	var (
+		writable *varsourcefakes.FakeWritableSource
+		source   *GeneratingSource
+	)
+
+	BeforeEach(func() {
+		writable = &varsourcefakes.FakeWritableSource{}
+		source = NewGeneratingSource(writable)
+	})
+
+	It("returns found values without generating", func() {
+		writable.GetReturns("secret", true, nil)
+
+		val, found, err := source.Get(boshtpl.VariableDefinition{Name: "pass", Type: "password"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		Expect(val).To(Equal("secret"))
+
+		Expect(writable.SetCallCount()).To(Equal(0))
+	})
+
+	It("does not generate values of variables without type", func() {
+		_, found, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeFalse())
+
+		Expect(writable.SetCallCount()).To(Equal(0))
+	})
+
+	It("generates and saves missing values based on type", func() {
+		varDef := boshtpl.VariableDefinition{Name: "key", Type: "rsa"}
+
+		val, found, err := source.Get(varDef)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+
+		hash, ok := val.(map[interface{}]interface{})
+		Expect(ok).To(BeTrue())
+		Expect(hash["private_key"]).To(ContainSubstring("PRIVATE KEY"))
+		Expect(hash["public_key"]).To(ContainSubstring("PUBLIC KEY"))
+
+		Expect(writable.SetCallCount()).To(Equal(1))
+
+		setDef, setVal := writable.SetArgsForCall(0)
+		Expect(setDef).To(Equal(varDef))
+		Expect(setVal).To(Equal(map[string]interface{}{"private_key": hash["private_key"], "public_key": hash["public_key"]}))
+	})
+
+	It("returns error if type is unknown", func() {
+		_, _, err := source.Get(boshtpl.VariableDefinition{Name: "x", Type: "unknown"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Generating variable 'x'"))
+	})
+
+	It("returns error if saving fails", func() {
+		writable.SetReturns(errors.New("fake-err"))
+
+		_, _, err := source.Get(boshtpl.VariableDefinition{Name: "pass", Type: "password"})
+		Expect(err).To(MatchError("fake-err"))
+	})
+
+	It("returns error if getting fails", func() {
+		writable.GetReturns(nil, false, errors.New("fake-err"))
+
+		_, _, err := source.Get(boshtpl.VariableDefinition{Name: "pass", Type: "password"})
+		Expect(err).To(MatchError("fake-err"))
+	})
+})
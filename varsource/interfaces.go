@@ -0,0 +1,18 @@
+package varsource
+
+import (
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+// You only need **one** of these per package!
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+//counterfeiter:generate . WritableSource
+
+// WritableSource is implemented by sources that can store values
+// generated by the CLI (e.g. for create-env)
+type WritableSource interface {
+	boshtpl.Variables
+
+	Set(varDef boshtpl.VariableDefinition, val interface{}) error
+}
@@ -0,0 +1,13 @@
+package varsource_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestVarsource(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "varsource")
+}
@@ -0,0 +1,110 @@
+package varsource
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// fromJSON converts decoded JSON into values produced by YAML parsing
+// so that template could look up nested keys (e.g. '((cert.private_key))')
+func fromJSON(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		result := map[interface{}]interface{}{}
+
+		for k, v := range typedVal {
+			result[k] = fromJSON(v)
+		}
+
+		return result
+
+	case []interface{}:
+		result := make([]interface{}, len(typedVal))
+
+		for i, v := range typedVal {
+			result[i] = fromJSON(v)
+		}
+
+		return result
+
+	case float64:
+		if typedVal == math.Trunc(typedVal) && math.Abs(typedVal) < math.MaxInt64 {
+			return int(typedVal)
+		}
+
+		return typedVal
+
+	default:
+		return val
+	}
+}
+
+// toJSON converts values produced by YAML parsing or value generators
+// into values that could be serialized as JSON
+func toJSON(val interface{}) (interface{}, error) {
+	switch typedVal := val.(type) {
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+
+		for k, v := range typedVal {
+			converted, err := toJSON(v)
+			if err != nil {
+				return nil, err
+			}
+
+			result[fmt.Sprintf("%v", k)] = converted
+		}
+
+		return result, nil
+
+	case []interface{}:
+		result := make([]interface{}, len(typedVal))
+
+		for i, v := range typedVal {
+			converted, err := toJSON(v)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = converted
+		}
+
+		return result, nil
+
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+
+		for k, v := range typedVal {
+			converted, err := toJSON(v)
+			if err != nil {
+				return nil, err
+			}
+
+			result[k] = converted
+		}
+
+		return result, nil
+
+	case nil, string, bool, int, int64, uint64, float64:
+		return val, nil
+
+	default:
+		// Generated values are structs (e.g. certificates)
+		bytes, err := json.Marshal(val)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Serializing value")
+		}
+
+		var result interface{}
+
+		err = json.Unmarshal(bytes, &result)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Deserializing value")
+		}
+
+		return result, nil
+	}
+}
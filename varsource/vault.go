@@ -0,0 +1,167 @@
+package varsource
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+)
+
+// Vault reads and writes secrets in Vault's KV v2 secrets engine.
+// Each variable is a separate secret; non-hash values are kept under 'value' key.
+type Vault struct {
+	url    string
+	token  string
+	mount  string
+	prefix string
+
+	httpClient *http.Client
+
+	logTag string
+	logger boshlog.Logger
+}
+
+var _ WritableSource = Vault{}
+
+const vaultValueKey = "value"
+
+type vaultSecretResp struct {
+	Data struct {
+		Data map[string]interface{} `json:"data"`
+	} `json:"data"`
+}
+
+type vaultSecretReq struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+func NewVault(config Config, httpClient *http.Client, logger boshlog.Logger) Vault {
+	return Vault{
+		url:    strings.TrimSuffix(config.URL, "/"),
+		token:  config.Token,
+		mount:  strings.Trim(config.Mount, "/"),
+		prefix: strings.Trim(config.Prefix, "/"),
+
+		httpClient: httpClient,
+
+		logTag: "varsource.Vault",
+		logger: logger,
+	}
+}
+
+func (v Vault) Get(varDef boshtpl.VariableDefinition) (interface{}, bool, error) {
+	secretPath := v.secretPath(varDef.Name)
+
+	respBody, status, err := v.request("GET", secretPath, nil)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Getting secret '%s' from Vault", secretPath)
+	}
+
+	if status == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	var resp vaultSecretResp
+
+	err = json.Unmarshal(respBody, &resp)
+	if err != nil {
+		return nil, false, bosherr.WrapErrorf(err, "Deserializing secret '%s' from Vault", secretPath)
+	}
+
+	// Deleted secret versions do not have data
+	if resp.Data.Data == nil {
+		return nil, false, nil
+	}
+
+	if val, found := resp.Data.Data[vaultValueKey]; found && len(resp.Data.Data) == 1 {
+		return fromJSON(val), true, nil
+	}
+
+	return fromJSON(resp.Data.Data), true, nil
+}
+
+// List does not return any definitions since Vault is expected
+// to contain secrets that are not used by the manifest
+func (v Vault) List() ([]boshtpl.VariableDefinition, error) {
+	return nil, nil
+}
+
+func (v Vault) Set(varDef boshtpl.VariableDefinition, val interface{}) error {
+	secretPath := v.secretPath(varDef.Name)
+
+	jsonVal, err := toJSON(val)
+	if err != nil {
+		return err
+	}
+
+	data, ok := jsonVal.(map[string]interface{})
+	if !ok {
+		data = map[string]interface{}{vaultValueKey: jsonVal}
+	}
+
+	reqBody, err := json.Marshal(vaultSecretReq{Data: data})
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Serializing secret '%s'", secretPath)
+	}
+
+	_, status, err := v.request("POST", secretPath, reqBody)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Setting secret '%s' in Vault", secretPath)
+	}
+
+	if status == http.StatusNotFound {
+		return bosherr.Errorf("Setting secret '%s' in Vault: secrets engine '%s' was not found", secretPath, v.mount)
+	}
+
+	return nil
+}
+
+// secretPath places all variables under the prefix;
+// leading slash of absolute names (e.g. '((/shared/name))') is dropped
+func (v Vault) secretPath(name string) string {
+	name = strings.TrimPrefix(name, "/")
+
+	if len(v.prefix) > 0 {
+		return v.prefix + "/" + name
+	}
+
+	return name
+}
+
+func (v Vault) request(method, secretPath string, body []byte) ([]byte, int, error) {
+	url := v.url + "/v1/" + v.mount + "/data/" + secretPath
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, bosherr.WrapError(err, "Building request")
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	v.logger.Debug(v.logTag, "Requesting '%s %s'", method, req.URL.Path)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, bosherr.WrapErrorf(err, "Performing request '%s %s'", method, req.URL.Path)
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, bosherr.WrapError(err, "Reading response")
+	}
+
+	if resp.StatusCode == http.StatusNotFound || (resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return respBody, resp.StatusCode, nil
+	}
+
+	return nil, 0, bosherr.Errorf("Vault responded with non-successful status code '%d' response '%s'", resp.StatusCode, respBody)
+}
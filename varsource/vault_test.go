@@ -0,0 +1,126 @@
+package varsource_test
+
+import (
+	"net/http"
+
+	boshlog "github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	boshtpl "github.com/cloudfoundry/bosh-cli/v7/director/template"
+	. "github.com/cloudfoundry/bosh-cli/v7/varsource"
+)
+
+var _ = Describe("Vault", func() {
+	var (
+		server *ghttp.Server
+		source WritableSource
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+
+		factory := NewFactory(fakesys.NewFakeFileSystem(), fakesys.NewFakeCmdRunner(), boshlog.NewLogger(boshlog.LevelNone))
+
+		vars, err := factory.New(Config{Type: "vault", URL: server.URL(), Token: "token", Mount: "kv", Prefix: "bosh/env"})
+		Expect(err).ToNot(HaveOccurred())
+
+		source = vars.(WritableSource)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Get", func() {
+		It("returns scalar values kept under 'value' key", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/kv/data/bosh/env/pass"),
+					ghttp.VerifyHeader(http.Header{"X-Vault-Token": []string{"token"}}),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"data":{"value":"secret"},"metadata":{"version":2}}}`),
+				),
+			)
+
+			val, found, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal("secret"))
+		})
+
+		It("returns hash values", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/kv/data/bosh/env/shared/key"),
+					ghttp.RespondWith(http.StatusOK, `{"data":{"data":{"private_key":"priv","public_key":"pub"}}}`),
+				),
+			)
+
+			val, found, err := source.Get(boshtpl.VariableDefinition{Name: "/shared/key"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(val).To(Equal(map[interface{}]interface{}{"private_key": "priv", "public_key": "pub"}))
+		})
+
+		It("returns not found if secret does not exist or was deleted", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusNotFound, `{"errors":[]}`),
+				ghttp.RespondWith(http.StatusOK, `{"data":{"data":null,"metadata":{"deletion_time":"2026-01-01T00:00:00Z"}}}`),
+			)
+
+			_, found, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			_, found, err = source.Get(boshtpl.VariableDefinition{Name: "pass"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+
+		It("returns error if Vault responds with error", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusForbidden, `{"errors":["permission denied"]}`))
+
+			_, _, err := source.Get(boshtpl.VariableDefinition{Name: "pass"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Getting secret 'bosh/env/pass' from Vault"))
+			Expect(err.Error()).To(ContainSubstring("permission denied"))
+		})
+	})
+
+	Describe("Set", func() {
+		It("saves scalar values under 'value' key", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/v1/kv/data/bosh/env/pass"),
+					ghttp.VerifyJSON(`{"data":{"value":"secret"}}`),
+					ghttp.RespondWith(http.StatusOK, `{}`),
+				),
+			)
+
+			err := source.Set(boshtpl.VariableDefinition{Name: "pass"}, "secret")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("saves hash values as secret data", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/v1/kv/data/bosh/env/cert"),
+					ghttp.VerifyJSON(`{"data":{"ca":"ca","certificate":"cert"}}`),
+					ghttp.RespondWith(http.StatusOK, `{}`),
+				),
+			)
+
+			err := source.Set(boshtpl.VariableDefinition{Name: "cert"}, map[interface{}]interface{}{"ca": "ca", "certificate": "cert"})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns error if secrets engine does not exist", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, `{}`))
+
+			err := source.Set(boshtpl.VariableDefinition{Name: "pass"}, "secret")
+			Expect(err).To(MatchError("Setting secret 'bosh/env/pass' in Vault: secrets engine 'kv' was not found"))
+		})
+	})
+})